@@ -3,11 +3,11 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -16,6 +16,7 @@ import (
 	"github.com/STRML/claude-cells/internal/docker"
 	"github.com/STRML/claude-cells/internal/git"
 	"github.com/STRML/claude-cells/internal/gitproxy"
+	"github.com/STRML/claude-cells/internal/hooks"
 	"github.com/STRML/claude-cells/internal/tui"
 	"github.com/STRML/claude-cells/internal/workstream"
 )
@@ -72,52 +73,6 @@ func (s *spinner) Stop() {
 	time.Sleep(100 * time.Millisecond)
 }
 
-const lockFileName = ".ccells.lock"
-
-// lockFile represents an acquired lock
-type lockFile struct {
-	path string
-}
-
-// acquireLock attempts to acquire an exclusive lock for this repo.
-// Returns a lockFile on success, or an error if another instance is running.
-func acquireLock(stateDir string) (*lockFile, error) {
-	lockPath := filepath.Join(stateDir, lockFileName)
-
-	// Check if lock file exists
-	if data, err := os.ReadFile(lockPath); err == nil {
-		// Lock file exists - check if the process is still running
-		pidStr := strings.TrimSpace(string(data))
-		if pid, err := strconv.Atoi(pidStr); err == nil {
-			// Check if process is still alive
-			if process, err := os.FindProcess(pid); err == nil {
-				// On Unix, FindProcess always succeeds, so we need to send signal 0
-				if err := process.Signal(syscall.Signal(0)); err == nil {
-					// Process is still running
-					return nil, fmt.Errorf("another ccells instance is already running (PID %d)", pid)
-				}
-			}
-		}
-		// Stale lock file - remove it
-		os.Remove(lockPath)
-	}
-
-	// Create lock file with our PID
-	pid := os.Getpid()
-	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(pid)), 0644); err != nil {
-		return nil, fmt.Errorf("failed to create lock file: %w", err)
-	}
-
-	return &lockFile{path: lockPath}, nil
-}
-
-// Release removes the lock file
-func (l *lockFile) Release() {
-	if l != nil && l.path != "" {
-		os.Remove(l.path)
-	}
-}
-
 // getStateDir returns the state directory for the current repo.
 // Falls back to cwd if repo ID cannot be determined.
 func getStateDir() string {
@@ -159,7 +114,10 @@ Options:
 
 Keyboard Shortcuts (in TUI):
   n             Create new workstream
+  N             Best-of-N: run one prompt in several cells
+  C             Compare a Best-of-N group and pick a winner
   d             Destroy workstream (with confirmation)
+  A             Browse archived workstreams and restore one
   1-9           Jump to pane by number
   Tab/Shift+Tab Navigate between panes
   Space         Toggle between main pane and others
@@ -223,14 +181,21 @@ func main() {
 
 	// Acquire lock to ensure only one instance runs per repo
 	stateDir := getStateDir()
-	lock, err := acquireLock(stateDir)
+	lock, err := workstream.AcquireLock(stateDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		fmt.Fprintf(os.Stderr, "If the other instance crashed, delete: %s/%s\n", stateDir, lockFileName)
+		fmt.Fprintf(os.Stderr, "If the other instance crashed, delete: %s/%s\n", stateDir, workstream.LockFileName)
 		os.Exit(1)
 	}
 	defer lock.Release()
 
+	// Refuse to run against state written by a newer ccells; saving over it
+	// would drop whatever that version stored
+	if err := workstream.CheckStateVersion(stateDir); errors.Is(err, workstream.ErrStateTooNew) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create a cancellable context for the entire application.
 	// This context is cancelled on SIGINT/SIGTERM and propagates
 	// cancellation to all running operations.
@@ -270,13 +235,15 @@ func main() {
 	// Start git proxy server for proxying git/gh commands from containers
 	gitProxyServer := gitproxy.NewServer(func(workstreamID string, prNumber int, prURL string) {
 		// Callback when a PR is created via the proxy.
-		// Currently only logs the event. State updates happen via the gitproxy
-		// server's internal workstream tracking (UpdateWorkstream method).
+		// State updates happen via the gitproxy server's internal workstream
+		// tracking (UpdateWorkstream method); here we log and run user hooks.
 		tui.LogDebug("PR #%d created for workstream %s: %s", prNumber, workstreamID, prURL)
+		tui.RequestHookEvent(workstreamID, hooks.EventPROpened, prURL)
 	})
-	// Set callback to refresh PR status after successful push
+	// Set callback to refresh PR status and run push hooks after successful push
 	gitProxyServer.SetPushCompleteCallback(func(workstreamID string) {
 		tui.RequestPRStatusRefresh(workstreamID)
+		tui.RequestHookEvent(workstreamID, hooks.EventPush, "")
 	})
 	defer gitProxyServer.Shutdown()
 	tui.SetGitProxyServer(gitProxyServer)
@@ -706,6 +673,21 @@ func runStateRepair() error {
 		return nil
 	}
 
+	// Report the schema version before loading (loading migrates old files)
+	version, err := workstream.StateFileVersion(stateDir)
+	if err != nil {
+		return fmt.Errorf("failed to read state: %w", err)
+	}
+	fmt.Printf("State file: %s\n", workstream.StateFilePath(stateDir))
+	switch {
+	case version > workstream.CurrentStateVersion:
+		fmt.Printf("Schema version: %d (newer than this ccells, which supports up to %d)\n", version, workstream.CurrentStateVersion)
+	case version < workstream.CurrentStateVersion:
+		fmt.Printf("Schema version: %d (will be migrated to %d; backup: %s)\n", version, workstream.CurrentStateVersion, workstream.StateBackupPath(stateDir, version))
+	default:
+		fmt.Printf("Schema version: %d (current)\n", version)
+	}
+
 	// Load current state
 	state, err := workstream.LoadState(stateDir)
 	if err != nil {
@@ -719,15 +701,17 @@ func runStateRepair() error {
 
 	fmt.Printf("Found %d workstream(s) in state file\n", len(state.Workstreams))
 
+	// Keep timelines intact when the repaired state is saved
+	events, err := workstream.LoadEvents(stateDir)
+	if err != nil {
+		return fmt.Errorf("failed to load events: %w", err)
+	}
+
 	// Convert saved workstreams to full workstreams for repair
 	var workstreams []*workstream.Workstream
 	for _, saved := range state.Workstreams {
-		ws := workstream.NewWithID(saved.ID, saved.BranchName, saved.Prompt)
-		ws.ContainerID = saved.ContainerID
-		ws.Title = saved.Title
-		ws.Synopsis = saved.Synopsis
-		ws.CreatedAt = saved.CreatedAt
-		ws.ClaudeSessionID = saved.ClaudeSessionID
+		ws := saved.Restore()
+		ws.SetEvents(events[saved.ID])
 		workstreams = append(workstreams, ws)
 	}
 
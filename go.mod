@@ -9,6 +9,7 @@ require (
 	github.com/charmbracelet/x/ansi v0.11.4
 	github.com/docker/docker v27.0.0+incompatible
 	github.com/hinshun/vt10x v0.0.0-20220301184237-5011da428d02
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -51,6 +52,5 @@ require (
 	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/sys v0.40.0 // indirect
 	golang.org/x/time v0.14.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	gotest.tools/v3 v3.5.2 // indirect
 )
@@ -0,0 +1,31 @@
+package claude
+
+// Budget limits the tokens or estimated cost a workstream or project may use.
+// Zero fields are unlimited.
+type Budget struct {
+	CostUSD float64 `json:"cost_usd,omitempty" yaml:"cost,omitempty"`
+	Tokens  int64   `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+}
+
+// IsZero reports whether the budget is unlimited.
+func (b Budget) IsZero() bool {
+	return b.CostUSD <= 0 && b.Tokens <= 0
+}
+
+// Fraction returns how much of the budget u has used: the larger of the cost
+// and token fractions, or 0 when the budget is unlimited.
+func (b Budget) Fraction(u Usage) float64 {
+	var f float64
+	if b.CostUSD > 0 {
+		f = u.CostUSD / b.CostUSD
+	}
+	if b.Tokens > 0 {
+		f = max(f, float64(u.TotalTokens())/float64(b.Tokens))
+	}
+	return f
+}
+
+// Exceeded reports whether u has used the whole budget.
+func (b Budget) Exceeded(u Usage) bool {
+	return !b.IsZero() && b.Fraction(u) >= 1
+}
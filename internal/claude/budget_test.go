@@ -0,0 +1,24 @@
+package claude
+
+import "testing"
+
+func TestBudget_Fraction(t *testing.T) {
+	u := Usage{InputTokens: 600, OutputTokens: 200, CostUSD: 2}
+
+	if f := (Budget{}).Fraction(u); f != 0 {
+		t.Errorf("unlimited Fraction() = %v, want 0", f)
+	}
+	if (Budget{}).Exceeded(u) {
+		t.Error("unlimited budget should never be exceeded")
+	}
+	if f := (Budget{CostUSD: 4}).Fraction(u); f != 0.5 {
+		t.Errorf("cost Fraction() = %v, want 0.5", f)
+	}
+	// The larger of the cost and token fractions wins
+	if f := (Budget{CostUSD: 4, Tokens: 1000}).Fraction(u); f != 0.8 {
+		t.Errorf("Fraction() = %v, want 0.8", f)
+	}
+	if !(Budget{Tokens: 800}).Exceeded(u) {
+		t.Error("token budget should be exceeded")
+	}
+}
@@ -0,0 +1,286 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Usage is the token usage and estimated cost of one or more Claude sessions.
+type Usage struct {
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+}
+
+// Add returns the sum of u and other.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		InputTokens:         u.InputTokens + other.InputTokens,
+		OutputTokens:        u.OutputTokens + other.OutputTokens,
+		CacheCreationTokens: u.CacheCreationTokens + other.CacheCreationTokens,
+		CacheReadTokens:     u.CacheReadTokens + other.CacheReadTokens,
+		CostUSD:             u.CostUSD + other.CostUSD,
+	}
+}
+
+// TotalTokens returns input, output and cache creation tokens.
+// Cache reads are excluded since they re-read tokens already counted.
+func (u Usage) TotalTokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens
+}
+
+// IsZero reports whether no usage has been recorded.
+func (u Usage) IsZero() bool {
+	return u == Usage{}
+}
+
+// ModelPrice is the price of a model in USD per million tokens.
+type ModelPrice struct {
+	Input      float64 `yaml:"input"`
+	Output     float64 `yaml:"output"`
+	CacheWrite float64 `yaml:"cache_write"`
+	CacheRead  float64 `yaml:"cache_read"`
+}
+
+// Cost returns the estimated cost of usage at this price.
+func (p ModelPrice) Cost(u Usage) float64 {
+	return (float64(u.InputTokens)*p.Input +
+		float64(u.OutputTokens)*p.Output +
+		float64(u.CacheCreationTokens)*p.CacheWrite +
+		float64(u.CacheReadTokens)*p.CacheRead) / 1e6
+}
+
+// PriceTable maps a model name fragment (e.g. "opus") to its price.
+type PriceTable map[string]ModelPrice
+
+// DefaultPrices are Anthropic list prices per model generation. The bare
+// family names price the current generation; older generations that cost
+// differently have their own keys. They are estimates only; override them
+// with the pricing section of the ccells config.
+var DefaultPrices = PriceTable{
+	"opus":                   {Input: 5, Output: 25, CacheWrite: 6.25, CacheRead: 0.5}, // Opus 4.5 and later
+	"claude-opus-4-1":        {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.5},
+	"claude-opus-4-20250514": {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.5},
+	"claude-3-opus":          {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.5},
+	"sonnet":                 {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.3},
+	"haiku":                  {Input: 1, Output: 5, CacheWrite: 1.25, CacheRead: 0.1}, // Haiku 4.5 and later
+	"claude-3-5-haiku":       {Input: 0.8, Output: 4, CacheWrite: 1, CacheRead: 0.08},
+	"claude-3-haiku":         {Input: 0.25, Output: 1.25, CacheWrite: 0.3, CacheRead: 0.03},
+}
+
+// Lookup returns the price for a model. The longest key contained in the
+// model name wins, so "claude-opus-4" can be priced separately from "opus".
+func (t PriceTable) Lookup(model string) (ModelPrice, bool) {
+	model = strings.ToLower(model)
+	best := ""
+	for key := range t {
+		k := strings.ToLower(key)
+		if k != "" && strings.Contains(model, k) && len(k) > len(best) {
+			best = key
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return t[best], true
+}
+
+// MergePrices returns base with the entries in override added or replaced.
+func MergePrices(base, override PriceTable) PriceTable {
+	result := make(PriceTable, len(base)+len(override))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range override {
+		result[k] = v
+	}
+	return result
+}
+
+// sessionLine is the subset of a session JSONL entry that carries usage.
+type sessionLine struct {
+	Type      string `json:"type"`
+	RequestID string `json:"requestId"`
+	Message   struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// ParseSessionUsage sums the usage of the assistant messages in a Claude
+// session JSONL stream and prices it with prices. Claude writes one line per
+// content block, each repeating the message usage, so messages are counted
+// once (using their last line). Malformed lines are skipped.
+func ParseSessionUsage(r io.Reader, prices PriceTable) (Usage, error) {
+	byModel, err := parseSessionTokens(r)
+	if err != nil {
+		return Usage{}, err
+	}
+	return priceUsage(byModel, prices), nil
+}
+
+// parseSessionTokens sums the unpriced usage of a session JSONL stream per
+// model, for ParseSessionUsage.
+func parseSessionTokens(r io.Reader) (map[string]Usage, error) {
+	type entry struct {
+		usage Usage
+		model string
+	}
+	messages := make(map[string]entry)
+	anon := 0
+
+	br := bufio.NewReader(r)
+	for {
+		// ReadBytes rather than Scanner: tool results can make lines huge
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			var sl sessionLine
+			if json.Unmarshal(line, &sl) == nil && sl.Type == "assistant" && sl.Message.Usage != nil {
+				key := sl.Message.ID + "/" + sl.RequestID
+				if sl.Message.ID == "" {
+					key = fmt.Sprintf("#%d", anon)
+					anon++
+				}
+				u := sl.Message.Usage
+				messages[key] = entry{
+					usage: Usage{
+						InputTokens:         u.InputTokens,
+						OutputTokens:        u.OutputTokens,
+						CacheCreationTokens: u.CacheCreationInputTokens,
+						CacheReadTokens:     u.CacheReadInputTokens,
+					},
+					model: sl.Message.Model,
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	byModel := make(map[string]Usage)
+	for _, e := range messages {
+		byModel[e.model] = byModel[e.model].Add(e.usage)
+	}
+	return byModel, nil
+}
+
+// priceUsage returns the total of per-model usage priced with prices.
+// Models without a price count their tokens but no cost.
+func priceUsage(byModel map[string]Usage, prices PriceTable) Usage {
+	models := make([]string, 0, len(byModel))
+	for model := range byModel {
+		models = append(models, model)
+	}
+	sort.Strings(models) // Sum costs in a stable order
+
+	var total Usage
+	for _, model := range models {
+		u := byModel[model]
+		if price, ok := prices.Lookup(model); ok {
+			u.CostUSD = price.Cost(u)
+		}
+		total = total.Add(u)
+	}
+	return total
+}
+
+// UsageTracker scans session directories, re-parsing only session files
+// that changed since the last scan. Token counts are cached per model and
+// priced on every scan, so a pricing change applies to parsed sessions too.
+// It is safe for concurrent use.
+type UsageTracker struct {
+	mu    sync.Mutex
+	files map[string]trackedSession
+}
+
+type trackedSession struct {
+	size    int64
+	modTime time.Time
+	byModel map[string]Usage // Unpriced usage per model
+}
+
+// NewUsageTracker creates an empty tracker.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{files: make(map[string]trackedSession)}
+}
+
+// ScanDir returns the usage of every session file (*.jsonl) in dir, keyed by
+// session ID (the file name without extension). A missing directory yields
+// no sessions.
+func (t *UsageTracker) ScanDir(dir string, prices PriceTable) (map[string]Usage, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make(map[string]Usage, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		usage, err := t.scanFile(path, info, prices)
+		if err != nil {
+			return nil, err
+		}
+		sessions[strings.TrimSuffix(filepath.Base(path), ".jsonl")] = usage
+	}
+	return sessions, nil
+}
+
+// ForgetDir drops the cached session files in dir, e.g. when the container
+// that wrote them is removed.
+func (t *UsageTracker) ForgetDir(dir string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for path := range t.files {
+		if filepath.Dir(path) == filepath.Clean(dir) {
+			delete(t.files, path)
+		}
+	}
+}
+
+func (t *UsageTracker) scanFile(path string, info os.FileInfo, prices PriceTable) (Usage, error) {
+	t.mu.Lock()
+	cached, ok := t.files[path]
+	t.mu.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return priceUsage(cached.byModel, prices), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Usage{}, err
+	}
+	defer f.Close()
+	byModel, err := parseSessionTokens(f)
+	if err != nil {
+		return Usage{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	t.mu.Lock()
+	t.files[path] = trackedSession{size: info.Size(), modTime: info.ModTime(), byModel: byModel}
+	t.mu.Unlock()
+	return priceUsage(byModel, prices), nil
+}
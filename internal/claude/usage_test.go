@@ -0,0 +1,169 @@
+package claude
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sessionFixture = `{"type":"user","message":{"role":"user","content":"hi"}}
+{"type":"assistant","requestId":"req_1","message":{"id":"msg_1","model":"claude-sonnet-4-5","usage":{"input_tokens":100,"output_tokens":5,"cache_creation_input_tokens":1000,"cache_read_input_tokens":0}}}
+{"type":"assistant","requestId":"req_1","message":{"id":"msg_1","model":"claude-sonnet-4-5","usage":{"input_tokens":100,"output_tokens":50,"cache_creation_input_tokens":1000,"cache_read_input_tokens":0}}}
+not json
+{"type":"assistant","requestId":"req_2","message":{"id":"msg_2","model":"claude-opus-4-1","usage":{"input_tokens":10,"output_tokens":20,"cache_creation_input_tokens":0,"cache_read_input_tokens":2000}}}
+{"type":"assistant","message":{"id":"msg_3","model":"<synthetic>"}}
+`
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestParseSessionUsage(t *testing.T) {
+	u, err := ParseSessionUsage(strings.NewReader(sessionFixture), DefaultPrices)
+	if err != nil {
+		t.Fatalf("ParseSessionUsage() error = %v", err)
+	}
+
+	// msg_1 counted once using its last line
+	want := Usage{InputTokens: 110, OutputTokens: 70, CacheCreationTokens: 1000, CacheReadTokens: 2000}
+	if u.InputTokens != want.InputTokens || u.OutputTokens != want.OutputTokens ||
+		u.CacheCreationTokens != want.CacheCreationTokens || u.CacheReadTokens != want.CacheReadTokens {
+		t.Errorf("tokens = %+v, want %+v", u, want)
+	}
+	if u.TotalTokens() != 1180 {
+		t.Errorf("TotalTokens() = %d, want 1180", u.TotalTokens())
+	}
+
+	sonnet := (100*3.0 + 50*15.0 + 1000*3.75) / 1e6
+	opus := (10*15.0 + 20*75.0 + 2000*1.5) / 1e6
+	if !almostEqual(u.CostUSD, sonnet+opus) {
+		t.Errorf("CostUSD = %v, want %v", u.CostUSD, sonnet+opus)
+	}
+}
+
+func TestParseSessionUsage_UnknownModelHasNoCost(t *testing.T) {
+	line := `{"type":"assistant","message":{"id":"m","model":"mystery","usage":{"input_tokens":100}}}`
+	u, err := ParseSessionUsage(strings.NewReader(line), DefaultPrices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.InputTokens != 100 || u.CostUSD != 0 {
+		t.Errorf("got %+v, want 100 input tokens and no cost", u)
+	}
+}
+
+func TestParseSessionUsage_LongLine(t *testing.T) {
+	big := strings.Repeat("x", 2<<20)
+	line := `{"type":"assistant","message":{"id":"m","model":"haiku","content":"` + big + `","usage":{"output_tokens":7}}}`
+	u, err := ParseSessionUsage(strings.NewReader(line), DefaultPrices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.OutputTokens != 7 {
+		t.Errorf("OutputTokens = %d, want 7", u.OutputTokens)
+	}
+}
+
+func TestPriceTable_LookupPrefersLongestKey(t *testing.T) {
+	prices := MergePrices(DefaultPrices, PriceTable{"claude-opus-4-1-20250805": {Input: 1}})
+
+	p, ok := prices.Lookup("claude-opus-4-1-20250805")
+	if !ok || p.Input != 1 {
+		t.Errorf("Lookup(opus-4-1) = %+v, %v; want override", p, ok)
+	}
+	p, ok = prices.Lookup("claude-opus-4-1-20250901")
+	if !ok || p.Input != 15 {
+		t.Errorf("Lookup(opus-4-1 snapshot) = %+v, %v; want default opus 4.1", p, ok)
+	}
+	if _, ok := prices.Lookup("gpt"); ok {
+		t.Error("Lookup(gpt) should not match")
+	}
+	if _, ok := DefaultPrices["claude-opus-4-1-20250805"]; ok {
+		t.Error("MergePrices must not modify base")
+	}
+}
+
+func TestDefaultPrices_ModelGenerations(t *testing.T) {
+	tests := []struct {
+		model string
+		input float64
+	}{
+		{"claude-opus-4-5-20251101", 5},
+		{"claude-opus-4-1-20250805", 15},
+		{"claude-opus-4-20250514", 15},
+		{"claude-3-opus-20240229", 15},
+		{"claude-sonnet-4-5-20250929", 3},
+		{"claude-haiku-4-5-20251001", 1},
+		{"claude-3-5-haiku-20241022", 0.8},
+		{"claude-3-haiku-20240307", 0.25},
+	}
+	for _, tt := range tests {
+		if p, ok := DefaultPrices.Lookup(tt.model); !ok || p.Input != tt.input {
+			t.Errorf("Lookup(%s) = %+v, %v; want input $%v", tt.model, p, ok, tt.input)
+		}
+	}
+}
+
+func TestUsageTracker_ScanDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sess-1.jsonl")
+	if err := os.WriteFile(path, []byte(sessionFixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := NewUsageTracker()
+	sessions, err := tracker.ScanDir(dir, DefaultPrices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sessions["sess-1"].OutputTokens; got != 70 {
+		t.Errorf("OutputTokens = %d, want 70", got)
+	}
+
+	// Appending a message is picked up on the next scan
+	extra := `{"type":"assistant","requestId":"req_9","message":{"id":"msg_9","model":"haiku","usage":{"output_tokens":30}}}` + "\n"
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(extra)
+	f.Close()
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(path, later, later)
+
+	sessions, err = tracker.ScanDir(dir, DefaultPrices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sessions["sess-1"].OutputTokens; got != 100 {
+		t.Errorf("OutputTokens after append = %d, want 100", got)
+	}
+
+	// A pricing change re-prices the cached session without re-parsing it
+	free := MergePrices(DefaultPrices, PriceTable{"haiku": {}})
+	repriced, err := tracker.ScanDir(dir, free)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, was := repriced["sess-1"].CostUSD, sessions["sess-1"].CostUSD; got >= was {
+		t.Errorf("CostUSD = %v with free haiku, want less than %v", got, was)
+	}
+
+	tracker.ForgetDir(dir)
+	if len(tracker.files) != 0 {
+		t.Errorf("files = %v, want the directory forgotten", tracker.files)
+	}
+}
+
+func TestUsageTracker_MissingDir(t *testing.T) {
+	sessions, err := NewUsageTracker().ScanDir(filepath.Join(t.TempDir(), "nope"), DefaultPrices)
+	if err != nil {
+		t.Fatalf("ScanDir() error = %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("got %d sessions, want 0", len(sessions))
+	}
+}
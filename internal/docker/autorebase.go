@@ -0,0 +1,36 @@
+package docker
+
+// AutoRebaseConfig enables rebasing idle workstreams onto their base branch
+// in the background whenever new base commits are fetched.
+type AutoRebaseConfig struct {
+	// Enabled turns background rebasing on. Default: off
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// AllowForcePush also rebases branches that were already pushed, and
+	// force-pushes them (with lease) afterwards. Default: pushed branches
+	// are skipped
+	AllowForcePush *bool `yaml:"allow_force_push,omitempty"`
+}
+
+// IsEnabled reports whether background rebasing is on.
+func (c *AutoRebaseConfig) IsEnabled() bool {
+	return c.Enabled != nil && *c.Enabled
+}
+
+// ForcePushAllowed reports whether pushed branches may be rebased and
+// force-pushed.
+func (c *AutoRebaseConfig) ForcePushAllowed() bool {
+	return c.AllowForcePush != nil && *c.AllowForcePush
+}
+
+// mergeAutoRebaseConfig merges override values into base.
+func mergeAutoRebaseConfig(base, override AutoRebaseConfig) AutoRebaseConfig {
+	result := base
+	if override.Enabled != nil {
+		result.Enabled = override.Enabled
+	}
+	if override.AllowForcePush != nil {
+		result.AllowForcePush = override.AllowForcePush
+	}
+	return result
+}
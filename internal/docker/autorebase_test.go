@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig_AutoRebaseMerge(t *testing.T) {
+	globalDir := t.TempDir()
+	SetTestCellsDir(globalDir)
+	defer SetTestCellsDir("")
+
+	globalContent := `auto_rebase:
+  enabled: true
+`
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalContent), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, ".claude-cells"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	projectContent := `auto_rebase:
+  allow_force_push: true
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ".claude-cells", "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir).AutoRebase
+	if !cfg.IsEnabled() || !cfg.ForcePushAllowed() {
+		t.Errorf("AutoRebase = enabled %v, force push %v; want both on", cfg.IsEnabled(), cfg.ForcePushAllowed())
+	}
+
+	// A project can turn off globally enabled rebasing
+	projectContent = `auto_rebase:
+  enabled: false
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ".claude-cells", "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg = LoadConfig(projectDir).AutoRebase; cfg.IsEnabled() {
+		t.Error("the project config should disable background rebasing")
+	}
+
+	defaults := AutoRebaseConfig{}
+	if defaults.IsEnabled() || defaults.ForcePushAllowed() {
+		t.Errorf("defaults = %+v, want off", defaults)
+	}
+}
@@ -0,0 +1,157 @@
+package docker
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/STRML/claude-cells/internal/claude"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBudgetWarnAt is the fraction of a budget at which a warning is shown.
+const DefaultBudgetWarnAt = 0.8
+
+// BudgetConfig limits token usage per workstream and per project.
+type BudgetConfig struct {
+	// Workstream is the default budget for each workstream. It can be
+	// overridden per workstream from the TUI.
+	Workstream claude.Budget `yaml:"workstream,omitempty"`
+
+	// Project limits the combined usage of all workstreams.
+	Project claude.Budget `yaml:"project,omitempty"`
+
+	// WarnAt is the fraction of a budget (0-1) at which a warning is shown.
+	// Default: 0.8
+	WarnAt float64 `yaml:"warn_at,omitempty"`
+}
+
+// GetWarnAt returns the warning threshold, defaulting to DefaultBudgetWarnAt.
+func (b *BudgetConfig) GetWarnAt() float64 {
+	if b.WarnAt <= 0 || b.WarnAt > 1 {
+		return DefaultBudgetWarnAt
+	}
+	return b.WarnAt
+}
+
+// mergeBudgetConfig merges override values into base.
+// Each limit is replaced only when set in override.
+func mergeBudgetConfig(base, override BudgetConfig) BudgetConfig {
+	result := base
+	if override.Workstream.CostUSD > 0 {
+		result.Workstream.CostUSD = override.Workstream.CostUSD
+	}
+	if override.Workstream.Tokens > 0 {
+		result.Workstream.Tokens = override.Workstream.Tokens
+	}
+	if override.Project.CostUSD > 0 {
+		result.Project.CostUSD = override.Project.CostUSD
+	}
+	if override.Project.Tokens > 0 {
+		result.Project.Tokens = override.Project.Tokens
+	}
+	if override.WarnAt > 0 {
+		result.WarnAt = override.WarnAt
+	}
+	return result
+}
+
+// SaveProjectBudget sets the project budget in the project's config file.
+// Only the budget's project entry is rewritten; comments, formatting of
+// other settings and keys this version doesn't know about are kept. A zero
+// budget removes the project limit from the project config (a global limit
+// still applies).
+func SaveProjectBudget(projectPath string, budget claude.Budget) error {
+	configDir := filepath.Join(projectPath, ".claude-cells")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	configPath := filepath.Join(configDir, "config.yaml")
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		// Don't replace a config we couldn't parse
+		return fmt.Errorf("cannot parse %s: %w", configPath, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("cannot update %s: not a mapping", configPath)
+	}
+
+	budgetNode := yamlMappingValue(root, "budget")
+	if budgetNode == nil || budgetNode.Tag == "!!null" {
+		if budget.IsZero() {
+			return nil // No project limit to remove
+		}
+		budgetNode = &yaml.Node{Kind: yaml.MappingNode}
+		yamlSetMappingValue(root, "budget", budgetNode)
+	}
+	if budgetNode.Kind != yaml.MappingNode {
+		return fmt.Errorf("cannot update %s: budget is not a mapping", configPath)
+	}
+	if budget.IsZero() {
+		yamlDeleteMappingKey(budgetNode, "project")
+	} else {
+		var project yaml.Node
+		if err := project.Encode(budget); err != nil {
+			return fmt.Errorf("failed to encode budget: %w", err)
+		}
+		yamlSetMappingValue(budgetNode, "project", &project)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.WriteFile(configPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// yamlMappingValue returns the value of key in a YAML mapping node, or nil.
+func yamlMappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlSetMappingValue sets key in a YAML mapping node, replacing an existing
+// value in place (keeping its comments) or appending the key.
+func yamlSetMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			old := mapping.Content[i+1]
+			value.HeadComment, value.LineComment, value.FootComment = old.HeadComment, old.LineComment, old.FootComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// yamlDeleteMappingKey removes key and its value from a YAML mapping node.
+func yamlDeleteMappingKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
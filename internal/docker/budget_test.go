@@ -0,0 +1,142 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/claude"
+)
+
+func TestLoadConfig_BudgetMerge(t *testing.T) {
+	globalDir := t.TempDir()
+	SetTestCellsDir(globalDir)
+	defer SetTestCellsDir("")
+
+	globalContent := `budget:
+  workstream:
+    cost: 5
+    tokens: 2000000
+  warn_at: 0.9
+`
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalContent), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, ".claude-cells"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	projectContent := `budget:
+  workstream:
+    cost: 10
+  project:
+    cost: 50
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ".claude-cells", "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir)
+	want := BudgetConfig{
+		Workstream: claude.Budget{CostUSD: 10, Tokens: 2000000},
+		Project:    claude.Budget{CostUSD: 50},
+		WarnAt:     0.9,
+	}
+	if cfg.Budget != want {
+		t.Errorf("Budget = %+v, want %+v", cfg.Budget, want)
+	}
+	if got := cfg.Budget.GetWarnAt(); got != 0.9 {
+		t.Errorf("GetWarnAt() = %v, want 0.9", got)
+	}
+	if got := (&BudgetConfig{}).GetWarnAt(); got != DefaultBudgetWarnAt {
+		t.Errorf("default GetWarnAt() = %v, want %v", got, DefaultBudgetWarnAt)
+	}
+}
+
+func TestSaveProjectBudget(t *testing.T) {
+	SetTestCellsDir(t.TempDir())
+	defer SetTestCellsDir("")
+
+	projectDir := t.TempDir()
+	configDir := filepath.Join(projectDir, ".claude-cells")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte("runtime: claudesp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SaveProjectBudget(projectDir, claude.Budget{CostUSD: 25}); err != nil {
+		t.Fatalf("SaveProjectBudget() error = %v", err)
+	}
+	cfg := LoadConfig(projectDir)
+	if cfg.Budget.Project.CostUSD != 25 {
+		t.Errorf("Project budget = %+v, want $25", cfg.Budget.Project)
+	}
+	if cfg.Runtime != "claudesp" {
+		t.Errorf("Runtime = %q, other settings should be preserved", cfg.Runtime)
+	}
+
+	// Unparseable configs are left alone
+	bad := filepath.Join(configDir, "config.yaml")
+	if err := os.WriteFile(bad, []byte("runtime: [unclosed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveProjectBudget(projectDir, claude.Budget{CostUSD: 30}); err == nil {
+		t.Error("expected error for unparseable config")
+	}
+	data, _ := os.ReadFile(bad)
+	if !strings.Contains(string(data), "[unclosed") {
+		t.Error("unparseable config should not be overwritten")
+	}
+}
+
+func TestSaveProjectBudget_KeepsCommentsAndUnknownKeys(t *testing.T) {
+	SetTestCellsDir(t.TempDir())
+	defer SetTestCellsDir("")
+
+	projectDir := t.TempDir()
+	configDir := filepath.Join(projectDir, ".claude-cells")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(configDir, "config.yaml")
+	original := `# Team settings
+runtime: claudesp # keep the sandboxed runtime
+future_setting:
+  enabled: true
+budget:
+  # Per-workstream default
+  workstream:
+    cost: 5
+  project:
+    cost: 10
+`
+	if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SaveProjectBudget(projectDir, claude.Budget{CostUSD: 25, Tokens: 1000000}); err != nil {
+		t.Fatalf("SaveProjectBudget() error = %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	for _, want := range []string{"# Team settings", "# keep the sandboxed runtime", "future_setting:\n  enabled: true", "# Per-workstream default", "cost: 25", "tokens: 1000000"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config missing %q after saving:\n%s", want, data)
+		}
+	}
+	if cfg := LoadConfig(projectDir); cfg.Budget.Workstream.CostUSD != 5 || cfg.Budget.Project.CostUSD != 25 {
+		t.Errorf("Budget = %+v, want workstream $5 and project $25", cfg.Budget)
+	}
+
+	// A zero budget removes only the project limit
+	if err := SaveProjectBudget(projectDir, claude.Budget{}); err != nil {
+		t.Fatalf("SaveProjectBudget() error = %v", err)
+	}
+	data, _ = os.ReadFile(configPath)
+	if strings.Contains(string(data), "project:") || !strings.Contains(string(data), "cost: 5") || !strings.Contains(string(data), "future_setting:") {
+		t.Errorf("removing the project budget should keep everything else:\n%s", data)
+	}
+}
@@ -0,0 +1,86 @@
+package docker
+
+import "time"
+
+// DefaultCheckpointInterval is how often worktrees are checkpointed by default.
+const DefaultCheckpointInterval = 10 * time.Minute
+
+// DefaultCheckpointKeep is the default number of checkpoints kept per branch.
+const DefaultCheckpointKeep = 20
+
+// DefaultCheckpointMaxAge is the default age after which checkpoints are pruned.
+const DefaultCheckpointMaxAge = 7 * 24 * time.Hour
+
+// CheckpointConfig controls periodic WIP checkpoints: snapshots of each
+// worktree's uncommitted work stored under refs/ccells/checkpoints.
+type CheckpointConfig struct {
+	// Enabled turns checkpoints on or off. Default: on
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// Interval between checkpoints, as a Go duration (e.g. "5m").
+	// Default: 10m
+	Interval string `yaml:"interval,omitempty"`
+
+	// Keep is the number of checkpoints kept per branch. Default: 20
+	Keep int `yaml:"keep,omitempty"`
+
+	// MaxAge prunes checkpoints older than this Go duration (e.g. "72h").
+	// Default: 168h
+	MaxAge string `yaml:"max_age,omitempty"`
+}
+
+// IsEnabled reports whether checkpoints are taken.
+func (c *CheckpointConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// GetInterval returns the parsed interval, falling back to
+// DefaultCheckpointInterval when unset or invalid.
+func (c *CheckpointConfig) GetInterval() time.Duration {
+	return parsePositiveDuration(c.Interval, DefaultCheckpointInterval)
+}
+
+// GetKeep returns the checkpoints kept per branch, defaulting to DefaultCheckpointKeep.
+func (c *CheckpointConfig) GetKeep() int {
+	if c.Keep <= 0 {
+		return DefaultCheckpointKeep
+	}
+	return c.Keep
+}
+
+// GetMaxAge returns the parsed maximum age, falling back to
+// DefaultCheckpointMaxAge when unset or invalid.
+func (c *CheckpointConfig) GetMaxAge() time.Duration {
+	return parsePositiveDuration(c.MaxAge, DefaultCheckpointMaxAge)
+}
+
+// parsePositiveDuration parses a Go duration, returning def for empty,
+// invalid or non-positive values.
+func parsePositiveDuration(s string, def time.Duration) time.Duration {
+	if s == "" {
+		return def
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
+// mergeCheckpointConfig merges override values into base.
+func mergeCheckpointConfig(base, override CheckpointConfig) CheckpointConfig {
+	result := base
+	if override.Enabled != nil {
+		result.Enabled = override.Enabled
+	}
+	if override.Interval != "" {
+		result.Interval = override.Interval
+	}
+	if override.Keep > 0 {
+		result.Keep = override.Keep
+	}
+	if override.MaxAge != "" {
+		result.MaxAge = override.MaxAge
+	}
+	return result
+}
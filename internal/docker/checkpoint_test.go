@@ -0,0 +1,54 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig_CheckpointMerge(t *testing.T) {
+	globalDir := t.TempDir()
+	SetTestCellsDir(globalDir)
+	defer SetTestCellsDir("")
+
+	globalContent := `checkpoints:
+  interval: 5m
+  keep: 50
+`
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalContent), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, ".claude-cells"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	projectContent := `checkpoints:
+  max_age: 72h
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ".claude-cells", "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir).Checkpoint
+	if !cfg.IsEnabled() || cfg.GetInterval() != 5*time.Minute || cfg.GetKeep() != 50 || cfg.GetMaxAge() != 72*time.Hour {
+		t.Errorf("Checkpoint = enabled %v, every %v, keep %d, max age %v; want true, 5m, 50, 72h", cfg.IsEnabled(), cfg.GetInterval(), cfg.GetKeep(), cfg.GetMaxAge())
+	}
+
+	// A project can turn checkpoints off
+	projectContent = `checkpoints:
+  enabled: false
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ".claude-cells", "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg = LoadConfig(projectDir).Checkpoint; cfg.IsEnabled() {
+		t.Error("the project config should disable checkpoints")
+	}
+
+	defaults := CheckpointConfig{Interval: "soon"}
+	if !defaults.IsEnabled() || defaults.GetInterval() != DefaultCheckpointInterval || defaults.GetKeep() != DefaultCheckpointKeep || defaults.GetMaxAge() != DefaultCheckpointMaxAge {
+		t.Errorf("defaults = %+v", defaults)
+	}
+}
@@ -0,0 +1,60 @@
+package docker
+
+// DefaultCIFixMaxAttempts is the default number of times Claude is asked to
+// fix a failing CI run before giving up.
+const DefaultCIFixMaxAttempts = 3
+
+// DefaultCIFixLogLines is the default number of log lines sent per failing check.
+const DefaultCIFixLogLines = 80
+
+// CIFixConfig enables the CI auto-fix loop. When a workstream's PR checks
+// fail, the failing checks and their log excerpts are sent to its Claude
+// with instructions to fix and push.
+type CIFixConfig struct {
+	// Enabled turns the auto-fix loop on. Default: off
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// MaxAttempts limits the fix attempts per workstream until CI passes again.
+	// Default: 3
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+
+	// LogLines is the number of log lines sent per failing check (the end of
+	// the failed steps' output). Default: 80
+	LogLines int `yaml:"log_lines,omitempty"`
+}
+
+// IsEnabled reports whether the auto-fix loop is on.
+func (c *CIFixConfig) IsEnabled() bool {
+	return c.Enabled != nil && *c.Enabled
+}
+
+// GetMaxAttempts returns the attempt limit, defaulting to DefaultCIFixMaxAttempts.
+func (c *CIFixConfig) GetMaxAttempts() int {
+	if c.MaxAttempts <= 0 {
+		return DefaultCIFixMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+// GetLogLines returns the log excerpt length, defaulting to DefaultCIFixLogLines.
+func (c *CIFixConfig) GetLogLines() int {
+	if c.LogLines <= 0 {
+		return DefaultCIFixLogLines
+	}
+	return c.LogLines
+}
+
+// mergeCIFixConfig merges override values into base.
+func mergeCIFixConfig(base, override CIFixConfig) CIFixConfig {
+	result := base
+	if override.Enabled != nil {
+		result.Enabled = override.Enabled
+	}
+	if override.MaxAttempts > 0 {
+		result.MaxAttempts = override.MaxAttempts
+	}
+	if override.LogLines > 0 {
+		result.LogLines = override.LogLines
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig_CIFixMerge(t *testing.T) {
+	globalDir := t.TempDir()
+	SetTestCellsDir(globalDir)
+	defer SetTestCellsDir("")
+
+	globalContent := `ci_fix:
+  enabled: true
+  max_attempts: 5
+`
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalContent), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, ".claude-cells"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	projectContent := `ci_fix:
+  log_lines: 20
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ".claude-cells", "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir).CIFix
+	if !cfg.IsEnabled() || cfg.GetMaxAttempts() != 5 || cfg.GetLogLines() != 20 {
+		t.Errorf("CIFix = enabled %v, %d attempts, %d lines; want true, 5, 20", cfg.IsEnabled(), cfg.GetMaxAttempts(), cfg.GetLogLines())
+	}
+
+	// A project can turn off a globally enabled loop
+	projectContent = `ci_fix:
+  enabled: false
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ".claude-cells", "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg = LoadConfig(projectDir).CIFix
+	if cfg.IsEnabled() {
+		t.Error("the project config should disable the loop")
+	}
+
+	defaults := CIFixConfig{}
+	if defaults.IsEnabled() || defaults.GetMaxAttempts() != DefaultCIFixMaxAttempts || defaults.GetLogLines() != DefaultCIFixLogLines {
+		t.Errorf("defaults = %+v", defaults)
+	}
+}
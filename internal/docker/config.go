@@ -138,8 +138,9 @@ Then inform the user:
 `
 
 // GetCCellsInstructions returns the CLAUDE.md content for ccells containers.
-// It includes runtime-specific context.
-func GetCCellsInstructions(runtime string) string {
+// It includes runtime-specific context and, for sparse worktrees, the
+// directories that are checked out.
+func GetCCellsInstructions(runtime string, sparsePaths []string) string {
 	runtimeInfo := ""
 	if runtime == "claudesp" {
 		runtimeInfo = `
@@ -157,24 +158,38 @@ See https://github.com/mikekelly/claude-sneakpeek for details.
 		runtimeInfo = fmt.Sprintf("\n**Runtime:** %s\n\n", runtime)
 	}
 
+	sparseInfo := ""
+	if len(sparsePaths) > 0 {
+		sparseInfo = `
+## Sparse Checkout
+
+This worktree is a cone-mode sparse checkout. Only these directories (and the files at the repository root) are checked out:
+
+- ` + "`" + strings.Join(sparsePaths, "`\n- `") + "`" + `
+
+Other directories exist in the repository but not on disk - don't recreate or "fix" missing files outside these paths. If the task needs another directory, ask the user to widen the checkout (they can press **S** in ccells).
+`
+	}
+
 	return `# Claude Cells Session
 
 ` + runtimeInfo + `You are in an isolated container with a dedicated git worktree. **Commit your work** - this is the most important thing. A dirty worktree means lost work.
 
 ## Git Operations
 
-You can use git and gh commands for remote operations - they're proxied through the host:
+You can use git, gh and glab commands for remote operations - they're proxied through the host:
 
 - ` + "`git fetch`" + `, ` + "`git pull`" + `, ` + "`git push`" + ` - all work normally
 - ` + "`gh pr create`" + `, ` + "`gh pr view`" + `, ` + "`gh pr merge`" + ` - for PR management
+- ` + "`glab mr create`" + `, ` + "`glab mr view`" + `, ` + "`glab mr merge`" + ` - for merge requests on GitLab
 
 **Restrictions** (enforced by the proxy):
 - You can only push to your assigned branch
-- ` + "`gh pr merge`" + ` only works on your own PR
+- ` + "`gh pr merge`" + ` and ` + "`glab mr merge`" + ` only work on your own PR/MR
 - No branch switching - you're locked to this worktree's branch
 
 **Rebasing**: Run ` + "`git rebase main`" + ` (uses local ref). You can ` + "`git fetch`" + ` first if needed.
-
+` + sparseInfo + `
 ## Committing
 
 **ALWAYS use ` + "`/ccells-commit`" + ` when committing.** This skill handles the commit workflow correctly. Never use raw ` + "`git commit`" + ` commands - a hook will block them.
@@ -194,7 +209,8 @@ The user is running multiple containers in parallel and relies on Status to tria
 // Each container gets its own copy to prevent race conditions when multiple
 // Claude Code instances modify credentials simultaneously.
 // runtime specifies which Claude runtime to use: "claude" (default) or "claudesp" (experimental).
-func CreateContainerConfig(containerName string, runtime string) (*ConfigPaths, error) {
+// sparsePaths lists the directories of a sparse worktree, for CLAUDE.md.
+func CreateContainerConfig(containerName string, runtime string, sparsePaths []string) (*ConfigPaths, error) {
 	configMutex.Lock()
 	defer configMutex.Unlock()
 
@@ -270,7 +286,7 @@ func CreateContainerConfig(containerName string, runtime string) (*ConfigPaths,
 		return nil, fmt.Errorf("failed to create .claude directory: %w", err)
 	}
 	claudeMdPath := filepath.Join(dstClaudeDir, "CLAUDE.md")
-	if err := os.WriteFile(claudeMdPath, []byte(GetCCellsInstructions(runtime)), 0644); err != nil {
+	if err := os.WriteFile(claudeMdPath, []byte(GetCCellsInstructions(runtime, sparsePaths)), 0644); err != nil {
 		return nil, fmt.Errorf("failed to write CLAUDE.md: %w", err)
 	}
 
@@ -348,6 +364,53 @@ func CleanupContainerConfig(containerName string) error {
 	return removeAllSafe(containerConfigDir)
 }
 
+// UpdateContainerInstructions rewrites the CLAUDE.md of an existing container
+// config, e.g. after its sparse checkout was widened. Claude reads the new
+// instructions at its next session start.
+func UpdateContainerInstructions(containerName, runtime string, sparsePaths []string) error {
+	cellsDir, err := GetCellsDir()
+	if err != nil {
+		return err
+	}
+	claudeDir := filepath.Join(cellsDir, "containers", containerName, ClaudeDir)
+	if _, err := os.Stat(claudeDir); err != nil {
+		return fmt.Errorf("no config for container %s: %w", containerName, err)
+	}
+	return os.WriteFile(filepath.Join(claudeDir, "CLAUDE.md"), []byte(GetCCellsInstructions(runtime, sparsePaths)), 0644)
+}
+
+// containerSessionDir returns where Claude keeps sessions for /workspace
+// inside a container config directory.
+func containerSessionDir(configDir string) string {
+	return filepath.Join(configDir, ClaudeDir, "projects", "-workspace")
+}
+
+// ContainerSessionDir returns the host directory holding a container's
+// Claude session files (<session-id>.jsonl).
+func ContainerSessionDir(containerName string) (string, error) {
+	cellsDir, err := GetCellsDir()
+	if err != nil {
+		return "", err
+	}
+	return containerSessionDir(filepath.Join(cellsDir, "containers", containerName)), nil
+}
+
+// ArchiveSessionData copies a container's Claude session data to dst so the
+// session can be resumed after the container and its config are gone.
+func ArchiveSessionData(containerName, dst string) error {
+	src, err := ContainerSessionDir(containerName)
+	if err != nil {
+		return err
+	}
+	return copyDir(src, dst)
+}
+
+// RestoreSessionData copies archived session data into a container config
+// directory (as returned by CreateContainerConfig's parent dir).
+func RestoreSessionData(src, configDir string) error {
+	return copyDir(src, containerSessionDir(configDir))
+}
+
 // CleanupOrphanedContainerConfigs removes config directories for containers
 // that no longer exist. Returns the number of configs cleaned up.
 func CleanupOrphanedContainerConfigs(existingContainerNames map[string]bool) (int, error) {
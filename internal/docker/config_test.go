@@ -64,7 +64,7 @@ func TestCreateContainerConfig(t *testing.T) {
 			}
 
 			// Create container config
-			cfg, err := CreateContainerConfig("test-container", tt.runtime)
+			cfg, err := CreateContainerConfig("test-container", tt.runtime, nil)
 
 			// Check error expectation
 			if (err != nil) != tt.wantErr {
@@ -385,3 +385,33 @@ func TestGitIdentityStruct(t *testing.T) {
 		t.Errorf("Email = %q, want %q", identity.Email, "test@example.com")
 	}
 }
+
+func TestGetCCellsInstructions_SparsePaths(t *testing.T) {
+	if full := GetCCellsInstructions("claude", nil); strings.Contains(full, "Sparse Checkout") {
+		t.Error("a full checkout should not mention sparse checkout")
+	}
+	sparse := GetCCellsInstructions("claude", []string{"services/api", "libs/core"})
+	if !strings.Contains(sparse, "## Sparse Checkout") || !strings.Contains(sparse, "- `services/api`\n- `libs/core`") {
+		t.Errorf("instructions should list the sparse paths:\n%s", sparse)
+	}
+}
+
+func TestUpdateContainerInstructions(t *testing.T) {
+	cellsDir := t.TempDir()
+	SetTestCellsDir(cellsDir)
+	defer SetTestCellsDir("")
+
+	if err := UpdateContainerInstructions("missing", "claude", nil); err == nil {
+		t.Error("a container without config should fail")
+	}
+	claudeDir := filepath.Join(cellsDir, "containers", "ccells-app-feature", ClaudeDir)
+	if err := os.MkdirAll(claudeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateContainerInstructions("ccells-app-feature", "claude", []string{"web"}); err != nil {
+		t.Fatalf("UpdateContainerInstructions() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(claudeDir, "CLAUDE.md")); !strings.Contains(string(data), "- `web`") {
+		t.Errorf("CLAUDE.md = %q, want the sparse path listed", data)
+	}
+}
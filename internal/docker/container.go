@@ -442,6 +442,52 @@ func (c *Client) ExecInContainer(ctx context.Context, containerID string, cmd []
 	return stdout.String() + stderr.String(), nil
 }
 
+// ExecInContainerStream runs a command in a container, streaming combined
+// stdout/stderr to output as it is produced. Returns the command's exit code.
+func (c *Client) ExecInContainerStream(ctx context.Context, containerID string, cmd []string, output io.Writer) (int, error) {
+	execCfg := container.ExecOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+
+	execID, err := c.cli.ContainerExecCreate(ctx, containerID, execCfg)
+	if err != nil {
+		return -1, err
+	}
+
+	resp, err := c.cli.ContainerExecAttach(ctx, execID.ID, container.ExecStartOptions{})
+	if err != nil {
+		return -1, err
+	}
+	defer resp.Close()
+
+	// Close the stream when the context is cancelled so StdCopy unblocks
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			resp.Close()
+		case <-done:
+		}
+	}()
+
+	_, err = stdcopy.StdCopy(output, output, resp.Reader)
+	if ctx.Err() != nil {
+		return -1, ctx.Err()
+	}
+	if err != nil && err != io.EOF {
+		return -1, err
+	}
+
+	inspect, err := c.cli.ContainerExecInspect(ctx, execID.ID)
+	if err != nil {
+		return -1, err
+	}
+	return inspect.ExitCode, nil
+}
+
 // ContainerInfo holds basic info about a container
 type ContainerInfo struct {
 	ID      string
@@ -0,0 +1,30 @@
+package docker
+
+import "slices"
+
+// ForgeConfig configures which forge (GitHub or GitLab) pull requests are
+// created on. By default it is detected from the origin remote's host.
+type ForgeConfig struct {
+	// Type forces the forge: "github" or "gitlab". Default: detected
+	Type string `yaml:"type,omitempty"`
+
+	// GitLabHosts lists self-hosted GitLab instances whose host names don't
+	// contain "gitlab", e.g. "code.example.com".
+	GitLabHosts []string `yaml:"gitlab_hosts,omitempty"`
+}
+
+// mergeForgeConfig merges override forge settings into base.
+// GitLabHosts lists from both are combined.
+func mergeForgeConfig(base, override ForgeConfig) ForgeConfig {
+	result := base
+	result.GitLabHosts = slices.Clone(base.GitLabHosts)
+	if override.Type != "" {
+		result.Type = override.Type
+	}
+	for _, host := range override.GitLabHosts {
+		if !slices.Contains(result.GitLabHosts, host) {
+			result.GitLabHosts = append(result.GitLabHosts, host)
+		}
+	}
+	return result
+}
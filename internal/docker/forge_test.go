@@ -0,0 +1,41 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfig_ForgeMerge(t *testing.T) {
+	globalDir := t.TempDir()
+	SetTestCellsDir(globalDir)
+	defer SetTestCellsDir("")
+
+	globalContent := `forge:
+  gitlab_hosts: [code.example.com]
+`
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalContent), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, ".claude-cells"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	projectContent := `forge:
+  type: gitlab
+  gitlab_hosts: [git.internal, code.example.com]
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ".claude-cells", "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir).Forge
+	if cfg.Type != "gitlab" {
+		t.Errorf("Type = %q, want the project's gitlab", cfg.Type)
+	}
+	if want := []string{"code.example.com", "git.internal"}; !reflect.DeepEqual(cfg.GitLabHosts, want) {
+		t.Errorf("GitLabHosts = %v, want %v", cfg.GitLabHosts, want)
+	}
+}
@@ -1,6 +1,9 @@
 package docker
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 // DockerClient defines the interface for Docker operations.
 // This allows for easy mocking in tests.
@@ -21,6 +24,7 @@ type DockerClient interface {
 	GetContainerState(ctx context.Context, containerID string) (string, error)
 	IsContainerRunning(ctx context.Context, containerID string) (bool, error)
 	ExecInContainer(ctx context.Context, containerID string, cmd []string) (string, error)
+	ExecInContainerStream(ctx context.Context, containerID string, cmd []string, output io.Writer) (int, error)
 	SignalProcess(ctx context.Context, containerID, processName, signal string) error
 	PersistSessions(ctx context.Context, containerID string) error
 
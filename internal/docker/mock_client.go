@@ -3,6 +3,7 @@ package docker
 import (
 	"context"
 	"fmt"
+	"io"
 	"sync"
 )
 
@@ -15,6 +16,7 @@ type MockClient struct {
 	PingErr           error
 	CreateContainerFn func(ctx context.Context, cfg *ContainerConfig) (string, error)
 	ImageExistsFn     func(ctx context.Context, imageName string) (bool, error)
+	ExecStreamFn      func(ctx context.Context, containerID string, cmd []string, output io.Writer) (int, error)
 }
 
 type mockContainer struct {
@@ -163,6 +165,22 @@ func (m *MockClient) ExecInContainer(ctx context.Context, containerID string, cm
 	return "mock output", nil
 }
 
+func (m *MockClient) ExecInContainerStream(ctx context.Context, containerID string, cmd []string, output io.Writer) (int, error) {
+	m.mu.Lock()
+	_, ok := m.containers[containerID]
+	fn := m.ExecStreamFn
+	m.mu.Unlock()
+
+	if !ok {
+		return -1, &containerNotFoundError{containerID}
+	}
+	if fn != nil {
+		return fn(ctx, containerID, cmd, output)
+	}
+	_, err := io.WriteString(output, "mock output\n")
+	return 0, err
+}
+
 func (m *MockClient) SignalProcess(ctx context.Context, containerID, processName, signal string) error {
 	return nil
 }
@@ -0,0 +1,57 @@
+package docker
+
+// PRConfig sets defaults for pull requests created from the TUI. All of
+// them can be changed in the PR preview dialog.
+type PRConfig struct {
+	// Template preselects a PR template by file name, e.g. "feature.md".
+	// Default: the repository's pull_request_template.md, else the first
+	// template in PULL_REQUEST_TEMPLATE/
+	Template string `yaml:"template,omitempty"`
+
+	// Base is the branch to merge into. Default: the repository's default branch
+	Base string `yaml:"base,omitempty"`
+
+	// Reviewers, Labels and Assignees are added to every PR.
+	Reviewers []string `yaml:"reviewers,omitempty"`
+	Labels    []string `yaml:"labels,omitempty"`
+	Assignees []string `yaml:"assignees,omitempty"`
+
+	// Milestone is the name of the milestone to add PRs to.
+	Milestone string `yaml:"milestone,omitempty"`
+
+	// Draft creates PRs as drafts. Default: false
+	Draft *bool `yaml:"draft,omitempty"`
+}
+
+// IsDraft reports whether PRs are created as drafts by default.
+func (p *PRConfig) IsDraft() bool {
+	return p.Draft != nil && *p.Draft
+}
+
+// mergePRConfig merges override PR defaults into base.
+// Each list is replaced as a whole when set in override.
+func mergePRConfig(base, override PRConfig) PRConfig {
+	result := base
+	if override.Template != "" {
+		result.Template = override.Template
+	}
+	if override.Base != "" {
+		result.Base = override.Base
+	}
+	if len(override.Reviewers) > 0 {
+		result.Reviewers = override.Reviewers
+	}
+	if len(override.Labels) > 0 {
+		result.Labels = override.Labels
+	}
+	if len(override.Assignees) > 0 {
+		result.Assignees = override.Assignees
+	}
+	if override.Milestone != "" {
+		result.Milestone = override.Milestone
+	}
+	if override.Draft != nil {
+		result.Draft = override.Draft
+	}
+	return result
+}
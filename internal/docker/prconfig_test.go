@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfig_PRMerge(t *testing.T) {
+	globalDir := t.TempDir()
+	SetTestCellsDir(globalDir)
+	defer SetTestCellsDir("")
+
+	globalContent := `pr:
+  reviewers: [alice]
+  assignees: ["@me"]
+  draft: true
+`
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalContent), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, ".claude-cells"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	projectContent := `pr:
+  template: feature.md
+  base: develop
+  reviewers: [org/backend]
+  labels: [needs-review]
+  draft: false
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ".claude-cells", "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir).PR
+	if cfg.Template != "feature.md" || cfg.Base != "develop" || cfg.IsDraft() {
+		t.Errorf("PR = %+v, want the project's template, base and draft setting", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Reviewers, []string{"org/backend"}) || !reflect.DeepEqual(cfg.Labels, []string{"needs-review"}) {
+		t.Errorf("reviewers %v, labels %v: want the project lists", cfg.Reviewers, cfg.Labels)
+	}
+	if !reflect.DeepEqual(cfg.Assignees, []string{"@me"}) {
+		t.Errorf("assignees = %v, want the global list", cfg.Assignees)
+	}
+}
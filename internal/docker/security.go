@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/STRML/claude-cells/internal/claude"
 	"gopkg.in/yaml.v3"
 )
 
@@ -97,17 +99,219 @@ type DockerfileConfig struct {
 	Inject []string `yaml:"inject,omitempty"`
 }
 
+// DefaultHookTimeout is the default time limit for a single lifecycle hook.
+const DefaultHookTimeout = 30 * time.Second
+
+// HooksConfig defines user commands run on the host when workstreams change state.
+// Each command is run with "sh -c" and receives a JSON payload on stdin.
+// Hooks are only honoured in the global config; project configs can't set them.
+type HooksConfig struct {
+	// Timeout limits each hook command (Go duration, e.g. "30s").
+	// Default: 30s
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// Create runs after a new workstream's container has started.
+	Create []string `yaml:"create,omitempty"`
+
+	// Idle runs when Claude's session in a workstream ends.
+	Idle []string `yaml:"idle,omitempty"`
+
+	// Push runs after a branch is pushed (from the TUI or through the git proxy).
+	Push []string `yaml:"push,omitempty"`
+
+	// PROpened runs after a pull request is created for a workstream.
+	PROpened []string `yaml:"pr_opened,omitempty"`
+
+	// Merge runs after a workstream's branch is merged (locally or via GitHub).
+	Merge []string `yaml:"merge,omitempty"`
+
+	// Destroy runs when a workstream is destroyed.
+	Destroy []string `yaml:"destroy,omitempty"`
+}
+
+// GetTimeout returns the parsed hook timeout, falling back to DefaultHookTimeout
+// when unset or invalid.
+func (h *HooksConfig) GetTimeout() time.Duration {
+	if h.Timeout == "" {
+		return DefaultHookTimeout
+	}
+	d, err := time.ParseDuration(h.Timeout)
+	if err != nil || d <= 0 {
+		return DefaultHookTimeout
+	}
+	return d
+}
+
+// mergeHooksConfig merges override hooks into base.
+// Command lists are replaced per event (not appended) when non-empty.
+func mergeHooksConfig(base, override HooksConfig) HooksConfig {
+	result := base
+	if override.Timeout != "" {
+		result.Timeout = override.Timeout
+	}
+	if len(override.Create) > 0 {
+		result.Create = override.Create
+	}
+	if len(override.Idle) > 0 {
+		result.Idle = override.Idle
+	}
+	if len(override.Push) > 0 {
+		result.Push = override.Push
+	}
+	if len(override.PROpened) > 0 {
+		result.PROpened = override.PROpened
+	}
+	if len(override.Merge) > 0 {
+		result.Merge = override.Merge
+	}
+	if len(override.Destroy) > 0 {
+		result.Destroy = override.Destroy
+	}
+	return result
+}
+
+// DefaultVerifyTimeout is the default time limit for the pre-merge verification command.
+const DefaultVerifyTimeout = 10 * time.Minute
+
+// VerifyConfig defines the pre-merge verification command.
+// When set, the command runs inside the workstream's container before any
+// merge, and a non-zero exit blocks the merge.
+type VerifyConfig struct {
+	// Command is run with "sh -c" in /workspace, e.g. "make test" or
+	// "npm run lint && npm test". Empty disables verification.
+	Command string `yaml:"command,omitempty"`
+
+	// Timeout limits the verification run (Go duration, e.g. "10m").
+	// Default: 10m
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// GetTimeout returns the parsed verification timeout, falling back to
+// DefaultVerifyTimeout when unset or invalid.
+func (v *VerifyConfig) GetTimeout() time.Duration {
+	if v.Timeout == "" {
+		return DefaultVerifyTimeout
+	}
+	d, err := time.ParseDuration(v.Timeout)
+	if err != nil || d <= 0 {
+		return DefaultVerifyTimeout
+	}
+	return d
+}
+
+// mergeVerifyConfig merges override verification settings into base.
+func mergeVerifyConfig(base, override VerifyConfig) VerifyConfig {
+	result := base
+	if override.Command != "" {
+		result.Command = override.Command
+	}
+	if override.Timeout != "" {
+		result.Timeout = override.Timeout
+	}
+	return result
+}
+
+// Provision link modes.
+const (
+	// ProvisionLinkSymlink symlinks shared paths into the worktree and mounts
+	// the originals read-only at the same host path so the links resolve in
+	// the container too.
+	ProvisionLinkSymlink = "symlink"
+	// ProvisionLinkMount bind-mounts shared paths read-only at their
+	// location under /workspace without touching the worktree.
+	ProvisionLinkMount = "mount"
+)
+
+// ProvisionConfig defines untracked files provisioned into every new worktree
+// without prompting. Patterns are filepath globs relative to the repo root.
+// Provisioning is also applied when a container is rebuilt.
+type ProvisionConfig struct {
+	// Copy lists globs copied into the worktree, e.g. ".env*" or
+	// "config/local.yml". Files that already exist in the worktree are left alone.
+	Copy []string `yaml:"copy,omitempty"`
+
+	// Link lists globs shared read-only instead of copied, e.g. large
+	// fixture directories.
+	Link []string `yaml:"link,omitempty"`
+
+	// LinkMode is "symlink" (default) or "mount".
+	LinkMode string `yaml:"link_mode,omitempty"`
+}
+
+// GetLinkMode returns the link mode, defaulting to ProvisionLinkSymlink.
+func (p *ProvisionConfig) GetLinkMode() string {
+	if p.LinkMode == ProvisionLinkMount {
+		return ProvisionLinkMount
+	}
+	return ProvisionLinkSymlink
+}
+
+// IsEmpty reports whether no provisioning is configured.
+func (p *ProvisionConfig) IsEmpty() bool {
+	return len(p.Copy) == 0 && len(p.Link) == 0
+}
+
+// Covers reports whether a repo-relative path is provisioned by a copy or
+// link pattern, either directly or because a parent directory matches.
+func (p *ProvisionConfig) Covers(relPath string) bool {
+	for _, pattern := range append(append([]string{}, p.Copy...), p.Link...) {
+		pattern = filepath.Clean(pattern)
+		for dir := filepath.Clean(relPath); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+			if ok, _ := filepath.Match(pattern, dir); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeProvisionConfig merges override provisioning settings into base.
+// Each list is replaced as a whole when set in override.
+func mergeProvisionConfig(base, override ProvisionConfig) ProvisionConfig {
+	result := base
+	if len(override.Copy) > 0 {
+		result.Copy = override.Copy
+	}
+	if len(override.Link) > 0 {
+		result.Link = override.Link
+	}
+	if override.LinkMode != "" {
+		result.LinkMode = override.LinkMode
+	}
+	return result
+}
+
 // CellsConfig is the top-level configuration file structure.
 type CellsConfig struct {
-	Runtime    string           `yaml:"runtime,omitempty"`
-	Security   SecurityConfig   `yaml:"security,omitempty"`
-	Dockerfile DockerfileConfig `yaml:"dockerfile,omitempty"`
+	Runtime    string            `yaml:"runtime,omitempty"`
+	Security   SecurityConfig    `yaml:"security,omitempty"`
+	Dockerfile DockerfileConfig  `yaml:"dockerfile,omitempty"`
+	Hooks      HooksConfig       `yaml:"hooks,omitempty"`
+	Verify     VerifyConfig      `yaml:"verify,omitempty"`
+	Provision  ProvisionConfig   `yaml:"provision,omitempty"`
+	Templates  []TemplateConfig  `yaml:"templates,omitempty"`
+	Pricing    claude.PriceTable `yaml:"pricing,omitempty"` // Per-model token prices for cost estimates
+	Budget     BudgetConfig      `yaml:"budget,omitempty"`
+	CIFix      CIFixConfig       `yaml:"ci_fix,omitempty"`
+	AutoRebase AutoRebaseConfig  `yaml:"auto_rebase,omitempty"`
+	Checkpoint CheckpointConfig  `yaml:"checkpoints,omitempty"`
+	PR         PRConfig          `yaml:"pr,omitempty"`
+	Forge      ForgeConfig       `yaml:"forge,omitempty"`
 }
 
 // Helper functions for pointer creation
 func boolPtr(b bool) *bool    { return &b }
 func int64Ptr(i int64) *int64 { return &i }
 
+// IsValidTier reports whether tier is one of the known security tiers.
+func IsValidTier(tier SecurityTier) bool {
+	switch tier {
+	case TierHardened, TierModerate, TierCompat:
+		return true
+	}
+	return false
+}
+
 // TierCapDrops returns the default capabilities to drop for each tier.
 func TierCapDrops(tier SecurityTier) []string {
 	switch tier {
@@ -169,6 +373,13 @@ func ConfigForTier(tier SecurityTier) SecurityConfig {
 // 2. Global config (~/.claude-cells/config.yaml)
 // 3. Hardened defaults (moderate tier)
 func LoadSecurityConfig(projectPath string) SecurityConfig {
+	return LoadSecurityConfigWithTier(projectPath, "")
+}
+
+// LoadSecurityConfigWithTier loads the security configuration like
+// LoadSecurityConfig, then replaces the tier unless tier is empty. The new
+// tier's capability drops only apply if cap_drop wasn't set explicitly.
+func LoadSecurityConfigWithTier(projectPath string, tier SecurityTier) SecurityConfig {
 	cfg := DefaultSecurityConfig()
 
 	// Load global config
@@ -184,6 +395,9 @@ func LoadSecurityConfig(projectPath string) SecurityConfig {
 			cfg = mergeSecurityConfig(cfg, projectCfg.Security)
 		}
 	}
+	if tier != "" {
+		cfg.Tier = tier
+	}
 
 	// Apply tier defaults if CapDrop wasn't explicitly set
 	if cfg.CapDrop == nil {
@@ -243,14 +457,18 @@ func normalizeRuntime(runtime string) string {
 	return "claude" // Default fallback
 }
 
-// LoadConfig loads and merges the full CellsConfig (runtime, security, dockerfile).
+// LoadConfig loads and merges the full CellsConfig (runtime, security, dockerfile, hooks, verify).
 // Order of precedence (highest to lowest):
 // 1. Project config (.claude-cells/config.yaml in projectPath)
 // 2. Global config (~/.claude-cells/config.yaml)
 // 3. Default (runtime="claude")
+//
+// Hooks are only read from the global config: they run on the host, so a
+// cloned repository must not be able to declare them.
 func LoadConfig(projectPath string) CellsConfig {
 	cfg := CellsConfig{
-		Runtime: "claude", // Default runtime
+		Runtime: "claude",             // Default runtime
+		Pricing: claude.DefaultPrices, // Merged with configured prices below
 	}
 
 	// Load global config
@@ -263,6 +481,17 @@ func LoadConfig(projectPath string) CellsConfig {
 		if len(globalCfg.Dockerfile.Inject) > 0 {
 			cfg.Dockerfile.Inject = globalCfg.Dockerfile.Inject
 		}
+		cfg.Hooks = mergeHooksConfig(cfg.Hooks, globalCfg.Hooks)
+		cfg.Verify = mergeVerifyConfig(cfg.Verify, globalCfg.Verify)
+		cfg.Provision = mergeProvisionConfig(cfg.Provision, globalCfg.Provision)
+		cfg.Templates = mergeTemplates(cfg.Templates, globalCfg.Templates)
+		cfg.Pricing = claude.MergePrices(cfg.Pricing, globalCfg.Pricing)
+		cfg.Budget = mergeBudgetConfig(cfg.Budget, globalCfg.Budget)
+		cfg.CIFix = mergeCIFixConfig(cfg.CIFix, globalCfg.CIFix)
+		cfg.AutoRebase = mergeAutoRebaseConfig(cfg.AutoRebase, globalCfg.AutoRebase)
+		cfg.Checkpoint = mergeCheckpointConfig(cfg.Checkpoint, globalCfg.Checkpoint)
+		cfg.PR = mergePRConfig(cfg.PR, globalCfg.PR)
+		cfg.Forge = mergeForgeConfig(cfg.Forge, globalCfg.Forge)
 	} else {
 		cfg.Security = DefaultSecurityConfig()
 	}
@@ -278,6 +507,16 @@ func LoadConfig(projectPath string) CellsConfig {
 			if len(projectCfg.Dockerfile.Inject) > 0 {
 				cfg.Dockerfile.Inject = projectCfg.Dockerfile.Inject
 			}
+			cfg.Verify = mergeVerifyConfig(cfg.Verify, projectCfg.Verify)
+			cfg.Provision = mergeProvisionConfig(cfg.Provision, projectCfg.Provision)
+			cfg.Templates = mergeTemplates(cfg.Templates, projectCfg.Templates)
+			cfg.Pricing = claude.MergePrices(cfg.Pricing, projectCfg.Pricing)
+			cfg.Budget = mergeBudgetConfig(cfg.Budget, projectCfg.Budget)
+			cfg.CIFix = mergeCIFixConfig(cfg.CIFix, projectCfg.CIFix)
+			cfg.AutoRebase = mergeAutoRebaseConfig(cfg.AutoRebase, projectCfg.AutoRebase)
+			cfg.Checkpoint = mergeCheckpointConfig(cfg.Checkpoint, projectCfg.Checkpoint)
+			cfg.PR = mergePRConfig(cfg.PR, projectCfg.PR)
+			cfg.Forge = mergeForgeConfig(cfg.Forge, projectCfg.Forge)
 		}
 	}
 
@@ -487,6 +726,53 @@ const DefaultConfigYAML = `# Claude Cells Configuration
 #     - "apt-get update && apt-get install -y vim"
 #     - "pip install ipython"
 
+# Lifecycle hooks - shell commands run on the host when workstreams change state.
+# Each command receives a JSON payload on stdin with the workstream ID, branch,
+# worktree path, PR URL and synopsis. Output appears in the log panel.
+# Events: create, idle, push, pr_opened, merge, destroy
+# hooks:
+#   timeout: 30s
+#   pr_opened:
+#     - "./scripts/notify-chat.sh"
+#   merge:
+#     - "jq -r .branch | xargs ./scripts/close-ticket.sh"
+
+# Pre-merge verification - run inside the workstream's container before merging.
+# A non-zero exit blocks the merge. Usually set in the project config.
+# verify:
+#   command: "make test"
+#   timeout: 10m
+
+# Untracked files provisioned into every new worktree without prompting.
+# Globs are relative to the repo root and also apply when a container is rebuilt.
+# "copy" entries are copied (existing worktree files are left alone); "link"
+# entries are shared read-only, either as symlinks (default) or bind mounts.
+# provision:
+#   copy:
+#     - ".env*"
+#     - "config/local.yml"
+#   link:
+#     - "fixtures/large"
+#   link_mode: symlink   # or "mount"
+
+# Workstream templates - presets picked in the new-workstream dialog ([Tab]).
+# Project templates replace global templates with the same name.
+# templates:
+#   - name: bugfix
+#     description: "Fix a reported bug with a regression test"
+#     prompt_prefix: "Fix this bug:"
+#     prompt_suffix: "Add a regression test that fails before the fix."
+#     branch_prefix: "bugfix/"
+#     base_branch: main
+#   - name: spike
+#     runtime: claudesp
+#     security_tier: compat
+#     cpus: 4
+#     memory: 8g
+#     env:
+#       SPIKE: "1"
+#     branch_prefix: "spike/"
+
 security:
   # Security tier controls the default capability drops.
   # Options:
@@ -3,7 +3,9 @@ package docker
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig_DefaultRuntime(t *testing.T) {
@@ -201,6 +203,28 @@ func TestMergeSecurityConfig(t *testing.T) {
 	}
 }
 
+func TestLoadSecurityConfigWithTier(t *testing.T) {
+	globalDir := t.TempDir()
+	SetTestCellsDir(globalDir)
+	defer SetTestCellsDir("")
+	project := t.TempDir()
+
+	// The tier's capability drops replace the configured tier's
+	cfg := LoadSecurityConfigWithTier(project, TierHardened)
+	if cfg.Tier != TierHardened || !reflect.DeepEqual(cfg.CapDrop, TierCapDrops(TierHardened)) {
+		t.Errorf("cfg = %s %v, want hardened with its capability drops", cfg.Tier, cfg.CapDrop)
+	}
+
+	// An explicit cap_drop is kept
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte("security:\n  cap_drop: [NET_RAW]\n"), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+	cfg = LoadSecurityConfigWithTier(project, TierHardened)
+	if cfg.Tier != TierHardened || !reflect.DeepEqual(cfg.CapDrop, []string{"NET_RAW"}) {
+		t.Errorf("cfg = %s %v, want hardened with the explicit cap_drop", cfg.Tier, cfg.CapDrop)
+	}
+}
+
 func TestLoadSecurityConfigWithFiles(t *testing.T) {
 	// Create temp directories for testing
 	tmpDir, err := os.MkdirTemp("", "ccells-security-test-*")
@@ -443,3 +467,184 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestLoadConfig_HooksGlobalOnly(t *testing.T) {
+	globalDir := t.TempDir()
+	globalContent := `hooks:
+  timeout: 10s
+  create:
+    - "echo global-create"
+  merge:
+    - "echo global-merge"
+`
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalContent), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+	SetTestCellsDir(globalDir)
+	defer SetTestCellsDir("")
+
+	projectDir := t.TempDir()
+	projectConfigDir := filepath.Join(projectDir, ".claude-cells")
+	if err := os.MkdirAll(projectConfigDir, 0755); err != nil {
+		t.Fatalf("Failed to create project config dir: %v", err)
+	}
+	projectContent := `hooks:
+  merge:
+    - "echo project-merge"
+`
+	if err := os.WriteFile(filepath.Join(projectConfigDir, "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir)
+
+	if len(cfg.Hooks.Create) != 1 || cfg.Hooks.Create[0] != "echo global-create" {
+		t.Errorf("Create hooks should come from global config, got %v", cfg.Hooks.Create)
+	}
+	if len(cfg.Hooks.Merge) != 1 || cfg.Hooks.Merge[0] != "echo global-merge" {
+		t.Errorf("Merge hooks should ignore the project config, got %v", cfg.Hooks.Merge)
+	}
+	if cfg.Hooks.GetTimeout() != 10*time.Second {
+		t.Errorf("Timeout should be 10s, got %v", cfg.Hooks.GetTimeout())
+	}
+}
+
+func TestHooksConfig_GetTimeout(t *testing.T) {
+	tests := []struct {
+		timeout string
+		want    time.Duration
+	}{
+		{"", DefaultHookTimeout},
+		{"5s", 5 * time.Second},
+		{"invalid", DefaultHookTimeout},
+		{"-1s", DefaultHookTimeout},
+	}
+	for _, tt := range tests {
+		h := HooksConfig{Timeout: tt.timeout}
+		if got := h.GetTimeout(); got != tt.want {
+			t.Errorf("GetTimeout(%q) = %v, want %v", tt.timeout, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig_VerifyFromProject(t *testing.T) {
+	SetTestCellsDir(t.TempDir())
+	defer SetTestCellsDir("")
+
+	projectDir := t.TempDir()
+	projectConfigDir := filepath.Join(projectDir, ".claude-cells")
+	if err := os.MkdirAll(projectConfigDir, 0755); err != nil {
+		t.Fatalf("Failed to create project config dir: %v", err)
+	}
+	content := `verify:
+  command: "npm run lint && npm test"
+  timeout: 2m
+`
+	if err := os.WriteFile(filepath.Join(projectConfigDir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir)
+	if cfg.Verify.Command != "npm run lint && npm test" {
+		t.Errorf("Verify.Command = %q", cfg.Verify.Command)
+	}
+	if cfg.Verify.GetTimeout() != 2*time.Minute {
+		t.Errorf("Verify timeout = %v, want 2m", cfg.Verify.GetTimeout())
+	}
+
+	empty := VerifyConfig{}
+	if empty.GetTimeout() != DefaultVerifyTimeout {
+		t.Errorf("default verify timeout = %v, want %v", empty.GetTimeout(), DefaultVerifyTimeout)
+	}
+}
+
+func TestLoadConfig_PricingFromProject(t *testing.T) {
+	SetTestCellsDir(t.TempDir())
+	defer SetTestCellsDir("")
+
+	projectDir := t.TempDir()
+	projectConfigDir := filepath.Join(projectDir, ".claude-cells")
+	if err := os.MkdirAll(projectConfigDir, 0755); err != nil {
+		t.Fatalf("Failed to create project config dir: %v", err)
+	}
+	content := `pricing:
+  opus:
+    input: 5
+    output: 25
+    cache_write: 6.25
+    cache_read: 0.5
+`
+	if err := os.WriteFile(filepath.Join(projectConfigDir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir)
+	if p := cfg.Pricing["opus"]; p.Input != 5 || p.Output != 25 || p.CacheWrite != 6.25 || p.CacheRead != 0.5 {
+		t.Errorf("Pricing[opus] = %+v, want project override", p)
+	}
+	if _, ok := cfg.Pricing["sonnet"]; !ok {
+		t.Error("default sonnet price should be kept")
+	}
+}
+
+func TestLoadConfig_ProvisionFromProject(t *testing.T) {
+	SetTestCellsDir(t.TempDir())
+	defer SetTestCellsDir("")
+
+	projectDir := t.TempDir()
+	projectConfigDir := filepath.Join(projectDir, ".claude-cells")
+	if err := os.MkdirAll(projectConfigDir, 0755); err != nil {
+		t.Fatalf("Failed to create project config dir: %v", err)
+	}
+	content := `provision:
+  copy:
+    - ".env*"
+    - "config/local.yml"
+  link:
+    - "fixtures/large"
+  link_mode: mount
+`
+	if err := os.WriteFile(filepath.Join(projectConfigDir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir)
+	if len(cfg.Provision.Copy) != 2 || cfg.Provision.Copy[0] != ".env*" {
+		t.Errorf("Provision.Copy = %v", cfg.Provision.Copy)
+	}
+	if len(cfg.Provision.Link) != 1 || cfg.Provision.Link[0] != "fixtures/large" {
+		t.Errorf("Provision.Link = %v", cfg.Provision.Link)
+	}
+	if cfg.Provision.GetLinkMode() != ProvisionLinkMount {
+		t.Errorf("link mode = %q, want %q", cfg.Provision.GetLinkMode(), ProvisionLinkMount)
+	}
+
+	empty := ProvisionConfig{}
+	if !empty.IsEmpty() || empty.GetLinkMode() != ProvisionLinkSymlink {
+		t.Error("empty provision config should be empty and default to symlink mode")
+	}
+}
+
+func TestProvisionConfig_Covers(t *testing.T) {
+	p := ProvisionConfig{
+		Copy: []string{".env*", "config/local.yml"},
+		Link: []string{"fixtures/large"},
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{".env", true},
+		{".env.local", true},
+		{"config/local.yml", true},
+		{"config/other.yml", false},
+		{"fixtures/large/data/big.json", true},
+		{"fixtures/small.json", false},
+		{"notes.txt", false},
+	}
+	for _, tt := range tests {
+		if got := p.Covers(tt.path); got != tt.want {
+			t.Errorf("Covers(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
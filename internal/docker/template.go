@@ -0,0 +1,136 @@
+package docker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TemplateConfig is a named workstream preset for a recurring task shape.
+// Every field is optional; unset fields fall back to the normal defaults.
+type TemplateConfig struct {
+	// Name identifies the template in the new-workstream dialog.
+	Name string `yaml:"name"`
+
+	// Description is shown next to the name in the picker.
+	Description string `yaml:"description,omitempty"`
+
+	// PromptPrefix and PromptSuffix wrap the user's prompt before it is
+	// sent to Claude. Title and branch name generation use the bare prompt.
+	PromptPrefix string `yaml:"prompt_prefix,omitempty"`
+	PromptSuffix string `yaml:"prompt_suffix,omitempty"`
+
+	// Runtime overrides the session runtime ("claude" or "claudesp").
+	Runtime string `yaml:"runtime,omitempty"`
+
+	// SecurityTier overrides the configured security tier.
+	SecurityTier SecurityTier `yaml:"security_tier,omitempty"`
+
+	// CPUs limits the container's CPUs (e.g. 4 or 0.5). Default: 2
+	CPUs float64 `yaml:"cpus,omitempty"`
+
+	// Memory limits the container's memory (e.g. "8g", "512m"). Default: 4g
+	Memory string `yaml:"memory,omitempty"`
+
+	// Env sets extra environment variables in the container.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// BaseBranch is the branch new workstream branches are created from.
+	// Default: the host repo's current HEAD.
+	BaseBranch string `yaml:"base_branch,omitempty"`
+
+	// BranchPrefix is prepended to generated branch names (e.g. "bugfix/").
+	BranchPrefix string `yaml:"branch_prefix,omitempty"`
+
+	// SparsePaths limits the worktree to these directories with a cone-mode
+	// sparse checkout (e.g. ["services/api", "libs/core"]). Default: full checkout
+	SparsePaths []string `yaml:"sparse_paths,omitempty"`
+}
+
+// ApplyPrompt wraps a prompt with the template's prefix and suffix.
+func (t *TemplateConfig) ApplyPrompt(prompt string) string {
+	var parts []string
+	if p := strings.TrimSpace(t.PromptPrefix); p != "" {
+		parts = append(parts, p)
+	}
+	parts = append(parts, prompt)
+	if s := strings.TrimSpace(t.PromptSuffix); s != "" {
+		parts = append(parts, s)
+	}
+	// Prompts are typed into Claude as a single line
+	return strings.Join(parts, " ")
+}
+
+// GetMemoryLimit returns the memory limit in bytes, or 0 if unset.
+func (t *TemplateConfig) GetMemoryLimit() (int64, error) {
+	if t.Memory == "" {
+		return 0, nil
+	}
+	return ParseMemoryLimit(t.Memory)
+}
+
+// ParseMemoryLimit parses a memory size like "512m", "4g" or "1073741824"
+// into bytes. Suffixes k, m and g (optionally followed by "b") are binary units.
+func ParseMemoryLimit(s string) (int64, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	v = strings.TrimSuffix(v, "b")
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(v, "k"):
+		multiplier = 1024
+	case strings.HasSuffix(v, "m"):
+		multiplier = 1024 * 1024
+	case strings.HasSuffix(v, "g"):
+		multiplier = 1024 * 1024 * 1024
+	}
+	if multiplier > 1 {
+		v = v[:len(v)-1]
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid memory limit %q", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// Template returns the template with the given name.
+func (c *CellsConfig) Template(name string) (TemplateConfig, bool) {
+	if name == "" {
+		return TemplateConfig{}, false
+	}
+	for _, t := range c.Templates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return TemplateConfig{}, false
+}
+
+// mergeTemplates merges override templates into base. A template in override
+// replaces the base template with the same name; new names are appended.
+// Templates without a name are ignored.
+func mergeTemplates(base, override []TemplateConfig) []TemplateConfig {
+	result := make([]TemplateConfig, 0, len(base)+len(override))
+	for _, t := range base {
+		if t.Name != "" {
+			result = append(result, t)
+		}
+	}
+	for _, t := range override {
+		if t.Name == "" {
+			continue
+		}
+		replaced := false
+		for i := range result {
+			if result[i].Name == t.Name {
+				result[i] = t
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			result = append(result, t)
+		}
+	}
+	return result
+}
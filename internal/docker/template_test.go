@@ -0,0 +1,135 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseMemoryLimit(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"1073741824", 1073741824, false},
+		{"512m", 512 * 1024 * 1024, false},
+		{"8g", 8 * 1024 * 1024 * 1024, false},
+		{"8GB", 8 * 1024 * 1024 * 1024, false},
+		{"64k", 64 * 1024, false},
+		{"1.5g", 1536 * 1024 * 1024, false},
+		{"", 0, true},
+		{"lots", 0, true},
+		{"-1g", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMemoryLimit(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMemoryLimit(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMemoryLimit(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateConfig_ApplyPrompt(t *testing.T) {
+	tmpl := TemplateConfig{PromptPrefix: "Fix this bug:", PromptSuffix: " Add a regression test. "}
+	got := tmpl.ApplyPrompt("login fails on Safari")
+	want := "Fix this bug: login fails on Safari Add a regression test."
+	if got != want {
+		t.Errorf("ApplyPrompt() = %q, want %q", got, want)
+	}
+
+	empty := TemplateConfig{}
+	if got := empty.ApplyPrompt("task"); got != "task" {
+		t.Errorf("ApplyPrompt() without prefix/suffix = %q, want %q", got, "task")
+	}
+}
+
+func TestMergeTemplates(t *testing.T) {
+	base := []TemplateConfig{
+		{Name: "bugfix", BranchPrefix: "bugfix/"},
+		{Name: "docs", BranchPrefix: "docs/"},
+	}
+	override := []TemplateConfig{
+		{Name: "bugfix", BranchPrefix: "fix/"},
+		{Name: "spike", BranchPrefix: "spike/"},
+		{BranchPrefix: "unnamed/"},
+	}
+
+	got := mergeTemplates(base, override)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 templates, got %d: %+v", len(got), got)
+	}
+	if got[0].Name != "bugfix" || got[0].BranchPrefix != "fix/" {
+		t.Errorf("project template should replace global one in place, got %+v", got[0])
+	}
+	if got[1].Name != "docs" || got[2].Name != "spike" {
+		t.Errorf("unexpected template order: %+v", got)
+	}
+}
+
+func TestLoadConfig_Templates(t *testing.T) {
+	globalDir := t.TempDir()
+	SetTestCellsDir(globalDir)
+	defer SetTestCellsDir("")
+
+	globalContent := `templates:
+  - name: docs
+    branch_prefix: docs/
+`
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte(globalContent), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+
+	projectDir := t.TempDir()
+	projectConfigDir := filepath.Join(projectDir, ".claude-cells")
+	if err := os.MkdirAll(projectConfigDir, 0755); err != nil {
+		t.Fatalf("Failed to create project config dir: %v", err)
+	}
+	projectContent := `templates:
+  - name: bugfix
+    description: Fix a reported bug
+    prompt_prefix: "Fix this bug:"
+    runtime: claudesp
+    security_tier: hardened
+    cpus: 4
+    memory: 8g
+    env:
+      LOG_LEVEL: debug
+    base_branch: develop
+    branch_prefix: bugfix/
+`
+	if err := os.WriteFile(filepath.Join(projectConfigDir, "config.yaml"), []byte(projectContent), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	cfg := LoadConfig(projectDir)
+	if len(cfg.Templates) != 2 || cfg.Templates[0].Name != "docs" || cfg.Templates[1].Name != "bugfix" {
+		t.Fatalf("Templates = %+v, want docs then bugfix", cfg.Templates)
+	}
+
+	tmpl, ok := cfg.Template("bugfix")
+	if !ok {
+		t.Fatal("bugfix template not found")
+	}
+	if tmpl.Runtime != "claudesp" || tmpl.SecurityTier != TierHardened || tmpl.CPUs != 4 {
+		t.Errorf("unexpected template: %+v", tmpl)
+	}
+	if tmpl.Env["LOG_LEVEL"] != "debug" || tmpl.BaseBranch != "develop" || tmpl.BranchPrefix != "bugfix/" {
+		t.Errorf("unexpected template: %+v", tmpl)
+	}
+	if mem, err := tmpl.GetMemoryLimit(); err != nil || mem != 8*1024*1024*1024 {
+		t.Errorf("GetMemoryLimit() = %d, %v", mem, err)
+	}
+
+	if _, ok := cfg.Template("missing"); ok {
+		t.Error("unknown template should not be found")
+	}
+	if _, ok := cfg.Template(""); ok {
+		t.Error("empty name should not match a template")
+	}
+}
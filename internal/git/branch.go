@@ -2,7 +2,9 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -22,8 +24,16 @@ func New(repoPath string) *Git {
 
 // run executes a git command and returns output.
 func (g *Git) run(ctx context.Context, args ...string) (string, error) {
+	return g.runEnv(ctx, nil, args...)
+}
+
+// runEnv executes a git command with extra environment variables.
+func (g *Git) runEnv(ctx context.Context, env []string, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = g.repoPath
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	out, err := cmd.CombinedOutput()
 	output := strings.TrimSpace(string(out))
 	if err != nil && output != "" {
@@ -437,12 +447,58 @@ func (g *Git) RebaseBranch(ctx context.Context, branch string) error {
 	return nil
 }
 
+// RebaseOntoBase rebases branch onto the local base branch, picking up merges
+// that have not been pushed yet. A conflicting rebase is aborted, leaving the
+// branch as it was, and reported as a MergeConflictError.
+func (g *Git) RebaseOntoBase(ctx context.Context, branch string) error {
+	if !IsValidBranchName(branch) {
+		return fmt.Errorf("invalid branch name: %q", branch)
+	}
+	baseBranch, err := g.GetBaseBranch(ctx)
+	if err != nil {
+		baseBranch = "main"
+	}
+	if _, err := g.run(ctx, "checkout", branch); err != nil {
+		return fmt.Errorf("failed to checkout branch %s: %w", branch, err)
+	}
+	if _, err := g.run(ctx, "rebase", baseBranch); err != nil {
+		conflictFiles, conflictErr := g.GetConflictFiles(ctx)
+		_ = g.AbortRebase(ctx)
+		if conflictErr == nil && len(conflictFiles) > 0 {
+			return &MergeConflictError{Branch: branch, ConflictFiles: conflictFiles}
+		}
+		return fmt.Errorf("rebase onto %s failed: %w", baseBranch, err)
+	}
+	return nil
+}
+
 // AbortRebase aborts an in-progress rebase
 func (g *Git) AbortRebase(ctx context.Context) error {
 	_, err := g.run(ctx, "rebase", "--abort")
 	return err
 }
 
+// CherryPick applies commits, in the order given, onto the current branch.
+// Each new commit notes the commit it was picked from. On conflicts the
+// cherry-pick is left in progress for resolution (git cherry-pick --continue)
+// and a MergeConflictError is returned; other failures are aborted.
+func (g *Git) CherryPick(ctx context.Context, commits []string) error {
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits to cherry-pick")
+	}
+	branch, _ := g.CurrentBranch(ctx)
+	args := append([]string{"cherry-pick", "-x"}, commits...)
+	if _, err := g.run(ctx, args...); err != nil {
+		conflictFiles, conflictErr := g.GetConflictFiles(ctx)
+		if conflictErr == nil && len(conflictFiles) > 0 {
+			return &MergeConflictError{Branch: branch, ConflictFiles: conflictFiles}
+		}
+		_, _ = g.run(ctx, "cherry-pick", "--abort")
+		return fmt.Errorf("cherry-pick failed: %w", err)
+	}
+	return nil
+}
+
 // BranchExistsRemote checks if a branch exists on the remote.
 func (g *Git) BranchExistsRemote(ctx context.Context, name string) (bool, error) {
 	out, err := g.run(ctx, "ls-remote", "--heads", "origin", name)
@@ -539,6 +595,12 @@ func (g *Git) CreateWorktreeFromExisting(ctx context.Context, worktreePath, bran
 	return err
 }
 
+// CreateWorktreeFromBase creates a new worktree with a new branch started from baseBranch.
+func (g *Git) CreateWorktreeFromBase(ctx context.Context, worktreePath, branchName, baseBranch string) error {
+	_, err := g.run(ctx, "worktree", "add", "-b", branchName, worktreePath, baseBranch)
+	return err
+}
+
 // RemoveWorktree removes a worktree and optionally its branch.
 func (g *Git) RemoveWorktree(ctx context.Context, worktreePath string) error {
 	// First prune any stale worktrees
@@ -712,6 +774,15 @@ func (g *Git) GetBranchCommitLogs(ctx context.Context, branchName string) (strin
 	return strings.TrimSpace(out), nil
 }
 
+// RevParse resolves a ref (branch, tag or SHA) to its full commit SHA.
+func (g *Git) RevParse(ctx context.Context, ref string) (string, error) {
+	out, err := g.run(ctx, "rev-parse", "--verify", ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
 // GetUntrackedFiles returns a list of untracked files in the repository.
 // These are files that are not ignored and not added to the index.
 func (g *Git) GetUntrackedFiles(ctx context.Context) ([]string, error) {
@@ -869,17 +940,42 @@ func (g *Git) FetchAndRebase(ctx context.Context) error {
 	}
 
 	// Rebase onto origin/<baseBranch>
-	_, err = g.run(ctx, "rebase", "origin/"+baseBranch)
-	if err != nil {
+	return g.RebaseOnto(ctx, "origin/"+baseBranch)
+}
+
+// RebaseOnto rebases the current branch onto upstream. On conflicts the
+// rebase is left in progress (see AbortRebase) and a MergeConflictError is
+// returned.
+func (g *Git) RebaseOnto(ctx context.Context, upstream string) error {
+	// Get current branch for error message (a stopped rebase detaches HEAD)
+	branch, _ := g.CurrentBranch(ctx)
+	if _, err := g.run(ctx, "rebase", upstream); err != nil {
 		// Check if rebase has conflicts
 		conflictFiles, conflictErr := g.GetConflictFiles(ctx)
 		if conflictErr == nil && len(conflictFiles) > 0 {
-			// Get current branch for error message
-			branch, _ := g.CurrentBranch(ctx)
 			return &MergeConflictError{Branch: branch, ConflictFiles: conflictFiles}
 		}
 		return fmt.Errorf("rebase failed: %w", err)
 	}
-
 	return nil
 }
+
+// IsAncestor reports whether ancestor is reachable from ref, i.e. ref
+// already contains every commit of ancestor.
+func (g *Git) IsAncestor(ctx context.Context, ancestor, ref string) (bool, error) {
+	cmd := exec.CommandContext(ctx, "git", "merge-base", "--is-ancestor", ancestor, ref)
+	cmd.Dir = g.repoPath
+	out, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		return false, nil
+	default:
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return false, fmt.Errorf("%s: %w", msg, err)
+		}
+		return false, err
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -351,6 +352,49 @@ func TestGit_CreateWorktreeFromExisting(t *testing.T) {
 	}
 }
 
+func TestGit_CreateWorktreeFromBase(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	g := New(dir)
+	ctx := context.Background()
+
+	head, err := g.CurrentBranch(ctx)
+	if err != nil {
+		t.Fatalf("CurrentBranch() error = %v", err)
+	}
+
+	// Create a base branch with a commit that HEAD doesn't have
+	if err := g.CreateBranch(ctx, "develop"); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	if err := g.Checkout(ctx, "develop"); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "develop.txt"), []byte("develop"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.run(ctx, "add", "develop.txt"); err != nil {
+		t.Fatalf("git add error = %v", err)
+	}
+	if _, err := g.run(ctx, "commit", "-m", "develop only"); err != nil {
+		t.Fatalf("git commit error = %v", err)
+	}
+	if err := g.Checkout(ctx, head); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	worktreePath := filepath.Join(os.TempDir(), "git-worktree-base-test-"+filepath.Base(dir))
+	defer os.RemoveAll(worktreePath)
+
+	if err := g.CreateWorktreeFromBase(ctx, worktreePath, "bugfix/from-develop", "develop"); err != nil {
+		t.Fatalf("CreateWorktreeFromBase() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "develop.txt")); err != nil {
+		t.Errorf("worktree should be based on develop: %v", err)
+	}
+}
+
 func TestGit_RemoveWorktree(t *testing.T) {
 	dir := setupTestRepo(t)
 	defer os.RemoveAll(dir)
@@ -1484,3 +1528,95 @@ func TestGetDivergedCommitCount_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestGit_RevParse(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+
+	g := New(dir)
+	ctx := context.Background()
+
+	if err := g.CreateBranch(ctx, "feature"); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+	sha, err := g.RevParse(ctx, "feature")
+	if err != nil {
+		t.Fatalf("RevParse() error = %v", err)
+	}
+	if len(sha) != 40 {
+		t.Errorf("RevParse() = %q, want a full SHA", sha)
+	}
+
+	// The SHA resolves to itself
+	again, err := g.RevParse(ctx, sha)
+	if err != nil || again != sha {
+		t.Errorf("RevParse(%q) = %q, %v", sha, again, err)
+	}
+
+	if _, err := g.RevParse(ctx, "does-not-exist"); err == nil {
+		t.Error("RevParse() should fail for an unknown ref")
+	}
+}
+
+func TestGit_RebaseOntoBase(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+
+	base, _ := g.CurrentBranch(ctx)
+	exec.Command("git", "-C", dir, "branch", "-m", base, "main").Run()
+	commitFile(t, dir, "main", "shared.txt", "line\n")
+	for _, b := range []string{"clean", "conflicting"} {
+		if err := g.CreateBranch(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commitFile(t, dir, "clean", "clean.txt", "clean\n")
+	commitFile(t, dir, "conflicting", "shared.txt", "branch\n")
+	commitFile(t, dir, "main", "shared.txt", "main\n")
+
+	if err := g.RebaseOntoBase(ctx, "clean"); err != nil {
+		t.Fatalf("RebaseOntoBase(clean) error = %v", err)
+	}
+	if out, err := exec.Command("git", "-C", dir, "merge-base", "--is-ancestor", "main", "clean").CombinedOutput(); err != nil {
+		t.Errorf("clean should contain main after the rebase: %v: %s", err, out)
+	}
+
+	head, _ := g.RevParse(ctx, "conflicting")
+	err := g.RebaseOntoBase(ctx, "conflicting")
+	conflictErr, ok := err.(*MergeConflictError)
+	if !ok || !reflect.DeepEqual(conflictErr.ConflictFiles, []string{"shared.txt"}) {
+		t.Fatalf("RebaseOntoBase(conflicting) error = %v, want a conflict on shared.txt", err)
+	}
+	if after, _ := g.RevParse(ctx, "conflicting"); after != head {
+		t.Error("a conflicting rebase should be aborted, leaving the branch unchanged")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "rebase-merge")); !os.IsNotExist(err) {
+		t.Error("no rebase should be left in progress")
+	}
+}
+
+func TestGit_IsAncestor(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+
+	base, _ := g.CurrentBranch(ctx)
+	if err := g.CreateBranch(ctx, "feature"); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, dir, "feature", "feature.txt", "feature\n")
+
+	if ok, err := g.IsAncestor(ctx, base, "feature"); err != nil || !ok {
+		t.Errorf("IsAncestor(%s, feature) = %v, %v; want true", base, ok, err)
+	}
+	commitFile(t, dir, base, "base.txt", "base\n")
+	if ok, err := g.IsAncestor(ctx, base, "feature"); err != nil || ok {
+		t.Errorf("IsAncestor(%s, feature) = %v, %v; want false once %s moved", base, ok, err, base)
+	}
+	if _, err := g.IsAncestor(ctx, "no-such-ref", "feature"); err == nil {
+		t.Error("IsAncestor() should fail for an unknown ref")
+	}
+}
@@ -0,0 +1,223 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CheckpointRefPrefix is the ref namespace WIP checkpoints are stored under,
+// as refs/ccells/checkpoints/<branch>/<unix milliseconds>.
+const CheckpointRefPrefix = "refs/ccells/checkpoints/"
+
+// checkpointIdentity is the author and committer of checkpoint commits, so
+// they can be made without a configured git identity.
+var checkpointIdentity = []string{
+	"GIT_AUTHOR_NAME=ccells",
+	"GIT_AUTHOR_EMAIL=ccells@localhost",
+	"GIT_COMMITTER_NAME=ccells",
+	"GIT_COMMITTER_EMAIL=ccells@localhost",
+}
+
+// Checkpoint is a snapshot of a worktree's full state, including untracked
+// files, stored as a commit on top of the HEAD it was taken at.
+type Checkpoint struct {
+	Ref    string
+	SHA    string
+	Branch string
+	Time   time.Time
+}
+
+// checkpointRef returns the ref of a checkpoint of branch taken at t.
+func checkpointRef(branch string, t time.Time) string {
+	return fmt.Sprintf("%s%s/%d", CheckpointRefPrefix, branch, t.UnixMilli())
+}
+
+// parseCheckpointRef splits a checkpoint ref into its branch and time.
+func parseCheckpointRef(ref string) (string, time.Time, bool) {
+	rest, ok := strings.CutPrefix(ref, CheckpointRefPrefix)
+	if !ok {
+		return "", time.Time{}, false
+	}
+	i := strings.LastIndex(rest, "/")
+	if i <= 0 {
+		return "", time.Time{}, false
+	}
+	ms, err := strconv.ParseInt(rest[i+1:], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return rest[:i], time.UnixMilli(ms), true
+}
+
+// ArchiveRefPrefix is the ref namespace the uncommitted work of archived
+// workstreams is kept under, as refs/ccells/archive/<workstream ID>. Unlike
+// checkpoints, these refs are never pruned; they are deleted with DeleteRef
+// once the workstream is restored.
+const ArchiveRefPrefix = "refs/ccells/archive/"
+
+// snapshotTree writes the worktree, including untracked files that are not
+// ignored, as a tree object and returns it with HEAD. The index and HEAD are
+// left alone: the snapshot is built in a temporary index.
+func (g *Git) snapshotTree(ctx context.Context) (tree, head string, err error) {
+	head, err = g.RevParse(ctx, "HEAD")
+	if err != nil {
+		return "", "", err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "ccells-checkpoint-*")
+	if err != nil {
+		return "", "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmpDir, "index")}
+
+	// Start from a copy of the real index so unchanged files needn't be rehashed
+	if indexPath, err := g.run(ctx, "rev-parse", "--path-format=absolute", "--git-path", "index"); err == nil {
+		if data, err := os.ReadFile(indexPath); err == nil {
+			_ = os.WriteFile(filepath.Join(tmpDir, "index"), data, 0600)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "index")); err != nil {
+		if _, err := g.runEnv(ctx, env, "read-tree", "HEAD"); err != nil {
+			return "", "", err
+		}
+	}
+	if _, err := g.runEnv(ctx, env, "add", "-A"); err != nil {
+		return "", "", fmt.Errorf("failed to snapshot worktree: %w", err)
+	}
+	tree, err = g.runEnv(ctx, env, "write-tree")
+	if err != nil {
+		return "", "", err
+	}
+	return tree, head, nil
+}
+
+// CreateCheckpoint snapshots the worktree, including untracked files that
+// are not ignored, under a checkpoint ref for branch. The index and HEAD are
+// left alone: the snapshot is built in a temporary index. It returns nil if
+// there is nothing to save, because the worktree matches HEAD or the latest
+// checkpoint.
+func (g *Git) CreateCheckpoint(ctx context.Context, branch string) (*Checkpoint, error) {
+	if !IsValidBranchName(branch) {
+		return nil, fmt.Errorf("invalid branch name: %q", branch)
+	}
+	tree, head, err := g.snapshotTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if headTree, err := g.run(ctx, "rev-parse", "HEAD^{tree}"); err == nil && headTree == tree {
+		return nil, nil // Nothing uncommitted
+	}
+	if existing, err := g.ListCheckpoints(ctx, branch); err == nil && len(existing) > 0 {
+		if latestTree, err := g.run(ctx, "rev-parse", existing[0].SHA+"^{tree}"); err == nil && latestTree == tree {
+			return nil, nil // Unchanged since the latest checkpoint
+		}
+	}
+
+	now := time.Now()
+	sha, err := g.runEnv(ctx, checkpointIdentity, "commit-tree", tree, "-p", head, "-m", "WIP checkpoint of "+branch)
+	if err != nil {
+		return nil, err
+	}
+	cp := &Checkpoint{Ref: checkpointRef(branch, now), SHA: sha, Branch: branch, Time: now}
+	if _, err := g.run(ctx, "update-ref", cp.Ref, sha); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// SnapshotWorktree saves the worktree's uncommitted changes, including
+// untracked files that are not ignored, as a commit on top of HEAD under ref.
+// It returns the commit, or "" if the worktree matches HEAD. The snapshot
+// can be applied with RestoreCheckpoint.
+func (g *Git) SnapshotWorktree(ctx context.Context, ref string) (string, error) {
+	tree, head, err := g.snapshotTree(ctx)
+	if err != nil {
+		return "", err
+	}
+	if headTree, err := g.run(ctx, "rev-parse", "HEAD^{tree}"); err == nil && headTree == tree {
+		return "", nil
+	}
+	sha, err := g.runEnv(ctx, checkpointIdentity, "commit-tree", tree, "-p", head, "-m", "Uncommitted work snapshot")
+	if err != nil {
+		return "", err
+	}
+	if _, err := g.run(ctx, "update-ref", ref, sha); err != nil {
+		return "", err
+	}
+	return sha, nil
+}
+
+// DeleteRef deletes ref. Deleting a ref that doesn't exist is not an error.
+func (g *Git) DeleteRef(ctx context.Context, ref string) error {
+	if _, err := g.run(ctx, "update-ref", "-d", ref); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", ref, err)
+	}
+	return nil
+}
+
+// ListCheckpoints returns the checkpoints of branch, newest first. An empty
+// branch lists the checkpoints of all branches.
+func (g *Git) ListCheckpoints(ctx context.Context, branch string) ([]Checkpoint, error) {
+	prefix := strings.TrimSuffix(CheckpointRefPrefix, "/")
+	if branch != "" {
+		prefix = CheckpointRefPrefix + branch
+	}
+	out, err := g.run(ctx, "for-each-ref", "--format=%(refname) %(objectname)", prefix)
+	if err != nil {
+		return nil, err
+	}
+	var checkpoints []Checkpoint
+	for _, line := range splitLines(out) {
+		ref, sha, _ := strings.Cut(line, " ")
+		refBranch, t, ok := parseCheckpointRef(ref)
+		// for-each-ref matches whole components, so branch "a" also lists "a/b"
+		if !ok || (branch != "" && refBranch != branch) {
+			continue
+		}
+		checkpoints = append(checkpoints, Checkpoint{Ref: ref, SHA: sha, Branch: refBranch, Time: t})
+	}
+	sort.SliceStable(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Time.After(checkpoints[j].Time)
+	})
+	return checkpoints, nil
+}
+
+// RestoreCheckpoint makes the worktree's files match a checkpoint. HEAD and
+// the index are not changed, so the restored work shows up as uncommitted
+// changes. Files created since the checkpoint that it doesn't know are kept.
+func (g *Git) RestoreCheckpoint(ctx context.Context, cp Checkpoint) error {
+	if _, err := g.run(ctx, "restore", "--source="+cp.SHA, "--worktree", "--", "."); err != nil {
+		return fmt.Errorf("failed to restore checkpoint: %w", err)
+	}
+	return nil
+}
+
+// PruneCheckpoints deletes checkpoints beyond the newest keep of each branch
+// and those older than maxAge (if positive). It returns how many were deleted.
+func (g *Git) PruneCheckpoints(ctx context.Context, keep int, maxAge time.Duration) (int, error) {
+	checkpoints, err := g.ListCheckpoints(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+	perBranch := make(map[string]int)
+	pruned := 0
+	for _, cp := range checkpoints {
+		perBranch[cp.Branch]++
+		if perBranch[cp.Branch] <= keep && (maxAge <= 0 || time.Since(cp.Time) <= maxAge) {
+			continue
+		}
+		if _, err := g.run(ctx, "update-ref", "-d", cp.Ref); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
@@ -0,0 +1,170 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseCheckpointRef(t *testing.T) {
+	at := time.UnixMilli(1700000000123)
+	branch, got, ok := parseCheckpointRef(checkpointRef("ccells/fix-login", at))
+	if !ok || branch != "ccells/fix-login" || !got.Equal(at) {
+		t.Errorf("parseCheckpointRef() = %q, %v, %v", branch, got, ok)
+	}
+	for _, ref := range []string{"refs/heads/main", CheckpointRefPrefix + "main", CheckpointRefPrefix + "main/latest"} {
+		if _, _, ok := parseCheckpointRef(ref); ok {
+			t.Errorf("parseCheckpointRef(%q) should fail", ref)
+		}
+	}
+}
+
+func TestGit_Checkpoints(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+	branch, _ := g.CurrentBranch(ctx)
+	commitFile(t, dir, branch, "app.go", "v1\n")
+
+	// A clean worktree has nothing to save
+	cp, err := g.CreateCheckpoint(ctx, branch)
+	if err != nil || cp != nil {
+		t.Fatalf("CreateCheckpoint(clean) = %v, %v; want nothing", cp, err)
+	}
+
+	// Staged, modified and untracked work is all saved
+	os.WriteFile(filepath.Join(dir, "staged.go"), []byte("staged\n"), 0644)
+	if _, err := g.run(ctx, "add", "staged.go"); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "app.go"), []byte("v2\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("untracked\n"), 0644)
+	head, _ := g.RevParse(ctx, "HEAD")
+	statusBefore, _ := g.run(ctx, "status", "--porcelain")
+
+	cp, err = g.CreateCheckpoint(ctx, branch)
+	if err != nil || cp == nil {
+		t.Fatalf("CreateCheckpoint() = %v, %v", cp, err)
+	}
+	if statusAfter, _ := g.run(ctx, "status", "--porcelain"); statusAfter != statusBefore {
+		t.Errorf("status changed from %q to %q; the index must be left alone", statusBefore, statusAfter)
+	}
+	if now, _ := g.RevParse(ctx, "HEAD"); now != head {
+		t.Error("HEAD must not move")
+	}
+	for file, want := range map[string]string{"app.go": "v2", "staged.go": "staged", "notes.txt": "untracked"} {
+		if got, err := g.run(ctx, "show", cp.SHA+":"+file); err != nil || got != want {
+			t.Errorf("checkpoint %s = %q, %v; want %q", file, got, err, want)
+		}
+	}
+	if parent, _ := g.RevParse(ctx, cp.SHA+"^"); parent != head {
+		t.Error("the checkpoint should sit on top of HEAD")
+	}
+
+	// Unchanged since the last checkpoint: nothing new
+	if again, err := g.CreateCheckpoint(ctx, branch); err != nil || again != nil {
+		t.Errorf("CreateCheckpoint(unchanged) = %v, %v; want nothing", again, err)
+	}
+
+	// Lose the work, then restore it
+	if _, err := g.run(ctx, "checkout", "--", "app.go"); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filepath.Join(dir, "notes.txt"))
+	list, err := g.ListCheckpoints(ctx, branch)
+	if err != nil || len(list) != 1 || list[0].SHA != cp.SHA || list[0].Branch != branch {
+		t.Fatalf("ListCheckpoints() = %+v, %v", list, err)
+	}
+	if err := g.RestoreCheckpoint(ctx, list[0]); err != nil {
+		t.Fatalf("RestoreCheckpoint() error = %v", err)
+	}
+	for file, want := range map[string]string{"app.go": "v2\n", "notes.txt": "untracked\n"} {
+		if got, _ := os.ReadFile(filepath.Join(dir, file)); string(got) != want {
+			t.Errorf("restored %s = %q, want %q", file, got, want)
+		}
+	}
+	if now, _ := g.RevParse(ctx, "HEAD"); now != head {
+		t.Error("restoring must not move HEAD")
+	}
+}
+
+func TestGit_SnapshotWorktree(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+	branch, _ := g.CurrentBranch(ctx)
+	ref := ArchiveRefPrefix + "ws-1"
+
+	if sha, err := g.SnapshotWorktree(ctx, ref); err != nil || sha != "" {
+		t.Fatalf("SnapshotWorktree(clean) = %q, %v; want nothing", sha, err)
+	}
+
+	// Work that is already checkpointed is still snapshotted
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("untracked\n"), 0644)
+	if _, err := g.CreateCheckpoint(ctx, branch); err != nil {
+		t.Fatal(err)
+	}
+	sha, err := g.SnapshotWorktree(ctx, ref)
+	if err != nil || sha == "" {
+		t.Fatalf("SnapshotWorktree() = %q, %v", sha, err)
+	}
+	if got, _ := g.run(ctx, "show", ref+":notes.txt"); got != "untracked" {
+		t.Errorf("snapshot notes.txt = %q, want the untracked file", got)
+	}
+
+	// Pruning checkpoints leaves it alone
+	if _, err := g.PruneCheckpoints(ctx, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := g.RevParse(ctx, ref); err != nil || got != sha {
+		t.Errorf("%s = %q, %v; want it kept", ref, got, err)
+	}
+
+	// Deleting it once restored, and again, succeeds
+	for range 2 {
+		if err := g.DeleteRef(ctx, ref); err != nil {
+			t.Fatalf("DeleteRef() error = %v", err)
+		}
+	}
+	if _, err := g.RevParse(ctx, ref); err == nil {
+		t.Errorf("%s should be deleted", ref)
+	}
+}
+
+func TestGit_PruneCheckpoints(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+	head, _ := g.RevParse(ctx, "HEAD")
+
+	now := time.Now()
+	refs := map[string]time.Time{
+		checkpointRef("alpha", now.Add(-time.Minute)):    now,
+		checkpointRef("alpha", now.Add(-2*time.Minute)):  now,
+		checkpointRef("alpha", now.Add(-3*time.Minute)):  now,
+		checkpointRef("alpha/b", now.Add(-time.Minute)):  now,
+		checkpointRef("beta", now.Add(-30*24*time.Hour)): now,
+	}
+	for ref := range refs {
+		if _, err := g.run(ctx, "update-ref", ref, head); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if list, _ := g.ListCheckpoints(ctx, "alpha"); len(list) != 3 || !list[0].Time.After(list[1].Time) {
+		t.Fatalf("ListCheckpoints(alpha) = %+v, want 3 newest first without alpha/b", list)
+	}
+
+	pruned, err := g.PruneCheckpoints(ctx, 2, 7*24*time.Hour)
+	if err != nil || pruned != 2 {
+		t.Fatalf("PruneCheckpoints() = %d, %v; want the oldest alpha and the stale beta pruned", pruned, err)
+	}
+	if list, _ := g.ListCheckpoints(ctx, ""); len(list) != 3 {
+		t.Errorf("remaining = %+v, want 2 alpha and 1 alpha/b", list)
+	}
+}
@@ -0,0 +1,80 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// FailedCheck is a failing status check of a pull request.
+type FailedCheck struct {
+	Name     string
+	Workflow string // GitHub Actions workflow, if the check is an Actions job
+	Link     string
+	Log      string // End of the failed steps' log (Actions jobs only)
+}
+
+// prCheck is an entry of gh pr checks --json output.
+type prCheck struct {
+	Name     string `json:"name"`
+	Bucket   string `json:"bucket"` // pass, fail, pending, skipping or cancel
+	Link     string `json:"link"`
+	Workflow string `json:"workflow"`
+}
+
+var actionsJobRegex = regexp.MustCompile(`/actions/runs/\d+/job/(\d+)`)
+
+// actionsJobID returns the job ID of a GitHub Actions check link, or "".
+func actionsJobID(link string) string {
+	if m := actionsJobRegex.FindStringSubmatch(link); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GetFailedChecks returns the failing checks of a pull request with up to
+// logLines lines of each failing GitHub Actions job's log.
+func (g *GH) GetFailedChecks(ctx context.Context, repoPath string, number int, logLines int) ([]FailedCheck, error) {
+	cmd := exec.CommandContext(ctx, "gh", "pr", "checks", fmt.Sprint(number), "--json", "name,bucket,link,workflow")
+	cmd.Dir = repoPath
+	// gh pr checks exits non-zero when checks fail, so only give up
+	// when there is no output to parse
+	out, err := cmd.Output()
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("gh pr checks failed: %w", err)
+	}
+
+	var checks []prCheck
+	if err := json.Unmarshal(out, &checks); err != nil {
+		return nil, fmt.Errorf("failed to parse PR checks: %w", err)
+	}
+
+	var failed []FailedCheck
+	for _, c := range checks {
+		if c.Bucket != "fail" {
+			continue
+		}
+		check := FailedCheck{Name: c.Name, Workflow: c.Workflow, Link: c.Link}
+		if jobID := actionsJobID(c.Link); jobID != "" {
+			logCmd := exec.CommandContext(ctx, "gh", "run", "view", "--job", jobID, "--log-failed")
+			logCmd.Dir = repoPath
+			if log, err := logCmd.Output(); err == nil {
+				check.Log = tailLines(string(log), logLines)
+			}
+		}
+		failed = append(failed, check)
+	}
+	return failed, nil
+}
@@ -0,0 +1,42 @@
+package git
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGH_GetFailedChecks(t *testing.T) {
+	fakeExecutable(t, "gh", `case "$1 $2" in
+"pr checks")
+  echo '[{"name":"test","bucket":"fail","link":"https://github.com/o/r/actions/runs/1/job/77","workflow":"CI"},
+         {"name":"lint","bucket":"pass","link":"https://github.com/o/r/actions/runs/1/job/78","workflow":"CI"},
+         {"name":"deploy/preview","bucket":"fail","link":"https://vercel.example/build/9","workflow":""}]'
+  exit 1 ;;
+"run view")
+  [ "$4" = "77" ] || exit 1
+  printf 'setup\nline 1\nline 2\nFAIL: TestX\n' ;;
+esac
+`)
+
+	checks, err := NewGH().GetFailedChecks(context.Background(), t.TempDir(), 42, 2)
+	if err != nil {
+		t.Fatalf("GetFailedChecks() error = %v", err)
+	}
+	if len(checks) != 2 {
+		t.Fatalf("got %d failed checks, want 2: %+v", len(checks), checks)
+	}
+	if checks[0].Name != "test" || checks[0].Workflow != "CI" || checks[0].Log != "line 2\nFAIL: TestX" {
+		t.Errorf("first check = %+v, want the last 2 log lines", checks[0])
+	}
+	if checks[1].Name != "deploy/preview" || checks[1].Log != "" || !strings.Contains(checks[1].Link, "vercel") {
+		t.Errorf("non-Actions check = %+v, want only its link", checks[1])
+	}
+}
+
+func TestGH_GetFailedChecks_Error(t *testing.T) {
+	fakeExecutable(t, "gh", "exit 1\n")
+	if _, err := NewGH().GetFailedChecks(context.Background(), t.TempDir(), 42, 10); err == nil {
+		t.Error("expected an error when gh fails without output")
+	}
+}
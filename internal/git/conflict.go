@@ -0,0 +1,73 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ChangedFiles returns the files a branch changed since it forked from the
+// base branch.
+func (g *Git) ChangedFiles(ctx context.Context, branch string) ([]string, error) {
+	baseBranch, err := g.GetBaseBranch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := g.run(ctx, "diff", "--name-only", baseBranch+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(out), nil
+}
+
+// MergeTreeConflicts simulates merging two branches without touching the
+// working tree or index and returns the files that would conflict.
+// Requires git 2.38 or later (git merge-tree --write-tree).
+func (g *Git) MergeTreeConflicts(ctx context.Context, branchA, branchB string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "merge-tree", "--write-tree", "--name-only", "--no-messages", branchA, branchB)
+	cmd.Dir = g.repoPath
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return nil, nil // Merges cleanly
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		// Conflicts: the first line is the tree OID, followed by the conflicted files
+		lines := splitLines(string(out))
+		if len(lines) > 0 {
+			lines = lines[1:]
+		}
+		return dedupe(lines), nil
+	default:
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("%s: %w", strings.TrimSpace(string(exitErr.Stderr)), err)
+		}
+		return nil, err
+	}
+}
+
+// splitLines splits command output into non-empty lines.
+func splitLines(out string) []string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// dedupe removes repeated entries, keeping the first occurrence.
+func dedupe(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	var result []string
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			result = append(result, item)
+		}
+	}
+	return result
+}
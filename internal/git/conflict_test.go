@@ -0,0 +1,148 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// commitFile writes a file on the given branch and commits it.
+func commitFile(t *testing.T, dir, branch, name, content string) {
+	t.Helper()
+	gitC := func(args ...string) {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	gitC("checkout", "-q", branch)
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitC("add", name)
+	gitC("commit", "-q", "-m", "edit "+name)
+}
+
+func TestGit_ChangedFilesAndMergeTreeConflicts(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+
+	base, _ := g.CurrentBranch(ctx)
+	exec.Command("git", "-C", dir, "branch", "-m", base, "main").Run()
+	commitFile(t, dir, "main", "shared.txt", "line\n")
+	for _, b := range []string{"alpha", "beta", "gamma"} {
+		if err := g.CreateBranch(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commitFile(t, dir, "alpha", "shared.txt", "alpha\n")
+	commitFile(t, dir, "beta", "shared.txt", "beta\n")
+	commitFile(t, dir, "gamma", "other.txt", "gamma\n")
+
+	files, err := g.ChangedFiles(ctx, "alpha")
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	if !reflect.DeepEqual(files, []string{"shared.txt"}) {
+		t.Errorf("ChangedFiles(alpha) = %v, want [shared.txt]", files)
+	}
+
+	conflicts, err := g.MergeTreeConflicts(ctx, "alpha", "beta")
+	if err != nil {
+		t.Skipf("git merge-tree --write-tree unsupported: %v", err)
+	}
+	if !reflect.DeepEqual(conflicts, []string{"shared.txt"}) {
+		t.Errorf("MergeTreeConflicts(alpha, beta) = %v, want [shared.txt]", conflicts)
+	}
+	conflicts, err = g.MergeTreeConflicts(ctx, "alpha", "gamma")
+	if err != nil || len(conflicts) != 0 {
+		t.Errorf("MergeTreeConflicts(alpha, gamma) = %v, %v; want clean merge", conflicts, err)
+	}
+}
+
+func TestGit_RebaseOnto(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+
+	base, _ := g.CurrentBranch(ctx)
+	exec.Command("git", "-C", dir, "branch", "-m", base, "main").Run()
+	commitFile(t, dir, "main", "shared.txt", "line\n")
+	for _, b := range []string{"clean", "conflict"} {
+		if err := g.CreateBranch(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commitFile(t, dir, "clean", "other.txt", "clean\n")
+	commitFile(t, dir, "conflict", "shared.txt", "conflict\n")
+	commitFile(t, dir, "main", "shared.txt", "main\n")
+
+	commitFile(t, dir, "clean", "other.txt", "clean 2\n")
+	if err := g.RebaseOnto(ctx, "main"); err != nil {
+		t.Fatalf("RebaseOnto() error = %v", err)
+	}
+	if _, err := g.run(ctx, "merge-base", "--is-ancestor", "main", "clean"); err != nil {
+		t.Error("clean should contain main after the rebase")
+	}
+
+	if err := g.Checkout(ctx, "conflict"); err != nil {
+		t.Fatal(err)
+	}
+	err := g.RebaseOnto(ctx, "main")
+	conflictErr, ok := err.(*MergeConflictError)
+	if !ok || conflictErr.Branch != "conflict" || len(conflictErr.ConflictFiles) != 1 || conflictErr.ConflictFiles[0] != "shared.txt" {
+		t.Fatalf("RebaseOnto() error = %v, want a conflict in shared.txt", err)
+	}
+	if err := g.AbortRebase(ctx); err != nil {
+		t.Fatalf("AbortRebase() error = %v", err)
+	}
+}
+
+func TestGit_CherryPick(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+
+	base, _ := g.CurrentBranch(ctx)
+	exec.Command("git", "-C", dir, "branch", "-m", base, "main").Run()
+	commitFile(t, dir, "main", "shared.txt", "line\n")
+	for _, b := range []string{"source", "target"} {
+		if err := g.CreateBranch(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commitFile(t, dir, "source", "fix.txt", "fix\n")
+	commitFile(t, dir, "source", "shared.txt", "source\n")
+	commits, err := g.BranchCommits(ctx, "source")
+	if err != nil || len(commits) != 2 {
+		t.Fatalf("BranchCommits() = %v, %v", commits, err)
+	}
+
+	commitFile(t, dir, "target", "shared.txt", "target\n")
+	if err := g.CherryPick(ctx, []string{commits[0].Hash}); err != nil {
+		t.Fatalf("CherryPick() error = %v", err)
+	}
+	msg, _ := g.run(ctx, "log", "-1", "--format=%B")
+	if !strings.Contains(msg, "cherry picked from commit") {
+		t.Errorf("picked commit message = %q, want its origin noted", msg)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "fix.txt")); err != nil {
+		t.Error("fix.txt should be picked into target")
+	}
+
+	err = g.CherryPick(ctx, []string{commits[1].Hash})
+	conflictErr, ok := err.(*MergeConflictError)
+	if !ok || conflictErr.Branch != "target" || len(conflictErr.ConflictFiles) != 1 || conflictErr.ConflictFiles[0] != "shared.txt" {
+		t.Fatalf("CherryPick() error = %v, want a conflict in shared.txt", err)
+	}
+	if _, err := g.run(ctx, "cherry-pick", "--abort"); err != nil {
+		t.Errorf("the conflicting cherry-pick should be left in progress: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package git
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Commit is a commit listed by BranchCommits.
+type Commit struct {
+	Hash    string // Abbreviated hash
+	Subject string
+}
+
+// MergeBase returns the commit a branch forked from the base branch at.
+func (g *Git) MergeBase(ctx context.Context, branch string) (string, error) {
+	baseBranch, err := g.GetBaseBranch(ctx)
+	if err != nil {
+		return "", err
+	}
+	return g.run(ctx, "merge-base", baseBranch, branch)
+}
+
+// BranchCommits returns the commits a branch has on top of the base branch,
+// oldest first.
+func (g *Git) BranchCommits(ctx context.Context, branch string) ([]Commit, error) {
+	baseBranch, err := g.GetBaseBranch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := g.run(ctx, "log", "--reverse", "--format=%h%x09%s", baseBranch+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, line := range splitLines(out) {
+		hash, subject, _ := strings.Cut(line, "\t")
+		commits = append(commits, Commit{Hash: hash, Subject: subject})
+	}
+	return commits, nil
+}
+
+// CommitsAhead returns the number of commits a branch has on top of the
+// base branch.
+func (g *Git) CommitsAhead(ctx context.Context, branch string) (int, error) {
+	baseBranch, err := g.GetBaseBranch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	out, err := g.run(ctx, "rev-list", "--count", baseBranch+".."+branch)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(out)
+}
+
+// DiffShortStat returns the summary of a branch's changes since it forked
+// from the base branch, e.g. "3 files changed, 10 insertions(+)". It is
+// empty when the branch changes nothing.
+func (g *Git) DiffShortStat(ctx context.Context, branch string) (string, error) {
+	baseBranch, err := g.GetBaseBranch(ctx)
+	if err != nil {
+		return "", err
+	}
+	return g.run(ctx, "diff", "--shortstat", baseBranch+"..."+branch)
+}
+
+// Diff returns the unified diff between two commits. An empty to diffs
+// against the working tree, including uncommitted changes.
+func (g *Git) Diff(ctx context.Context, from, to string) (string, error) {
+	args := []string{"diff", "--no-color", "--no-ext-diff", "-M", from}
+	if to != "" {
+		args = append(args, to)
+	}
+	return g.run(ctx, args...)
+}
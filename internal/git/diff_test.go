@@ -0,0 +1,68 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGit_BranchCommitsAndDiff(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+
+	base, _ := g.CurrentBranch(ctx)
+	exec.Command("git", "-C", dir, "branch", "-m", base, "main").Run()
+	if err := g.CreateBranch(ctx, "feature"); err != nil {
+		t.Fatal(err)
+	}
+	forkPoint, _ := g.RevParse(ctx, "main")
+	commitFile(t, dir, "feature", "one.txt", "one\n")
+	commitFile(t, dir, "feature", "two.txt", "two\n")
+	commitFile(t, dir, "main", "main.txt", "main\n")
+
+	mergeBase, err := g.MergeBase(ctx, "feature")
+	if err != nil || mergeBase != forkPoint {
+		t.Fatalf("MergeBase() = %q, %v; want %q", mergeBase, err, forkPoint)
+	}
+
+	commits, err := g.BranchCommits(ctx, "feature")
+	if err != nil {
+		t.Fatalf("BranchCommits() error = %v", err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "edit one.txt" || commits[1].Subject != "edit two.txt" {
+		t.Fatalf("BranchCommits() = %+v, want both commits oldest first", commits)
+	}
+
+	if n, err := g.CommitsAhead(ctx, "feature"); err != nil || n != 2 {
+		t.Errorf("CommitsAhead() = %d, %v; want 2", n, err)
+	}
+	if stat, err := g.DiffShortStat(ctx, "feature"); err != nil || stat != "2 files changed, 2 insertions(+)" {
+		t.Errorf("DiffShortStat() = %q, %v; want only the branch's changes", stat, err)
+	}
+
+	diff, err := g.Diff(ctx, mergeBase, "feature")
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if !strings.Contains(diff, "+++ b/one.txt") || !strings.Contains(diff, "+++ b/two.txt") || strings.Contains(diff, "main.txt") {
+		t.Errorf("Diff(merge base, feature) should contain only the branch's files:\n%s", diff)
+	}
+
+	// An empty "to" includes uncommitted changes
+	exec.Command("git", "-C", dir, "checkout", "-q", "feature").Run()
+	if err := os.WriteFile(filepath.Join(dir, "one.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	diff, err = g.Diff(ctx, "HEAD", "")
+	if err != nil {
+		t.Fatalf("Diff(HEAD) error = %v", err)
+	}
+	if !strings.Contains(diff, "+changed") {
+		t.Errorf("Diff(HEAD) should show the uncommitted edit:\n%s", diff)
+	}
+}
@@ -0,0 +1,88 @@
+package git
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Forge is a code hosting service that pull requests (GitHub) or merge
+// requests (GitLab) are created, viewed and merged on.
+type Forge interface {
+	// Name returns the forge's kind, e.g. ForgeGitHub.
+	Name() string
+	// CreatePR creates a pull/merge request for req.Head.
+	CreatePR(ctx context.Context, repoPath string, req *PRRequest) (*PRResponse, error)
+	// GetPR gets info about a pull/merge request by number.
+	GetPR(ctx context.Context, repoPath string, number int) (*PRResponse, error)
+	// PRExists checks if a pull/merge request exists for the current branch.
+	PRExists(ctx context.Context, repoPath string) (bool, *PRResponse, error)
+	// GetPRStatus retrieves the current branch's pull/merge request status,
+	// including its checks and how it compares with the local branch.
+	GetPRStatus(ctx context.Context, repoPath string, gitClient GitClient) (*PRStatusInfo, error)
+	// MergePR merges the current branch's pull/merge request.
+	MergePR(ctx context.Context, repoPath string, opts *PRMergeOptions) error
+}
+
+// Forge kinds.
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+)
+
+var (
+	_ Forge = (*GH)(nil)
+	_ Forge = (*GitLab)(nil)
+)
+
+// NewForge returns the forge for a kind, GitHub for anything unknown.
+func NewForge(kind string) Forge {
+	if kind == ForgeGitLab {
+		return NewGitLab()
+	}
+	return NewGH()
+}
+
+// remoteHost returns the host name of a git remote URL. It handles URLs
+// like https://host/owner/repo.git, ssh://git@host:22/owner/repo.git and
+// scp-like git@host:owner/repo.git.
+func remoteHost(remoteURL string) string {
+	remoteURL = strings.TrimSpace(remoteURL)
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil {
+			return ""
+		}
+		return strings.ToLower(u.Hostname())
+	}
+	host, _, ok := strings.Cut(remoteURL, ":")
+	if !ok {
+		return ""
+	}
+	if _, after, found := strings.Cut(host, "@"); found {
+		host = after
+	}
+	return strings.ToLower(host)
+}
+
+// DetectForge returns the forge kind for a git remote URL. Hosts named like
+// gitlab.com or gitlab.example.com are GitLab, as are gitlabHosts, which
+// lists self-hosted GitLab instances with other names. Everything else is
+// GitHub.
+func DetectForge(remoteURL string, gitlabHosts []string) string {
+	host := remoteHost(remoteURL)
+	if host == "" {
+		return ForgeGitHub
+	}
+	for _, h := range gitlabHosts {
+		if strings.EqualFold(host, strings.TrimSpace(h)) {
+			return ForgeGitLab
+		}
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "gitlab" {
+			return ForgeGitLab
+		}
+	}
+	return ForgeGitHub
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakeExecutable puts an executable called name that runs the given shell
+// script on PATH.
+func fakeExecutable(t *testing.T, name, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake " + name + " requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// fakeCommand puts an executable called name on PATH that records its
+// arguments, one per line, and prints output. It returns the arguments file.
+func fakeCommand(t *testing.T, name, output string) (argsFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+	outFile := filepath.Join(dir, "out")
+	if err := os.WriteFile(outFile, []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fakeExecutable(t, name, "printf '%s\\n' \"$@\" > "+argsFile+"\ncat "+outFile+"\n")
+	return argsFile
+}
+
+func TestDetectForge(t *testing.T) {
+	tests := []struct {
+		remoteURL   string
+		gitlabHosts []string
+		want        string
+	}{
+		{"https://github.com/owner/repo.git", nil, ForgeGitHub},
+		{"git@github.com:owner/repo.git", nil, ForgeGitHub},
+		{"https://gitlab.com/group/sub/project.git", nil, ForgeGitLab},
+		{"git@gitlab.com:group/project.git", nil, ForgeGitLab},
+		{"ssh://git@gitlab.example.com:2222/group/project.git", nil, ForgeGitLab},
+		{"https://GitLab.Example.com/group/project", nil, ForgeGitLab},
+		{"git@code.example.com:group/project.git", []string{"code.example.com"}, ForgeGitLab},
+		{"git@code.example.com:group/project.git", nil, ForgeGitHub},
+		{"https://notgitlab.example.com/group/project", nil, ForgeGitHub},
+		{"/local/path/repo.git", nil, ForgeGitHub},
+		{"", nil, ForgeGitHub},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remoteURL, func(t *testing.T) {
+			if got := DetectForge(tt.remoteURL, tt.gitlabHosts); got != tt.want {
+				t.Errorf("DetectForge(%q, %v) = %q, want %q", tt.remoteURL, tt.gitlabHosts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewForge(t *testing.T) {
+	if got := NewForge(ForgeGitLab).Name(); got != ForgeGitLab {
+		t.Errorf("NewForge(gitlab) = %q", got)
+	}
+	if got := NewForge("").Name(); got != ForgeGitHub {
+		t.Errorf("NewForge(\"\") = %q, want GitHub by default", got)
+	}
+}
@@ -0,0 +1,230 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GitLab wraps the GitLab CLI (glab) for merge request operations. Merge
+// requests use the PR types shared with GH; their number is the MR's IID.
+type GitLab struct{}
+
+// NewGitLab creates a new GitLab CLI wrapper.
+func NewGitLab() *GitLab {
+	return &GitLab{}
+}
+
+// Name returns ForgeGitLab.
+func (g *GitLab) Name() string {
+	return ForgeGitLab
+}
+
+// CheckInstalled verifies glab CLI is available.
+func (g *GitLab) CheckInstalled(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "glab", "--version")
+	return cmd.Run()
+}
+
+// mrURLRegex matches merge request URLs like
+// https://gitlab.example.com/group/project/-/merge_requests/123
+var mrURLRegex = regexp.MustCompile(`https?://\S+/-/merge_requests/(\d+)`)
+
+// extractMRURL finds the first merge request URL in glab output and
+// returns it with its number.
+func extractMRURL(output string) (string, int) {
+	match := mrURLRegex.FindStringSubmatch(output)
+	if match == nil {
+		return "", 0
+	}
+	num, err := strconv.Atoi(match[1])
+	if err != nil {
+		return "", 0
+	}
+	return match[0], num
+}
+
+// CreatePR creates a merge request using glab CLI.
+func (g *GitLab) CreatePR(ctx context.Context, repoPath string, req *PRRequest) (*PRResponse, error) {
+	args := []string{"mr", "create",
+		"--title", req.Title,
+		"--description", req.Body,
+		"--yes", // Don't prompt for confirmation
+	}
+
+	if req.Head != "" {
+		args = append(args, "--source-branch", req.Head)
+	}
+	if req.Base != "" {
+		args = append(args, "--target-branch", req.Base)
+	}
+	if req.Draft {
+		args = append(args, "--draft")
+	}
+	if len(req.Reviewers) > 0 {
+		args = append(args, "--reviewer", strings.Join(req.Reviewers, ","))
+	}
+	if len(req.Labels) > 0 {
+		args = append(args, "--label", strings.Join(req.Labels, ","))
+	}
+	if len(req.Assignees) > 0 {
+		args = append(args, "--assignee", strings.Join(req.Assignees, ","))
+	}
+	if req.Milestone != "" {
+		args = append(args, "--milestone", req.Milestone)
+	}
+
+	cmd := exec.CommandContext(ctx, "glab", args...)
+	cmd.Dir = repoPath
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("glab mr create failed: %w: %s", err, out)
+	}
+
+	// glab mr create prints a summary ending in the MR URL
+	url, number := extractMRURL(string(out))
+	if url == "" {
+		return nil, fmt.Errorf("glab mr create: no merge request URL in output: %s", out)
+	}
+	return &PRResponse{Number: number, URL: url}, nil
+}
+
+// mrViewResponse is the part of glab mr view --output json we use.
+type mrViewResponse struct {
+	IID          int    `json:"iid"`
+	WebURL       string `json:"web_url"`
+	SHA          string `json:"sha"`
+	SourceBranch string `json:"source_branch"`
+	HeadPipeline *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+// viewMR runs glab mr view for a merge request number, or the current
+// branch's merge request when number is 0.
+func (g *GitLab) viewMR(ctx context.Context, repoPath string, number int) (*mrViewResponse, error) {
+	args := []string{"mr", "view"}
+	if number > 0 {
+		args = append(args, fmt.Sprint(number))
+	}
+	args = append(args, "--output", "json")
+
+	cmd := exec.CommandContext(ctx, "glab", args...)
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("glab mr view failed: %w", err)
+	}
+
+	var resp mrViewResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse MR response: %w", err)
+	}
+	return &resp, nil
+}
+
+// GetPR gets info about a merge request by number.
+func (g *GitLab) GetPR(ctx context.Context, repoPath string, number int) (*PRResponse, error) {
+	resp, err := g.viewMR(ctx, repoPath, number)
+	if err != nil {
+		return nil, err
+	}
+	return &PRResponse{Number: resp.IID, URL: resp.WebURL}, nil
+}
+
+// PRExists checks if a merge request exists for the current branch.
+func (g *GitLab) PRExists(ctx context.Context, repoPath string) (bool, *PRResponse, error) {
+	resp, err := g.viewMR(ctx, repoPath, 0)
+	if err != nil {
+		// No MR for this branch (or other error, but we treat as no MR)
+		return false, nil, nil
+	}
+	return true, &PRResponse{Number: resp.IID, URL: resp.WebURL}, nil
+}
+
+// GetPRStatus retrieves the current branch's merge request status. Its
+// checks are the head pipeline's status.
+func (g *GitLab) GetPRStatus(ctx context.Context, repoPath string, gitClient GitClient) (*PRStatusInfo, error) {
+	resp, err := g.viewMR(ctx, repoPath, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pipelineStatus := ""
+	if resp.HeadPipeline != nil {
+		pipelineStatus = resp.HeadPipeline.Status
+	}
+	checkStatus, checksSummary := pipelineCheckStatus(pipelineStatus)
+
+	status := &PRStatusInfo{
+		Number:        resp.IID,
+		URL:           resp.WebURL,
+		HeadSHA:       resp.SHA,
+		CheckStatus:   checkStatus,
+		ChecksSummary: checksSummary,
+		Forge:         ForgeGitLab,
+	}
+	compareLocalBranch(ctx, gitClient, resp.SourceBranch, status)
+
+	return status, nil
+}
+
+// pipelineCheckStatus converts a GitLab pipeline status into a simple
+// status and summary.
+func pipelineCheckStatus(pipeline string) (PRCheckStatus, string) {
+	switch pipeline {
+	case "":
+		return PRCheckStatusUnknown, "No pipeline"
+	case "success":
+		return PRCheckStatusSuccess, "Pipeline passed"
+	case "failed":
+		return PRCheckStatusFailure, "Pipeline failed"
+	case "canceled":
+		return PRCheckStatusFailure, "Pipeline canceled"
+	case "created", "waiting_for_resource", "preparing", "pending", "running", "scheduled", "manual":
+		return PRCheckStatusPending, "Pipeline " + strings.ReplaceAll(pipeline, "_", " ")
+	default:
+		return PRCheckStatusUnknown, "Pipeline " + pipeline
+	}
+}
+
+// MergePR merges the current branch's merge request using glab CLI.
+func (g *GitLab) MergePR(ctx context.Context, repoPath string, opts *PRMergeOptions) error {
+	args := []string{"mr", "merge", "--yes"}
+
+	// Handle nil opts by using safe defaults
+	method := "squash"
+	deleteBranch := false
+	if opts != nil {
+		if opts.Method != "" {
+			method = opts.Method
+		}
+		deleteBranch = opts.DeleteBranch
+	}
+
+	// A plain merge commit needs no flag
+	switch method {
+	case "merge":
+	case "rebase":
+		args = append(args, "--rebase")
+	default:
+		args = append(args, "--squash")
+	}
+
+	if deleteBranch {
+		args = append(args, "--remove-source-branch")
+	}
+
+	cmd := exec.CommandContext(ctx, "glab", args...)
+	cmd.Dir = repoPath
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("glab mr merge failed: %w: %s", err, out)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package git
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func readArgs(t *testing.T, argsFile string) []string {
+	t.Helper()
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestGitLab_CreatePR(t *testing.T) {
+	argsFile := fakeCommand(t, "glab", "\nCreating merge request for feature into main in group/project\n\n!12 Add login (feature)\n https://gitlab.example.com/group/project/-/merge_requests/12\n\n")
+
+	pr, err := NewGitLab().CreatePR(context.Background(), t.TempDir(), &PRRequest{
+		Title:     "Add login",
+		Body:      "Adds a login form",
+		Head:      "feature",
+		Base:      "develop",
+		Draft:     true,
+		Reviewers: []string{"alice", "bob"},
+		Labels:    []string{"ui"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePR() error = %v", err)
+	}
+	if pr.Number != 12 || pr.URL != "https://gitlab.example.com/group/project/-/merge_requests/12" {
+		t.Errorf("CreatePR() = %+v", pr)
+	}
+
+	got := strings.Join(readArgs(t, argsFile), " ")
+	want := "mr create --title Add login --description Adds a login form --yes --source-branch feature --target-branch develop --draft --reviewer alice,bob --label ui"
+	if got != want {
+		t.Errorf("glab args = %q, want %q", got, want)
+	}
+}
+
+func TestGitLab_GetPRStatus(t *testing.T) {
+	argsFile := fakeCommand(t, "glab", `{"iid":7,"web_url":"https://gitlab.com/g/p/-/merge_requests/7","sha":"abc123","source_branch":"feature","head_pipeline":{"status":"failed"}}`)
+
+	gitClient := NewMockGitClient()
+	gitClient.GetUnpushedCommitCountFn = func(ctx context.Context, branch string) (int, error) { return 2, nil }
+	gitClient.GetDivergedCommitCountFn = func(ctx context.Context, branch string) (int, error) { return 1, nil }
+
+	status, err := NewGitLab().GetPRStatus(context.Background(), t.TempDir(), gitClient)
+	if err != nil {
+		t.Fatalf("GetPRStatus() error = %v", err)
+	}
+	want := PRStatusInfo{
+		Number:        7,
+		URL:           "https://gitlab.com/g/p/-/merge_requests/7",
+		HeadSHA:       "abc123",
+		CheckStatus:   PRCheckStatusFailure,
+		ChecksSummary: "Pipeline failed",
+		UnpushedCount: 2,
+		DivergedCount: 1,
+		IsDiverged:    true,
+		Forge:         ForgeGitLab,
+	}
+	if *status != want {
+		t.Errorf("GetPRStatus() = %+v, want %+v", *status, want)
+	}
+	if got := strings.Join(readArgs(t, argsFile), " "); got != "mr view --output json" {
+		t.Errorf("glab args = %q", got)
+	}
+}
+
+func TestGitLab_MergePR(t *testing.T) {
+	tests := []struct {
+		opts *PRMergeOptions
+		want string
+	}{
+		{nil, "mr merge --yes --squash"},
+		{&PRMergeOptions{Method: "merge"}, "mr merge --yes"},
+		{&PRMergeOptions{Method: "rebase", DeleteBranch: true}, "mr merge --yes --rebase --remove-source-branch"},
+	}
+
+	for _, tt := range tests {
+		argsFile := fakeCommand(t, "glab", "")
+		if err := NewGitLab().MergePR(context.Background(), t.TempDir(), tt.opts); err != nil {
+			t.Fatalf("MergePR() error = %v", err)
+		}
+		if got := strings.Join(readArgs(t, argsFile), " "); got != tt.want {
+			t.Errorf("glab args = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestPipelineCheckStatus(t *testing.T) {
+	tests := []struct {
+		pipeline    string
+		wantStatus  PRCheckStatus
+		wantSummary string
+	}{
+		{"success", PRCheckStatusSuccess, "Pipeline passed"},
+		{"failed", PRCheckStatusFailure, "Pipeline failed"},
+		{"waiting_for_resource", PRCheckStatusPending, "Pipeline waiting for resource"},
+		{"running", PRCheckStatusPending, "Pipeline running"},
+		{"", PRCheckStatusUnknown, "No pipeline"},
+		{"skipped", PRCheckStatusUnknown, "Pipeline skipped"},
+	}
+
+	for _, tt := range tests {
+		status, summary := pipelineCheckStatus(tt.pipeline)
+		if status != tt.wantStatus || summary != tt.wantSummary {
+			t.Errorf("pipelineCheckStatus(%q) = %q, %q; want %q, %q", tt.pipeline, status, summary, tt.wantStatus, tt.wantSummary)
+		}
+	}
+}
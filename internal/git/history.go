@@ -0,0 +1,373 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/STRML/claude-cells/internal/claude"
+)
+
+// PlannedCommit is one commit of a proposed branch history.
+type PlannedCommit struct {
+	Message string   // Full commit message, subject line first
+	Sources []string // Original commit hashes combined into this commit, in order
+	Fixups  []string // Sources flagged as fixups of earlier work
+}
+
+// IsFixup reports whether an original commit was flagged as a fixup.
+func (c PlannedCommit) IsFixup(hash string) bool {
+	for _, f := range c.Fixups {
+		if f == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// HistoryPlan is a restructured history for a branch: its commits on top of
+// Base, replaced by Commits.
+type HistoryPlan struct {
+	Base     string          // Commit the branch forked from
+	Head     string          // Branch head the plan was made for
+	Original []Commit        // The branch's commits, oldest first
+	Commits  []PlannedCommit // The new commits, oldest first
+}
+
+// Validate checks that the plan uses every original commit exactly once and
+// that each new commit has a message.
+func (p *HistoryPlan) Validate() error {
+	if len(p.Commits) == 0 {
+		return fmt.Errorf("the plan has no commits")
+	}
+	used := make(map[string]bool)
+	for i, c := range p.Commits {
+		if strings.TrimSpace(c.Message) == "" {
+			return fmt.Errorf("commit %d has no message", i+1)
+		}
+		if len(c.Sources) == 0 {
+			return fmt.Errorf("commit %d combines no original commits", i+1)
+		}
+		for _, hash := range c.Sources {
+			if p.original(hash) == nil {
+				return fmt.Errorf("%s is not a commit on this branch", hash)
+			}
+			if used[hash] {
+				return fmt.Errorf("%s is used more than once", hash)
+			}
+			used[hash] = true
+		}
+	}
+	for _, c := range p.Original {
+		if !used[c.Hash] {
+			return fmt.Errorf("%s (%s) is missing from the plan", c.Hash, c.Subject)
+		}
+	}
+	return nil
+}
+
+// original returns the original commit a hash (or hash prefix) refers to.
+func (p *HistoryPlan) original(hash string) *Commit {
+	if len(hash) < 4 {
+		return nil
+	}
+	for i, c := range p.Original {
+		if strings.HasPrefix(c.Hash, hash) || strings.HasPrefix(hash, c.Hash) {
+			return &p.Original[i]
+		}
+	}
+	return nil
+}
+
+// historySourceRegex matches the source lines of a formatted plan, e.g.
+// "pick a1b2c3d Add login form".
+var historySourceRegex = regexp.MustCompile(`^(pick|fixup)\s+([0-9a-fA-F]{4,40})\b`)
+
+// FormatHistoryPlan renders a plan as editable text. Each new commit is a
+// block of "pick" and "fixup" lines naming the original commits it
+// combines, followed by its message. Lines starting with # are comments.
+func FormatHistoryPlan(p *HistoryPlan) string {
+	var sb strings.Builder
+	sb.WriteString("# Each commit lists the original commits it combines (pick, or fixup\n")
+	sb.WriteString("# for fixes of earlier work), followed by its message. Reorder, merge or\n")
+	sb.WriteString("# split blocks and edit messages freely. Lines starting with # are ignored.\n")
+	for _, c := range p.Commits {
+		sb.WriteString("\n")
+		for _, hash := range c.Sources {
+			verb := "pick"
+			if c.IsFixup(hash) {
+				verb = "fixup"
+			}
+			subject := ""
+			if orig := p.original(hash); orig != nil {
+				subject = " " + orig.Subject
+			}
+			sb.WriteString(verb + " " + hash + subject + "\n")
+		}
+		sb.WriteString(strings.TrimSpace(c.Message) + "\n")
+	}
+	return sb.String()
+}
+
+// ParseHistoryPlan reads the commits of an edited plan formatted by
+// FormatHistoryPlan, keeping base's other fields. The result is validated.
+func ParseHistoryPlan(base *HistoryPlan, text string) (*HistoryPlan, error) {
+	plan := &HistoryPlan{Base: base.Base, Head: base.Head, Original: base.Original}
+	var current *PlannedCommit
+	var message []string
+
+	finish := func() {
+		if current != nil {
+			current.Message = strings.TrimSpace(strings.Join(message, "\n"))
+			plan.Commits = append(plan.Commits, *current)
+		}
+		current, message = nil, nil
+	}
+
+	for n, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if m := historySourceRegex.FindStringSubmatch(line); m != nil {
+			// A source after a message starts the next commit
+			if current == nil || strings.TrimSpace(strings.Join(message, "")) != "" {
+				finish()
+				current = &PlannedCommit{}
+			}
+			hash := strings.ToLower(m[2])
+			if orig := plan.original(hash); orig != nil {
+				hash = orig.Hash
+			}
+			current.Sources = append(current.Sources, hash)
+			if m[1] == "fixup" {
+				current.Fixups = append(current.Fixups, hash)
+			}
+			continue
+		}
+		if current == nil {
+			if strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("line %d: a commit must start with a pick line", n+1)
+			}
+			continue
+		}
+		message = append(message, line)
+	}
+	finish()
+
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// historyProposal is the JSON response expected from Claude.
+type historyProposal struct {
+	Commits []struct {
+		Message string   `json:"message"`
+		Commits []string `json:"commits"`
+		Fixups  []string `json:"fixups"`
+	} `json:"commits"`
+}
+
+// buildHistoryPrompt constructs the prompt asking Claude to restructure a
+// branch's commits.
+func buildHistoryPrompt(branchName string, commits []Commit, commitLogs string) string {
+	var sb strings.Builder
+	sb.WriteString(`Restructure the commit history of a git branch before it is merged. Output valid JSON only.
+
+Rules:
+- Group related commits into logical commits; keep unrelated changes apart
+- Fold WIP, typo and "fix previous commit" commits into the commit they fix, and list them in "fixups"
+- Write each message as a conventional commit (feat:, fix:, refactor:, test:, docs:, chore: ...): an imperative subject under 72 characters, then a blank line and a short body when useful
+- Use every original commit exactly once; keep their original order where you can, since reordering may cause conflicts
+- Order the new commits oldest first
+
+Output format:
+{"commits": [{"message": "feat(auth): add login form\n\nAdds the form and its validation.", "commits": ["a1b2c3d", "d4e5f6a"], "fixups": ["d4e5f6a"]}]}
+
+`)
+	sb.WriteString(fmt.Sprintf("Branch: %s\n\nCommits (oldest first):\n", branchName))
+	for _, c := range commits {
+		sb.WriteString(fmt.Sprintf("%s %s\n", c.Hash, c.Subject))
+	}
+	if commitLogs != "" {
+		sb.WriteString("\nFull commit messages (newest first):\n")
+		sb.WriteString(commitLogs)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// parseHistoryProposal parses Claude's response into the commits of plan.
+func parseHistoryProposal(plan *HistoryPlan, result string) error {
+	var resp historyProposal
+	if err := json.Unmarshal([]byte(stripMarkdownCodeBlock(result)), &resp); err != nil {
+		return fmt.Errorf("failed to parse history proposal: %w", err)
+	}
+	plan.Commits = nil
+	for _, c := range resp.Commits {
+		planned := PlannedCommit{Message: strings.TrimSpace(c.Message)}
+		for _, hash := range c.Commits {
+			if orig := plan.original(hash); orig != nil {
+				hash = orig.Hash
+			}
+			planned.Sources = append(planned.Sources, hash)
+			if containsPrefix(c.Fixups, hash) {
+				planned.Fixups = append(planned.Fixups, hash)
+			}
+		}
+		plan.Commits = append(plan.Commits, planned)
+	}
+	return plan.Validate()
+}
+
+// containsPrefix reports whether any of hashes is a prefix of hash or the
+// other way around.
+func containsPrefix(hashes []string, hash string) bool {
+	for _, h := range hashes {
+		if h != "" && (strings.HasPrefix(hash, h) || strings.HasPrefix(h, hash)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProposeHistory asks Claude to restructure a branch's commits on top of
+// its merge base into logical commits with conventional messages.
+func ProposeHistory(ctx context.Context, gitClient GitClient, branchName string) (*HistoryPlan, error) {
+	commits, err := gitClient.BranchCommits(ctx, branchName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("%s has no commits to clean up", branchName)
+	}
+	base, err := gitClient.MergeBase(ctx, branchName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base: %w", err)
+	}
+	head, err := gitClient.RevParse(ctx, branchName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", branchName, err)
+	}
+	commitLogs, _ := gitClient.GetBranchCommitLogs(ctx, branchName)
+
+	plan := &HistoryPlan{Base: base, Head: head, Original: commits}
+	result, err := claude.Query(ctx, buildHistoryPrompt(branchName, commits, commitLogs), &claude.QueryOptions{
+		Timeout: claude.DefaultTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claude query failed: %w", err)
+	}
+	if err := parseHistoryProposal(plan, result); err != nil {
+		return nil, fmt.Errorf("invalid history proposal: %w", err)
+	}
+	return plan, nil
+}
+
+// historyRebaseTodo builds the rebase todo list for a plan. Each new commit
+// picks its first source, fixes up the rest into it and sets its message.
+func historyRebaseTodo(plan *HistoryPlan, messageFiles []string) string {
+	var sb strings.Builder
+	for i, c := range plan.Commits {
+		for j, hash := range c.Sources {
+			verb := "pick"
+			if j > 0 {
+				verb = "fixup"
+			}
+			sb.WriteString(verb + " " + hash + "\n")
+		}
+		sb.WriteString("exec git commit --amend --no-verify --allow-empty --quiet -F " + shellQuote(messageFiles[i]) + "\n")
+	}
+	return sb.String()
+}
+
+// shellQuote quotes a path for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// RewriteHistory replaces the current branch's commits with the plan's
+// using a scripted, non-interactive rebase. The branch must still be at
+// the plan's head and the worktree clean. When the rebase fails, or the
+// result's content differs from the original, the branch is reset to the
+// original head.
+func (g *Git) RewriteHistory(ctx context.Context, plan *HistoryPlan) error {
+	if err := plan.Validate(); err != nil {
+		return err
+	}
+	hasChanges, err := g.HasUncommittedChanges(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check for uncommitted changes: %w", err)
+	}
+	if hasChanges {
+		return fmt.Errorf("commit or stash uncommitted changes first")
+	}
+	head, err := g.RevParse(ctx, "HEAD")
+	if err != nil {
+		return err
+	}
+	if head != plan.Head {
+		return fmt.Errorf("the branch has new commits since the history was proposed")
+	}
+
+	dir, err := os.MkdirTemp("", "ccells-history-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	messageFiles := make([]string, len(plan.Commits))
+	for i, c := range plan.Commits {
+		messageFiles[i] = filepath.Join(dir, fmt.Sprintf("message-%d", i))
+		if err := os.WriteFile(messageFiles[i], []byte(c.Message+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+	todoFile := filepath.Join(dir, "todo")
+	if err := os.WriteFile(todoFile, []byte(historyRebaseTodo(plan, messageFiles)), 0644); err != nil {
+		return err
+	}
+
+	// The sequence editor replaces git's todo list with ours
+	env := []string{"GIT_SEQUENCE_EDITOR=cp " + shellQuote(todoFile), "GIT_EDITOR=true"}
+	if _, err := g.runEnv(ctx, env, "rebase", "-i", "--no-autosquash", plan.Base); err != nil {
+		_, _ = g.run(ctx, "rebase", "--abort")
+		return g.restoreHead(ctx, plan.Head, fmt.Errorf("rebase failed: %w", err))
+	}
+
+	// Regrouping commits must not change the branch's content
+	newTree, err := g.run(ctx, "rev-parse", "HEAD^{tree}")
+	if err != nil {
+		return g.restoreHead(ctx, plan.Head, err)
+	}
+	oldTree, err := g.run(ctx, "rev-parse", plan.Head+"^{tree}")
+	if err != nil {
+		return g.restoreHead(ctx, plan.Head, err)
+	}
+	if newTree != oldTree {
+		return g.restoreHead(ctx, plan.Head, fmt.Errorf("the rewritten branch's content differs from the original"))
+	}
+	return nil
+}
+
+// restoreHead resets the branch to its original head after a failed rewrite
+// and returns cause, noting whether the reset worked.
+func (g *Git) restoreHead(ctx context.Context, head string, cause error) error {
+	if _, err := g.run(ctx, "reset", "--hard", "--quiet", head); err != nil {
+		return fmt.Errorf("%w; restoring %s also failed: %v", cause, shortSHA(head), err)
+	}
+	return fmt.Errorf("%w; branch restored to %s", cause, shortSHA(head))
+}
+
+// shortSHA abbreviates a commit SHA for messages.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
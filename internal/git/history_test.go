@@ -0,0 +1,155 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func testHistoryPlan() *HistoryPlan {
+	return &HistoryPlan{
+		Base: "base",
+		Head: "head",
+		Original: []Commit{
+			{Hash: "aaaa111", Subject: "Add login form"},
+			{Hash: "bbbb222", Subject: "wip"},
+			{Hash: "cccc333", Subject: "tests"},
+		},
+	}
+}
+
+func TestParseHistoryProposal(t *testing.T) {
+	plan := testHistoryPlan()
+	result := "```json\n" + `{"commits": [
+  {"message": "feat(auth): add login form\n\nAdds the form.", "commits": ["aaaa111", "bbbb"], "fixups": ["bbbb222"]},
+  {"message": "test(auth): cover login", "commits": ["cccc333"]}
+]}` + "\n```"
+	if err := parseHistoryProposal(plan, result); err != nil {
+		t.Fatalf("parseHistoryProposal() error = %v", err)
+	}
+	if len(plan.Commits) != 2 {
+		t.Fatalf("commits = %+v, want 2", plan.Commits)
+	}
+	first := plan.Commits[0]
+	if first.Message != "feat(auth): add login form\n\nAdds the form." || strings.Join(first.Sources, ",") != "aaaa111,bbbb222" {
+		t.Errorf("first commit = %+v", first)
+	}
+	if !first.IsFixup("bbbb222") || first.IsFixup("aaaa111") {
+		t.Errorf("fixups = %v, want bbbb222 flagged", first.Fixups)
+	}
+
+	// A proposal that drops a commit is rejected
+	plan = testHistoryPlan()
+	err := parseHistoryProposal(plan, `{"commits": [{"message": "feat: all", "commits": ["aaaa111", "bbbb222"]}]}`)
+	if err == nil || !strings.Contains(err.Error(), "cccc333 (tests) is missing") {
+		t.Errorf("error = %v, want the missing commit reported", err)
+	}
+}
+
+func TestFormatAndParseHistoryPlan(t *testing.T) {
+	plan := testHistoryPlan()
+	plan.Commits = []PlannedCommit{
+		{Message: "feat(auth): add login form\n\nAdds the form.", Sources: []string{"aaaa111", "bbbb222"}, Fixups: []string{"bbbb222"}},
+		{Message: "test(auth): cover login", Sources: []string{"cccc333"}},
+	}
+	text := FormatHistoryPlan(plan)
+	for _, want := range []string{"pick aaaa111 Add login form\nfixup bbbb222 wip\nfeat(auth): add login form\n\nAdds the form.\n", "pick cccc333 tests\ntest(auth): cover login\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatted plan missing %q:\n%s", want, text)
+		}
+	}
+
+	parsed, err := ParseHistoryPlan(plan, text)
+	if err != nil {
+		t.Fatalf("ParseHistoryPlan() error = %v", err)
+	}
+	if len(parsed.Commits) != 2 || parsed.Commits[0].Message != plan.Commits[0].Message || !parsed.Commits[0].IsFixup("bbbb222") {
+		t.Errorf("round trip = %+v, want %+v", parsed.Commits, plan.Commits)
+	}
+	if parsed.Base != "base" || parsed.Head != "head" {
+		t.Errorf("parsed plan lost its base and head: %+v", parsed)
+	}
+
+	// Edits: keep all three as separate commits, with a short hash
+	parsed, err = ParseHistoryPlan(plan, "pick aaaa Add login form\nfeat: form\n\npick bbbb222\nfix: form\npick cccc333\ntest: login\n")
+	if err != nil {
+		t.Fatalf("ParseHistoryPlan(edited) error = %v", err)
+	}
+	if len(parsed.Commits) != 3 || parsed.Commits[0].Sources[0] != "aaaa111" || parsed.Commits[1].Message != "fix: form" {
+		t.Errorf("edited plan = %+v", parsed.Commits)
+	}
+
+	for name, text := range map[string]string{
+		"no message":       "pick aaaa111\npick bbbb222\npick cccc333\n",
+		"duplicate":        "pick aaaa111\npick aaaa111\npick bbbb222\npick cccc333\nfeat: x\n",
+		"unknown commit":   "pick aaaa111\npick bbbb222\npick cccc333\npick dddd444\nfeat: x\n",
+		"message first":    "feat: x\npick aaaa111\npick bbbb222\npick cccc333\n",
+		"missing a commit": "pick aaaa111\npick bbbb222\nfeat: x\n",
+	} {
+		if _, err := ParseHistoryPlan(plan, text); err == nil {
+			t.Errorf("%s: ParseHistoryPlan() should fail", name)
+		}
+	}
+}
+
+func TestGit_RewriteHistory(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+
+	base, _ := g.CurrentBranch(ctx)
+	exec.Command("git", "-C", dir, "branch", "-m", base, "main").Run()
+	if err := g.CreateBranch(ctx, "feature"); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, dir, "feature", "a.txt", "a\n")
+	commitFile(t, dir, "feature", "b.txt", "b\n")
+	commitFile(t, dir, "feature", "a.txt", "a fixed\n")
+
+	plan := &HistoryPlan{}
+	plan.Base, _ = g.MergeBase(ctx, "feature")
+	plan.Head, _ = g.RevParse(ctx, "feature")
+	plan.Original, _ = g.BranchCommits(ctx, "feature")
+	c := plan.Original
+
+	// Put the fixup of a.txt first: it conflicts, so the branch is restored
+	plan.Commits = []PlannedCommit{
+		{Message: "fix: a", Sources: []string{c[2].Hash}},
+		{Message: "feat: add a and b", Sources: []string{c[0].Hash, c[1].Hash}},
+	}
+	if err := g.RewriteHistory(ctx, plan); err == nil || !strings.Contains(err.Error(), "branch restored") {
+		t.Errorf("RewriteHistory(conflicting) error = %v, want a restore", err)
+	}
+	if head, _ := g.RevParse(ctx, "HEAD"); head != plan.Head {
+		t.Fatalf("HEAD = %s after a failed rewrite, want the original %s", head, plan.Head)
+	}
+	if branch, _ := g.CurrentBranch(ctx); branch != "feature" {
+		t.Fatalf("branch = %q after a failed rewrite, want feature", branch)
+	}
+
+	plan.Commits = []PlannedCommit{
+		{Message: "feat: add a\n\nWith its fix.", Sources: []string{c[0].Hash, c[2].Hash}, Fixups: []string{c[2].Hash}},
+		{Message: "feat: add b", Sources: []string{c[1].Hash}},
+	}
+	if err := g.RewriteHistory(ctx, plan); err != nil {
+		t.Fatalf("RewriteHistory() error = %v", err)
+	}
+	commits, _ := g.BranchCommits(ctx, "feature")
+	if len(commits) != 2 || commits[0].Subject != "feat: add a" || commits[1].Subject != "feat: add b" {
+		t.Errorf("commits after rewrite = %+v", commits)
+	}
+	if body, _ := g.run(ctx, "log", "-1", "--format=%b", "HEAD~1"); body != "With its fix." {
+		t.Errorf("body = %q, want the planned message body", body)
+	}
+	if diff, _ := g.run(ctx, "diff", plan.Head, "HEAD"); diff != "" {
+		t.Errorf("the rewrite changed the branch's content:\n%s", diff)
+	}
+
+	// The old plan no longer matches the branch
+	if err := g.RewriteHistory(ctx, plan); err == nil || !strings.Contains(err.Error(), "new commits") {
+		t.Errorf("RewriteHistory(stale plan) error = %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package git
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // GitClient defines the interface for git operations.
 // This allows mocking git operations in tests without requiring a real git repository.
@@ -19,6 +22,7 @@ type GitClient interface {
 	GetBaseBranch(ctx context.Context) (string, error)
 	GetBranchInfo(ctx context.Context, branchName string) (string, error)
 	GetBranchCommitLogs(ctx context.Context, branchName string) (string, error)
+	RevParse(ctx context.Context, ref string) (string, error)
 
 	// Working directory operations
 	HasUncommittedChanges(ctx context.Context) (bool, error)
@@ -41,12 +45,37 @@ type GitClient interface {
 	MergeBranch(ctx context.Context, branch string) error
 	MergeBranchWithOptions(ctx context.Context, branch string, squash bool) error
 	RebaseBranch(ctx context.Context, branch string) error
+	RebaseOntoBase(ctx context.Context, branch string) error
+	RebaseOnto(ctx context.Context, upstream string) error
+	IsAncestor(ctx context.Context, ancestor, ref string) (bool, error)
 	AbortRebase(ctx context.Context) error
+	CherryPick(ctx context.Context, commits []string) error
 	GetConflictFiles(ctx context.Context) ([]string, error)
+	ChangedFiles(ctx context.Context, branch string) ([]string, error)
+	MergeTreeConflicts(ctx context.Context, branchA, branchB string) ([]string, error)
+	RewriteHistory(ctx context.Context, plan *HistoryPlan) error
+
+	// Diff operations
+	MergeBase(ctx context.Context, branch string) (string, error)
+	BranchCommits(ctx context.Context, branch string) ([]Commit, error)
+	CommitsAhead(ctx context.Context, branch string) (int, error)
+	DiffShortStat(ctx context.Context, branch string) (string, error)
+	Diff(ctx context.Context, from, to string) (string, error)
+
+	// WIP checkpoints
+	CreateCheckpoint(ctx context.Context, branch string) (*Checkpoint, error)
+	ListCheckpoints(ctx context.Context, branch string) ([]Checkpoint, error)
+	RestoreCheckpoint(ctx context.Context, cp Checkpoint) error
+	PruneCheckpoints(ctx context.Context, keep int, maxAge time.Duration) (int, error)
+	SnapshotWorktree(ctx context.Context, ref string) (string, error)
+	DeleteRef(ctx context.Context, ref string) error
 
 	// Worktree operations
 	CreateWorktree(ctx context.Context, worktreePath, branchName string) error
 	CreateWorktreeFromExisting(ctx context.Context, worktreePath, branchName string) error
+	CreateWorktreeFromBase(ctx context.Context, worktreePath, branchName, baseBranch string) error
+	CreateSparseWorktree(ctx context.Context, worktreePath, branchName, startPoint string, createBranch bool, paths []string) error
+	AddSparseCheckoutPaths(ctx context.Context, paths []string) error
 	RemoveWorktree(ctx context.Context, worktreePath string) error
 	WorktreeList(ctx context.Context) ([]string, error)
 	WorktreeExistsForBranch(ctx context.Context, branchName string) (string, bool)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 )
 
 // MockGitClient is a mock implementation of GitClient for testing.
@@ -37,6 +38,7 @@ type MockGitClient struct {
 	GetBaseBranchFn              func(ctx context.Context) (string, error)
 	GetBranchInfoFn              func(ctx context.Context, branchName string) (string, error)
 	GetBranchCommitLogsFn        func(ctx context.Context, branchName string) (string, error)
+	RevParseFn                   func(ctx context.Context, ref string) (string, error)
 	HasUncommittedChangesFn      func(ctx context.Context) (bool, error)
 	GetUntrackedFilesFn          func(ctx context.Context) ([]string, error)
 	StashFn                      func(ctx context.Context) error
@@ -53,10 +55,31 @@ type MockGitClient struct {
 	MergeBranchFn                func(ctx context.Context, branch string) error
 	MergeBranchWithOptionsFn     func(ctx context.Context, branch string, squash bool) error
 	RebaseBranchFn               func(ctx context.Context, branch string) error
+	RebaseOntoBaseFn             func(ctx context.Context, branch string) error
+	RebaseOntoFn                 func(ctx context.Context, upstream string) error
+	IsAncestorFn                 func(ctx context.Context, ancestor, ref string) (bool, error)
 	AbortRebaseFn                func(ctx context.Context) error
+	CherryPickFn                 func(ctx context.Context, commits []string) error
 	GetConflictFilesFn           func(ctx context.Context) ([]string, error)
+	ChangedFilesFn               func(ctx context.Context, branch string) ([]string, error)
+	MergeTreeConflictsFn         func(ctx context.Context, branchA, branchB string) ([]string, error)
+	RewriteHistoryFn             func(ctx context.Context, plan *HistoryPlan) error
+	MergeBaseFn                  func(ctx context.Context, branch string) (string, error)
+	BranchCommitsFn              func(ctx context.Context, branch string) ([]Commit, error)
+	CommitsAheadFn               func(ctx context.Context, branch string) (int, error)
+	DiffShortStatFn              func(ctx context.Context, branch string) (string, error)
+	DiffFn                       func(ctx context.Context, from, to string) (string, error)
+	CreateCheckpointFn           func(ctx context.Context, branch string) (*Checkpoint, error)
+	ListCheckpointsFn            func(ctx context.Context, branch string) ([]Checkpoint, error)
+	RestoreCheckpointFn          func(ctx context.Context, cp Checkpoint) error
+	PruneCheckpointsFn           func(ctx context.Context, keep int, maxAge time.Duration) (int, error)
+	SnapshotWorktreeFn           func(ctx context.Context, ref string) (string, error)
+	DeleteRefFn                  func(ctx context.Context, ref string) error
 	CreateWorktreeFn             func(ctx context.Context, worktreePath, branchName string) error
 	CreateWorktreeFromExistingFn func(ctx context.Context, worktreePath, branchName string) error
+	CreateWorktreeFromBaseFn     func(ctx context.Context, worktreePath, branchName, baseBranch string) error
+	CreateSparseWorktreeFn       func(ctx context.Context, worktreePath, branchName, startPoint string, createBranch bool, paths []string) error
+	AddSparseCheckoutPathsFn     func(ctx context.Context, paths []string) error
 	RemoveWorktreeFn             func(ctx context.Context, worktreePath string) error
 	WorktreeListFn               func(ctx context.Context) ([]string, error)
 	WorktreeExistsForBranchFn    func(ctx context.Context, branchName string) (string, bool)
@@ -328,6 +351,22 @@ func (m *MockGitClient) GetBranchCommitLogs(ctx context.Context, branchName stri
 	return "", nil
 }
 
+func (m *MockGitClient) RevParse(ctx context.Context, ref string) (string, error) {
+	if m.Err != nil {
+		return "", m.Err
+	}
+	if m.RevParseFn != nil {
+		return m.RevParseFn(ctx, ref)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.branches[ref] {
+		return "", fmt.Errorf("unknown revision %s", ref)
+	}
+	// Default: a stable fake SHA derived from the branch name
+	return fmt.Sprintf("%040x", []byte(ref))[:40], nil
+}
+
 // Working directory operations
 
 func (m *MockGitClient) HasUncommittedChanges(ctx context.Context) (bool, error) {
@@ -517,6 +556,36 @@ func (m *MockGitClient) RebaseBranch(ctx context.Context, branch string) error {
 	return nil
 }
 
+func (m *MockGitClient) RebaseOntoBase(ctx context.Context, branch string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.RebaseOntoBaseFn != nil {
+		return m.RebaseOntoBaseFn(ctx, branch)
+	}
+	return nil
+}
+
+func (m *MockGitClient) RebaseOnto(ctx context.Context, upstream string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.RebaseOntoFn != nil {
+		return m.RebaseOntoFn(ctx, upstream)
+	}
+	return nil
+}
+
+func (m *MockGitClient) IsAncestor(ctx context.Context, ancestor, ref string) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	if m.IsAncestorFn != nil {
+		return m.IsAncestorFn(ctx, ancestor, ref)
+	}
+	return false, nil
+}
+
 func (m *MockGitClient) AbortRebase(ctx context.Context) error {
 	if m.Err != nil {
 		return m.Err
@@ -527,6 +596,16 @@ func (m *MockGitClient) AbortRebase(ctx context.Context) error {
 	return nil
 }
 
+func (m *MockGitClient) CherryPick(ctx context.Context, commits []string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.CherryPickFn != nil {
+		return m.CherryPickFn(ctx, commits)
+	}
+	return nil
+}
+
 func (m *MockGitClient) GetConflictFiles(ctx context.Context) ([]string, error) {
 	if m.Err != nil {
 		return nil, m.Err
@@ -537,8 +616,150 @@ func (m *MockGitClient) GetConflictFiles(ctx context.Context) ([]string, error)
 	return nil, nil
 }
 
+func (m *MockGitClient) ChangedFiles(ctx context.Context, branch string) ([]string, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if m.ChangedFilesFn != nil {
+		return m.ChangedFilesFn(ctx, branch)
+	}
+	return nil, nil
+}
+
+func (m *MockGitClient) MergeTreeConflicts(ctx context.Context, branchA, branchB string) ([]string, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if m.MergeTreeConflictsFn != nil {
+		return m.MergeTreeConflictsFn(ctx, branchA, branchB)
+	}
+	return nil, nil
+}
+
+func (m *MockGitClient) RewriteHistory(ctx context.Context, plan *HistoryPlan) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.RewriteHistoryFn != nil {
+		return m.RewriteHistoryFn(ctx, plan)
+	}
+	return nil
+}
+
+// Diff operations
+
+func (m *MockGitClient) MergeBase(ctx context.Context, branch string) (string, error) {
+	if m.Err != nil {
+		return "", m.Err
+	}
+	if m.MergeBaseFn != nil {
+		return m.MergeBaseFn(ctx, branch)
+	}
+	return "", nil
+}
+
+func (m *MockGitClient) BranchCommits(ctx context.Context, branch string) ([]Commit, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if m.BranchCommitsFn != nil {
+		return m.BranchCommitsFn(ctx, branch)
+	}
+	return nil, nil
+}
+
+func (m *MockGitClient) CommitsAhead(ctx context.Context, branch string) (int, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	if m.CommitsAheadFn != nil {
+		return m.CommitsAheadFn(ctx, branch)
+	}
+	return 0, nil
+}
+
+func (m *MockGitClient) DiffShortStat(ctx context.Context, branch string) (string, error) {
+	if m.Err != nil {
+		return "", m.Err
+	}
+	if m.DiffShortStatFn != nil {
+		return m.DiffShortStatFn(ctx, branch)
+	}
+	return "", nil
+}
+
+func (m *MockGitClient) Diff(ctx context.Context, from, to string) (string, error) {
+	if m.Err != nil {
+		return "", m.Err
+	}
+	if m.DiffFn != nil {
+		return m.DiffFn(ctx, from, to)
+	}
+	return "", nil
+}
+
 // Worktree operations
 
+func (m *MockGitClient) CreateCheckpoint(ctx context.Context, branch string) (*Checkpoint, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if m.CreateCheckpointFn != nil {
+		return m.CreateCheckpointFn(ctx, branch)
+	}
+	return nil, nil
+}
+
+func (m *MockGitClient) ListCheckpoints(ctx context.Context, branch string) ([]Checkpoint, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if m.ListCheckpointsFn != nil {
+		return m.ListCheckpointsFn(ctx, branch)
+	}
+	return nil, nil
+}
+
+func (m *MockGitClient) RestoreCheckpoint(ctx context.Context, cp Checkpoint) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.RestoreCheckpointFn != nil {
+		return m.RestoreCheckpointFn(ctx, cp)
+	}
+	return nil
+}
+
+func (m *MockGitClient) PruneCheckpoints(ctx context.Context, keep int, maxAge time.Duration) (int, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	if m.PruneCheckpointsFn != nil {
+		return m.PruneCheckpointsFn(ctx, keep, maxAge)
+	}
+	return 0, nil
+}
+
+func (m *MockGitClient) SnapshotWorktree(ctx context.Context, ref string) (string, error) {
+	if m.Err != nil {
+		return "", m.Err
+	}
+	if m.SnapshotWorktreeFn != nil {
+		return m.SnapshotWorktreeFn(ctx, ref)
+	}
+	return "", nil
+}
+
+func (m *MockGitClient) DeleteRef(ctx context.Context, ref string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.DeleteRefFn != nil {
+		return m.DeleteRefFn(ctx, ref)
+	}
+	return nil
+}
+
 func (m *MockGitClient) CreateWorktree(ctx context.Context, worktreePath, branchName string) error {
 	if m.Err != nil {
 		return m.Err
@@ -572,6 +793,60 @@ func (m *MockGitClient) CreateWorktreeFromExisting(ctx context.Context, worktree
 	return nil
 }
 
+func (m *MockGitClient) CreateWorktreeFromBase(ctx context.Context, worktreePath, branchName, baseBranch string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.CreateWorktreeFromBaseFn != nil {
+		return m.CreateWorktreeFromBaseFn(ctx, worktreePath, branchName, baseBranch)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.branches[baseBranch] {
+		return fmt.Errorf("base branch %s does not exist", baseBranch)
+	}
+	if m.branches[branchName] {
+		return fmt.Errorf("branch %s already exists", branchName)
+	}
+	m.branches[branchName] = true
+	m.worktrees[worktreePath] = branchName
+	return nil
+}
+
+func (m *MockGitClient) CreateSparseWorktree(ctx context.Context, worktreePath, branchName, startPoint string, createBranch bool, paths []string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.CreateSparseWorktreeFn != nil {
+		return m.CreateSparseWorktreeFn(ctx, worktreePath, branchName, startPoint, createBranch, paths)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if createBranch {
+		if startPoint != "" && !m.branches[startPoint] {
+			return fmt.Errorf("base branch %s does not exist", startPoint)
+		}
+		if m.branches[branchName] {
+			return fmt.Errorf("branch %s already exists", branchName)
+		}
+		m.branches[branchName] = true
+	} else if !m.branches[branchName] {
+		return fmt.Errorf("branch %s does not exist", branchName)
+	}
+	m.worktrees[worktreePath] = branchName
+	return nil
+}
+
+func (m *MockGitClient) AddSparseCheckoutPaths(ctx context.Context, paths []string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.AddSparseCheckoutPathsFn != nil {
+		return m.AddSparseCheckoutPathsFn(ctx, paths)
+	}
+	return nil
+}
+
 func (m *MockGitClient) RemoveWorktree(ctx context.Context, worktreePath string) error {
 	if m.Err != nil {
 		return m.Err
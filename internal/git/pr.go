@@ -33,6 +33,7 @@ type PRStatusInfo struct {
 	UnpushedCount int           // Local commits not in PR
 	DivergedCount int           // Remote commits not in local
 	IsDiverged    bool          // True if remote has commits not in local
+	Forge         string        // Forge kind the PR is on, e.g. ForgeGitHub
 }
 
 // GH wraps the GitHub CLI for PR operations.
@@ -43,6 +44,11 @@ func NewGH() *GH {
 	return &GH{}
 }
 
+// Name returns ForgeGitHub.
+func (g *GH) Name() string {
+	return ForgeGitHub
+}
+
 // CheckInstalled verifies gh CLI is available.
 func (g *GH) CheckInstalled(ctx context.Context) error {
 	cmd := exec.CommandContext(ctx, "gh", "--version")
@@ -51,11 +57,15 @@ func (g *GH) CheckInstalled(ctx context.Context) error {
 
 // PRRequest contains data for creating a PR.
 type PRRequest struct {
-	Title string
-	Body  string
-	Head  string // Branch to create PR from (required for worktrees)
-	Base  string // Optional, defaults to default branch
-	Draft bool
+	Title     string
+	Body      string
+	Head      string // Branch to create PR from (required for worktrees)
+	Base      string // Optional, defaults to default branch
+	Draft     bool
+	Reviewers []string // Users or org/team names
+	Labels    []string
+	Assignees []string // "@me" assigns yourself
+	Milestone string   // Milestone name
 }
 
 // PRResponse contains the created PR info.
@@ -101,6 +111,18 @@ func (g *GH) CreatePR(ctx context.Context, repoPath string, req *PRRequest) (*PR
 	if req.Draft {
 		args = append(args, "--draft")
 	}
+	if len(req.Reviewers) > 0 {
+		args = append(args, "--reviewer", strings.Join(req.Reviewers, ","))
+	}
+	if len(req.Labels) > 0 {
+		args = append(args, "--label", strings.Join(req.Labels, ","))
+	}
+	if len(req.Assignees) > 0 {
+		args = append(args, "--assignee", strings.Join(req.Assignees, ","))
+	}
+	if req.Milestone != "" {
+		args = append(args, "--milestone", req.Milestone)
+	}
 
 	cmd := exec.CommandContext(ctx, "gh", args...)
 	cmd.Dir = repoPath
@@ -196,29 +218,38 @@ func (g *GH) GetPRStatus(ctx context.Context, repoPath string, gitClient GitClie
 		HeadSHA:       resp.HeadRefOid,
 		CheckStatus:   checkStatus,
 		ChecksSummary: checksSummary,
+		Forge:         ForgeGitHub,
 	}
 
 	// Compare local commits with PR's remote head
-	if gitClient != nil && resp.HeadRefName != "" {
-		// Get unpushed commits: commits in local branch but not in origin/<branch>
-		unpushed, err := gitClient.GetUnpushedCommitCount(ctx, resp.HeadRefName)
-		if err != nil {
-			log.Printf("GetPRStatus: failed to get unpushed commit count for %s: %v", resp.HeadRefName, err)
-		} else {
-			status.UnpushedCount = unpushed
-		}
+	compareLocalBranch(ctx, gitClient, resp.HeadRefName, status)
 
-		// Get diverged commits: commits in origin/<branch> but not in local
-		diverged, err := gitClient.GetDivergedCommitCount(ctx, resp.HeadRefName)
-		if err != nil {
-			log.Printf("GetPRStatus: failed to get diverged commit count for %s: %v", resp.HeadRefName, err)
-		} else {
-			status.DivergedCount = diverged
-			status.IsDiverged = diverged > 0
-		}
+	return status, nil
+}
+
+// compareLocalBranch fills in how the local branch compares with its remote
+// copy: commits not pushed yet and remote commits not in the local branch.
+func compareLocalBranch(ctx context.Context, gitClient GitClient, branch string, status *PRStatusInfo) {
+	if gitClient == nil || branch == "" {
+		return
 	}
 
-	return status, nil
+	// Get unpushed commits: commits in local branch but not in origin/<branch>
+	unpushed, err := gitClient.GetUnpushedCommitCount(ctx, branch)
+	if err != nil {
+		log.Printf("GetPRStatus: failed to get unpushed commit count for %s: %v", branch, err)
+	} else {
+		status.UnpushedCount = unpushed
+	}
+
+	// Get diverged commits: commits in origin/<branch> but not in local
+	diverged, err := gitClient.GetDivergedCommitCount(ctx, branch)
+	if err != nil {
+		log.Printf("GetPRStatus: failed to get diverged commit count for %s: %v", branch, err)
+	} else {
+		status.DivergedCount = diverged
+		status.IsDiverged = diverged > 0
+	}
 }
 
 // aggregateCheckStatus converts the statusCheckRollup into a simple status and summary.
@@ -303,11 +334,16 @@ func stripMarkdownCodeBlock(s string) string {
 }
 
 // GeneratePRContent uses Claude to generate a PR title and description based on
-// the branch commits and workstream context. Returns sensible defaults on failure.
-func GeneratePRContent(ctx context.Context, gitClient GitClient, branchName, workstreamPrompt string) (title, body string) {
+// the branch commits and workstream context. When template is set, the
+// description fills in the repository's PR template. Returns sensible
+// defaults on failure.
+func GeneratePRContent(ctx context.Context, gitClient GitClient, branchName, workstreamPrompt, template string) (title, body string) {
 	// Default fallbacks
 	defaultTitle := branchNameToTitle(branchName)
 	defaultBody := fmt.Sprintf("## Summary\n\n%s\n\n## Changes\n\nCreated by [claude-cells](https://github.com/STRML/claude-cells).", workstreamPrompt)
+	if template != "" {
+		defaultBody = fillPRTemplate(template, workstreamPrompt)
+	}
 
 	// Get commit logs for context
 	commitLogs, err := gitClient.GetBranchCommitLogs(ctx, branchName)
@@ -320,7 +356,7 @@ func GeneratePRContent(ctx context.Context, gitClient GitClient, branchName, wor
 	branchInfo, _ := gitClient.GetBranchInfo(ctx, branchName)
 
 	// Build the prompt
-	prompt := buildPRPrompt(branchName, workstreamPrompt, commitLogs, branchInfo)
+	prompt := buildPRPrompt(branchName, workstreamPrompt, commitLogs, branchInfo, template)
 
 	// Query Claude (now always uses JSON output internally and extracts result)
 	result, err := claude.Query(ctx, prompt, &claude.QueryOptions{
@@ -358,7 +394,7 @@ func GeneratePRContent(ctx context.Context, gitClient GitClient, branchName, wor
 }
 
 // buildPRPrompt constructs the prompt for PR content generation.
-func buildPRPrompt(branchName, workstreamPrompt, commitLogs, branchInfo string) string {
+func buildPRPrompt(branchName, workstreamPrompt, commitLogs, branchInfo, template string) string {
 	var sb strings.Builder
 
 	sb.WriteString(`Generate a GitHub PR title and description. Output valid JSON only.
@@ -371,13 +407,28 @@ Rules for title:
 - Under 72 characters
 - No period at the end
 
-Rules for body:
+`)
+
+	if template != "" {
+		sb.WriteString(`Rules for body:
+- Fill in the repository's PR template below, keeping its headings, order and checklists
+- Replace placeholder text and HTML comments with content about this change
+- Only tick checklist items the change clearly satisfies
+- Write "N/A" under sections that don't apply
+
+PR template:
+`)
+		sb.WriteString(template)
+		sb.WriteString("\n\n")
+	} else {
+		sb.WriteString(`Rules for body:
 - Start with "## Summary" section with 2-3 bullet points
 - Include "## Changes" section listing key modifications
 - Keep it scannable and concise
 - Use markdown formatting
 
 `)
+	}
 
 	sb.WriteString(fmt.Sprintf("Branch: %s\n\n", branchName))
 
@@ -3,6 +3,7 @@ package git
 import (
 	"context"
 	"encoding/json"
+	"os"
 	"strings"
 	"testing"
 )
@@ -218,7 +219,7 @@ func TestBuildPRPrompt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := buildPRPrompt(tt.branchName, tt.workstreamPrompt, tt.commitLogs, tt.branchInfo)
+			result := buildPRPrompt(tt.branchName, tt.workstreamPrompt, tt.commitLogs, tt.branchInfo, "")
 
 			for _, want := range tt.wantContains {
 				if !strings.Contains(result, want) {
@@ -705,3 +706,31 @@ func TestStripMarkdownCodeBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestGH_CreatePR_Options(t *testing.T) {
+	argsFile := fakeCommand(t, "gh", "https://github.com/o/r/pull/12\n")
+
+	pr, err := NewGH().CreatePR(context.Background(), t.TempDir(), &PRRequest{
+		Title:     "Add login",
+		Body:      "body",
+		Head:      "feature",
+		Base:      "develop",
+		Draft:     true,
+		Reviewers: []string{"alice", "org/team"},
+		Labels:    []string{"auth"},
+		Assignees: []string{"@me"},
+		Milestone: "v2",
+	})
+	if err != nil {
+		t.Fatalf("CreatePR() error = %v", err)
+	}
+	if pr.Number != 12 {
+		t.Errorf("PR number = %d, want 12", pr.Number)
+	}
+	args, _ := os.ReadFile(argsFile)
+	for _, want := range []string{"--base\ndevelop\n", "--draft\n", "--reviewer\nalice,org/team\n", "--label\nauth\n", "--assignee\n@me\n", "--milestone\nv2\n"} {
+		if !strings.Contains(string(args), want) {
+			t.Errorf("gh args missing %q:\n%s", want, args)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PRTemplate is a pull request template found in a repository.
+type PRTemplate struct {
+	Name    string // Path relative to the repository root
+	Content string
+}
+
+// prTemplateDirs are the directories GitHub looks for PR templates in.
+var prTemplateDirs = []string{".github", "", "docs"}
+
+// isPRTemplateFile reports whether name is a template file name GitHub accepts.
+func isPRTemplateFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".md" || ext == ".txt"
+}
+
+// FindPRTemplates returns the repository's pull request templates: a single
+// pull_request_template.md (the default) first, then the templates in
+// PULL_REQUEST_TEMPLATE/ directories. Names are matched case-insensitively
+// like GitHub does.
+func FindPRTemplates(repoPath string) []PRTemplate {
+	var single, multiple []PRTemplate
+	for _, dir := range prTemplateDirs {
+		entries, err := os.ReadDir(filepath.Join(repoPath, dir))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			rel := filepath.Join(dir, e.Name())
+			switch {
+			case !e.IsDir() && isPRTemplateFile(e.Name()) &&
+				strings.EqualFold(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())), "pull_request_template"):
+				if t, ok := readPRTemplate(repoPath, rel); ok {
+					single = append(single, t)
+				}
+			case e.IsDir() && strings.EqualFold(e.Name(), "PULL_REQUEST_TEMPLATE"):
+				files, err := os.ReadDir(filepath.Join(repoPath, rel))
+				if err != nil {
+					continue
+				}
+				for _, f := range files {
+					if !f.IsDir() && isPRTemplateFile(f.Name()) {
+						if t, ok := readPRTemplate(repoPath, filepath.Join(rel, f.Name())); ok {
+							multiple = append(multiple, t)
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.SliceStable(multiple, func(i, j int) bool { return multiple[i].Name < multiple[j].Name })
+	return append(single, multiple...)
+}
+
+// readPRTemplate reads a template file relative to the repository root.
+func readPRTemplate(repoPath, rel string) (PRTemplate, bool) {
+	data, err := os.ReadFile(filepath.Join(repoPath, rel))
+	if err != nil {
+		return PRTemplate{}, false
+	}
+	return PRTemplate{Name: filepath.ToSlash(rel), Content: string(data)}, true
+}
+
+// fillPRTemplate puts a summary into a template without Claude: right after
+// its first heading, or at the top when it has none.
+func fillPRTemplate(template, summary string) string {
+	lines := strings.Split(template, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			rest := append([]string{"", summary}, lines[i+1:]...)
+			return strings.Join(append(lines[:i+1], rest...), "\n")
+		}
+	}
+	return summary + "\n\n" + template
+}
@@ -0,0 +1,70 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindPRTemplates(t *testing.T) {
+	repo := t.TempDir()
+	files := map[string]string{
+		".github/PULL_REQUEST_TEMPLATE.md":         "## Summary\n",
+		".github/PULL_REQUEST_TEMPLATE/feature.md": "## Feature\n",
+		".github/PULL_REQUEST_TEMPLATE/bug_fix.md": "## Bug\n",
+		".github/PULL_REQUEST_TEMPLATE/notes.json": "{}",
+		"docs/pull_request_template/other.txt":     "docs template",
+		"docs/unrelated.md":                        "not a template",
+	}
+	for name, content := range files {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var names []string
+	for _, tmpl := range FindPRTemplates(repo) {
+		names = append(names, tmpl.Name)
+	}
+	want := []string{
+		".github/PULL_REQUEST_TEMPLATE.md",
+		".github/PULL_REQUEST_TEMPLATE/bug_fix.md",
+		".github/PULL_REQUEST_TEMPLATE/feature.md",
+		"docs/pull_request_template/other.txt",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("FindPRTemplates() = %v, want %v", names, want)
+	}
+
+	if got := FindPRTemplates(t.TempDir()); len(got) != 0 {
+		t.Errorf("FindPRTemplates() without templates = %v", got)
+	}
+}
+
+func TestFillPRTemplate(t *testing.T) {
+	got := fillPRTemplate("<!-- intro -->\n## What\n\n## Testing\n", "Add login")
+	if want := "<!-- intro -->\n## What\n\nAdd login\n\n## Testing\n"; got != want {
+		t.Errorf("fillPRTemplate() = %q, want %q", got, want)
+	}
+	if got := fillPRTemplate("- [ ] tested", "Add login"); got != "Add login\n\n- [ ] tested" {
+		t.Errorf("fillPRTemplate() without headings = %q", got)
+	}
+}
+
+func TestBuildPRPrompt_Template(t *testing.T) {
+	prompt := buildPRPrompt("feature", "Add login", "", "", "## What\n- [ ] Tests added")
+	for _, want := range []string{"Fill in the repository's PR template", "PR template:\n## What\n- [ ] Tests added"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, `Start with "## Summary"`) {
+		t.Error("the default body rules should not apply with a template")
+	}
+}
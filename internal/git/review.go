@@ -0,0 +1,221 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// PR review states as reported by GitHub.
+const (
+	PRReviewApproved         = "APPROVED"
+	PRReviewChangesRequested = "CHANGES_REQUESTED"
+	PRReviewCommented        = "COMMENTED"
+	PRReviewPending          = "PENDING"
+)
+
+// PRReview is a review submitted on a pull request.
+type PRReview struct {
+	Author      string
+	State       string // APPROVED, CHANGES_REQUESTED, COMMENTED, ...
+	Body        string
+	SubmittedAt time.Time
+}
+
+// PRReviewComment is a single comment in a review thread.
+type PRReviewComment struct {
+	Author    string
+	Body      string
+	DiffHunk  string // Diff context the comment was left on
+	CreatedAt time.Time
+}
+
+// PRReviewThread is a conversation on a file or line of a pull request.
+type PRReviewThread struct {
+	Path       string
+	Line       int // 0 for comments on the whole file
+	IsResolved bool
+	IsOutdated bool // The commented code changed since
+	Comments   []PRReviewComment
+}
+
+// PRReviews contains the review feedback on a pull request.
+type PRReviews struct {
+	Number   int
+	Decision string // Overall review decision, e.g. CHANGES_REQUESTED
+	Reviews  []PRReview
+	Threads  []PRReviewThread
+}
+
+// Unresolved returns the review threads that have not been resolved.
+func (r *PRReviews) Unresolved() []PRReviewThread {
+	var threads []PRReviewThread
+	for _, t := range r.Threads {
+		if !t.IsResolved && len(t.Comments) > 0 {
+			threads = append(threads, t)
+		}
+	}
+	return threads
+}
+
+// ChangesRequested returns the reviews still requesting changes: each
+// reviewer's latest review that approved, requested changes or was
+// dismissed decides, so a request followed by an approval no longer counts.
+// Comment-only reviews don't change a reviewer's verdict.
+func (r *PRReviews) ChangesRequested() []PRReview {
+	latest := make(map[string]int) // Author -> index of their deciding review
+	for i, rv := range r.Reviews {
+		if rv.State == PRReviewCommented || rv.State == PRReviewPending {
+			continue
+		}
+		if j, ok := latest[rv.Author]; ok && r.Reviews[j].SubmittedAt.After(rv.SubmittedAt) {
+			continue
+		}
+		latest[rv.Author] = i
+	}
+	var reviews []PRReview
+	for i, rv := range r.Reviews {
+		if rv.State == PRReviewChangesRequested && latest[rv.Author] == i {
+			reviews = append(reviews, rv)
+		}
+	}
+	return reviews
+}
+
+// LatestActivity returns when the most recent review or comment was left.
+func (r *PRReviews) LatestActivity() time.Time {
+	var latest time.Time
+	for _, rv := range r.Reviews {
+		if rv.SubmittedAt.After(latest) {
+			latest = rv.SubmittedAt
+		}
+	}
+	for _, t := range r.Threads {
+		for _, c := range t.Comments {
+			if c.CreatedAt.After(latest) {
+				latest = c.CreatedAt
+			}
+		}
+	}
+	return latest
+}
+
+// prReviewsQuery fetches reviews and review threads of a pull request.
+// The gh CLI fills in {owner} and {repo} from the current repository.
+const prReviewsQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewDecision
+      reviews(last: 50) {
+        nodes { author { login } state body submittedAt }
+      }
+      reviewThreads(first: 100) {
+        nodes {
+          isResolved isOutdated path line originalLine
+          comments(first: 50) {
+            nodes { author { login } body createdAt diffHunk }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type prAuthor struct {
+	Login string `json:"login"`
+}
+
+// prReviewsResponse is the GraphQL response for prReviewsQuery.
+type prReviewsResponse struct {
+	Data struct {
+		Repository struct {
+			PullRequest *struct {
+				ReviewDecision string `json:"reviewDecision"`
+				Reviews        struct {
+					Nodes []struct {
+						Author      prAuthor  `json:"author"`
+						State       string    `json:"state"`
+						Body        string    `json:"body"`
+						SubmittedAt time.Time `json:"submittedAt"`
+					} `json:"nodes"`
+				} `json:"reviews"`
+				ReviewThreads struct {
+					Nodes []struct {
+						IsResolved   bool   `json:"isResolved"`
+						IsOutdated   bool   `json:"isOutdated"`
+						Path         string `json:"path"`
+						Line         int    `json:"line"`
+						OriginalLine int    `json:"originalLine"`
+						Comments     struct {
+							Nodes []struct {
+								Author    prAuthor  `json:"author"`
+								Body      string    `json:"body"`
+								CreatedAt time.Time `json:"createdAt"`
+								DiffHunk  string    `json:"diffHunk"`
+							} `json:"nodes"`
+						} `json:"comments"`
+					} `json:"nodes"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+}
+
+// GetPRReviews retrieves the reviews and review threads of a pull request.
+func (g *GH) GetPRReviews(ctx context.Context, repoPath string, number int) (*PRReviews, error) {
+	cmd := exec.CommandContext(ctx, "gh", "api", "graphql",
+		"-F", "owner={owner}", "-F", "repo={repo}", "-F", fmt.Sprintf("number=%d", number),
+		"-f", "query="+prReviewsQuery)
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("gh api graphql failed: %w", err)
+	}
+	return parsePRReviews(number, out)
+}
+
+// parsePRReviews converts a prReviewsQuery response into PRReviews.
+func parsePRReviews(number int, data []byte) (*PRReviews, error) {
+	var resp prReviewsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse PR reviews: %w", err)
+	}
+	pr := resp.Data.Repository.PullRequest
+	if pr == nil {
+		return nil, fmt.Errorf("PR #%d not found", number)
+	}
+
+	reviews := &PRReviews{Number: number, Decision: pr.ReviewDecision}
+	for _, n := range pr.Reviews.Nodes {
+		reviews.Reviews = append(reviews.Reviews, PRReview{
+			Author:      n.Author.Login,
+			State:       n.State,
+			Body:        n.Body,
+			SubmittedAt: n.SubmittedAt,
+		})
+	}
+	for _, n := range pr.ReviewThreads.Nodes {
+		thread := PRReviewThread{
+			Path:       n.Path,
+			Line:       n.Line,
+			IsResolved: n.IsResolved,
+			IsOutdated: n.IsOutdated,
+		}
+		// Outdated threads no longer have a line on the current diff
+		if thread.Line == 0 {
+			thread.Line = n.OriginalLine
+		}
+		for _, c := range n.Comments.Nodes {
+			thread.Comments = append(thread.Comments, PRReviewComment{
+				Author:    c.Author.Login,
+				Body:      c.Body,
+				DiffHunk:  c.DiffHunk,
+				CreatedAt: c.CreatedAt,
+			})
+		}
+		reviews.Threads = append(reviews.Threads, thread)
+	}
+	return reviews, nil
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGH_GetPRReviews(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "pr_reviews.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	argsFile := fakeCommand(t, "gh", string(fixture))
+
+	reviews, err := NewGH().GetPRReviews(context.Background(), t.TempDir(), 42)
+	if err != nil {
+		t.Fatalf("GetPRReviews() error = %v", err)
+	}
+	args, _ := os.ReadFile(argsFile)
+	for _, want := range []string{"api\ngraphql\n", "owner={owner}", "number=42"} {
+		if !strings.Contains(string(args), want) {
+			t.Errorf("gh args missing %q:\n%s", want, args)
+		}
+	}
+
+	if reviews.Number != 42 || reviews.Decision != PRReviewChangesRequested {
+		t.Errorf("got PR #%d decision %q", reviews.Number, reviews.Decision)
+	}
+	unresolved := reviews.Unresolved()
+	if len(unresolved) != 2 {
+		t.Fatalf("got %d unresolved threads, want 2", len(unresolved))
+	}
+	if unresolved[0].Path != "api.go" || unresolved[0].Line != 12 || unresolved[0].Comments[0].Author != "alice" {
+		t.Errorf("first thread = %+v", unresolved[0])
+	}
+	if unresolved[1].Line != 7 || !unresolved[1].IsOutdated {
+		t.Errorf("outdated thread should fall back to its original line, got %+v", unresolved[1])
+	}
+	if cr := reviews.ChangesRequested(); len(cr) != 1 || cr[0].Body != "Needs tests" {
+		t.Errorf("ChangesRequested() = %+v", cr)
+	}
+	if want := time.Date(2026, 5, 1, 10, 6, 0, 0, time.UTC); !reviews.LatestActivity().Equal(want) {
+		t.Errorf("LatestActivity() = %v, want %v", reviews.LatestActivity(), want)
+	}
+}
+
+func TestPRReviews_ChangesRequested(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2026, 5, 1, hour, 0, 0, 0, time.UTC) }
+	reviews := &PRReviews{Reviews: []PRReview{
+		{Author: "alice", State: PRReviewChangesRequested, Body: "Needs tests", SubmittedAt: at(9)},
+		{Author: "alice", State: PRReviewApproved, SubmittedAt: at(11)},
+		{Author: "bob", State: PRReviewChangesRequested, Body: "Rename it", SubmittedAt: at(10)},
+		{Author: "bob", State: PRReviewCommented, Body: "Still looking", SubmittedAt: at(12)},
+		{Author: "carol", State: PRReviewChangesRequested, Body: "Old", SubmittedAt: at(8)},
+		{Author: "carol", State: "DISMISSED", SubmittedAt: at(9)},
+	}}
+
+	// alice approved and carol's review was dismissed; bob's comment keeps his request
+	if cr := reviews.ChangesRequested(); len(cr) != 1 || cr[0].Author != "bob" {
+		t.Errorf("ChangesRequested() = %+v, want only bob's request", cr)
+	}
+}
+
+func TestGH_GetPRReviews_NotFound(t *testing.T) {
+	fakeCommand(t, "gh", `{"data":{"repository":{"pullRequest":null}}}`)
+	if _, err := NewGH().GetPRReviews(context.Background(), t.TempDir(), 7); err == nil {
+		t.Error("expected an error for a missing PR")
+	}
+}
@@ -0,0 +1,92 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// CleanSparsePaths normalizes directories for a cone-mode sparse checkout:
+// surrounding slashes and "./" are dropped and duplicates removed. Absolute
+// paths, paths leaving the repository and option-like paths are rejected.
+func CleanSparsePaths(paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var cleaned []string
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if strings.HasPrefix(p, "/") || strings.HasPrefix(p, "-") {
+			return nil, fmt.Errorf("invalid sparse path %q: must be a directory relative to the repository root", p)
+		}
+		p = strings.Trim(path.Clean(p), "/")
+		if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+			return nil, fmt.Errorf("invalid sparse path %q: must be a directory inside the repository", p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			cleaned = append(cleaned, p)
+		}
+	}
+	return cleaned, nil
+}
+
+// CreateSparseWorktree creates a worktree that checks out only the given
+// directories (plus files at the repository root) using cone-mode sparse
+// checkout. With createBranch, branchName is created from startPoint (HEAD if
+// empty), like CreateWorktree and CreateWorktreeFromBase; otherwise the
+// existing branch is checked out, like CreateWorktreeFromExisting. The sparse
+// checkout only applies to the new worktree.
+func (g *Git) CreateSparseWorktree(ctx context.Context, worktreePath, branchName, startPoint string, createBranch bool, paths []string) error {
+	paths, err := CleanSparsePaths(paths)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no sparse checkout paths given")
+	}
+
+	// Skip the checkout so the whole tree is never written out
+	args := []string{"worktree", "add", "--no-checkout"}
+	if createBranch {
+		args = append(args, "-b", branchName, worktreePath)
+		if startPoint != "" {
+			args = append(args, startPoint)
+		}
+	} else {
+		args = append(args, worktreePath, branchName)
+	}
+	if _, err := g.run(ctx, args...); err != nil {
+		return err
+	}
+
+	wt := New(worktreePath)
+	if _, err := wt.run(ctx, append([]string{"sparse-checkout", "set", "--cone", "--"}, paths...)...); err != nil {
+		_ = g.RemoveWorktree(ctx, worktreePath)
+		return fmt.Errorf("failed to set up sparse checkout: %w", err)
+	}
+	// Populate the index and the chosen directories from HEAD
+	if _, err := wt.run(ctx, "read-tree", "-mu", "HEAD"); err != nil {
+		_ = g.RemoveWorktree(ctx, worktreePath)
+		return fmt.Errorf("failed to check out sparse worktree: %w", err)
+	}
+	return nil
+}
+
+// AddSparseCheckoutPaths widens the worktree's sparse checkout with more
+// directories, checking them out.
+func (g *Git) AddSparseCheckoutPaths(ctx context.Context, paths []string) error {
+	paths, err := CleanSparsePaths(paths)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	if _, err := g.run(ctx, append([]string{"sparse-checkout", "add", "--"}, paths...)...); err != nil {
+		return fmt.Errorf("failed to widen sparse checkout: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCleanSparsePaths(t *testing.T) {
+	got, err := CleanSparsePaths([]string{" services/api/ ", "./libs/core", "", "services/api"})
+	if err != nil || !reflect.DeepEqual(got, []string{"services/api", "libs/core"}) {
+		t.Errorf("CleanSparsePaths() = %v, %v", got, err)
+	}
+	for _, bad := range []string{"/etc", "../other", "a/../..", ".", "--cone"} {
+		if _, err := CleanSparsePaths([]string{bad}); err == nil {
+			t.Errorf("CleanSparsePaths(%q) should fail", bad)
+		}
+	}
+}
+
+func TestGit_CreateSparseWorktree(t *testing.T) {
+	dir := setupTestRepo(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+	g := New(dir)
+	branch, _ := g.CurrentBranch(ctx)
+	for _, d := range []string{"services/api", "services/web", "libs"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commitFile(t, dir, branch, "services/api/main.go", "api\n")
+	commitFile(t, dir, branch, "services/web/main.go", "web\n")
+	commitFile(t, dir, branch, "libs/util.go", "util\n")
+	commitFile(t, dir, branch, "go.mod", "module example\n")
+
+	worktreePath := filepath.Join(os.TempDir(), "git-sparse-worktree-test-"+filepath.Base(dir))
+	defer os.RemoveAll(worktreePath)
+	if err := g.CreateSparseWorktree(ctx, worktreePath, "sparse-branch", "", true, []string{"services/api/"}); err != nil {
+		t.Fatalf("CreateSparseWorktree() error = %v", err)
+	}
+
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(worktreePath, name))
+		return err == nil
+	}
+	if !exists("services/api/main.go") || !exists("go.mod") {
+		t.Error("the chosen directory and root files should be checked out")
+	}
+	if exists("services/web/main.go") || exists("libs/util.go") {
+		t.Error("other directories should not be checked out")
+	}
+	wt := New(worktreePath)
+	if status, _ := wt.run(ctx, "status", "--porcelain"); status != "" {
+		t.Errorf("sparse worktree should be clean, got %q", status)
+	}
+	if b, _ := wt.CurrentBranch(ctx); b != "sparse-branch" {
+		t.Errorf("worktree branch = %q, want sparse-branch", b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "libs", "util.go")); err != nil {
+		t.Error("the main checkout must stay complete")
+	}
+
+	if err := wt.AddSparseCheckoutPaths(ctx, []string{"libs"}); err != nil {
+		t.Fatalf("AddSparseCheckoutPaths() error = %v", err)
+	}
+	if !exists("libs/util.go") || exists("services/web/main.go") {
+		t.Error("widening should check out only the added directory")
+	}
+}
@@ -16,7 +16,7 @@ const (
 	DefaultTimeout = 120 * time.Second
 )
 
-// Executor runs git/gh commands on the host.
+// Executor runs git/gh/glab commands on the host.
 type Executor struct {
 	timeout time.Duration
 }
@@ -29,7 +29,7 @@ func NewExecutor() *Executor {
 }
 
 // Execute runs the operation and returns the response.
-// It also returns PRCreateResult if the operation was gh-pr-create.
+// It also returns PRCreateResult if the operation was gh-pr-create or glab-mr-create.
 func (e *Executor) Execute(ctx context.Context, op Operation, args []string, ws WorkstreamInfo) (*Response, *PRCreateResult) {
 	ctx, cancel := context.WithTimeout(ctx, e.timeout)
 	defer cancel()
@@ -67,7 +67,7 @@ func (e *Executor) Execute(ctx context.Context, op Operation, args []string, ws
 
 	// If this was a PR create, extract PR number from output
 	var prResult *PRCreateResult
-	if op == OpGHPRCreate && resp.ExitCode == 0 {
+	if (op == OpGHPRCreate || op == OpGLabMRCreate) && resp.ExitCode == 0 {
 		prResult = extractPRCreateResult(resp.Stdout)
 	}
 
@@ -116,6 +116,43 @@ var allowedGHFlags = map[string]bool{
 	"--color": true,
 }
 
+// allowedGLabFlags is a whitelist of safe glab CLI flags. glab's short
+// flags differ from gh's, so it has its own list.
+var allowedGLabFlags = map[string]bool{
+	// Common flags
+	"--repo": true, "-R": true,
+	"--output": true, "-F": true,
+	// MR create flags
+	"--title": true, "-t": true,
+	"--description": true, "-d": true,
+	"--source-branch": true, "-s": true,
+	"--target-branch": true, "-b": true,
+	"--assignee": true, "-a": true,
+	"--reviewer": true, "-r": true,
+	"--label": true, "-l": true,
+	"--milestone": true, "-m": true,
+	"--draft": true, "--wip": true,
+	"--fill": true, "-f": true, "--fill-commit-body": true,
+	"--related-issue": true, "-i": true,
+	"--yes": true, "-y": true,
+	"--web": true, "-w": true,
+	// MR merge flags
+	"--squash": true, "--rebase": true, "--squash-before-merge": true,
+	"--remove-source-branch": true, "--auto-merge": true,
+	"--message": true, "--squash-message": true, "--sha": true,
+	// View/list flags
+	"--comments": true, "-c": true,
+	"--all": true, "-A": true,
+	"--closed": true, "--merged": true, "-M": true,
+	"--author": true, "--search": true,
+	"--page": true, "-p": true,
+	"--per-page": true, "-P": true,
+	// CI status flags
+	"--branch": true, "--compact": true,
+	// Output format
+	"--color": true, "--raw": true,
+}
+
 // validateGitArgs checks git arguments for dangerous flags.
 // Returns an error if a dangerous flag is found.
 func validateGitArgs(args []string) error {
@@ -133,6 +170,17 @@ func validateGitArgs(args []string) error {
 // validateGHArgs checks gh arguments against the whitelist.
 // Returns an error if an unknown flag is found.
 func validateGHArgs(args []string) error {
+	return validateFlags(args, allowedGHFlags)
+}
+
+// validateGLabArgs checks glab arguments against the whitelist.
+// Returns an error if an unknown flag is found.
+func validateGLabArgs(args []string) error {
+	return validateFlags(args, allowedGLabFlags)
+}
+
+// validateFlags checks that every flag in args is in allowed.
+func validateFlags(args []string, allowed map[string]bool) error {
 	for _, arg := range args {
 		// Skip non-flag arguments (positional args like PR numbers, URLs)
 		if !strings.HasPrefix(arg, "-") {
@@ -146,7 +194,7 @@ func validateGHArgs(args []string) error {
 			flagName = arg[:idx]
 		}
 
-		if !allowedGHFlags[flagName] {
+		if !allowed[flagName] {
 			return fmt.Errorf("flag not allowed: %s", arg)
 		}
 	}
@@ -212,16 +260,59 @@ func (e *Executor) buildCommand(op Operation, args []string) []string {
 			return nil
 		}
 		return append([]string{"gh", "issue", "list"}, args...)
+	case OpGLabMRView:
+		if err := validateGLabArgs(args); err != nil {
+			return nil
+		}
+		return append([]string{"glab", "mr", "view"}, args...)
+	case OpGLabMRDiff:
+		if err := validateGLabArgs(args); err != nil {
+			return nil
+		}
+		return append([]string{"glab", "mr", "diff"}, args...)
+	case OpGLabMRList:
+		if err := validateGLabArgs(args); err != nil {
+			return nil
+		}
+		return append([]string{"glab", "mr", "list"}, args...)
+	case OpGLabMRCreate:
+		if err := validateGLabArgs(args); err != nil {
+			return nil
+		}
+		return append([]string{"glab", "mr", "create"}, args...)
+	case OpGLabMRMerge:
+		if err := validateGLabArgs(args); err != nil {
+			return nil
+		}
+		return append([]string{"glab", "mr", "merge"}, args...)
+	case OpGLabCIStatus:
+		if err := validateGLabArgs(args); err != nil {
+			return nil
+		}
+		return append([]string{"glab", "ci", "status"}, args...)
+	case OpGLabIssueView:
+		if err := validateGLabArgs(args); err != nil {
+			return nil
+		}
+		return append([]string{"glab", "issue", "view"}, args...)
+	case OpGLabIssueList:
+		if err := validateGLabArgs(args); err != nil {
+			return nil
+		}
+		return append([]string{"glab", "issue", "list"}, args...)
 	default:
 		return nil
 	}
 }
 
-// extractPRCreateResult parses the gh pr create output to get PR number and URL.
+// extractPRCreateResult parses the gh pr create or glab mr create output to
+// get the PR (or MR) number and URL.
 func extractPRCreateResult(output string) *PRCreateResult {
 	// gh pr create outputs the PR URL on success, e.g.:
 	// https://github.com/owner/repo/pull/123
-	prURLRegex := regexp.MustCompile(`https://github\.com/[^/]+/[^/]+/pull/(\d+)`)
+	// glab mr create outputs the MR URL, e.g.:
+	// https://gitlab.example.com/group/project/-/merge_requests/123
+	prURLRegex := regexp.MustCompile(`https://github\.com/[^/]+/[^/]+/pull/(\d+)|https?://\S+/-/merge_requests/(\d+)`)
 
 	match := prURLRegex.FindStringSubmatch(output)
 	if match == nil {
@@ -238,7 +329,11 @@ func extractPRCreateResult(output string) *PRCreateResult {
 		return nil
 	}
 
-	num, err := strconv.Atoi(match[1])
+	number := match[1]
+	if number == "" {
+		number = match[2] // GitLab MR
+	}
+	num, err := strconv.Atoi(number)
 	if err != nil {
 		return nil
 	}
@@ -79,6 +79,36 @@ func TestBuildCommand(t *testing.T) {
 			args:     []string{},
 			expected: []string{"gh", "issue", "list"},
 		},
+		{
+			name:     "glab mr view",
+			op:       OpGLabMRView,
+			args:     []string{"12", "--output", "json"},
+			expected: []string{"glab", "mr", "view", "12", "--output", "json"},
+		},
+		{
+			name:     "glab mr create",
+			op:       OpGLabMRCreate,
+			args:     []string{"--title", "Test MR", "--description", "Body", "--yes"},
+			expected: []string{"glab", "mr", "create", "--title", "Test MR", "--description", "Body", "--yes"},
+		},
+		{
+			name:     "glab mr merge",
+			op:       OpGLabMRMerge,
+			args:     []string{"--squash", "--remove-source-branch"},
+			expected: []string{"glab", "mr", "merge", "--squash", "--remove-source-branch"},
+		},
+		{
+			name:     "glab ci status",
+			op:       OpGLabCIStatus,
+			args:     []string{},
+			expected: []string{"glab", "ci", "status"},
+		},
+		{
+			name:     "glab mr create with unknown flag",
+			op:       OpGLabMRCreate,
+			args:     []string{"--push"},
+			expected: nil,
+		},
 		{
 			name:     "unknown operation",
 			op:       Operation("unknown"),
@@ -135,6 +165,12 @@ func TestExtractPRCreateResult(t *testing.T) {
 			wantNumber: 99999,
 			wantURL:    "https://github.com/owner/repo/pull/99999",
 		},
+		{
+			name:       "GitLab MR URL",
+			output:     "Creating merge request for feature into main in group/project\n\n!12 Add login (feature)\n https://gitlab.example.com/group/project/-/merge_requests/12\n",
+			wantNumber: 12,
+			wantURL:    "https://gitlab.example.com/group/project/-/merge_requests/12",
+		},
 		{
 			name:    "no URL",
 			output:  "Error: failed to create PR",
@@ -1,11 +1,11 @@
 package gitproxy
 
 // ProxyScript is the shell script that runs inside containers to communicate
-// with the git proxy socket. It intercepts git/gh commands and forwards them
+// with the git proxy socket. It intercepts git/gh/glab commands and forwards them
 // to the host for execution.
 const ProxyScript = `#!/bin/bash
-# ccells-git-proxy: Proxies git/gh commands to the host via Unix socket
-# This script is called by Claude Code hooks when git/gh commands are detected.
+# ccells-git-proxy: Proxies git/gh/glab commands to the host via Unix socket
+# This script is called by Claude Code hooks when git/gh/glab commands are detected.
 
 SOCKET_PATH="/var/run/ccells/git.sock"
 
@@ -48,6 +48,23 @@ build_operation() {
                     ;;
             esac
             ;;
+        glab)
+            local subcmd="$1 $2"
+            case "$subcmd" in
+                "mr view")    echo "glab-mr-view" ;;
+                "mr diff")    echo "glab-mr-diff" ;;
+                "mr list")    echo "glab-mr-list" ;;
+                "mr create")  echo "glab-mr-create" ;;
+                "mr merge")   echo "glab-mr-merge" ;;
+                "ci status")  echo "glab-ci-status" ;;
+                "issue view") echo "glab-issue-view" ;;
+                "issue list") echo "glab-issue-list" ;;
+                *)
+                    echo "ERROR: glab $subcmd is not proxied" >&2
+                    exit 1
+                    ;;
+            esac
+            ;;
         *)
             echo "ERROR: $cmd is not a proxied command" >&2
             exit 1
@@ -65,9 +82,9 @@ build_args_json() {
         git)
             shift  # Skip git subcommand (fetch/pull/push)
             ;;
-        gh)
-            shift  # Skip gh subcommand (pr/issue)
-            shift  # Skip gh sub-subcommand (view/create/etc)
+        gh|glab)
+            shift  # Skip subcommand (pr/mr/ci/issue)
+            shift  # Skip sub-subcommand (view/create/etc)
             ;;
     esac
 
@@ -100,10 +117,10 @@ if [ ! -S "$SOCKET_PATH" ]; then
     exit 1
 fi
 
-# First argument is the command (git or gh)
+# First argument is the command (git, gh or glab)
 CMD="$1"
 if [ -z "$CMD" ]; then
-    echo "Usage: ccells-git-proxy <git|gh> [args...]" >&2
+    echo "Usage: ccells-git-proxy <git|gh|glab> [args...]" >&2
     exit 1
 fi
 
@@ -183,8 +200,8 @@ fi
 exit $EXIT_CODE
 `
 
-// GitHookScript is a PreToolUse hook script that intercepts git and gh commands.
-// It receives JSON on stdin with the bash command, checks if it matches git/gh patterns,
+// GitHookScript is a PreToolUse hook script that intercepts git, gh and glab commands.
+// It receives JSON on stdin with the bash command, checks if it matches git/gh/glab patterns,
 // and either:
 // - Runs the command through the proxy and exits 2 (for proxied commands)
 // - Exits 2 with an error message (for blocked commands like git remote, git commit --amend on pushed branches)
@@ -195,7 +212,7 @@ const GitHookScript = `#!/bin/bash
 # - git fetch/pull/push: run through proxy, output result, exit 2 (block original)
 # - git remote: block with error message
 # - git commit --amend on pushed branch: block with error message
-# - gh pr/issue: run through proxy, output result, exit 2 (block original)
+# - gh pr/issue, glab mr/ci/issue: run through proxy, output result, exit 2 (block original)
 # - other commands: exit 0 (allow)
 
 # Read JSON input from stdin
@@ -255,6 +272,12 @@ if echo "$command" | grep -qE '^gh\s+issue\s+(view|list)(\s|$)'; then
     exit 2  # Block original
 fi
 
+# Check for glab (GitLab) commands (proxy)
+if echo "$command" | grep -qE '^glab\s+(mr\s+(view|diff|list|create|merge)|ci\s+status|issue\s+(view|list))(\s|$)'; then
+    /root/.claude/bin/ccells-git-proxy $command
+    exit 2  # Block original
+fi
+
 # Allow all other commands
 exit 0
 `
@@ -1,4 +1,4 @@
-// Package gitproxy provides a secure proxy for git and gh operations from containers.
+// Package gitproxy provides a secure proxy for git, gh and glab operations from containers.
 // It validates operations against the workstream's branch to prevent destructive actions.
 package gitproxy
 
@@ -24,6 +24,18 @@ const (
 	// gh CLI operations - mutating (require validation)
 	OpGHPRCreate Operation = "gh-pr-create"
 	OpGHPRMerge  Operation = "gh-pr-merge"
+
+	// glab CLI operations (GitLab) - read-only
+	OpGLabMRView    Operation = "glab-mr-view"
+	OpGLabMRDiff    Operation = "glab-mr-diff"
+	OpGLabMRList    Operation = "glab-mr-list"
+	OpGLabCIStatus  Operation = "glab-ci-status"
+	OpGLabIssueView Operation = "glab-issue-view"
+	OpGLabIssueList Operation = "glab-issue-list"
+
+	// glab CLI operations - mutating (same validation as their gh equivalents)
+	OpGLabMRCreate Operation = "glab-mr-create"
+	OpGLabMRMerge  Operation = "glab-mr-merge"
 )
 
 // Request is the JSON structure sent from container to host.
@@ -48,7 +60,8 @@ type WorkstreamInfo struct {
 	WorktreePath string // Path to the worktree
 }
 
-// PRCreateResult is returned when gh-pr-create succeeds, containing the PR details.
+// PRCreateResult is returned when gh-pr-create or glab-mr-create succeeds,
+// containing the PR (or MR) details.
 type PRCreateResult struct {
 	Number int
 	URL    string
@@ -30,6 +30,18 @@ var validators = map[Operation]Validator{
 	// gh CLI operations - mutating
 	OpGHPRCreate: validateUnrestricted, // PR number captured from output
 	OpGHPRMerge:  validateMerge,
+
+	// glab CLI operations - read-only
+	OpGLabMRView:    validateUnrestricted,
+	OpGLabMRDiff:    validateUnrestricted,
+	OpGLabMRList:    validateUnrestricted,
+	OpGLabCIStatus:  validateUnrestricted,
+	OpGLabIssueView: validateUnrestricted,
+	OpGLabIssueList: validateUnrestricted,
+
+	// glab CLI operations - mutating
+	OpGLabMRCreate: validateUnrestricted, // MR number captured from output
+	OpGLabMRMerge:  validateMerge,
 }
 
 // Validate checks if the operation is allowed with the given arguments.
@@ -149,11 +161,13 @@ func validateMerge(args []string, ws WorkstreamInfo) error {
 	return nil
 }
 
-// extractPRNumber extracts the PR number from gh pr merge arguments.
+// extractPRNumber extracts the PR number from gh pr merge or glab mr merge
+// arguments.
 func extractPRNumber(args []string) int {
 	// gh pr merge [<number> | <url> | <branch>] [flags]
+	// glab mr merge [<id> | <url> | <branch>] [flags]
 	prNumRegex := regexp.MustCompile(`^(\d+)$`)
-	prURLRegex := regexp.MustCompile(`/pull/(\d+)`)
+	prURLRegex := regexp.MustCompile(`/(?:pull|-/merge_requests)/(\d+)`)
 
 	for _, arg := range args {
 		if strings.HasPrefix(arg, "-") {
@@ -221,5 +235,29 @@ func ParseOperation(cmd string, args []string) (Operation, []string, error) {
 		return "", nil, fmt.Errorf("gh %s is not proxied", subCmd)
 	}
 
+	// glab commands
+	if cmd == "glab" && len(args) > 1 {
+		subCmd := args[0] + " " + args[1]
+		switch subCmd {
+		case "mr view":
+			return OpGLabMRView, args[2:], nil
+		case "mr diff":
+			return OpGLabMRDiff, args[2:], nil
+		case "mr list":
+			return OpGLabMRList, args[2:], nil
+		case "mr create":
+			return OpGLabMRCreate, args[2:], nil
+		case "mr merge":
+			return OpGLabMRMerge, args[2:], nil
+		case "ci status":
+			return OpGLabCIStatus, args[2:], nil
+		case "issue view":
+			return OpGLabIssueView, args[2:], nil
+		case "issue list":
+			return OpGLabIssueList, args[2:], nil
+		}
+		return "", nil, fmt.Errorf("glab %s is not proxied", subCmd)
+	}
+
 	return "", nil, fmt.Errorf("command not recognized: %s", cmd)
 }
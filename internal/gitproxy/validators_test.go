@@ -247,6 +247,40 @@ func TestParseOperation(t *testing.T) {
 			args:    []string{"pr", "close"},
 			wantErr: true,
 		},
+		{
+			name:     "glab mr view",
+			cmd:      "glab",
+			args:     []string{"mr", "view", "12", "--output", "json"},
+			wantOp:   OpGLabMRView,
+			wantArgs: []string{"12", "--output", "json"},
+		},
+		{
+			name:     "glab mr create",
+			cmd:      "glab",
+			args:     []string{"mr", "create", "--fill", "--yes"},
+			wantOp:   OpGLabMRCreate,
+			wantArgs: []string{"--fill", "--yes"},
+		},
+		{
+			name:     "glab mr merge",
+			cmd:      "glab",
+			args:     []string{"mr", "merge", "--squash"},
+			wantOp:   OpGLabMRMerge,
+			wantArgs: []string{"--squash"},
+		},
+		{
+			name:     "glab ci status",
+			cmd:      "glab",
+			args:     []string{"ci", "status"},
+			wantOp:   OpGLabCIStatus,
+			wantArgs: []string{},
+		},
+		{
+			name:    "glab mr close - not proxied",
+			cmd:     "glab",
+			args:    []string{"mr", "close", "12"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -278,6 +312,9 @@ func TestIsAllowedOperation(t *testing.T) {
 		OpGHPRView, OpGHPRChecks, OpGHPRDiff, OpGHPRList,
 		OpGHPRCreate, OpGHPRMerge,
 		OpGHIssueView, OpGHIssueList,
+		OpGLabMRView, OpGLabMRDiff, OpGLabMRList, OpGLabCIStatus,
+		OpGLabMRCreate, OpGLabMRMerge,
+		OpGLabIssueView, OpGLabIssueList,
 	}
 
 	for _, op := range allowed {
@@ -361,6 +398,11 @@ func TestExtractPRNumber(t *testing.T) {
 			args: []string{"--squash", "789"},
 			want: 789,
 		},
+		{
+			name: "GitLab MR URL",
+			args: []string{"https://gitlab.example.com/group/project/-/merge_requests/34"},
+			want: 34,
+		},
 		{
 			name: "empty args",
 			args: []string{},
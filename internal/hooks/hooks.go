@@ -0,0 +1,149 @@
+// Package hooks runs user-defined lifecycle hooks on the host.
+//
+// Hooks are shell commands declared in the cells config (hooks: section).
+// Each command is run with "sh -c" in the repository directory and receives
+// a JSON Payload describing the workstream on stdin.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/STRML/claude-cells/internal/docker"
+)
+
+// Event identifies a workstream lifecycle event.
+type Event string
+
+const (
+	EventCreate   Event = "create"    // Container started for a new workstream
+	EventIdle     Event = "idle"      // Claude session ended
+	EventPush     Event = "push"      // Branch pushed to remote
+	EventPROpened Event = "pr_opened" // Pull request created
+	EventMerge    Event = "merge"     // Branch merged (locally or via GitHub)
+	EventDestroy  Event = "destroy"   // Workstream destroyed
+)
+
+// maxOutputBytes caps the captured output of a single hook.
+const maxOutputBytes = 16 * 1024
+
+// Payload is the JSON document written to a hook's stdin.
+type Payload struct {
+	Event        Event     `json:"event"`
+	WorkstreamID string    `json:"workstream_id"`
+	Branch       string    `json:"branch"`
+	WorktreePath string    `json:"worktree_path"`
+	PRURL        string    `json:"pr_url,omitempty"`
+	Synopsis     string    `json:"synopsis,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// Result is the outcome of running a single hook command.
+type Result struct {
+	Command  string
+	Output   string // Combined stdout/stderr, trimmed and truncated
+	Duration time.Duration
+	Err      error
+}
+
+// Runner executes the hooks configured for each event.
+type Runner struct {
+	commands map[Event][]string
+	timeout  time.Duration
+	dir      string // Working directory for hook commands
+}
+
+// NewRunner creates a runner from the merged hooks config.
+// Hook commands run with dir as their working directory.
+func NewRunner(cfg docker.HooksConfig, dir string) *Runner {
+	return &Runner{
+		commands: map[Event][]string{
+			EventCreate:   cfg.Create,
+			EventIdle:     cfg.Idle,
+			EventPush:     cfg.Push,
+			EventPROpened: cfg.PROpened,
+			EventMerge:    cfg.Merge,
+			EventDestroy:  cfg.Destroy,
+		},
+		timeout: cfg.GetTimeout(),
+		dir:     dir,
+	}
+}
+
+// HasHooks reports whether any commands are configured for the event.
+func (r *Runner) HasHooks(event Event) bool {
+	if r == nil {
+		return false
+	}
+	return len(r.commands[event]) > 0
+}
+
+// Run executes every command configured for the payload's event, in order.
+// Each command is bounded by the configured timeout. A failing command does
+// not prevent later commands from running.
+func (r *Runner) Run(ctx context.Context, p Payload) []Result {
+	if !r.HasHooks(p.Event) {
+		return nil
+	}
+	if p.Timestamp.IsZero() {
+		p.Timestamp = time.Now()
+	}
+
+	input, err := json.Marshal(p)
+	if err != nil {
+		return []Result{{Err: fmt.Errorf("failed to marshal hook payload: %w", err)}}
+	}
+
+	var results []Result
+	for _, command := range r.commands[p.Event] {
+		results = append(results, r.runOne(ctx, command, input, p))
+	}
+	return results
+}
+
+// runOne runs a single hook command with the payload on stdin.
+func (r *Runner) runOne(ctx context.Context, command string, input []byte, p Payload) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = r.dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"CCELLS_EVENT="+string(p.Event),
+		"CCELLS_WORKSTREAM_ID="+p.WorkstreamID,
+		"CCELLS_BRANCH="+p.Branch,
+		"CCELLS_WORKTREE_PATH="+p.WorktreePath,
+		"CCELLS_PR_URL="+p.PRURL,
+	)
+	// Don't hang on grandchildren that keep stdout open after the timeout
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	result := Result{
+		Command:  command,
+		Output:   truncateOutput(strings.TrimSpace(string(output))),
+		Duration: time.Since(start),
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		result.Err = fmt.Errorf("timed out after %v", r.timeout)
+	} else if err != nil {
+		result.Err = err
+	}
+	return result
+}
+
+// truncateOutput limits hook output to maxOutputBytes.
+func truncateOutput(s string) string {
+	if len(s) <= maxOutputBytes {
+		return s
+	}
+	return s[:maxOutputBytes] + "\n... (output truncated)"
+}
@@ -0,0 +1,141 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/STRML/claude-cells/internal/docker"
+)
+
+func TestRunner_HasHooks(t *testing.T) {
+	r := NewRunner(docker.HooksConfig{Merge: []string{"true"}}, t.TempDir())
+
+	if !r.HasHooks(EventMerge) {
+		t.Error("HasHooks(merge) should be true")
+	}
+	if r.HasHooks(EventCreate) {
+		t.Error("HasHooks(create) should be false")
+	}
+
+	var nilRunner *Runner
+	if nilRunner.HasHooks(EventMerge) {
+		t.Error("nil runner should have no hooks")
+	}
+}
+
+func TestRunner_PayloadOnStdin(t *testing.T) {
+	dir := t.TempDir()
+	outFile := filepath.Join(dir, "payload.json")
+	r := NewRunner(docker.HooksConfig{
+		PROpened: []string{"cat > " + outFile},
+	}, dir)
+
+	results := r.Run(context.Background(), Payload{
+		Event:        EventPROpened,
+		WorkstreamID: "ws-1",
+		Branch:       "feature/x",
+		WorktreePath: "/tmp/ccells/worktrees/feature-x",
+		PRURL:        "https://github.com/o/r/pull/7",
+		Synopsis:     "Added the thing",
+	})
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Err != nil {
+		t.Fatalf("hook failed: %v (output: %s)", results[0].Err, results[0].Output)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("failed to read payload: %v", err)
+	}
+	var got Payload
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	if got.Event != EventPROpened || got.WorkstreamID != "ws-1" || got.Branch != "feature/x" {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+	if got.PRURL != "https://github.com/o/r/pull/7" || got.Synopsis != "Added the thing" {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+	if got.Timestamp.IsZero() {
+		t.Error("timestamp should be set")
+	}
+}
+
+func TestRunner_OutputAndEnv(t *testing.T) {
+	r := NewRunner(docker.HooksConfig{
+		Push: []string{`echo "$CCELLS_EVENT $CCELLS_BRANCH"`},
+	}, t.TempDir())
+
+	results := r.Run(context.Background(), Payload{Event: EventPush, Branch: "main-fix"})
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Output != "push main-fix" {
+		t.Errorf("Output = %q, want %q", results[0].Output, "push main-fix")
+	}
+}
+
+func TestRunner_FailureDoesNotStopLaterHooks(t *testing.T) {
+	r := NewRunner(docker.HooksConfig{
+		Destroy: []string{"echo oops >&2; exit 3", "echo second"},
+	}, t.TempDir())
+
+	results := r.Run(context.Background(), Payload{Event: EventDestroy})
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Err == nil {
+		t.Error("first hook should fail")
+	}
+	if results[0].Output != "oops" {
+		t.Errorf("stderr should be captured, got %q", results[0].Output)
+	}
+	if results[1].Err != nil || results[1].Output != "second" {
+		t.Errorf("second hook should succeed, got %+v", results[1])
+	}
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	r := NewRunner(docker.HooksConfig{
+		Timeout: "200ms",
+		Idle:    []string{"sleep 5"},
+	}, t.TempDir())
+
+	start := time.Now()
+	results := r.Run(context.Background(), Payload{Event: EventIdle})
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("hook was not killed on timeout (took %v)", elapsed)
+	}
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("expected timeout error, got %+v", results)
+	}
+	if !strings.Contains(results[0].Err.Error(), "timed out") {
+		t.Errorf("error should mention timeout, got %v", results[0].Err)
+	}
+}
+
+func TestRunner_NoHooks(t *testing.T) {
+	r := NewRunner(docker.HooksConfig{}, t.TempDir())
+	if results := r.Run(context.Background(), Payload{Event: EventCreate}); results != nil {
+		t.Errorf("expected no results, got %+v", results)
+	}
+}
+
+func TestTruncateOutput(t *testing.T) {
+	long := strings.Repeat("x", maxOutputBytes+10)
+	got := truncateOutput(long)
+	if !strings.HasSuffix(got, "(output truncated)") {
+		t.Error("long output should be truncated")
+	}
+	if truncateOutput("short") != "short" {
+		t.Error("short output should be unchanged")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/STRML/claude-cells/internal/docker"
 	"github.com/STRML/claude-cells/internal/gitproxy"
 	"github.com/STRML/claude-cells/internal/workstream"
+	"github.com/docker/docker/api/types/mount"
 )
 
 // DefaultGitProxyBaseDir is the base directory for git proxy sockets.
@@ -42,6 +43,7 @@ func sanitizeBranchName(branchName string) string {
 // This is the complete flow including:
 // - Optional main branch update
 // - Worktree creation (new or from existing branch)
+// - Config-driven file provisioning
 // - Image detection and building
 // - Container config setup (credentials, git identity)
 // - Container creation and starting
@@ -54,7 +56,11 @@ func (o *Orchestrator) CreateWorkstream(ctx context.Context, ws *workstream.Work
 	}
 
 	// Step 2: Create git worktree
-	worktreePath, err := o.createWorktree(ctx, ws.BranchName, opts.UseExistingBranch)
+	baseBranch := opts.StartPoint
+	if baseBranch == "" && opts.Preset != nil {
+		baseBranch = opts.Preset.BaseBranch
+	}
+	worktreePath, err := o.createWorktree(ctx, ws.BranchName, baseBranch, opts.UseExistingBranch, ws.GetSparsePaths())
 	if err != nil {
 		return nil, fmt.Errorf("create worktree: %w", err)
 	}
@@ -68,21 +74,32 @@ func (o *Orchestrator) CreateWorkstream(ctx context.Context, ws *workstream.Work
 		}
 	}
 
-	// Step 4: Determine image (auto-detect or use provided)
+	// Step 4: Provision configured files (also applies to rebuilds)
+	var provisionMounts []mount.Mount
+	if !opts.Provision.IsEmpty() {
+		provisionMounts, err = o.provisionWorktree(worktreePath, opts.Provision)
+		if err != nil {
+			o.cleanupWorktree(ctx, ws.BranchName)
+			return nil, fmt.Errorf("provision worktree: %w", err)
+		}
+	}
+
+	// Step 5: Determine image (auto-detect or use provided)
 	imageName, err := o.resolveImage(ctx, opts)
 	if err != nil {
 		o.cleanupWorktree(ctx, ws.BranchName)
 		return nil, fmt.Errorf("resolve image: %w", err)
 	}
 
-	// Step 5: Build container config with credentials
+	// Step 6: Build container config with credentials
 	cfgResult, err := o.buildFullContainerConfig(ws, worktreePath, imageName, opts)
 	if err != nil {
 		o.cleanupWorktree(ctx, ws.BranchName)
 		return nil, fmt.Errorf("build container config: %w", err)
 	}
+	cfgResult.config.ExtraMounts = append(cfgResult.config.ExtraMounts, provisionMounts...)
 
-	// Step 6: Create and start container
+	// Step 7: Create and start container
 	containerID, err := o.createAndStartContainer(ctx, cfgResult.config)
 	if err != nil {
 		o.cleanupWorktree(ctx, ws.BranchName)
@@ -143,7 +160,7 @@ func (o *Orchestrator) CheckBranchConflict(ctx context.Context, branchName strin
 	return nil, nil // No conflict
 }
 
-func (o *Orchestrator) createWorktree(ctx context.Context, branchName string, useExisting bool) (string, error) {
+func (o *Orchestrator) createWorktree(ctx context.Context, branchName, baseBranch string, useExisting bool, sparsePaths []string) (string, error) {
 	baseDir := o.getWorktreeBaseDir()
 
 	// Ensure base directory exists
@@ -167,11 +184,21 @@ func (o *Orchestrator) createWorktree(ctx context.Context, branchName string, us
 		}
 	}
 
-	if useExisting {
+	if len(sparsePaths) > 0 {
+		// Check out only the chosen directories (cone-mode sparse checkout)
+		if err := gitClient.CreateSparseWorktree(ctx, worktreePath, branchName, baseBranch, !useExisting, sparsePaths); err != nil {
+			return "", fmt.Errorf("git create sparse worktree: %w", err)
+		}
+	} else if useExisting {
 		// Create worktree from existing branch
 		if err := gitClient.CreateWorktreeFromExisting(ctx, worktreePath, branchName); err != nil {
 			return "", fmt.Errorf("git create worktree from existing: %w", err)
 		}
+	} else if baseBranch != "" {
+		// Create worktree with new branch off the preset's base branch
+		if err := gitClient.CreateWorktreeFromBase(ctx, worktreePath, branchName, baseBranch); err != nil {
+			return "", fmt.Errorf("git create worktree from %s: %w", baseBranch, err)
+		}
 	} else {
 		// Create worktree with new branch
 		if err := gitClient.CreateWorktree(ctx, worktreePath, branchName); err != nil {
@@ -264,6 +291,13 @@ func (o *Orchestrator) buildFullContainerConfig(ws *workstream.Workstream, workt
 		cfg.ExtraEnv = devCfg.ContainerEnv
 	}
 
+	// Apply template preset overrides (env, resource limits, security tier)
+	if opts.Preset != nil {
+		if err := o.applyPreset(cfg, opts.Preset); err != nil {
+			return nil, fmt.Errorf("apply template %q: %w", opts.Preset.Name, err)
+		}
+	}
+
 	// Create per-container isolated config directory
 	// Runtime comes from global app setting (set via --runtime flag or config file)
 	// Default to "claude" if not set to ensure runtime-specific setup always runs
@@ -271,7 +305,7 @@ func (o *Orchestrator) buildFullContainerConfig(ws *workstream.Workstream, workt
 	if runtime == "" {
 		runtime = "claude"
 	}
-	configPaths, err := docker.CreateContainerConfig(cfg.Name, runtime)
+	configPaths, err := docker.CreateContainerConfig(cfg.Name, runtime, ws.GetSparsePaths())
 	if err != nil {
 		return nil, fmt.Errorf("create container config: %w", err)
 	}
@@ -309,6 +343,40 @@ func (o *Orchestrator) buildFullContainerConfig(ws *workstream.Workstream, workt
 	}, nil
 }
 
+// applyPreset overrides container settings with those set by a template.
+func (o *Orchestrator) applyPreset(cfg *docker.ContainerConfig, preset *docker.TemplateConfig) error {
+	if len(preset.Env) > 0 {
+		env := make(map[string]string, len(cfg.ExtraEnv)+len(preset.Env))
+		for k, v := range cfg.ExtraEnv {
+			env[k] = v
+		}
+		for k, v := range preset.Env {
+			env[k] = v
+		}
+		cfg.ExtraEnv = env
+	}
+
+	if preset.CPUs > 0 {
+		cfg.CPULimit = preset.CPUs
+	}
+	memory, err := preset.GetMemoryLimit()
+	if err != nil {
+		return err
+	}
+	if memory > 0 {
+		cfg.MemoryLimit = memory
+	}
+
+	if preset.SecurityTier != "" {
+		if !docker.IsValidTier(preset.SecurityTier) {
+			return fmt.Errorf("invalid security tier %q", preset.SecurityTier)
+		}
+		sec := docker.LoadSecurityConfigWithTier(o.repoPath, preset.SecurityTier)
+		cfg.Security = &sec
+	}
+	return nil
+}
+
 func (o *Orchestrator) createAndStartContainer(ctx context.Context, cfg *docker.ContainerConfig) (string, error) {
 	containerID, err := o.dockerClient.CreateContainer(ctx, cfg)
 	if err != nil {
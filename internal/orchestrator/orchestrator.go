@@ -49,6 +49,13 @@ type CreateOptions struct {
 	IsResume          bool   // Resuming existing session (use existing branch)
 	UseExistingBranch bool   // Use existing branch without creating new one
 	UpdateMain        bool   // Auto-pull main before creating branch
+	// Provision lists files copied or shared into the worktree without prompting
+	Provision docker.ProvisionConfig
+	// Preset is the resolved workstream template, or nil for none
+	Preset *docker.TemplateConfig
+	// StartPoint is the commit or branch a new branch starts from
+	// (overrides Preset.BaseBranch; used when restoring archived workstreams)
+	StartPoint string
 }
 
 // CreateResult contains the result of workstream creation.
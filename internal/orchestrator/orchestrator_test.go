@@ -2,7 +2,9 @@ package orchestrator
 
 import (
 	"context"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/STRML/claude-cells/internal/docker"
@@ -621,3 +623,145 @@ func TestRebuildWorkstream_DestroyError(t *testing.T) {
 		t.Error("expected either result or error")
 	}
 }
+
+func TestCreateWorkstream_AppliesPreset(t *testing.T) {
+	mockDocker := docker.NewMockClient()
+	mockGit := git.NewMockGitClient()
+	mockGit.SetCurrentBranch("develop")
+	var baseBranch string
+	mockGit.CreateWorktreeFromBaseFn = func(ctx context.Context, worktreePath, branchName, base string) error {
+		baseBranch = base
+		return nil
+	}
+
+	orch := New(mockDocker, func(string) git.GitClient { return mockGit }, "/test/repo")
+	cleanup := setupTestDirs(t, orch)
+	defer cleanup()
+
+	var created *docker.ContainerConfig
+	mockDocker.CreateContainerFn = func(ctx context.Context, cfg *docker.ContainerConfig) (string, error) {
+		created = cfg
+		return "mock-preset", nil
+	}
+
+	ws := &workstream.Workstream{ID: "test-id", BranchName: "bugfix/login"}
+	opts := CreateOptions{
+		RepoPath:  "/test/repo",
+		ImageName: "ccells-test:latest",
+		Preset: &docker.TemplateConfig{
+			Name:         "bugfix",
+			SecurityTier: docker.TierHardened,
+			CPUs:         4,
+			Memory:       "8g",
+			Env:          map[string]string{"LOG_LEVEL": "debug"},
+			BaseBranch:   "develop",
+		},
+	}
+	if _, err := orch.CreateWorkstream(context.Background(), ws, opts); err != nil {
+		t.Fatalf("CreateWorkstream() error = %v", err)
+	}
+
+	if baseBranch != "develop" {
+		t.Errorf("worktree base branch = %q, want %q", baseBranch, "develop")
+	}
+	if created == nil {
+		t.Fatal("expected container to be created")
+	}
+	if created.CPULimit != 4 || created.MemoryLimit != 8*1024*1024*1024 {
+		t.Errorf("limits = %v CPUs, %d bytes", created.CPULimit, created.MemoryLimit)
+	}
+	if created.ExtraEnv["LOG_LEVEL"] != "debug" {
+		t.Errorf("ExtraEnv = %v", created.ExtraEnv)
+	}
+	if created.Security == nil || created.Security.Tier != docker.TierHardened {
+		t.Errorf("Security = %+v, want hardened tier", created.Security)
+	}
+}
+
+func TestCreateWorkstream_StartPointOverridesPreset(t *testing.T) {
+	mockDocker := docker.NewMockClient()
+	mockGit := git.NewMockGitClient()
+	var baseBranch string
+	mockGit.CreateWorktreeFromBaseFn = func(ctx context.Context, worktreePath, branchName, base string) error {
+		baseBranch = base
+		return nil
+	}
+
+	orch := New(mockDocker, func(string) git.GitClient { return mockGit }, "/test/repo")
+	cleanup := setupTestDirs(t, orch)
+	defer cleanup()
+
+	ws := &workstream.Workstream{ID: "test-id", BranchName: "restored-branch"}
+	opts := CreateOptions{
+		RepoPath:   "/test/repo",
+		ImageName:  "ccells-test:latest",
+		Preset:     &docker.TemplateConfig{Name: "bugfix", BaseBranch: "develop"},
+		StartPoint: "0123456789abcdef0123456789abcdef01234567",
+	}
+	if _, err := orch.CreateWorkstream(context.Background(), ws, opts); err != nil {
+		t.Fatalf("CreateWorkstream() error = %v", err)
+	}
+	if baseBranch != opts.StartPoint {
+		t.Errorf("worktree base = %q, want start point %q", baseBranch, opts.StartPoint)
+	}
+}
+
+func TestCreateWorkstream_InvalidPreset(t *testing.T) {
+	mockDocker := docker.NewMockClient()
+	mockGit := git.NewMockGitClient()
+	orch := New(mockDocker, func(string) git.GitClient { return mockGit }, "/test/repo")
+	cleanup := setupTestDirs(t, orch)
+	defer cleanup()
+
+	ws := &workstream.Workstream{ID: "test-id", BranchName: "spike/idea"}
+	opts := CreateOptions{
+		RepoPath:  "/test/repo",
+		ImageName: "ccells-test:latest",
+		Preset:    &docker.TemplateConfig{Name: "spike", Memory: "plenty"},
+	}
+	if _, err := orch.CreateWorkstream(context.Background(), ws, opts); err == nil {
+		t.Fatal("expected error for invalid memory limit")
+	}
+	if len(mockGit.GetWorktrees()) != 0 {
+		t.Error("worktree should be cleaned up after a preset error")
+	}
+}
+
+func TestCreateWorkstream_SparseCheckout(t *testing.T) {
+	mockDocker := docker.NewMockClient()
+	mockGit := git.NewMockGitClient()
+	var gotBase string
+	var gotCreate bool
+	var gotPaths []string
+	mockGit.CreateSparseWorktreeFn = func(ctx context.Context, worktreePath, branchName, startPoint string, createBranch bool, paths []string) error {
+		gotBase, gotCreate, gotPaths = startPoint, createBranch, paths
+		return nil
+	}
+
+	orch := New(mockDocker, func(string) git.GitClient { return mockGit }, "/test/repo")
+	cleanup := setupTestDirs(t, orch)
+	defer cleanup()
+
+	ws := &workstream.Workstream{ID: "test-id", BranchName: "api-fix"}
+	ws.SetSparsePaths([]string{"services/api"})
+	opts := CreateOptions{
+		RepoPath:  "/test/repo",
+		ImageName: "ccells-test:latest",
+		Preset:    &docker.TemplateConfig{Name: "api", BaseBranch: "develop"},
+	}
+	result, err := orch.CreateWorkstream(context.Background(), ws, opts)
+	if err != nil {
+		t.Fatalf("CreateWorkstream() error = %v", err)
+	}
+	if !gotCreate || gotBase != "develop" || len(gotPaths) != 1 || gotPaths[0] != "services/api" {
+		t.Errorf("sparse worktree created with base %q, create %v, paths %v", gotBase, gotCreate, gotPaths)
+	}
+
+	claudeMD, err := os.ReadFile(filepath.Join(result.ConfigDir, docker.ClaudeDir, "CLAUDE.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(claudeMD), "`services/api`") {
+		t.Error("CLAUDE.md should list the sparse paths")
+	}
+}
@@ -0,0 +1,188 @@
+package orchestrator
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// provisionWorktree applies config-driven provisioning to a worktree:
+// matching "copy" paths are copied in, and matching "link" paths are shared
+// read-only. It returns the extra container mounts the shared paths need.
+func (o *Orchestrator) provisionWorktree(worktreePath string, cfg docker.ProvisionConfig) ([]mount.Mount, error) {
+	copyPaths, err := expandProvisionGlobs(o.repoPath, cfg.Copy)
+	if err != nil {
+		return nil, err
+	}
+	linkPaths, err := expandProvisionGlobs(o.repoPath, cfg.Link)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range copyPaths {
+		if err := copyPathIfMissing(p.Src, filepath.Join(worktreePath, p.Rel)); err != nil {
+			return nil, fmt.Errorf("copy %s: %w", p.Rel, err)
+		}
+	}
+
+	var mounts []mount.Mount
+	for _, p := range linkPaths {
+		if cfg.GetLinkMode() == docker.ProvisionLinkMount {
+			mounts = append(mounts, mount.Mount{
+				Type:     mount.TypeBind,
+				Source:   p.Src,
+				Target:   path.Join("/workspace", filepath.ToSlash(p.Rel)),
+				ReadOnly: true,
+			})
+			continue
+		}
+
+		linked, err := symlinkIfMissing(p.Src, filepath.Join(worktreePath, p.Rel))
+		if err != nil {
+			return nil, fmt.Errorf("link %s: %w", p.Rel, err)
+		}
+		if !linked {
+			continue
+		}
+		// Mount the original at its host path so the symlink resolves in the container
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   p.Src,
+			Target:   p.Src,
+			ReadOnly: true,
+		})
+	}
+	return mounts, nil
+}
+
+// provisionPath is a path matched by a provision pattern.
+type provisionPath struct {
+	Rel string // Path relative to the repo, as matched
+	Src string // Absolute path with symlinks resolved
+}
+
+// expandProvisionGlobs expands glob patterns relative to repoPath and returns
+// the de-duplicated paths they match, sorted by relative path. Patterns may
+// not escape the repo, and paths inside .git are never matched. The patterns
+// come from project config, so a match that is a symlink pointing outside
+// the repo (or into .git) is skipped rather than shared with the container.
+func expandProvisionGlobs(repoPath string, patterns []string) ([]provisionPath, error) {
+	root, err := filepath.EvalSymlinks(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var result []provisionPath
+	for _, pattern := range patterns {
+		clean := filepath.Clean(pattern)
+		if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("provision pattern %q must be relative to the repo", pattern)
+		}
+		matches, err := filepath.Glob(filepath.Join(repoPath, clean))
+		if err != nil {
+			return nil, fmt.Errorf("provision pattern %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			rel, err := filepath.Rel(repoPath, match)
+			if err != nil || !isProvisionable(rel) || seen[rel] {
+				continue
+			}
+			src, err := filepath.EvalSymlinks(match)
+			if err != nil {
+				return nil, fmt.Errorf("provision path %s: %w", rel, err)
+			}
+			if srcRel, err := filepath.Rel(root, src); err != nil || !isProvisionable(srcRel) || srcRel == ".." || strings.HasPrefix(srcRel, ".."+string(filepath.Separator)) {
+				log.Printf("[orchestrator] Warning: not provisioning %s: it resolves to %s, outside the repo", rel, src)
+				continue
+			}
+			seen[rel] = true
+			result = append(result, provisionPath{Rel: rel, Src: src})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Rel < result[j].Rel })
+	return result, nil
+}
+
+// isProvisionable reports whether a repo-relative path may be provisioned:
+// the repo root itself and anything inside .git are excluded.
+func isProvisionable(rel string) bool {
+	return rel != "." && rel != ".git" && !strings.HasPrefix(rel, ".git"+string(filepath.Separator))
+}
+
+// copyPathIfMissing copies a file or directory tree from src to dst.
+// Files that already exist at the destination are left untouched, which
+// protects tracked files and any edits made inside the cell. Symlinks
+// inside a copied directory are skipped, since they may point outside it.
+func copyPathIfMissing(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return copyFileIfMissing(src, dst, info.Mode().Perm())
+	}
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		return copyFileIfMissing(p, target, fi.Mode().Perm())
+	})
+}
+
+// copyFileIfMissing copies a single file unless dst already exists.
+func copyFileIfMissing(src, dst string, perm os.FileMode) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, perm)
+}
+
+// symlinkIfMissing creates a symlink at dst pointing to src and reports
+// whether dst now links to src. An existing symlink to src is left as is;
+// any other existing path is left alone.
+func symlinkIfMissing(src, dst string) (bool, error) {
+	if _, err := os.Lstat(dst); err == nil {
+		if target, err := os.Readlink(dst); err != nil || target != src {
+			log.Printf("[orchestrator] Warning: not linking %s: path already exists in worktree", dst)
+			return false, nil
+		}
+		return true, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return false, err
+	}
+	if err := os.Symlink(src, dst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,219 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// writeTestFile creates a file (and parent directories) with the given content.
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestExpandProvisionGlobs(t *testing.T) {
+	repo := t.TempDir()
+	writeTestFile(t, filepath.Join(repo, ".env"), "A=1")
+	writeTestFile(t, filepath.Join(repo, ".env.local"), "B=2")
+	writeTestFile(t, filepath.Join(repo, "config", "local.yml"), "x: 1")
+	writeTestFile(t, filepath.Join(repo, ".git", "config"), "")
+
+	got, err := expandProvisionGlobs(repo, []string{".env*", "config/local.yml", ".env", "missing/*", ".git*"})
+	if err != nil {
+		t.Fatalf("expandProvisionGlobs() error = %v", err)
+	}
+	want := []string{".env", ".env.local", filepath.Join("config", "local.yml")}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Rel != want[i] || got[i].Src != filepath.Join(repo, want[i]) {
+			t.Errorf("got[%d] = %+v, want %q", i, got[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"/etc/passwd", "../outside", "a/../../outside"} {
+		if _, err := expandProvisionGlobs(repo, []string{bad}); err == nil {
+			t.Errorf("pattern %q should be rejected", bad)
+		}
+	}
+}
+
+func TestExpandProvisionGlobs_Symlinks(t *testing.T) {
+	repo := t.TempDir()
+	outside := t.TempDir()
+	writeTestFile(t, filepath.Join(outside, "id_rsa"), "secret")
+	writeTestFile(t, filepath.Join(repo, "fixtures", "data.json"), "{}")
+	writeTestFile(t, filepath.Join(repo, ".git", "config"), "")
+	for link, target := range map[string]string{
+		"escape":   outside,
+		"gitdir":   filepath.Join(repo, ".git"),
+		"internal": filepath.Join(repo, "fixtures"),
+	} {
+		if err := os.Symlink(target, filepath.Join(repo, link)); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+	}
+
+	got, err := expandProvisionGlobs(repo, []string{"*"})
+	if err != nil {
+		t.Fatalf("expandProvisionGlobs() error = %v", err)
+	}
+	// Links out of the repo or into .git are dropped; links within it resolve
+	want := []provisionPath{
+		{Rel: "fixtures", Src: filepath.Join(repo, "fixtures")},
+		{Rel: "internal", Src: filepath.Join(repo, "fixtures")},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestProvisionWorktree(t *testing.T) {
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	writeTestFile(t, filepath.Join(repo, ".env"), "SECRET=host")
+	writeTestFile(t, filepath.Join(repo, "config", "local.yml"), "x: 1")
+	writeTestFile(t, filepath.Join(repo, "fixtures", "large", "data.json"), "{}")
+	// Existing worktree files must not be overwritten
+	writeTestFile(t, filepath.Join(worktree, "config", "local.yml"), "x: edited")
+
+	orch := New(docker.NewMockClient(), func(string) git.GitClient { return git.NewMockGitClient() }, repo)
+
+	t.Run("symlink mode", func(t *testing.T) {
+		cfg := docker.ProvisionConfig{
+			Copy: []string{".env*", "config/*.yml"},
+			Link: []string{"fixtures/large"},
+		}
+		mounts, err := orch.provisionWorktree(worktree, cfg)
+		if err != nil {
+			t.Fatalf("provisionWorktree() error = %v", err)
+		}
+
+		if data, _ := os.ReadFile(filepath.Join(worktree, ".env")); string(data) != "SECRET=host" {
+			t.Errorf(".env = %q, want copied content", data)
+		}
+		if data, _ := os.ReadFile(filepath.Join(worktree, "config", "local.yml")); string(data) != "x: edited" {
+			t.Errorf("existing file was overwritten: %q", data)
+		}
+
+		src := filepath.Join(repo, "fixtures", "large")
+		target, err := os.Readlink(filepath.Join(worktree, "fixtures", "large"))
+		if err != nil || target != src {
+			t.Errorf("expected symlink to %s, got %q (err %v)", src, target, err)
+		}
+		if len(mounts) != 1 || mounts[0].Source != src || mounts[0].Target != src || !mounts[0].ReadOnly {
+			t.Errorf("unexpected mounts: %+v", mounts)
+		}
+
+		// Re-provisioning (e.g. on rebuild) is idempotent
+		if _, err := orch.provisionWorktree(worktree, cfg); err != nil {
+			t.Errorf("second provisionWorktree() error = %v", err)
+		}
+	})
+
+	t.Run("mount mode", func(t *testing.T) {
+		wt := t.TempDir()
+		cfg := docker.ProvisionConfig{Link: []string{"fixtures/*"}, LinkMode: docker.ProvisionLinkMount}
+		mounts, err := orch.provisionWorktree(wt, cfg)
+		if err != nil {
+			t.Fatalf("provisionWorktree() error = %v", err)
+		}
+		if len(mounts) != 1 || mounts[0].Target != "/workspace/fixtures/large" || !mounts[0].ReadOnly {
+			t.Errorf("unexpected mounts: %+v", mounts)
+		}
+		if _, err := os.Lstat(filepath.Join(wt, "fixtures", "large")); !os.IsNotExist(err) {
+			t.Error("mount mode should not modify the worktree")
+		}
+	})
+
+	t.Run("existing path is not mounted", func(t *testing.T) {
+		wt := t.TempDir()
+		writeTestFile(t, filepath.Join(wt, "fixtures", "large", "data.json"), "{\"tracked\": true}")
+		mounts, err := orch.provisionWorktree(wt, docker.ProvisionConfig{Link: []string{"fixtures/large"}})
+		if err != nil {
+			t.Fatalf("provisionWorktree() error = %v", err)
+		}
+		if len(mounts) != 0 {
+			t.Errorf("a path the link was skipped for should not be mounted: %+v", mounts)
+		}
+	})
+
+	t.Run("nested symlinks are not copied", func(t *testing.T) {
+		outside := t.TempDir()
+		writeTestFile(t, filepath.Join(outside, "id_rsa"), "secret")
+		if err := os.Symlink(filepath.Join(outside, "id_rsa"), filepath.Join(repo, "config", "key")); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+		wt := t.TempDir()
+		if _, err := orch.provisionWorktree(wt, docker.ProvisionConfig{Copy: []string{"config"}}); err != nil {
+			t.Fatalf("provisionWorktree() error = %v", err)
+		}
+		if _, err := os.Lstat(filepath.Join(wt, "config", "key")); !os.IsNotExist(err) {
+			t.Error("a symlink inside a copied directory should be skipped")
+		}
+		if _, err := os.Stat(filepath.Join(wt, "config", "local.yml")); err != nil {
+			t.Errorf("regular files should still be copied: %v", err)
+		}
+	})
+}
+
+func TestCreateWorkstream_ProvisionsWorktree(t *testing.T) {
+	repo := t.TempDir()
+	writeTestFile(t, filepath.Join(repo, ".env"), "SECRET=host")
+	writeTestFile(t, filepath.Join(repo, "fixtures", "large", "data.json"), "{}")
+
+	mockDocker := docker.NewMockClient()
+	mockGit := git.NewMockGitClient()
+	orch := New(mockDocker, func(string) git.GitClient { return mockGit }, repo)
+	cleanup := setupTestDirs(t, orch)
+	defer cleanup()
+
+	var created *docker.ContainerConfig
+	mockDocker.CreateContainerFn = func(ctx context.Context, cfg *docker.ContainerConfig) (string, error) {
+		created = cfg
+		return "mock-provisioned", nil
+	}
+
+	// The mock git client doesn't create worktree directories on disk
+	worktreePath := filepath.Join(orch.getWorktreeBaseDir(), "ccells-provision")
+	if err := os.MkdirAll(worktreePath, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	mockGit.AddWorktree(worktreePath, "ccells/provision")
+
+	ws := &workstream.Workstream{ID: "test-id", BranchName: "ccells/provision"}
+	opts := CreateOptions{
+		RepoPath:          repo,
+		ImageName:         "ccells-test:latest",
+		UseExistingBranch: true, // Same path a rebuild takes
+		Provision: docker.ProvisionConfig{
+			Copy: []string{".env"},
+			Link: []string{"fixtures/large"},
+		},
+	}
+	if _, err := orch.CreateWorkstream(context.Background(), ws, opts); err != nil {
+		t.Fatalf("CreateWorkstream() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(worktreePath, ".env")); err != nil {
+		t.Errorf(".env should be provisioned: %v", err)
+	}
+	if created == nil || len(created.ExtraMounts) != 1 {
+		t.Fatalf("expected one extra mount, got %+v", created)
+	}
+	if created.ExtraMounts[0].Source != filepath.Join(repo, "fixtures", "large") {
+		t.Errorf("unexpected mount source %q", created.ExtraMounts[0].Source)
+	}
+}
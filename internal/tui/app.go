@@ -11,9 +11,11 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/claude"
 	"github.com/STRML/claude-cells/internal/config"
 	"github.com/STRML/claude-cells/internal/docker"
 	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/hooks"
 	"github.com/STRML/claude-cells/internal/orchestrator"
 	"github.com/STRML/claude-cells/internal/sync"
 	"github.com/STRML/claude-cells/internal/workstream"
@@ -112,14 +114,41 @@ type AppModel struct {
 	pairingOrchestrator *sync.Pairing
 	// Pane swap state
 	lastSwapPosition int // Position to swap back to when pressing Space at main (0 = none)
+	// Pane filter and sort (hidden panes keep running)
+	paneFilter paneFilter
+	paneSort   paneSortMode
+	// Token budgets of the primary repository (loaded from config on each
+	// usage refresh); other repositories keep theirs in repoContext
+	budget              docker.BudgetConfig
+	spent               map[string]claude.Usage // Usage of destroyed and archived workstreams by ID
+	budgetWarned        map[string]bool         // Workstream IDs already warned about their budget
+	projectBudgetWarned map[string]bool         // RepoPaths already warned about their project budget
 	// Log panel
 	logPanel *LogPanelModel
 	// Keyboard enhancement support (Kitty protocol)
 	keyboardEnhanced bool // True if terminal supports enhanced keyboard (shift+enter, etc.)
 	// Orchestrator for workstream lifecycle operations
 	orchestrator *orchestrator.Orchestrator
+	// Repositories opened in addition to workingDir
+	repos []*repoContext
+	// Predicted merge conflicts between workstreams (refreshed periodically)
+	conflicts []ConflictPair
+	// Workstreams marked ready, merged one at a time in order
+	mergeQueue     []string // Workstream IDs; the head is merging while mergeQueueBusy
+	mergeQueueBusy bool
+	// Repositories whose idle workstreams are being rebased in the background,
+	// and the base commit each workstream's last auto-rebase conflicted with
+	autoRebasing        map[string]bool
+	autoRebaseConflicts map[string]string
+	// Repositories being checkpointed, and when each was last checkpointed
+	checkpointing  map[string]bool
+	lastCheckpoint map[string]time.Time
 	// Synopsis display toggle
 	synopsisHidden bool // True to hide synopsis in pane headers
+	// User-defined lifecycle hooks from the cells config
+	hooks *hooks.Runner
+	// Best-of-N: runtimes chosen for the group whose prompt is being entered
+	pendingBestOfN []string
 }
 
 const tmuxPrefixTimeout = 2 * time.Second
@@ -182,6 +211,8 @@ func NewAppModel(ctx context.Context) AppModel {
 		orch = orchestrator.New(dockerClient, gitFactory, cwd)
 	}
 
+	spent, _ := workstream.LoadSpend(stateDir)
+
 	return AppModel{
 		ctx:                 ctx,
 		manager:             manager,
@@ -194,7 +225,40 @@ func NewAppModel(ctx context.Context) AppModel {
 		logPanel:            logPanel,
 		pairingOrchestrator: sync.NewPairing(gitOps, mutagenOps),
 		orchestrator:        orch,
+		hooks:               hooks.NewRunner(docker.LoadConfig(cwd).Hooks, cwd),
+		spent:               spent,
+	}
+}
+
+// startMerge shows the merge progress dialog in pane i and returns the command
+// performing the merge action.
+func (m *AppModel) startMerge(i int, action MergeAction) tea.Cmd {
+	ws := m.panes[i].Workstream()
+	var title, status, output string
+	var cmd tea.Cmd
+	switch action {
+	case MergeActionMergeMain:
+		title, status, output = "Merging Branch", "Merging into main (merge commit)...", "Merging branch into main (merge commit)..."
+		cmd = MergeBranchCmd(ws)
+	case MergeActionSquashMain:
+		title, status, output = "Squash Merging Branch", "Squash merging into main...", "Merging branch into main (squash)..."
+		cmd = SquashMergeBranchCmd(ws)
+	case MergeActionGHMergeSquash:
+		title, status, output = "Merging PR", "Merging PR via GitHub (squash)...", "Merging PR via GitHub (squash)..."
+		cmd = GHMergePRCmd(ws, "squash")
+	case MergeActionGHMergeMerge:
+		title, status, output = "Merging PR", "Merging PR via GitHub (merge commit)...", "Merging PR via GitHub (merge commit)..."
+		cmd = GHMergePRCmd(ws, "merge")
+	case MergeActionGHMergeRebase:
+		title, status, output = "Merging PR", "Merging PR via GitHub (rebase)...", "Merging PR via GitHub (rebase)..."
+		cmd = GHMergePRCmd(ws, "rebase")
+	default:
+		return nil
 	}
+	m.panes[i].AppendOutput("\n" + output + "\n")
+	dialog := NewProgressDialog(title, fmt.Sprintf("Branch: %s\n\n%s", ws.BranchName, status), ws.ID)
+	m.panes[i].SetInPaneDialog(&dialog)
+	return cmd
 }
 
 // projectName returns the project name derived from the working directory
@@ -233,6 +297,34 @@ func (m *AppModel) setFocusedPane(idx int) {
 	m.manager.SetFocused(idx)
 }
 
+// ptySize returns the PTY dimensions for a pane, accounting for borders and padding.
+func ptySize(p PaneModel) (width, height int) {
+	width, height = p.Width()-4, p.Height()-6
+	if width < 40 {
+		width = 40
+	}
+	if height < 10 {
+		height = 10
+	}
+	return width, height
+}
+
+// promptClaude submits prompt to the Claude session in pane i. If the session
+// has ended it is resumed with the prompt as its first message; an error is
+// returned if there is neither a session nor a container to resume it in.
+func (m *AppModel) promptClaude(i int, prompt string) (tea.Cmd, error) {
+	if m.panes[i].HasPTY() {
+		return nil, m.panes[i].SendInput(prompt, true)
+	}
+	ws := m.panes[i].Workstream()
+	if ws.ContainerID == "" {
+		return nil, fmt.Errorf("Claude's session has ended and the container is not running")
+	}
+	m.panes[i].AppendOutput("\nResuming Claude's session...\n")
+	width, height := ptySize(m.panes[i])
+	return ResumePTYWithPromptCmd(ws, prompt, width, height), nil
+}
+
 // setLayout updates the layout and syncs with persistent manager.
 func (m *AppModel) setLayout(layout LayoutType) {
 	m.layout = layout
@@ -241,8 +333,10 @@ func (m *AppModel) setLayout(layout LayoutType) {
 
 // StateLoadedMsg is sent when state has been loaded from disk
 type StateLoadedMsg struct {
-	State *workstream.AppState
-	Error error
+	State    *workstream.AppState
+	Events   map[string][]workstream.Event // Saved timelines keyed by workstream ID
+	Error    error
+	RepoPath string // Set when the state belongs to an additionally opened repository
 }
 
 // StateSavedMsg is sent when state has been saved
@@ -328,7 +422,15 @@ func LoadStateCmd(dir string) tea.Cmd {
 			return StateLoadedMsg{State: nil, Error: nil}
 		}
 		state, err := workstream.LoadState(dir)
-		return StateLoadedMsg{State: state, Error: err}
+		if err != nil {
+			return StateLoadedMsg{State: state, Error: err}
+		}
+		events, err := workstream.LoadEvents(dir)
+		if err != nil {
+			// Timelines are informational - don't block restoring workstreams
+			LogWarn("Failed to load workstream events: %v", err)
+		}
+		return StateLoadedMsg{State: state, Events: events}
 	}
 }
 
@@ -341,8 +443,34 @@ func SaveStateAndQuitCmd(dir string, workstreams []*workstream.Workstream, focus
 	}
 }
 
+// saveAllStates saves each workstream to the state directory of its
+// repository: repoDirs maps opened repositories to their state directories,
+// everything else goes to dir. Focus and layout are kept for dir only.
+func saveAllStates(dir string, repoDirs map[string]string, workstreams []*workstream.Workstream, focusedIndex int, layout int) error {
+	var primary []*workstream.Workstream
+	byRepo := make(map[string][]*workstream.Workstream)
+	primaryFocus := 0
+	for i, ws := range workstreams {
+		if _, ok := repoDirs[ws.RepoPath]; ok {
+			byRepo[ws.RepoPath] = append(byRepo[ws.RepoPath], ws)
+			continue
+		}
+		if i == focusedIndex {
+			primaryFocus = len(primary)
+		}
+		primary = append(primary, ws)
+	}
+	err := workstream.SaveState(dir, primary, primaryFocus, layout)
+	for repoPath, repoDir := range repoDirs {
+		if repoErr := workstream.SaveState(repoDir, byRepo[repoPath], 0, 0); repoErr != nil && err == nil {
+			err = repoErr
+		}
+	}
+	return err
+}
+
 // PauseAllAndSaveCmd gracefully stops claude processes, pauses containers, validates state, then saves
-func PauseAllAndSaveCmd(dir string, workstreams []*workstream.Workstream, focusedIndex int, layout int) tea.Cmd {
+func PauseAllAndSaveCmd(dir string, repoDirs map[string]string, workstreams []*workstream.Workstream, focusedIndex int, layout int) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
@@ -384,18 +512,20 @@ func PauseAllAndSaveCmd(dir string, workstreams []*workstream.Workstream, focuse
 			// Now pause all containers
 			for _, ws := range workstreams {
 				if ws.ContainerID != "" {
-					_ = dockerClient.PauseContainer(ctx, ws.ContainerID)
+					if err := dockerClient.PauseContainer(ctx, ws.ContainerID); err == nil {
+						ws.RecordEvent(workstream.EventContainerPaused, "")
+					}
 				}
 			}
 			dockerClient.Close()
 
 			// Save state (with any repairs applied)
-			saveErr := workstream.SaveState(dir, workstreams, focusedIndex, layout)
+			saveErr := saveAllStates(dir, repoDirs, workstreams, focusedIndex, layout)
 			return StateSavedMsg{Error: saveErr, RepairMessage: stateRepairMsg}
 		}
 
 		// Fallback: no Docker client, just save state
-		saveErr := workstream.SaveState(dir, workstreams, focusedIndex, layout)
+		saveErr := saveAllStates(dir, repoDirs, workstreams, focusedIndex, layout)
 		return StateSavedMsg{Error: saveErr}
 	}
 }
@@ -405,13 +535,18 @@ func (m AppModel) Init() tea.Cmd {
 	// Try to load saved state on startup
 	// Cursor visibility is now controlled via View().Cursor
 	// Also schedule a check for Kitty keyboard protocol support
-	// Start periodic PR status polling (self-restarts on tick)
+	// Start periodic PR status and token usage polling (self-restarts on tick)
 	return tea.Batch(
 		LoadStateCmd(m.stateDir),
+		reopenReposCmd(m.stateDir),
 		tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
 			return keyboardCheckMsg{}
 		}),
 		prStatusPollTickCmd(),
+		usagePollTickCmd(),
+		conflictPollTickCmd(),
+		autoRebasePollTickCmd(),
+		checkpointPollTickCmd(),
 	)
 }
 
@@ -443,16 +578,18 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-			// Calculate pane bounds (title bar is 1 line, panes start at Y=1)
-			titleBarHeight := 1
+			// Calculate pane bounds (panes start below the title bar and the filter bar, if shown)
+			headerHeight := 1 + m.filterBarHeight()
 			statusBarHeight := 1
 			logPanelH := m.logPanelHeight()
-			availableHeight := m.height - titleBarHeight - statusBarHeight - logPanelH
+			availableHeight := m.height - headerHeight - statusBarHeight - logPanelH
 
-			bounds := CalculatePaneBounds(m.layout, len(m.panes), m.width, availableHeight, titleBarHeight)
+			visible := m.visiblePaneIndices()
+			bounds := CalculatePaneBounds(m.layout, len(visible), m.width, availableHeight, headerHeight)
 			clickedPane := FindPaneAtPosition(bounds, msg.X, msg.Y)
 
-			if clickedPane >= 0 && clickedPane < len(m.panes) {
+			if clickedPane >= 0 && clickedPane < len(visible) {
+				clickedPane = visible[clickedPane]
 				// Focus the clicked pane
 				if m.focusedPane < len(m.panes) {
 					m.panes[m.focusedPane].SetFocused(false)
@@ -771,7 +908,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if len(m.panes) == 0 {
 					// No workstreams - quit immediately without confirmation
 					m.quitting = true
-					m.manager.Close()
+					m.closeManagers()
 					return m, SaveStateAndQuitCmd(m.stateDir, nil, 0, int(m.layout))
 				}
 				dialog := NewQuitConfirmDialog()
@@ -790,7 +927,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(m.panes) == 0 {
 				// No workstreams - quit immediately without confirmation
 				m.quitting = true
-				m.manager.Close()
+				m.closeManagers()
 				return m, SaveStateAndQuitCmd(m.stateDir, nil, 0, int(m.layout))
 			}
 			dialog := NewQuitConfirmDialog()
@@ -818,8 +955,9 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				// left/right fall through to navigate panes
 			}
-			// Arrow keys use spatial navigation based on layout
-			if len(m.panes) > 1 {
+			// Arrow keys use spatial navigation based on layout (visible panes only)
+			visible := m.visiblePaneIndices()
+			if len(visible) > 1 {
 				var dir Direction
 				switch msg.String() {
 				case "left":
@@ -832,10 +970,16 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					dir = DirDown
 				}
 
-				neighbor := FindNeighbor(m.layout, len(m.panes), m.focusedPane, dir)
+				pos := 0
+				for p, i := range visible {
+					if i == m.focusedPane {
+						pos = p
+					}
+				}
+				neighbor := FindNeighbor(m.layout, len(visible), pos, dir)
 				if neighbor >= 0 {
 					m.panes[m.focusedPane].SetFocused(false)
-					m.setFocusedPane(neighbor)
+					m.setFocusedPane(visible[neighbor])
 					m.panes[m.focusedPane].SetFocused(true)
 				}
 			}
@@ -879,10 +1023,41 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "n":
 			// New workstream dialog
 			dialog := NewWorkstreamDialog()
-			dialog.SetSize(70, 15)
+			dialog.SetTemplates(docker.LoadConfig(m.workingDir).Templates)
+			height := 15
+			if len(m.repos) > 0 {
+				// Templates follow the repository picked in the dialog
+				templates := [][]docker.TemplateConfig{dialog.templates}
+				for _, r := range m.repos {
+					templates = append(templates, docker.LoadConfig(r.Path).Templates)
+				}
+				dialog.SetRepos(m.repoNames(), templates)
+				height += 2 // Repository picker
+			}
+			dialog.SetSize(70, height)
+			m.dialog = &dialog
+			return m, nil
+
+		case "N":
+			// Best-of-N: run one prompt in several cells
+			dialog := NewBestOfNDialog()
+			dialog.SetSize(55, 17)
 			m.dialog = &dialog
 			return m, nil
 
+		case "C":
+			// Compare the Best-of-N group of the focused pane
+			if len(m.panes) > 0 && m.focusedPane < len(m.panes) {
+				groupID := m.panes[m.focusedPane].Workstream().GetGroupID()
+				if len(m.groupMembers(groupID)) < 2 {
+					m.toast = "Focused pane is not part of a Best-of-N group"
+					m.toastExpiry = time.Now().Add(toastDuration)
+					return m, nil
+				}
+				return m, m.openGroupComparison(groupID)
+			}
+			return m, nil
+
 		case "d":
 			// Destroy focused workstream
 			if len(m.panes) > 0 && m.focusedPane < len(m.panes) {
@@ -891,7 +1066,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Skip confirmation for errored workstreams - nothing to lose
 				if ws.GetState() == workstream.StateError {
 					ws := m.removePane(m.focusedPane)
-					return m, StopContainerCmd(ws)
+					return m, tea.Batch(ArchiveAndStopContainerCmd(ws, m.stateDirFor(ws)), m.runHooks(hooks.EventDestroy, ws))
 				}
 				dialog := NewDestroyDialog(ws.BranchName, ws.ID)
 				dialog.SetSize(50, 15)
@@ -910,6 +1085,11 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.toastExpiry = time.Now().Add(toastDuration)
 				return m, nil
 			}
+			if ws.RepoPath != "" {
+				m.toast = "Pairing is only available in the repository ccells was started in"
+				m.toastExpiry = time.Now().Add(toastDuration)
+				return m, nil
+			}
 
 			// Get current pairing state from orchestrator
 			pairingState := m.pairingOrchestrator.GetState()
@@ -976,6 +1156,12 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "i", "enter":
 			// Enter input mode for focused pane
 			if len(m.panes) > 0 && m.focusedPane < len(m.panes) {
+				if m.panes[m.focusedPane].IsBudgetExceeded() {
+					// Keep Claude stopped until the budget is raised
+					m.toast = "Budget exceeded - press $ (workstream) or B (project) to raise it"
+					m.toastExpiry = time.Now().Add(toastDuration * 2)
+					return m, nil
+				}
 				m.inputMode = true
 				return m, nil
 			}
@@ -1016,6 +1202,144 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "t":
+			// Show event timeline for focused workstream
+			if len(m.panes) > 0 && m.focusedPane < len(m.panes) {
+				dialog := NewTimelineDialog(m.panes[m.focusedPane].Workstream())
+				dialog.SetSize(m.width-10, m.height-6)
+				m.dialog = &dialog
+			}
+			return m, nil
+
+		case "/":
+			// Filter panes by label, state or text
+			dialog := NewFilterDialog(m.paneFilter.query)
+			dialog.SetSize(60, 16)
+			m.dialog = &dialog
+			return m, nil
+
+		case "#":
+			// Edit labels and priority of the focused workstream
+			if len(m.panes) > 0 && m.focusedPane < len(m.panes) {
+				dialog := NewLabelsDialog(m.panes[m.focusedPane].Workstream())
+				dialog.SetSize(60, 12)
+				m.dialog = &dialog
+			}
+			return m, nil
+
+		case "$":
+			// Edit the token budget of the focused workstream
+			if len(m.panes) > 0 && m.focusedPane < len(m.panes) {
+				ws := m.panes[m.focusedPane].Workstream()
+				budget := m.reloadBudgetConfig(ws.RepoPath)
+				dialog := NewBudgetDialog(ws, m.workstreamBudget(ws), budget.Workstream)
+				dialog.SetSize(65, 14)
+				m.dialog = &dialog
+			}
+			return m, nil
+
+		case "B":
+			// Edit the token budget of the focused workstream's project
+			var repoPath, workstreamID string
+			if len(m.panes) > 0 && m.focusedPane < len(m.panes) {
+				ws := m.panes[m.focusedPane].Workstream()
+				repoPath, workstreamID = ws.RepoPath, ws.ID
+			}
+			budget := m.reloadBudgetConfig(repoPath)
+			dialog := NewProjectBudgetDialog(budget.Project, m.projectSpend(repoPath))
+			dialog.WorkstreamID = workstreamID // Identifies the repository
+			if len(m.repos) > 0 {
+				dialog.Title += ": " + m.repoName(repoPath)
+			}
+			dialog.SetSize(65, 14)
+			m.dialog = &dialog
+			return m, nil
+
+		case "X":
+			// Predict conflicts between workstreams, then show the matrix
+			m.toast = "Analyzing conflicts..."
+			m.toastExpiry = time.Now().Add(toastDuration)
+			return m, AnalyzeConflictsCmd(m.workstreams(), true)
+
+		case "D":
+			// Show the focused workstream's diff against its merge base
+			if len(m.panes) > 0 {
+				ws := m.panes[m.focusedPane].Workstream()
+				dialog := NewDiffDialog(ws)
+				dialog.SetSize(m.width-4, m.height-2)
+				m.dialog = &dialog
+				return m, LoadDiffCmd(ws)
+			}
+			return m, nil
+
+		case "F":
+			// Send the focused workstream's unresolved PR review feedback to Claude
+			if len(m.panes) > 0 {
+				return m, m.sendPRFeedback(m.focusedPane)
+			}
+			return m, nil
+
+		case "P":
+			// Cherry-pick commits from another workstream into the focused one
+			if len(m.panes) > 0 {
+				m.openCherryPick(m.focusedPane)
+			}
+			return m, nil
+
+		case "S":
+			// Add directories to the focused workstream's sparse checkout
+			if len(m.panes) > 0 {
+				m.openSparseWiden(m.focusedPane)
+			}
+			return m, nil
+
+		case "W":
+			// Browse the focused workstream's WIP checkpoints
+			if len(m.panes) > 0 {
+				return m, m.openCheckpoints(m.focusedPane)
+			}
+			return m, nil
+
+		case "M":
+			// Mark the focused workstream ready for the merge queue (or unmark it)
+			if len(m.panes) > 0 {
+				return m, m.toggleMergeReady(m.focusedPane)
+			}
+			return m, nil
+
+		case "R":
+			// Open another repository
+			dialog := NewOpenRepoDialog(m.repoNames())
+			dialog.SetSize(75, 12)
+			m.dialog = &dialog
+			return m, nil
+
+		case "o":
+			// Cycle pane sort order
+			m.paneSort = m.paneSort.Next()
+			m.sortPanes(m.paneSort)
+			m.toast = fmt.Sprintf("Sorted by %s", m.paneSort)
+			m.toastExpiry = time.Now().Add(toastDuration)
+			return m, nil
+
+		case "A":
+			// Browse archived workstreams of all open repositories
+			entries, err := m.loadArchives()
+			if err != nil {
+				m.toast = fmt.Sprintf("Cannot read archive: %v", err)
+				m.toastExpiry = time.Now().Add(toastDuration * 2)
+				return m, nil
+			}
+			if len(entries) == 0 {
+				m.toast = "Archive is empty"
+				m.toastExpiry = time.Now().Add(toastDuration)
+				return m, nil
+			}
+			dialog := NewArchiveDialog(entries)
+			dialog.SetSize(m.width-10, m.height-6)
+			m.dialog = &dialog
+			return m, nil
+
 		case "L":
 			// Cycle through layout types
 			m.setLayout(m.layout.Next())
@@ -1096,24 +1420,28 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "space":
-			// Toggle focused pane with main pane (position 0)
-			if len(m.panes) > 1 {
-				if m.focusedPane > 0 {
+			// Toggle focused pane with main pane (first visible position)
+			visible := m.visiblePaneIndices()
+			if len(visible) > 1 {
+				mainPos := visible[0]
+				if m.focusedPane > mainPos {
+					// Arranging panes by hand turns the sort off
+					m.paneSort = paneSortCreated
 					// Swap focused pane to main position
 					swapPos := m.focusedPane
-					m.panes[0], m.panes[swapPos] = m.panes[swapPos], m.panes[0]
+					m.panes[mainPos], m.panes[swapPos] = m.panes[swapPos], m.panes[mainPos]
 					m.panes[swapPos].SetFocused(false)
-					m.setFocusedPane(0)
-					m.panes[0].SetFocused(true)
+					m.setFocusedPane(mainPos)
+					m.panes[mainPos].SetFocused(true)
 					m.lastSwapPosition = swapPos // Remember for toggle back
 					m.updateLayout()
 					m.toast = "Moved to main pane"
 					m.toastExpiry = time.Now().Add(toastDuration)
-				} else if m.lastSwapPosition > 0 && m.lastSwapPosition < len(m.panes) {
+				} else if m.lastSwapPosition > mainPos && m.lastSwapPosition < len(m.panes) {
 					// At main position - swap back to previous position
 					swapPos := m.lastSwapPosition
-					m.panes[0], m.panes[swapPos] = m.panes[swapPos], m.panes[0]
-					m.panes[0].SetFocused(false)
+					m.panes[mainPos], m.panes[swapPos] = m.panes[swapPos], m.panes[mainPos]
+					m.panes[mainPos].SetFocused(false)
 					m.setFocusedPane(swapPos)
 					m.panes[swapPos].SetFocused(true)
 					m.lastSwapPosition = 0 // Clear after swap back
@@ -1125,10 +1453,17 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "tab":
-			// Cycle focus (stay in nav mode)
-			if len(m.panes) > 0 {
+			// Cycle focus through visible panes (stay in nav mode)
+			if visible := m.visiblePaneIndices(); len(visible) > 0 {
+				next := visible[0]
+				for _, i := range visible {
+					if i > m.focusedPane {
+						next = i
+						break
+					}
+				}
 				m.panes[m.focusedPane].SetFocused(false)
-				m.setFocusedPane((m.focusedPane + 1) % len(m.panes))
+				m.setFocusedPane(next)
 				m.panes[m.focusedPane].SetFocused(true)
 			}
 			return m, nil
@@ -1137,7 +1472,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Direct focus by pane number (searches by permanent index, not position)
 			targetIndex := int(msg.String()[0] - '0') // Convert to 1-based index
 			for i, pane := range m.panes {
-				if pane.Index() == targetIndex {
+				if pane.Index() == targetIndex && m.isPaneVisible(i) {
 					if m.focusedPane < len(m.panes) {
 						m.panes[m.focusedPane].SetFocused(false)
 					}
@@ -1153,17 +1488,12 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			dialog := NewResourceUsageDialog(false) // Start with project view
 			dialog.SetSize(65, 30)                  // Taller to accommodate disk usage section
 			m.dialog = &dialog
-			// Fetch both resource stats and Claude usage for focused pane
-			var cmds []tea.Cmd
-			cmds = append(cmds, FetchResourceStatsCmd(false, m.getContainerIDs()))
-			// Fetch Claude usage from focused container
-			if len(m.panes) > 0 && m.focusedPane < len(m.panes) {
-				ws := m.panes[m.focusedPane].Workstream()
-				if ws.ContainerID != "" {
-					cmds = append(cmds, FetchClaudeUsageCmd(ws.ContainerID))
-				}
-			}
-			return m, tea.Batch(cmds...)
+			// Show recorded token usage now and refresh it alongside resource stats
+			dialog.SetClaudeUsage(renderUsageSummary(m.panes))
+			return m, tea.Batch(
+				FetchResourceStatsCmd(false, m.getContainerIDs()),
+				RefreshUsageCmd(m.workstreams()),
+			)
 
 		case "s":
 			// Settings dialog - first get container count
@@ -1198,13 +1528,30 @@ Navigation Mode:
   ←→ ↑↓       Switch between panes
   i, Enter    Enter input mode (interact with Claude)
   n           New workstream
+  N           Best-of-N (same prompt in several cells)
+  C           Compare Best-of-N group / pick winner
   d           Destroy workstream
+  /           Filter panes (label:x, state:x, text)
+  #           Set labels and priority
+  o           Cycle sort order (created/priority/activity)
+  $           Set token budget of focused workstream
+  B           Set project token budget
+  A           Browse archive / restore destroyed workstream
+  D           Diff viewer and review comments for the focused workstream
+  X           Conflict matrix (predicted merge conflicts)
+  F           Send unresolved PR review feedback to Claude
+  M           Mark ready / unmark for the merge queue
+  P           Cherry-pick commits from another workstream
+  W           Browse WIP checkpoints (diff, restore)
+  S           Widen the sparse checkout (add directories)
+  R           Open another repository
   m           Merge/PR options
   p           Toggle pairing mode
   u           Usage (CPU/memory/tokens)
   y           Toggle synopsis display
   s           Settings
   l           Show logs
+  t           Show workstream timeline
   e           Export logs to file
   L           Cycle layout
   `+"`"+`           Toggle log panel (system logs)
@@ -1255,7 +1602,26 @@ Scroll Mode:
 			// Create new workstream for summarizing (branch name derived from title later)
 			ws := workstream.NewForSummarizing(msg.Value)
 			ws.Runtime = globalRuntime // Set runtime from global config
-			if err := m.manager.Add(ws); err != nil {
+			if msg.Repo > 0 && msg.Repo <= len(m.repos) {
+				ws.RepoPath = m.repos[msg.Repo-1].Path
+			}
+			sparsePaths := msg.SparsePaths
+			if tmpl, ok := loadTemplate(m.repoDir(ws), msg.Template); ok {
+				ws.Template = tmpl.Name
+				if tmpl.Runtime != "" {
+					ws.Runtime = normalizeRuntime(tmpl.Runtime)
+				}
+				if len(sparsePaths) == 0 && len(tmpl.SparsePaths) > 0 {
+					// Paths typed in the dialog take precedence over the template's
+					paths, err := git.CleanSparsePaths(tmpl.SparsePaths)
+					if err != nil {
+						LogWarn("Template %s: ignoring sparse_paths: %v", tmpl.Name, err)
+					}
+					sparsePaths = paths
+				}
+			}
+			ws.SetSparsePaths(sparsePaths)
+			if err := m.managerFor(ws).Add(ws); err != nil {
 				m.toast = fmt.Sprintf("Cannot create workstream: %v", err)
 				m.toastExpiry = time.Now().Add(toastDuration * 2)
 				return m, nil
@@ -1265,6 +1631,7 @@ Scroll Mode:
 			m.nextPaneIndex++
 			pane.SetSummarizing(true) // Start with summarizing animation
 			m.panes = append(m.panes, pane)
+			m.revealPane(len(m.panes) - 1)
 			m.updateLayoutQuiet() // Use quiet mode to avoid sending Ctrl+L/Ctrl+O to existing panes
 			// Focus the new pane
 			if m.focusedPane < len(m.panes)-1 && m.focusedPane < len(m.panes) {
@@ -1272,29 +1639,146 @@ Scroll Mode:
 			}
 			m.setFocusedPane(len(m.panes) - 1)
 			m.panes[m.focusedPane].SetFocused(true)
+			m.keepPanesSorted()
 			// Generate title first (container starts after title is ready)
 			return m, tea.Batch(GenerateTitleCmd(ws), spinnerTickCmd())
 
+		case DialogOpenRepo:
+			return m, OpenRepoCmd(msg.Value)
+
+		case DialogFilter:
+			m.applyPaneFilter(msg.Value)
+			if m.paneFilter.Active() {
+				m.toast = fmt.Sprintf("Filter: %s (%d/%d panes)", m.paneFilter.query, len(m.visiblePaneIndices()), len(m.panes))
+			} else {
+				m.toast = "Filter cleared"
+			}
+			m.toastExpiry = time.Now().Add(toastDuration)
+			return m, nil
+
+		case DialogSparseWiden:
+			return m, m.widenSparseCheckout(msg)
+
+		case DialogLabels:
+			labels, priority := parseLabelInput(msg.Value)
+			for i := range m.panes {
+				if ws := m.panes[i].Workstream(); ws.ID == msg.WorkstreamID {
+					ws.SetLabels(labels)
+					ws.SetPriority(priority)
+					m.managerFor(ws).UpdateWorkstream(ws.ID)
+					break
+				}
+			}
+			// The edited pane may no longer match the filter or sort position
+			m.applyPaneFilter(m.paneFilter.query)
+			m.keepPanesSorted()
+			return m, nil
+
+		case DialogBudget, DialogProjectBudget:
+			budget, err := parseBudgetInput(msg.Value)
+			if err != nil {
+				m.toast = fmt.Sprintf("Budget not changed: %v", err)
+				m.toastExpiry = time.Now().Add(toastDuration * 2)
+				return m, nil
+			}
+			if msg.Type == DialogProjectBudget {
+				var repoPath string
+				if i := m.paneIndexByID(msg.WorkstreamID); i >= 0 {
+					repoPath = m.panes[i].Workstream().RepoPath
+				}
+				if err := docker.SaveProjectBudget(m.repoPathDir(repoPath), budget); err != nil {
+					m.toast = fmt.Sprintf("Failed to save project budget: %v", err)
+					m.toastExpiry = time.Now().Add(toastDuration * 2)
+					return m, nil
+				}
+				project := m.reloadBudgetConfig(repoPath).Project
+				m.toast = fmt.Sprintf("Project budget set to %s", describeBudgetLimit(project))
+			} else {
+				for i := range m.panes {
+					if ws := m.panes[i].Workstream(); ws.ID == msg.WorkstreamID {
+						ws.SetBudget(budget)
+						m.managerFor(ws).UpdateWorkstream(ws.ID)
+						m.toast = fmt.Sprintf("Budget for %s set to %s", ws.BranchName, describeBudgetLimit(m.workstreamBudget(ws)))
+						break
+					}
+				}
+			}
+			m.toastExpiry = time.Now().Add(toastDuration)
+			// Raising a budget releases panes that were stopped
+			return m, m.checkBudgets(nil)
+
+		case DialogArchive:
+			// Value is the archived workstream's ID
+			entries, err := m.loadArchives()
+			if err != nil {
+				m.toast = fmt.Sprintf("Cannot read archive: %v", err)
+				m.toastExpiry = time.Now().Add(toastDuration * 2)
+				return m, nil
+			}
+			for _, entry := range entries {
+				if entry.ID != msg.Value {
+					continue
+				}
+				ws := entry.Restore()
+				ws.Runtime = normalizeRuntime(ws.Runtime)
+				if err := m.managerFor(ws).Add(ws); err != nil {
+					m.toast = fmt.Sprintf("Cannot restore workstream: %v", err)
+					m.toastExpiry = time.Now().Add(toastDuration * 2)
+					return m, nil
+				}
+				ws.RecordEvent(workstream.EventRestored, "")
+				delete(m.spentUsage(ws.RepoPath), ws.ID) // Counted as live again
+				pane := NewPaneModel(ws)
+				pane.SetIndex(m.nextPaneIndex)
+				m.nextPaneIndex++
+				pane.SetInitializing(true)
+				m.panes = append(m.panes, pane)
+				m.revealPane(len(m.panes) - 1)
+				m.updateLayoutQuiet()
+				if m.focusedPane < len(m.panes)-1 && m.focusedPane < len(m.panes) {
+					m.panes[m.focusedPane].SetFocused(false)
+				}
+				m.setFocusedPane(len(m.panes) - 1)
+				m.panes[m.focusedPane].SetFocused(true)
+				m.keepPanesSorted()
+				m.toast = fmt.Sprintf("Restoring %s...", ws.BranchName)
+				m.toastExpiry = time.Now().Add(toastDuration)
+				return m, tea.Batch(RestoreArchivedCmd(ws, entry, m.stateDirFor(ws)), spinnerTickCmd())
+			}
+			return m, nil
+
+		case DialogBestOfNPrompt:
+			runtimes := m.pendingBestOfN
+			m.pendingBestOfN = nil
+			if len(runtimes) == 0 {
+				return m, nil
+			}
+			return m, m.startBestOfN(msg.Value, runtimes)
+
+		case DialogBestOfNDestroyRest:
+			// Value is "0" for "Yes, destroy others", "1" for "No, keep them"
+			return m, m.finishBestOfN(msg.WorkstreamID, msg.Value == "0")
+
 		case DialogDestroy:
 			// Destroy workstream
 			for i, pane := range m.panes {
 				if pane.Workstream().ID == msg.WorkstreamID {
 					ws := m.removePane(i)
-					return m, StopContainerCmd(ws)
+					return m, tea.Batch(ArchiveAndStopContainerCmd(ws, m.stateDirFor(ws)), m.runHooks(hooks.EventDestroy, ws))
 				}
 			}
 
 		case DialogPruneProjectConfirm:
 			// User typed "destroy" - close panes for this project, prune project containers and branches
-			m.clearAllPanes()
+			clearCmd := m.clearAllPanes()
 			// Prune containers and empty branches for this project only
-			return m, PruneProjectContainersAndBranchesCmd(m.projectName())
+			return m, tea.Batch(clearCmd, PruneProjectContainersAndBranchesCmd(m.projectName()))
 
 		case DialogPruneAllConfirm:
 			// User typed "destroy" - close all panes, prune ALL containers globally
-			m.clearAllPanes()
+			clearCmd := m.clearAllPanes()
 			// Prune all containers and empty branches (globally!)
-			return m, PruneAllContainersAndBranchesCmd()
+			return m, tea.Batch(clearCmd, PruneAllContainersAndBranchesCmd())
 
 		case DialogPostMergeDestroy:
 			// Value is "0" for "Yes, destroy container", "1" for "No, keep container"
@@ -1305,7 +1789,7 @@ Scroll Mode:
 						ws := m.removePane(i)
 						m.toast = "Destroying merged container..."
 						m.toastExpiry = time.Now().Add(toastDuration)
-						return m, StopContainerCmd(ws)
+						return m, tea.Batch(ArchiveAndStopContainerCmd(ws, m.stateDirFor(ws)), m.runHooks(hooks.EventDestroy, ws))
 					}
 				}
 			}
@@ -1335,11 +1819,15 @@ Scroll Mode:
 						fileList := strings.Join(msg.ConflictFiles, ", ")
 						prompt := fmt.Sprintf("Please run `git fetch origin %s && git rebase origin/%s` to start the rebase, then resolve the merge conflicts in these files: %s. After resolving each conflict, run `git add <file>` and then `git rebase --continue`. Let me know when done.", defaultBranch, defaultBranch, fileList)
 
-						// Send the prompt to Claude with Enter (uses Kitty keyboard protocol)
-						if err := m.panes[i].SendInput(prompt, true); err != nil {
+						// Send the prompt to Claude, resuming its session if it ended
+						cmd, err := m.promptClaude(i, prompt)
+						if err != nil {
 							LogWarn("Failed to send conflict resolution prompt to pane %d: %v", i, err)
+							m.panes[i].AppendOutput(fmt.Sprintf("Could not ask Claude: %v. Resolve the conflicts manually.\n", err))
+							m.toast = "Could not ask Claude to fix conflicts"
+							m.toastExpiry = time.Now().Add(toastDuration)
 						}
-						return m, nil
+						return m, cmd
 					}
 				}
 			}
@@ -1359,6 +1847,26 @@ Scroll Mode:
 				m.toastExpiry = time.Now().Add(toastDuration)
 			}
 
+		case DialogVerifyFailed:
+			// User chose to send the verification failure to Claude
+			for i := range m.panes {
+				if m.panes[i].Workstream().ID == msg.WorkstreamID {
+					m.panes[i].ClearInPaneDialog()
+					m.panes[i].AppendOutput("\nAsking Claude to fix verification failures...\n")
+					// Send the failure to Claude, resuming its session if it ended
+					cmd, err := m.promptClaude(i, msg.Value)
+					if err != nil {
+						LogWarn("Failed to send verification failure to pane %d: %v", i, err)
+						m.panes[i].AppendOutput(fmt.Sprintf("Could not ask Claude: %v. Fix the failures manually.\n", err))
+						m.toast = "Could not send verification failure to Claude"
+					} else {
+						m.toast = "Sent verification failure to Claude"
+					}
+					m.toastExpiry = time.Now().Add(toastDuration)
+					return m, cmd
+				}
+			}
+
 		case DialogForcePushConfirm:
 			// User typed "force push" - execute force push
 			for i := range m.panes {
@@ -1386,11 +1894,11 @@ Scroll Mode:
 					workstreams = append(workstreams, ws)
 				}
 				m.quitting = true
-				m.manager.Close() // Final flush before quit
-				return m, PauseAllAndSaveCmd(m.stateDir, workstreams, m.focusedPane, int(m.layout))
+				m.closeManagers() // Final flush before quit
+				return m, PauseAllAndSaveCmd(m.stateDir, m.repoStateDirs(), workstreams, m.focusedPane, int(m.layout))
 			}
 			m.quitting = true
-			m.manager.Close() // Final flush before quit
+			m.closeManagers() // Final flush before quit
 			// Save empty state (no panes) so next startup is clean
 			return m, SaveStateAndQuitCmd(m.stateDir, nil, 0, int(m.layout))
 		}
@@ -1401,24 +1909,29 @@ Scroll Mode:
 		for i := range m.panes {
 			if m.panes[i].Workstream().ID == msg.WorkstreamID {
 				ws := m.panes[i].Workstream()
+				if old := ws.ContainerID; old != "" && old != msg.ContainerID {
+					forgetContainerUsage(old) // Replaced by a rebuild
+				}
 				ws.SetContainerID(msg.ContainerID)
-				m.manager.UpdateWorkstream(ws.ID)
+				m.managerFor(ws).UpdateWorkstream(ws.ID)
 				if msg.IsResume {
+					ws.RecordEvent(workstream.EventContainerResumed, shortContainerID(msg.ContainerID))
 					m.panes[i].SetInitStatus("Resuming Claude Code...")
 				} else {
+					ws.RecordEvent(workstream.EventContainerCreated, shortContainerID(msg.ContainerID))
 					m.panes[i].SetInitStatus("Starting Claude Code...")
 				}
-				// Calculate PTY dimensions from pane size (account for borders/padding)
-				ptyWidth := m.panes[i].Width() - 4
-				ptyHeight := m.panes[i].Height() - 6
-				if ptyWidth < 40 {
-					ptyWidth = 40
+				ptyWidth, ptyHeight := ptySize(m.panes[i])
+				// Start PTY session with initial prompt (or --continue for resume)
+				prompt := ws.Prompt
+				if tmpl, ok := loadTemplate(m.repoDir(ws), ws.Template); ok && !msg.IsResume {
+					prompt = tmpl.ApplyPrompt(prompt)
 				}
-				if ptyHeight < 10 {
-					ptyHeight = 10
+				ptyCmd := StartPTYCmd(ws, prompt, ptyWidth, ptyHeight, msg.IsResume)
+				if msg.IsResume {
+					return m, ptyCmd
 				}
-				// Start PTY session with initial prompt (or --continue for resume)
-				return m, StartPTYCmd(ws, ws.Prompt, ptyWidth, ptyHeight, msg.IsResume)
+				return m, tea.Batch(ptyCmd, m.runHooks(hooks.EventCreate, ws))
 			}
 		}
 		return m, nil
@@ -1442,7 +1955,9 @@ Scroll Mode:
 			if m.panes[i].Workstream().ID == msg.WorkstreamID {
 				ws := m.panes[i].Workstream()
 				// Clear the old container ID
+				forgetContainerUsage(ws.ContainerID)
 				ws.ContainerID = ""
+				ws.RecordEvent(workstream.EventError, "container not found, rebuilding")
 				m.panes[i].AppendOutput("\nContainer not found, rebuilding...\n")
 				m.panes[i].SetInitializing(true)
 				return m, RebuildContainerCmd(ws)
@@ -1513,8 +2028,12 @@ Scroll Mode:
 							existingBranches = append(existingBranches, bn)
 						}
 					}
-					// Derive branch name from the generated title
-					ws.SetBranchNameFromTitle(title, existingBranches)
+					// Derive branch name from the generated title, with the template's prefix
+					if tmpl, ok := loadTemplate(m.repoDir(ws), ws.Template); ok && tmpl.BranchPrefix != "" {
+						ws.SetBranchNameFromTitleWithPrefix(title, tmpl.BranchPrefix, existingBranches)
+					} else {
+						ws.SetBranchNameFromTitle(title, existingBranches)
+					}
 
 					// Set title and start fading animation immediately
 					m.panes[i].SetSummarizeTitle(title)
@@ -1538,7 +2057,7 @@ Scroll Mode:
 				ws := m.panes[i].Workstream()
 				if msg.Error == nil && msg.Synopsis != "" {
 					ws.SetSynopsis(msg.Synopsis)
-					m.manager.UpdateWorkstream(ws.ID)
+					m.managerFor(ws).UpdateWorkstream(ws.ID)
 				}
 				break
 			}
@@ -1642,6 +2161,10 @@ Scroll Mode:
 
 	case ContainerStoppedMsg:
 		// Container stopped (already removed from panes in DialogDestroy)
+		if msg.KeptWorktree != "" {
+			m.toast = fmt.Sprintf("Couldn't save uncommitted changes; worktree kept at %s", msg.KeptWorktree)
+			m.toastExpiry = time.Now().Add(toastDuration)
+		}
 		return m, nil
 
 	case PTYReadyMsg:
@@ -1654,7 +2177,7 @@ Scroll Mode:
 				m.panes[i].SetPTY(msg.Session)
 				m.panes[i].SetInitStatus("Starting Claude Code...")
 				// PersistentManager auto-saves state
-				m.manager.UpdateWorkstream(ws.ID)
+				m.managerFor(ws).UpdateWorkstream(ws.ID)
 				return m, nil
 			}
 		}
@@ -1808,7 +2331,11 @@ Scroll Mode:
 				}
 				// Generate synopsis after session ends
 				var cmds []tea.Cmd
-				cmds = append(cmds, GenerateSynopsisCmd(ws))
+				cmds = append(cmds, GenerateSynopsisCmd(ws), m.runHooks(hooks.EventIdle, ws))
+				// Open the comparison once every cell of a Best-of-N group is done
+				if groupID := ws.GetGroupID(); m.dialog == nil && m.groupAllIdle(groupID) {
+					cmds = append(cmds, m.openGroupComparison(groupID))
+				}
 				// Start fade animation if needed
 				if m.panes[i].IsFading() {
 					cmds = append(cmds, fadeTickCmd())
@@ -1826,6 +2353,8 @@ Scroll Mode:
 					// Send "continue" followed by enter to resume the interrupted task (uses Kitty Enter)
 					if err := m.panes[i].SendInput("continue", true); err != nil {
 						LogWarn("Failed to send 'continue' to pane %d: %v", i, err)
+					} else {
+						m.panes[i].Workstream().RecordEvent(workstream.EventAutoContinue, "")
 					}
 				}
 				break
@@ -1841,6 +2370,8 @@ Scroll Mode:
 					// Just send enter to confirm the continue prompt (uses Kitty Enter)
 					if err := m.panes[i].SendInput("", true); err != nil {
 						LogWarn("Failed to send Enter to pane %d: %v", i, err)
+					} else {
+						m.panes[i].Workstream().RecordEvent(workstream.EventAutoContinue, "")
 					}
 				}
 				break
@@ -1854,7 +2385,7 @@ Scroll Mode:
 			if m.panes[i].Workstream().ID == msg.WorkstreamID {
 				ws := m.panes[i].Workstream()
 				ws.SetClaudeSessionID(msg.SessionID)
-				m.manager.UpdateWorkstream(ws.ID) // Auto-persists
+				m.managerFor(ws).UpdateWorkstream(ws.ID) // Auto-persists
 				break
 			}
 		}
@@ -1892,6 +2423,97 @@ Scroll Mode:
 		}
 		return m, nil
 
+	case BestOfNSelectMsg:
+		// Configuration chosen - ask for the shared prompt
+		m.pendingBestOfN = msg.Runtimes
+		dialog := NewBestOfNPromptDialog(len(msg.Runtimes))
+		dialog.SetSize(70, 15)
+		m.dialog = &dialog
+		return m, nil
+
+	case DiffLoadedMsg:
+		if m.dialog != nil && m.dialog.Type == DialogDiff && m.dialog.WorkstreamID == msg.WorkstreamID {
+			m.dialog.SetDiff(msg)
+		}
+		return m, nil
+
+	case ReviewCommentMsg, ReviewAddressedMsg, ReviewSubmitMsg:
+		m.handleReviewMsg(msg)
+		return m, nil
+
+	case CherryPickSourceMsg:
+		if src := m.paneIndexByID(msg.SourceID); src >= 0 {
+			return m, LoadCherryPickCommitsCmd(msg.WorkstreamID, m.panes[src].Workstream())
+		}
+		return m, nil
+
+	case CherryPickCommitsMsg:
+		if m.dialog != nil && m.dialog.Type == DialogCherryPick && m.dialog.WorkstreamID == msg.WorkstreamID {
+			m.dialog.SetCherryPickCommits(msg)
+		}
+		return m, nil
+
+	case CherryPickApplyMsg:
+		return m, m.applyCherryPick(msg)
+
+	case SparseWidenedMsg:
+		m.handleSparseWidened(msg)
+		return m, nil
+
+	case CheckpointsLoadedMsg:
+		if m.dialog != nil && m.dialog.Type == DialogCheckpoints && m.dialog.WorkstreamID == msg.WorkstreamID {
+			m.dialog.SetCheckpoints(msg)
+		}
+		return m, nil
+
+	case CheckpointDiffRequestMsg:
+		if i := m.paneIndexByID(msg.WorkstreamID); i >= 0 {
+			return m, CheckpointDiffCmd(m.panes[i].Workstream(), msg.Checkpoint)
+		}
+		return m, nil
+
+	case CheckpointDiffMsg:
+		m.showCheckpointDiff(msg)
+		return m, nil
+
+	case CheckpointRestoreMsg:
+		return m, m.restoreCheckpoint(msg)
+
+	case CheckpointRestoredMsg:
+		m.handleCheckpointRestored(msg)
+		return m, nil
+
+	case CherryPickResultMsg:
+		return m, m.handleCherryPickResult(msg)
+
+	case DiffRangeMsg:
+		if m.dialog != nil && m.dialog.Type == DialogDiff && m.dialog.WorkstreamID == msg.WorkstreamID {
+			m.dialog.SetDiffRange(msg)
+		}
+		return m, nil
+
+	case GroupComparisonMsg:
+		if m.dialog != nil && m.dialog.Type == DialogBestOfNCompare && m.dialog.groupID == msg.GroupID {
+			m.dialog.SetCompareEntries(msg.Entries)
+		}
+		return m, nil
+
+	case BestOfNWinnerMsg:
+		m.dialog = nil
+		others := len(m.groupMembers(msg.GroupID)) - 1
+		for i := range m.panes {
+			if m.panes[i].Workstream().ID == msg.WinnerID {
+				if others < 1 {
+					return m, m.finishBestOfN(msg.WinnerID, false)
+				}
+				dialog := NewBestOfNDestroyRestDialog(m.panes[i].Workstream().BranchName, msg.WinnerID, others)
+				dialog.SetSize(55, 15)
+				m.dialog = &dialog
+				break
+			}
+		}
+		return m, nil
+
 	case DialogCancelMsg:
 		m.dialog = nil
 		// Also clear any in-pane dialog on the focused pane
@@ -1941,20 +2563,22 @@ Scroll Mode:
 				ws := m.panes[i].Workstream()
 				switch msg.Action {
 				case MergeActionCreatePR:
-					m.panes[i].AppendOutput("\nCreating pull request...\n")
-					dialog := NewProgressDialog("Creating Pull Request", fmt.Sprintf("Branch: %s\n\nPushing and creating PR...", ws.BranchName), ws.ID)
-					m.panes[i].SetInPaneDialog(&dialog)
-					return m, CreatePRCmd(ws)
-				case MergeActionMergeMain:
-					m.panes[i].AppendOutput("\nMerging branch into main (merge commit)...\n")
-					dialog := NewProgressDialog("Merging Branch", fmt.Sprintf("Branch: %s\n\nMerging into main (merge commit)...", ws.BranchName), ws.ID)
-					m.panes[i].SetInPaneDialog(&dialog)
-					return m, MergeBranchCmd(ws)
-				case MergeActionSquashMain:
-					m.panes[i].AppendOutput("\nMerging branch into main (squash)...\n")
-					dialog := NewProgressDialog("Squash Merging Branch", fmt.Sprintf("Branch: %s\n\nSquash merging into main...", ws.BranchName), ws.ID)
-					m.panes[i].SetInPaneDialog(&dialog)
-					return m, SquashMergeBranchCmd(ws)
+					// Preview the generated PR, filled into the repository's template
+					m.panes[i].ClearInPaneDialog()
+					dialog := NewPRCreateDialog(ws, git.FindPRTemplates(m.repoDir(ws)), docker.LoadConfig(m.repoDir(ws)).PR)
+					dialog.SetSize(min(m.width-4, 100), m.height-2)
+					m.dialog = &dialog
+					return m, GeneratePRDraftCmd(ws, dialog.SelectedPRTemplate())
+				case MergeActionMergeMain, MergeActionSquashMain,
+					MergeActionGHMergeSquash, MergeActionGHMergeMerge, MergeActionGHMergeRebase:
+					// Run the project's verification command first, if configured
+					if verify := docker.LoadConfig(m.repoDir(ws)).Verify; verify.Command != "" {
+						m.panes[i].AppendOutput(fmt.Sprintf("\nVerifying branch before merge: %s\n", verify.Command))
+						dialog := NewVerifyProgressDialog(ws.BranchName, verify.Command, ws.ID)
+						m.panes[i].SetInPaneDialog(&dialog)
+						return m, VerifyBranchCmd(ws, verify, msg.Action)
+					}
+					return m, m.startMerge(i, msg.Action)
 				case MergeActionPush:
 					m.panes[i].AppendOutput("\nPushing branch to origin...\n")
 					dialog := NewProgressDialog("Pushing Branch", fmt.Sprintf("Branch: %s\n\nPushing to origin...", ws.BranchName), ws.ID)
@@ -1991,27 +2615,53 @@ Scroll Mode:
 					dialog := NewProgressDialog("Rebasing", fmt.Sprintf("Branch: %s\n\nFetching main and rebasing...", ws.BranchName), ws.ID)
 					m.panes[i].SetInPaneDialog(&dialog)
 					return m, FetchRebaseCmd(ws)
-				case MergeActionGHMergeSquash:
-					m.panes[i].AppendOutput("\nMerging PR via GitHub (squash)...\n")
-					dialog := NewProgressDialog("Merging PR", fmt.Sprintf("Branch: %s\n\nMerging PR via GitHub (squash)...", ws.BranchName), ws.ID)
-					m.panes[i].SetInPaneDialog(&dialog)
-					return m, GHMergePRCmd(ws, "squash")
-				case MergeActionGHMergeMerge:
-					m.panes[i].AppendOutput("\nMerging PR via GitHub (merge commit)...\n")
-					dialog := NewProgressDialog("Merging PR", fmt.Sprintf("Branch: %s\n\nMerging PR via GitHub (merge commit)...", ws.BranchName), ws.ID)
-					m.panes[i].SetInPaneDialog(&dialog)
-					return m, GHMergePRCmd(ws, "merge")
-				case MergeActionGHMergeRebase:
-					m.panes[i].AppendOutput("\nMerging PR via GitHub (rebase)...\n")
-					dialog := NewProgressDialog("Merging PR", fmt.Sprintf("Branch: %s\n\nMerging PR via GitHub (rebase)...", ws.BranchName), ws.ID)
+				case MergeActionCleanHistory:
+					// Ask Claude for a cleaner history, then review it in a dialog
+					m.panes[i].AppendOutput("\nAsking Claude to propose a cleaner history...\n")
+					dialog := NewProgressDialog("Cleaning Up History", fmt.Sprintf("Branch: %s\n\nAsking Claude to propose a cleaner history...", ws.BranchName), ws.ID)
 					m.panes[i].SetInPaneDialog(&dialog)
-					return m, GHMergePRCmd(ws, "rebase")
+					return m, ProposeHistoryCmd(ws)
 				}
 				break
 			}
 		}
 		return m, nil
 
+	case VerifyOutputMsg:
+		// Stream verification output into the pane's progress dialog
+		for i := range m.panes {
+			if m.panes[i].Workstream().ID == msg.WorkstreamID {
+				if dialog := m.panes[i].GetInPaneDialog(); dialog != nil && dialog.Type == DialogProgress {
+					dialog.AppendStreamOutput(msg.Output)
+				}
+				break
+			}
+		}
+		return m, nil
+
+	case VerifyResultMsg:
+		for i := range m.panes {
+			if m.panes[i].Workstream().ID == msg.WorkstreamID {
+				ws := m.panes[i].Workstream()
+				if msg.Error != nil {
+					m.panes[i].AppendOutput(fmt.Sprintf("Verification could not run: %v\n", msg.Error))
+					if dialog := m.panes[i].GetInPaneDialog(); dialog != nil && dialog.Type == DialogProgress {
+						dialog.SetComplete(fmt.Sprintf("Verification Failed\n\n%v\n\nThe merge was not performed.", msg.Error))
+					}
+					return m, nil
+				}
+				if !msg.Passed() {
+					m.panes[i].AppendOutput(fmt.Sprintf("Verification failed (exit code %d) - merge blocked\n", msg.ExitCode))
+					dialog := NewVerifyFailedDialog(ws.BranchName, ws.ID, msg.Command, msg.ExitCode, msg.Output)
+					m.panes[i].SetInPaneDialog(&dialog)
+					return m, nil
+				}
+				m.panes[i].AppendOutput("Verification passed.\n")
+				return m, m.startMerge(i, msg.Action)
+			}
+		}
+		return m, nil
+
 	case PRStatusMsg:
 		// Update pane's PR status
 		for i := range m.panes {
@@ -2022,12 +2672,38 @@ Scroll Mode:
 					m.panes[i].SetPRStatusLoading(false)
 				} else {
 					m.panes[i].SetPRStatus(msg.Status)
+					// Check the PR for new review feedback and failing CI.
+					// Both use GitHub's API, so only GitHub PRs are checked.
+					if msg.Status != nil && msg.Status.Number > 0 && msg.Status.Forge == git.ForgeGitHub {
+						return m, tea.Batch(
+							FetchPRReviewsCmd(m.panes[i].Workstream(), msg.Status.Number),
+							m.checkCIFix(i, msg.Status),
+						)
+					}
 				}
 				break
 			}
 		}
 		return m, nil
 
+	case PRReviewsMsg:
+		m.handlePRReviews(msg)
+		return m, nil
+
+	case CIFailuresMsg:
+		return m, m.handleCIFailures(msg)
+
+	case HistoryProposalMsg:
+		m.handleHistoryProposal(msg)
+		return m, nil
+
+	case HistoryApplyMsg:
+		return m, m.applyHistory(msg)
+
+	case HistoryRewriteMsg:
+		m.handleHistoryRewrite(msg)
+		return m, nil
+
 	case PRStatusRefreshRequestMsg:
 		// Request to refresh PR status (e.g., after a push via git proxy)
 		for i := range m.panes {
@@ -2044,6 +2720,20 @@ Scroll Mode:
 		}
 		return m, nil
 
+	case HookEventMsg:
+		// Lifecycle event reported from outside the TUI (e.g., push via git proxy)
+		for i := range m.panes {
+			if m.panes[i].Workstream().ID == msg.WorkstreamID {
+				ws := m.panes[i].Workstream()
+				payload := hookPayload(msg.Event, ws)
+				if msg.PRURL != "" {
+					payload.PRURL = msg.PRURL
+				}
+				return m, RunHooksCmd(m.hooksFor(ws), payload)
+			}
+		}
+		return m, nil
+
 	case FetchRebaseResultMsg:
 		// Handle fetch-and-rebase result
 		for i := range m.panes {
@@ -2104,6 +2794,7 @@ Scroll Mode:
 						dialogMsg = fmt.Sprintf("%s Successful\n\nPushed %d commits.\nPress Enter or Esc to close.", pushType, msg.CommitsPushed)
 					}
 					m.panes[i].AppendOutput(successMsg)
+					ws.RecordEvent(workstream.EventPushed, strings.TrimSpace(successMsg))
 					// Mark branch as pushed - this enables force push option in merge dialog
 					// and signals to Claude not to use commit amend
 					ws.SetHasBeenPushed(true)
@@ -2115,12 +2806,36 @@ Scroll Mode:
 					if dialog := m.panes[i].GetInPaneDialog(); dialog != nil && dialog.Type == DialogProgress {
 						dialog.SetComplete(dialogMsg)
 					}
+					return m, m.runHooks(hooks.EventPush, ws)
 				}
 				break
 			}
 		}
 		return m, nil
 
+	case PRDraftMsg:
+		if m.dialog != nil && m.dialog.Type == DialogPRPreview && m.dialog.WorkstreamID == msg.WorkstreamID {
+			m.dialog.SetPRDraft(msg)
+		}
+		return m, nil
+
+	case PRTemplateSelectMsg:
+		if ws := m.findWorkstream(msg.WorkstreamID); ws != nil {
+			return m, GeneratePRDraftCmd(ws, msg.Template)
+		}
+		return m, nil
+
+	case PRCreateConfirmMsg:
+		m.dialog = nil
+		if i := m.paneIndexByID(msg.WorkstreamID); i >= 0 {
+			ws := m.panes[i].Workstream()
+			m.panes[i].AppendOutput("\nCreating pull request...\n")
+			dialog := NewProgressDialog("Creating Pull Request", fmt.Sprintf("Branch: %s\n\nPushing and creating PR...", ws.BranchName), ws.ID)
+			m.panes[i].SetInPaneDialog(&dialog)
+			return m, CreatePRCmd(ws, msg.Request)
+		}
+		return m, nil
+
 	case PRCreatedMsg:
 		for i := range m.panes {
 			if m.panes[i].Workstream().ID == msg.WorkstreamID {
@@ -2136,10 +2851,12 @@ Scroll Mode:
 					// Store PR info and mark as pushed (PR creation pushes the branch)
 					ws.SetPRInfo(msg.PRNumber, msg.PRURL)
 					ws.SetHasBeenPushed(true)
+					ws.RecordEvent(workstream.EventPRCreated, fmt.Sprintf("#%d %s", msg.PRNumber, msg.PRURL))
 					// Update in-pane progress dialog if open
 					if dialog := m.panes[i].GetInPaneDialog(); dialog != nil && dialog.Type == DialogProgress {
 						dialog.SetComplete(fmt.Sprintf("Pull Request Created!\n\nPR #%d: %s\n\nPress Enter or Esc to close.", msg.PRNumber, msg.PRURL))
 					}
+					return m, m.runHooks(hooks.EventPROpened, ws)
 				}
 				break
 			}
@@ -2185,6 +2902,7 @@ Scroll Mode:
 					}
 				} else {
 					m.panes[i].AppendOutput("Branch merged into main successfully!\n")
+					ws.RecordEvent(workstream.EventMerged, "merged into main")
 					// Notify Claude Code about the merge (don't press Enter - avoids submitting Claude's pending input)
 					if err := m.panes[i].SendInput(fmt.Sprintf("[ccells] ✓ Branch '%s' merged into main", ws.BranchName), false); err != nil {
 						LogWarn("Failed to notify Claude about merge for %s (pane %d): %v", ws.BranchName, i, err)
@@ -2192,6 +2910,7 @@ Scroll Mode:
 					// Show post-merge destroy dialog in pane
 					dialog := NewPostMergeDestroyDialog(ws.BranchName, ws.ID)
 					m.panes[i].SetInPaneDialog(&dialog)
+					return m, m.runHooks(hooks.EventMerge, ws)
 				}
 				break
 			}
@@ -2210,6 +2929,7 @@ Scroll Mode:
 					}
 				} else {
 					m.panes[i].AppendOutput(fmt.Sprintf("PR merged via GitHub (%s) successfully!\n", msg.MergeMethod))
+					ws.RecordEvent(workstream.EventMerged, fmt.Sprintf("PR merged via GitHub (%s)", msg.MergeMethod))
 					// Notify Claude Code about the merge (don't press Enter - avoids submitting Claude's pending input)
 					if err := m.panes[i].SendInput(fmt.Sprintf("[ccells] ✓ PR merged via GitHub (%s)", msg.MergeMethod), false); err != nil {
 						LogWarn("Failed to notify Claude about PR merge for %s (pane %d): %v", ws.BranchName, i, err)
@@ -2217,12 +2937,16 @@ Scroll Mode:
 					// Show post-merge destroy dialog in pane
 					dialog := NewPostMergeDestroyDialog(ws.BranchName, ws.ID)
 					m.panes[i].SetInPaneDialog(&dialog)
+					return m, m.runHooks(hooks.EventMerge, ws)
 				}
 				break
 			}
 		}
 		return m, nil
 
+	case MergeQueueResultMsg:
+		return m, m.handleMergeQueueResult(msg)
+
 	case RebaseBranchMsg:
 		for i := range m.panes {
 			if m.panes[i].Workstream().ID == msg.WorkstreamID {
@@ -2385,14 +3109,24 @@ Scroll Mode:
 		}
 		return m, nil
 
-	case ClaudeUsageMsg:
-		// Update resource dialog with Claude usage
-		if m.dialog != nil && m.dialog.Type == DialogResourceUsage {
-			if msg.Error == nil && msg.Usage != "" {
-				m.dialog.SetClaudeUsage(msg.Usage)
+	case UsageRefreshedMsg:
+		// Record token usage and cost per workstream (persisted with state)
+		grown := make(map[string]bool)
+		for i := range m.panes {
+			ws := m.panes[i].Workstream()
+			if sessions, ok := msg.Sessions[ws.ID]; ok && ws.MergeSessionUsage(sessions) {
+				grown[ws.ID] = true
+				m.managerFor(ws).UpdateWorkstream(ws.ID)
 			}
 		}
-		return m, nil
+		if m.dialog != nil && m.dialog.Type == DialogResourceUsage {
+			m.dialog.SetClaudeUsage(renderUsageSummary(m.panes))
+		}
+		// Warn about and stop workstreams over budget
+		for repoPath, budget := range msg.Budgets {
+			m.setBudgetConfig(repoPath, budget)
+		}
+		return m, m.checkBudgets(grown)
 
 	case PromptMsg:
 		// Handle prompt from pane
@@ -2406,6 +3140,10 @@ Scroll Mode:
 		return m, nil
 
 	case StateLoadedMsg:
+		if msg.RepoPath != "" {
+			// State of an additionally opened repository
+			return m, m.restoreRepoState(msg)
+		}
 		// Handle loaded state - resume workstreams
 		if msg.Error != nil {
 			m.toast = fmt.Sprintf("Failed to load state: %v", msg.Error)
@@ -2427,41 +3165,7 @@ Scroll Mode:
 
 		// Restore workstreams
 		m.resuming = true
-		var cmds []tea.Cmd
-		for _, saved := range msg.State.Workstreams {
-			ws := workstream.NewWithID(saved.ID, saved.BranchName, saved.Prompt)
-			ws.ContainerID = saved.ContainerID
-			ws.CreatedAt = saved.CreatedAt
-			ws.Title = saved.Title                       // Restore generated title
-			ws.Synopsis = saved.Synopsis                 // Restore synopsis
-			ws.ClaudeSessionID = saved.ClaudeSessionID   // Restore session ID for --resume
-			ws.Runtime = normalizeRuntime(saved.Runtime) // Restore runtime selection (normalized)
-			ws.WasInterrupted = saved.WasInterrupted     // Restore interrupted state for auto-continue
-			ws.HasBeenPushed = saved.HasBeenPushed       // Restore push status
-			ws.PRNumber = saved.PRNumber                 // Restore PR number if created
-			ws.PRURL = saved.PRURL                       // Restore PR URL if created
-			if err := m.manager.Add(ws); err != nil {
-				// Skip workstreams that exceed the limit during restore
-				continue
-			}
-
-			pane := NewPaneModel(ws)
-			pane.SetIndex(m.nextPaneIndex) // Assign permanent index
-			m.nextPaneIndex++
-			pane.SetInitializing(true)
-			pane.SetInitStatus("Resuming session...")
-			m.panes = append(m.panes, pane)
-
-			// Resume container
-			if ws.ContainerID != "" {
-				cmds = append(cmds, ResumeContainerCmd(ws, 80, 24))
-			}
-			// Fetch PR status for workstreams with open PRs
-			if ws.PRURL != "" {
-				cmds = append(cmds, FetchPRStatusCmd(ws))
-			}
-			cmds = append(cmds, spinnerTickCmd())
-		}
+		cmds := m.restoreWorkstreams(msg.State, msg.Events, "", m.manager)
 
 		// Restore focus
 		if msg.State.FocusedIndex >= 0 && msg.State.FocusedIndex < len(m.panes) {
@@ -2476,6 +3180,7 @@ Scroll Mode:
 		m.updateLayoutQuiet() // Quiet mode - PTY sessions not created yet during state restore
 		m.toast = fmt.Sprintf("Resumed %d workstream(s)", len(msg.State.Workstreams))
 		m.toastExpiry = time.Now().Add(toastDuration)
+		cmds = append(cmds, RefreshUsageCmd(m.workstreams())) // Apply budgets to restored usage
 
 		// Don't delete state file here - keep it as backup until containers are confirmed running.
 		// The state will be overwritten on next save (quit), so stale data is not a concern.
@@ -2483,6 +3188,9 @@ Scroll Mode:
 
 		return m, tea.Batch(cmds...)
 
+	case RepoOpenedMsg:
+		return m, m.handleRepoOpened(msg)
+
 	case StateSavedMsg:
 		// State was saved, now quit
 		// Print any repair messages to stderr so user sees them after TUI exits
@@ -2533,6 +3241,47 @@ Scroll Mode:
 			LogDebug("Polling PR status for %d workstreams", len(cmds)-1)
 		}
 		return m, tea.Batch(cmds...)
+
+	case conflictPollTickMsg:
+		// Periodic conflict prediction between active workstreams
+		if len(m.panes) < 2 {
+			m.conflicts = nil
+			return m, conflictPollTickCmd()
+		}
+		return m, tea.Batch(conflictPollTickCmd(), AnalyzeConflictsCmd(m.workstreams(), false))
+
+	case ConflictsAnalyzedMsg:
+		m.conflicts = msg.Pairs
+		if msg.Show {
+			m.toast = ""
+			dialog := NewConflictMatrixDialog(m.panes, m.conflicts)
+			dialog.SetSize(m.width-10, m.height-6)
+			m.dialog = &dialog
+		}
+		return m, nil
+
+	case autoRebasePollTickMsg:
+		// Periodic background rebase of idle workstreams (opt-in per repository)
+		return m, tea.Batch(append(m.autoRebaseCmds(), autoRebasePollTickCmd())...)
+
+	case AutoRebaseMsg:
+		m.handleAutoRebase(msg)
+		return m, nil
+
+	case checkpointPollTickMsg:
+		// Periodic WIP checkpoints of active worktrees (each repository at its own interval)
+		return m, tea.Batch(append(m.checkpointCmds(time.Now()), checkpointPollTickCmd())...)
+
+	case CheckpointsMsg:
+		m.handleCheckpoints(msg)
+		return m, nil
+
+	case usagePollTickMsg:
+		// Periodic token usage refresh for all workstreams
+		if len(m.panes) == 0 {
+			return m, usagePollTickCmd()
+		}
+		return m, tea.Batch(usagePollTickCmd(), RefreshUsageCmd(m.workstreams()))
 	}
 
 	return m, nil
@@ -2549,8 +3298,11 @@ func (m AppModel) View() tea.View {
 	// Top title bar
 	titleBar := m.renderTitleBar()
 	sections = append(sections, titleBar)
+	if m.filterBarHeight() > 0 {
+		sections = append(sections, m.renderFilterBar())
+	}
 
-	// Update panes with pairing state before rendering
+	// Update panes with pairing state, repository tags and conflicts before rendering
 	pairingState := m.pairingOrchestrator.GetState()
 	for i := range m.panes {
 		ws := m.panes[i].Workstream()
@@ -2560,6 +3312,10 @@ func (m AppModel) View() tea.View {
 		} else {
 			m.panes[i].SetPairingState(nil)
 		}
+		m.panes[i].SetRepoName(m.paneRepoName(ws))
+		m.panes[i].SetConflictsWith(m.conflictPeerNames(ws))
+		pos := m.mergeQueuePosition(ws.ID)
+		m.panes[i].SetMergeQueue(pos, pos == 1 && m.mergeQueueBusy)
 	}
 
 	// Panes section
@@ -2585,11 +3341,12 @@ func (m AppModel) View() tea.View {
 
 	// Bottom status bar
 	m.statusBar.SetWidth(m.width)
-	m.statusBar.SetWorkstreamCount(m.manager.Count())
+	m.statusBar.SetWorkstreamCount(m.workstreamCount())
 	m.statusBar.SetInputMode(m.inputMode)
 	m.statusBar.SetLayoutName(m.layout.String())
 	m.statusBar.SetRepoPath(m.workingDir)
 	m.statusBar.SetRuntime(globalRuntime)
+	m.statusBar.SetUsage(projectUsage(m.panes))
 	if pairingState.Active {
 		m.statusBar.SetPairingBranch(pairingState.CurrentBranch)
 		m.statusBar.SetPairingStatus(pairingState.SyncStatus, pairingState.StashedChanges, len(pairingState.Conflicts))
@@ -2787,13 +3544,27 @@ func (m AppModel) renderPanes() string {
 	}
 
 	// Calculate available height for layout rendering
-	titleBarHeight := 1
+	headerHeight := 1 + m.filterBarHeight() // Title bar and filter bar
 	statusBarHeight := 1
 	logPanelH := m.logPanelHeight()
-	availableHeight := m.height - titleBarHeight - statusBarHeight - logPanelH
+	availableHeight := m.height - headerHeight - statusBarHeight - logPanelH
+
+	visible := m.visiblePaneIndices()
+	if len(visible) == 0 {
+		return lipgloss.NewStyle().
+			Width(m.width).
+			Height(availableHeight).
+			Align(lipgloss.Center, lipgloss.Center).
+			Foreground(lipgloss.Color("#666666")).
+			Render(fmt.Sprintf("No workstreams match %q. Press [/] to change the filter.", m.paneFilter.query))
+	}
+	panes := make([]PaneModel, len(visible))
+	for j, i := range visible {
+		panes[j] = m.panes[i]
+	}
 
 	// Use the layout system to render panes
-	return RenderPanesWithLayout(m.panes, m.layout, m.width, availableHeight)
+	return RenderPanesWithLayout(panes, m.layout, m.width, availableHeight)
 }
 
 // overlayDialog overlays the dialog on top of the view
@@ -2810,16 +3581,17 @@ func (m AppModel) overlayDialog(background string) string {
 	// For destroy dialogs and post-merge destroy dialogs, position over the target pane
 	if (m.dialog.Type == DialogDestroy || m.dialog.Type == DialogPostMergeDestroy) && m.dialog.WorkstreamID != "" {
 		// Find the pane with this workstream
-		titleBarHeight := 1
+		headerHeight := 1 + m.filterBarHeight()
 		statusBarHeight := 1
 		logPanelH := m.logPanelHeight()
-		availableHeight := m.height - titleBarHeight - statusBarHeight - logPanelH
-		bounds := CalculatePaneBounds(m.layout, len(m.panes), m.width, availableHeight, titleBarHeight)
+		availableHeight := m.height - headerHeight - statusBarHeight - logPanelH
+		visible := m.visiblePaneIndices()
+		bounds := CalculatePaneBounds(m.layout, len(visible), m.width, availableHeight, headerHeight)
 
-		for i, pane := range m.panes {
-			if pane.Workstream().ID == m.dialog.WorkstreamID && i < len(bounds) {
+		for j, i := range visible {
+			if m.panes[i].Workstream().ID == m.dialog.WorkstreamID && j < len(bounds) {
 				// Center dialog within this pane's bounds
-				paneBounds := bounds[i]
+				paneBounds := bounds[j]
 				x = paneBounds.X + (paneBounds.Width-dialogWidth)/2
 				y = paneBounds.Y + (paneBounds.Height-dialogHeight)/2
 				break
@@ -2936,10 +3708,10 @@ func (m *AppModel) updateLayoutQuiet() {
 
 // updateLayoutInternal is the internal implementation of updateLayout.
 func (m *AppModel) updateLayoutInternal(quiet bool) {
-	titleBarHeight := 1
+	headerHeight := 1 + m.filterBarHeight() // Title bar and filter bar
 	statusBarHeight := 1
 	logPanelH := m.logPanelHeight()
-	availableHeight := m.height - titleBarHeight - statusBarHeight - logPanelH
+	availableHeight := m.height - headerHeight - statusBarHeight - logPanelH
 
 	// Update log panel size
 	if m.logPanel != nil {
@@ -2950,16 +3722,17 @@ func (m *AppModel) updateLayoutInternal(quiet bool) {
 		return
 	}
 
-	// Calculate sizes using the layout system
-	sizes := CalculateLayout(m.layout, len(m.panes), m.width, availableHeight)
+	// Calculate sizes using the layout system (filtered-out panes keep their size)
+	visible := m.visiblePaneIndices()
+	sizes := CalculateLayout(m.layout, len(visible), m.width, availableHeight)
 
 	// Apply sizes to panes
-	for i := range m.panes {
-		if i < len(sizes) {
+	for j, i := range visible {
+		if j < len(sizes) {
 			if quiet {
-				m.panes[i].SetSizeQuiet(sizes[i].Width, sizes[i].Height)
+				m.panes[i].SetSizeQuiet(sizes[j].Width, sizes[j].Height)
 			} else {
-				m.panes[i].SetSize(sizes[i].Width, sizes[i].Height)
+				m.panes[i].SetSize(sizes[j].Width, sizes[j].Height)
 			}
 		}
 	}
@@ -2986,6 +3759,40 @@ func (m *AppModel) renumberPanes() {
 	m.lastSwapPosition = 0
 }
 
+// restoreWorkstreams adds panes for the saved workstreams of a repository
+// (repoPath is empty for the primary one) and returns the commands resuming them.
+func (m *AppModel) restoreWorkstreams(state *workstream.AppState, events map[string][]workstream.Event, repoPath string, manager *workstream.PersistentManager) []tea.Cmd {
+	var cmds []tea.Cmd
+	for _, saved := range state.Workstreams {
+		ws := saved.Restore()
+		ws.Runtime = normalizeRuntime(ws.Runtime) // Restore runtime selection (normalized)
+		ws.SetEvents(events[saved.ID])            // Restore timeline
+		ws.RepoPath = repoPath
+		if err := manager.Add(ws); err != nil {
+			// Skip workstreams that exceed the limit during restore
+			continue
+		}
+
+		pane := NewPaneModel(ws)
+		pane.SetIndex(m.nextPaneIndex) // Assign permanent index
+		m.nextPaneIndex++
+		pane.SetInitializing(true)
+		pane.SetInitStatus("Resuming session...")
+		m.panes = append(m.panes, pane)
+
+		// Resume container
+		if ws.ContainerID != "" {
+			cmds = append(cmds, ResumeContainerCmd(ws, 80, 24))
+		}
+		// Fetch PR status for workstreams with open PRs
+		if ws.PRURL != "" {
+			cmds = append(cmds, FetchPRStatusCmd(ws))
+		}
+		cmds = append(cmds, spinnerTickCmd())
+	}
+	return cmds
+}
+
 // removePane removes a pane at the given index and handles all bookkeeping:
 // removes from slice, adjusts focus, renumbers remaining panes, and updates layout.
 // Returns the removed workstream so caller can issue StopContainerCmd if needed.
@@ -2994,7 +3801,8 @@ func (m *AppModel) removePane(index int) *workstream.Workstream {
 		return nil
 	}
 	ws := m.panes[index].Workstream()
-	m.manager.Remove(ws.ID)
+	m.managerFor(ws).Remove(ws.ID)
+	m.retireUsage(ws)
 	m.panes = append(m.panes[:index], m.panes[index+1:]...)
 	if m.focusedPane >= len(m.panes) && len(m.panes) > 0 {
 		m.setFocusedPane(len(m.panes) - 1)
@@ -3008,18 +3816,24 @@ func (m *AppModel) removePane(index int) *workstream.Workstream {
 }
 
 // clearAllPanes removes all panes and resets state.
-// Closes PTY sessions and removes workstreams from manager.
-func (m *AppModel) clearAllPanes() {
+// Closes PTY sessions and removes workstreams from manager. The returned
+// command persists their usage, which keeps counting toward project budgets.
+func (m *AppModel) clearAllPanes() tea.Cmd {
+	var cmds []tea.Cmd
 	for _, pane := range m.panes {
 		if pty := pane.PTY(); pty != nil {
 			pty.Close()
 		}
-		m.manager.Remove(pane.Workstream().ID)
+		ws := pane.Workstream()
+		m.managerFor(ws).Remove(ws.ID)
+		m.retireUsage(ws)
+		cmds = append(cmds, RecordSpendCmd(ws, m.stateDirFor(ws)))
 	}
 	m.panes = nil
 	m.setFocusedPane(0)
 	m.renumberPanes()
 	m.updateLayoutQuiet() // No panes left, but keep consistent
+	return tea.Batch(cmds...)
 }
 
 // getContainerIDs returns the container IDs for all current panes
@@ -3034,6 +3848,15 @@ func (m *AppModel) getContainerIDs() []string {
 	return ids
 }
 
+// workstreams returns the workstreams of all panes in display order
+func (m *AppModel) workstreams() []*workstream.Workstream {
+	result := make([]*workstream.Workstream, 0, len(m.panes))
+	for i := range m.panes {
+		result = append(result, m.panes[i].Workstream())
+	}
+	return result
+}
+
 // formatResourceStats formats resource stats as a table for display
 func (m *AppModel) formatResourceStats(stats []docker.ContainerStats, totalCPU float64, totalMemory uint64, diskUsage *docker.DiskUsage) string {
 	var sb strings.Builder
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
 	"github.com/STRML/claude-cells/internal/git"
 	"github.com/STRML/claude-cells/internal/workstream"
 )
@@ -57,6 +61,40 @@ func shiftKey(code rune) tea.KeyPressMsg {
 	return tea.KeyPressMsg{Code: code, Mod: tea.ModShift}
 }
 
+// newTestApp returns an app with three workstream panes, alpha, beta and
+// gamma, whose IDs and branch names match their names.
+func newTestApp(t *testing.T) AppModel {
+	t.Helper()
+	app := NewAppModel(context.Background())
+	app.width = 120
+	app.height = 40
+	app.spent = nil // Ignore usage recorded by real sessions in this repository
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		ws := workstream.NewWithID(name, name, "task "+name)
+		if err := app.manager.Add(ws); err != nil {
+			t.Fatal(err)
+		}
+		pane := NewPaneModel(ws)
+		pane.SetIndex(len(app.panes) + 1)
+		app.panes = append(app.panes, pane)
+	}
+	return app
+}
+
+// fakeExecutable puts an executable called name that runs the given shell
+// script on PATH.
+func fakeExecutable(t *testing.T, name, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake " + name + " requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
 func TestNewAppModel(t *testing.T) {
 	app := NewAppModel(context.Background())
 
@@ -403,6 +441,46 @@ func TestAppModel_Update_DestroyWorkstream(t *testing.T) {
 	}
 }
 
+func TestAppModel_Update_NewWorkstreamWithTemplate(t *testing.T) {
+	cellsDir := t.TempDir()
+	docker.SetTestCellsDir(cellsDir)
+	defer docker.SetTestCellsDir("")
+	config := `templates:
+  - name: bugfix
+    runtime: claudesp
+    branch_prefix: bugfix/
+`
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	app := NewAppModel(context.Background())
+	app.width = 100
+	app.height = 40
+
+	model, _ := app.Update(keyPress('n'))
+	app = model.(AppModel)
+	if app.dialog == nil || len(app.dialog.templates) != 1 {
+		t.Fatalf("dialog should offer the configured template, got %+v", app.dialog)
+	}
+
+	model, _ = app.Update(DialogConfirmMsg{Type: DialogNewWorkstream, Value: "login redirect loops", Template: "bugfix"})
+	app = model.(AppModel)
+	ws := app.panes[0].Workstream()
+	if ws.Template != "bugfix" {
+		t.Errorf("Template = %q, want %q", ws.Template, "bugfix")
+	}
+	if ws.Runtime != "claudesp" {
+		t.Errorf("Runtime = %q, want template runtime %q", ws.Runtime, "claudesp")
+	}
+
+	model, _ = app.Update(TitleGeneratedMsg{WorkstreamID: ws.ID, Title: "Fix login redirect"})
+	app = model.(AppModel)
+	if ws.BranchName != "bugfix/fix-login-redirect" {
+		t.Errorf("BranchName = %q, want %q", ws.BranchName, "bugfix/fix-login-redirect")
+	}
+}
+
 func TestAppModel_Update_TabCycle(t *testing.T) {
 	app := NewAppModel(context.Background())
 	app.width = 100
@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/orchestrator"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// archiveWorkstream records a workstream in the archive before it is destroyed.
+// Uncommitted changes in its worktree are saved under an archive ref, so a
+// restore brings them back; if that fails, the error is returned and the
+// worktree must be kept. Must run before the branch or container config is
+// removed.
+func archiveWorkstream(ctx context.Context, ws *workstream.Workstream, stateDir string) error {
+	if stateDir == "" || ws.BranchName == "" {
+		return nil
+	}
+	entry := workstream.NewArchivedWorkstream(ws)
+
+	var snapshotErr error
+	if worktreePath := resolveWorktreePath(ws); worktreePath != "" {
+		if _, err := os.Stat(worktreePath); err == nil {
+			entry.WorktreeSnapshot, snapshotErr = GitClientFactory(worktreePath).SnapshotWorktree(ctx, git.ArchiveRefPrefix+ws.ID)
+			if snapshotErr != nil {
+				LogWarn("Failed to save the uncommitted changes of %s: %v", ws.BranchName, snapshotErr)
+			}
+		}
+	}
+
+	if repoPath, err := workstreamRepoPath(ws); err == nil {
+		gitRepo := GitClientFactory(repoPath)
+		if sha, err := gitRepo.RevParse(ctx, ws.BranchName); err == nil {
+			entry.CommitSHA = sha
+		}
+		commits, stat := summarizeBranch(ctx, gitRepo, ws.BranchName)
+		entry.DiffStat = strings.Trim(commits+", "+stat, ", ")
+	}
+
+	// Keep the Claude session so it can be resumed after restore
+	if ws.ContainerID != "" && entry.ClaudeSessionID != "" {
+		if dockerClient, err := docker.NewClient(); err == nil {
+			if name, err := dockerClient.GetContainerName(ctx, ws.ContainerID); err == nil && name != "" {
+				dst := workstream.ArchiveSessionDir(stateDir, ws.ID)
+				if err := docker.ArchiveSessionData(name, dst); err != nil {
+					LogWarn("Failed to archive session data for %s: %v", ws.BranchName, err)
+				} else {
+					entry.SessionDir = dst
+				}
+			}
+			dockerClient.Close()
+		}
+	}
+
+	if err := workstream.AddToArchive(stateDir, entry); err != nil {
+		LogWarn("Failed to archive %s: %v", ws.BranchName, err)
+	}
+	return snapshotErr
+}
+
+// ArchiveAndStopContainerCmd archives a workstream and records its usage,
+// then stops and removes its container. The worktree is kept if its
+// uncommitted changes could not be saved.
+func ArchiveAndStopContainerCmd(ws *workstream.Workstream, stateDir string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		recordSpend(ws, stateDir)
+		keptWorktree := ""
+		if err := archiveWorkstream(ctx, ws, stateDir); err != nil {
+			keptWorktree = resolveWorktreePath(ws)
+		}
+		cancel()
+		return stopContainerCmd(ws, keptWorktree)()
+	}
+}
+
+// RestoreArchivedCmd recreates the worktree and container for an archived
+// workstream. The existing branch is reused when it still exists; otherwise
+// the branch is recreated at the archived commit. Archived session data is
+// copied back so the Claude session can be resumed.
+func RestoreArchivedCmd(ws *workstream.Workstream, entry workstream.ArchivedWorkstream, stateDir string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		repoPath, err := workstreamRepoPath(ws)
+		if err != nil {
+			return ContainerErrorMsg{WorkstreamID: ws.ID, Error: err}
+		}
+
+		dockerClient, err := docker.NewClient()
+		if err != nil {
+			return ContainerErrorMsg{WorkstreamID: ws.ID, Error: err}
+		}
+		defer dockerClient.Close()
+
+		orch := workstreamOrchestrator(dockerClient, ws, repoPath)
+
+		branchExists, err := GitClientFactory(repoPath).BranchExists(ctx, ws.BranchName)
+		if err != nil {
+			return ContainerErrorMsg{WorkstreamID: ws.ID, Error: err}
+		}
+		if !branchExists && entry.CommitSHA == "" {
+			return ContainerErrorMsg{
+				WorkstreamID: ws.ID,
+				Error:        fmt.Errorf("branch %s no longer exists and no commit was archived", ws.BranchName),
+			}
+		}
+
+		cellsCfg := docker.LoadConfig(repoPath)
+		opts := orchestrator.CreateOptions{
+			RepoPath:          repoPath,
+			UseExistingBranch: branchExists,
+			Provision:         cellsCfg.Provision,
+			Preset:            workstreamPreset(cellsCfg, ws.Template),
+		}
+		if !branchExists {
+			opts.StartPoint = entry.CommitSHA
+		}
+
+		result, err := orch.CreateWorkstream(ctx, ws, opts)
+		if err != nil {
+			return ContainerErrorMsg{WorkstreamID: ws.ID, Error: err}
+		}
+
+		trackContainer(result.ContainerID, ws.ID, ws.BranchName, result.WorktreePath)
+		registerContainerCredentials(result.ContainerID, result.ContainerName, result.ConfigDir)
+		if result.GitProxySocketDir != "" {
+			startGitProxySocket(ctx, result.ContainerID, ws)
+		}
+
+		// Bring back the uncommitted changes saved when it was archived
+		if entry.WorktreeSnapshot != "" {
+			if err := GitClientFactory(result.WorktreePath).RestoreCheckpoint(ctx, git.Checkpoint{SHA: entry.WorktreeSnapshot}); err != nil {
+				LogWarn("Failed to restore the uncommitted changes of %s: %v", ws.BranchName, err)
+			} else if err := GitClientFactory(repoPath).DeleteRef(ctx, git.ArchiveRefPrefix+entry.ID); err != nil {
+				LogWarn("Failed to delete the archived snapshot of %s: %v", ws.BranchName, err)
+			}
+		}
+
+		// Resume the session only if its data made it back into the container
+		resume := false
+		if entry.SessionDir != "" && ws.GetClaudeSessionID() != "" {
+			if err := docker.RestoreSessionData(entry.SessionDir, result.ConfigDir); err != nil {
+				LogWarn("Failed to restore session data for %s: %v", ws.BranchName, err)
+			} else {
+				resume = true
+			}
+		}
+
+		if err := workstream.RemoveFromArchive(stateDir, entry.ID); err != nil {
+			LogWarn("Failed to remove %s from archive: %v", ws.BranchName, err)
+		}
+		// Its usage counts as live again
+		if err := workstream.ForgetSpend(stateDir, entry.ID); err != nil {
+			LogWarn("Failed to update the recorded usage of %s: %v", ws.BranchName, err)
+		}
+
+		return ContainerStartedMsg{
+			WorkstreamID: ws.ID,
+			ContainerID:  result.ContainerID,
+			IsResume:     resume,
+		}
+	}
+}
+
+// loadArchives returns the archived workstreams of every open repository,
+// most recently archived first. Each entry's RepoPath names the repository
+// whose archive it came from.
+func (m *AppModel) loadArchives() ([]workstream.ArchivedWorkstream, error) {
+	entries, err := workstream.LoadArchive(m.stateDir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].RepoPath = ""
+	}
+	for _, r := range m.repos {
+		repoEntries, err := workstream.LoadArchive(r.StateDir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Name, err)
+		}
+		for i := range repoEntries {
+			repoEntries[i].RepoPath = r.Path
+		}
+		entries = append(entries, repoEntries...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].ArchivedAt.After(entries[j].ArchivedAt)
+	})
+	return entries, nil
+}
+
+// archiveMenuLabel formats an archive entry for the browser list.
+func archiveMenuLabel(e workstream.ArchivedWorkstream) string {
+	label := e.BranchName
+	if e.Title != "" {
+		label += " - " + e.Title
+	}
+	return fmt.Sprintf("%s  (%s)", label, e.ArchivedAt.Format("Jan 02 15:04"))
+}
+
+// renderArchiveEntry renders the details of an archived workstream.
+func renderArchiveEntry(e workstream.ArchivedWorkstream) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Prompt:   %s\n", truncatePrompt(e.Prompt, 200))
+	if e.RepoPath != "" {
+		fmt.Fprintf(&b, "Repo:     %s\n", filepath.Base(e.RepoPath))
+	}
+	if e.Synopsis != "" {
+		fmt.Fprintf(&b, "Synopsis: %s\n", e.Synopsis)
+	}
+	if e.CommitSHA != "" {
+		fmt.Fprintf(&b, "Commit:   %s\n", shortSHA(e.CommitSHA))
+	}
+	if e.DiffStat != "" {
+		fmt.Fprintf(&b, "Changes:  %s\n", e.DiffStat)
+	}
+	if e.PRURL != "" {
+		fmt.Fprintf(&b, "PR:       %s\n", e.PRURL)
+	}
+	if e.ClaudeSessionID != "" {
+		resumable := "no (session data not saved)"
+		if e.SessionDir != "" {
+			resumable = "yes"
+		}
+		fmt.Fprintf(&b, "Resume:   %s\n", resumable)
+	}
+	fmt.Fprintf(&b, "Archived: %s", e.ArchivedAt.Format("2006-01-02 15:04"))
+	return b.String()
+}
+
+// shortSHA shortens a commit SHA for display, as git log --oneline does.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// truncatePrompt shortens a prompt to a single line of at most n runes.
+func truncatePrompt(prompt string, n int) string {
+	prompt = strings.Join(strings.Fields(prompt), " ")
+	r := []rune(prompt)
+	if len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return prompt
+}
+
+// NewArchiveDialog creates the archive browser for the given entries.
+func NewArchiveDialog(entries []workstream.ArchivedWorkstream) DialogModel {
+	items := make([]string, len(entries))
+	for i, e := range entries {
+		items[i] = archiveMenuLabel(e)
+	}
+	return DialogModel{
+		Type:           DialogArchive,
+		Title:          "Archived Workstreams",
+		Body:           "Select a workstream to restore its branch, container and session.",
+		MenuItems:      items,
+		archiveEntries: entries,
+	}
+}
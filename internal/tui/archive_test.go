@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestRenderArchiveEntry(t *testing.T) {
+	entry := workstream.ArchivedWorkstream{
+		ID:              "abc",
+		BranchName:      "fix-login",
+		Prompt:          "fix the\nlogin bug",
+		Synopsis:        "Fixed Safari cookies",
+		CommitSHA:       "0123456789abcdef0123456789abcdef01234567",
+		DiffStat:        "2 commits, 3 files changed, 40 insertions(+)",
+		PRURL:           "https://github.com/example/repo/pull/7",
+		ClaudeSessionID: "session-1",
+		ArchivedAt:      time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC),
+	}
+
+	out := renderArchiveEntry(entry)
+	for _, want := range []string{"fix the login bug", "Fixed Safari cookies", "0123456", "3 files changed", "pull/7", "session data not saved", "2026-01-02 15:04"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("renderArchiveEntry() missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "0123456789abcdef") {
+		t.Error("commit SHA should be shortened")
+	}
+}
+
+func TestArchiveWorkstream_SavesUncommittedChanges(t *testing.T) {
+	var snapshotErr error
+	var snapshotRef string
+	mockGit := &git.MockGitClient{
+		SnapshotWorktreeFn: func(ctx context.Context, ref string) (string, error) {
+			snapshotRef = ref
+			return "wip1", snapshotErr
+		},
+	}
+	restore := SetGitClientFactory(func(path string) git.GitClient { return mockGit })
+	defer restore()
+
+	stateDir := t.TempDir()
+	ws := workstream.NewWithID("ws-1", "feature", "task")
+	ws.RepoPath = t.TempDir()
+	ws.WorktreePath = t.TempDir()
+
+	if err := archiveWorkstream(context.Background(), ws, stateDir); err != nil {
+		t.Fatalf("archiveWorkstream() error = %v", err)
+	}
+	entries, _ := workstream.LoadArchive(stateDir)
+	if len(entries) != 1 || entries[0].WorktreeSnapshot != "wip1" || snapshotRef != git.ArchiveRefPrefix+"ws-1" {
+		t.Errorf("entries = %+v, ref %q; want the snapshot recorded under the archive ref", entries, snapshotRef)
+	}
+
+	// The worktree must be kept when its changes can't be saved
+	snapshotErr = errors.New("disk full")
+	if err := archiveWorkstream(context.Background(), ws, stateDir); err == nil {
+		t.Error("expected the snapshot failure to be reported")
+	}
+}
+
+func TestArchiveDialog_EnterSelectsEntry(t *testing.T) {
+	entries := []workstream.ArchivedWorkstream{
+		{ID: "first", BranchName: "one"},
+		{ID: "second", BranchName: "two", Title: "Second"},
+	}
+	d := NewArchiveDialog(entries)
+	if len(d.MenuItems) != 2 || !strings.Contains(d.MenuItems[1], "two - Second") {
+		t.Fatalf("unexpected menu items: %v", d.MenuItems)
+	}
+
+	d, _ = d.Update(dSpecialKey(tea.KeyDown))
+	_, cmd := d.Update(dSpecialKey(tea.KeyEnter))
+	if cmd == nil {
+		t.Fatal("expected a command on enter")
+	}
+	msg, ok := cmd().(DialogConfirmMsg)
+	if !ok || msg.Type != DialogArchive || msg.Value != "second" {
+		t.Errorf("got %+v, want confirm for entry \"second\"", msg)
+	}
+}
+
+func TestAppModel_ArchiveKey(t *testing.T) {
+	app := NewAppModel(context.Background())
+	app.width = 100
+	app.height = 40
+	app.stateDir = t.TempDir()
+
+	model, _ := app.Update(keyPress('A'))
+	app = model.(AppModel)
+	if app.dialog != nil {
+		t.Fatal("empty archive should not open a dialog")
+	}
+	if app.toast != "Archive is empty" {
+		t.Errorf("toast = %q, want empty archive notice", app.toast)
+	}
+
+	ws := workstream.NewWithID("abc", "archived-branch", "old task")
+	if err := workstream.AddToArchive(app.stateDir, workstream.NewArchivedWorkstream(ws)); err != nil {
+		t.Fatal(err)
+	}
+	model, _ = app.Update(keyPress('A'))
+	app = model.(AppModel)
+	if app.dialog == nil || app.dialog.Type != DialogArchive {
+		t.Fatalf("expected archive dialog, got %+v", app.dialog)
+	}
+
+	// Choosing the entry recreates the workstream in a new pane
+	model, cmd := app.Update(DialogConfirmMsg{Type: DialogArchive, Value: "abc"})
+	app = model.(AppModel)
+	if cmd == nil {
+		t.Fatal("expected restore command")
+	}
+	if len(app.panes) != 1 || app.panes[0].Workstream().BranchName != "archived-branch" {
+		t.Fatalf("expected restored pane, got %d panes", len(app.panes))
+	}
+}
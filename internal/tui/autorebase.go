@@ -0,0 +1,257 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+const autoRebasePollInterval = 5 * time.Minute
+
+// autoRebaseTimeout bounds fetching the base branch and rebasing one repository's workstreams.
+const autoRebaseTimeout = 4 * time.Minute
+
+// autoRebasePollTickMsg is sent periodically to fetch base branches and
+// rebase idle workstreams onto them.
+type autoRebasePollTickMsg struct{}
+
+// autoRebasePollTickCmd returns a command that sends an auto-rebase poll tick after a delay
+func autoRebasePollTickCmd() tea.Cmd {
+	return tea.Tick(autoRebasePollInterval, func(t time.Time) tea.Msg {
+		return autoRebasePollTickMsg{}
+	})
+}
+
+// autoRebaseCandidate is an idle workstream that may be rebased.
+type autoRebaseCandidate struct {
+	ID           string
+	Branch       string
+	WorktreePath string
+	ForcePush    bool   // The branch was pushed; force-push it after rebasing
+	ConflictedAt string // Base commit the last rebase conflicted with; retried once the base moves
+}
+
+// AutoRebaseResult is the outcome of rebasing one workstream.
+type AutoRebaseResult struct {
+	WorkstreamID  string
+	Rebased       bool // False if the branch already contained the base or had uncommitted changes
+	ForcePushed   bool
+	ConflictFiles []string // Files that conflicted; the rebase was aborted
+	Error         error
+}
+
+// AutoRebaseMsg is sent when a repository's idle workstreams were checked
+// against its base branch. Results is empty if the fetch failed.
+type AutoRebaseMsg struct {
+	RepoPath    string
+	Upstream    string // The ref branches were rebased onto, e.g. origin/main
+	UpstreamSHA string // The commit Upstream pointed at
+	Results     []AutoRebaseResult
+}
+
+// AutoRebaseCmd returns a command that fetches a repository's base branch
+// and rebases the candidates' worktrees that are behind it.
+func AutoRebaseCmd(repoPath string, candidates []autoRebaseCandidate) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), autoRebaseTimeout)
+		defer cancel()
+		return autoRebase(ctx, repoPath, GitClientFactory, candidates)
+	}
+}
+
+// autoRebase fetches the base branch of the repository at repoPath and
+// rebases each candidate that doesn't contain it yet. Whether a branch is
+// behind is decided per candidate, so branches left behind by an earlier
+// tick (dirty, busy or created from an old base) catch up even when this
+// fetch brought nothing new. Candidates whose last rebase conflicted with the
+// same base commit are skipped.
+func autoRebase(ctx context.Context, repoPath string, clientFor func(string) git.GitClient, candidates []autoRebaseCandidate) AutoRebaseMsg {
+	repo := clientFor(repoPath)
+	baseBranch, err := repo.GetBaseBranch(ctx)
+	if err != nil {
+		baseBranch = "main"
+	}
+	msg := AutoRebaseMsg{RepoPath: repoPath, Upstream: "origin/" + baseBranch}
+
+	// The fetch only refreshes the remote-tracking ref
+	if err := repo.FetchMain(ctx); err != nil {
+		LogDebug("Auto-rebase: cannot fetch %s in %s: %v", baseBranch, repoPath, err)
+		return msg
+	}
+	upstreamSHA, err := repo.RevParse(ctx, msg.Upstream)
+	if err != nil {
+		LogDebug("Auto-rebase: cannot resolve %s in %s: %v", msg.Upstream, repoPath, err)
+		return msg
+	}
+	msg.UpstreamSHA = upstreamSHA
+
+	for _, c := range candidates {
+		if c.ConflictedAt == upstreamSHA {
+			continue
+		}
+		msg.Results = append(msg.Results, rebaseIdleWorkstream(ctx, clientFor(c.WorktreePath), c, msg.Upstream))
+	}
+	return msg
+}
+
+// rebaseIdleWorkstream rebases a candidate's worktree onto upstream if it is
+// behind. Worktrees with uncommitted changes are left alone; a conflicting
+// rebase is aborted.
+func rebaseIdleWorkstream(ctx context.Context, g git.GitClient, c autoRebaseCandidate, upstream string) AutoRebaseResult {
+	result := AutoRebaseResult{WorkstreamID: c.ID}
+	upToDate, err := g.IsAncestor(ctx, upstream, "HEAD")
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	if upToDate {
+		return result
+	}
+	if dirty, err := g.HasUncommittedChanges(ctx); err != nil || dirty {
+		return result
+	}
+	head, err := g.RevParse(ctx, "HEAD")
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	if err := g.RebaseOnto(ctx, upstream); err != nil {
+		var conflictErr *git.MergeConflictError
+		if errors.As(err, &conflictErr) {
+			result.ConflictFiles = conflictErr.ConflictFiles
+		}
+		// A failed rebase may have stopped midway; abort it to restore the branch
+		if abortErr := g.AbortRebase(ctx); abortErr != nil {
+			LogDebug("Auto-rebase: abort of %s: %v", c.Branch, abortErr)
+		}
+		result.Error = err
+		return result
+	}
+
+	newHead, _ := g.RevParse(ctx, "HEAD")
+	result.Rebased = newHead != head
+	if result.Rebased && c.ForcePush {
+		if err := g.ForcePush(ctx, c.Branch); err != nil {
+			result.Error = fmt.Errorf("rebased, but force push failed: %w", err)
+			return result
+		}
+		result.ForcePushed = true
+	}
+	return result
+}
+
+// autoRebaseCmds starts a background rebase for each repository that has
+// auto-rebase enabled, covering its idle workstreams with clean state: not
+// queued for merge, no dialog open, and not pushed unless force pushes are
+// allowed. Repositories with a rebase still running are skipped.
+func (m *AppModel) autoRebaseCmds() []tea.Cmd {
+	byRepo := make(map[string][]autoRebaseCandidate)
+	configs := make(map[string]docker.AutoRebaseConfig)
+	for i := range m.panes {
+		ws := m.panes[i].Workstream()
+		if ws.BranchName == "" || ws.GetState() != workstream.StateIdle {
+			continue
+		}
+		if m.mergeQueuePosition(ws.ID) > 0 || m.panes[i].GetInPaneDialog() != nil {
+			continue
+		}
+		worktreePath := resolveWorktreePath(ws)
+		if worktreePath == "" {
+			continue
+		}
+		repoPath, err := workstreamRepoPath(ws)
+		if err != nil || m.autoRebasing[repoPath] {
+			continue
+		}
+		cfg, ok := configs[repoPath]
+		if !ok {
+			cfg = docker.LoadConfig(repoPath).AutoRebase
+			configs[repoPath] = cfg
+		}
+		if !cfg.IsEnabled() {
+			continue
+		}
+		pushed := ws.GetHasBeenPushed()
+		if pushed && !cfg.ForcePushAllowed() {
+			continue
+		}
+		byRepo[repoPath] = append(byRepo[repoPath], autoRebaseCandidate{
+			ID:           ws.ID,
+			Branch:       ws.BranchName,
+			WorktreePath: worktreePath,
+			ForcePush:    pushed,
+			ConflictedAt: m.autoRebaseConflicts[ws.ID],
+		})
+	}
+
+	if len(byRepo) == 0 {
+		return nil
+	}
+	if m.autoRebasing == nil {
+		m.autoRebasing = make(map[string]bool)
+	}
+	var cmds []tea.Cmd
+	for repoPath, candidates := range byRepo {
+		m.autoRebasing[repoPath] = true
+		cmds = append(cmds, AutoRebaseCmd(repoPath, candidates))
+	}
+	return cmds
+}
+
+// handleAutoRebase reports the outcome of a background rebase in each
+// workstream's pane. Conflicts are flagged with the conflict dialog, which
+// offers to hand them to Claude.
+func (m *AppModel) handleAutoRebase(msg AutoRebaseMsg) {
+	delete(m.autoRebasing, msg.RepoPath)
+	for _, r := range msg.Results {
+		i := m.paneIndexByID(r.WorkstreamID)
+		if i < 0 {
+			continue
+		}
+		ws := m.panes[i].Workstream()
+		switch {
+		case len(r.ConflictFiles) > 0:
+			// Don't retry until the base branch moves again
+			if m.autoRebaseConflicts == nil {
+				m.autoRebaseConflicts = make(map[string]string)
+			}
+			m.autoRebaseConflicts[ws.ID] = msg.UpstreamSHA
+			m.panes[i].AppendOutput(fmt.Sprintf("\nAuto-rebase onto %s has conflicts in %d file(s); the rebase was aborted\n", msg.Upstream, len(r.ConflictFiles)))
+			ws.RecordEvent(workstream.EventAutoRebase, "conflicts: "+strings.Join(r.ConflictFiles, ", "))
+			m.managerFor(ws).UpdateWorkstream(ws.ID)
+			if m.panes[i].GetInPaneDialog() == nil {
+				dialog := NewMergeConflictDialog(ws.BranchName, ws.ID, r.ConflictFiles)
+				m.panes[i].SetInPaneDialog(&dialog)
+			}
+			m.toast = fmt.Sprintf("%s conflicts with %s", ws.BranchName, msg.Upstream)
+			m.toastExpiry = time.Now().Add(toastDuration)
+		case r.Error != nil:
+			m.panes[i].AppendOutput(fmt.Sprintf("\nAuto-rebase onto %s failed: %v\n", msg.Upstream, r.Error))
+			LogWarn("Auto-rebase of %s failed: %v", ws.BranchName, r.Error)
+		case r.Rebased:
+			delete(m.autoRebaseConflicts, ws.ID)
+			detail := "onto " + msg.Upstream
+			if r.ForcePushed {
+				detail += ", force-pushed"
+			}
+			m.panes[i].AppendOutput(fmt.Sprintf("\nAuto-rebased %s\n", detail))
+			ws.RecordEvent(workstream.EventAutoRebase, detail)
+			m.managerFor(ws).UpdateWorkstream(ws.ID)
+			// Tell a running Claude its branch moved (don't press Enter - avoids submitting Claude's pending input)
+			if !m.panes[i].HasPTY() {
+				continue
+			}
+			if err := m.panes[i].SendInput(fmt.Sprintf("[ccells] ✓ Rebased '%s' onto %s", ws.BranchName, msg.Upstream), false); err != nil {
+				LogWarn("Failed to notify Claude about auto-rebase for %s (pane %d): %v", ws.BranchName, i, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestAutoRebase(t *testing.T) {
+	ctx := context.Background()
+	repo := &git.MockGitClient{
+		GetBaseBranchFn: func(ctx context.Context) (string, error) { return "main", nil },
+		RevParseFn:      func(ctx context.Context, ref string) (string, error) { return "base1", nil },
+	}
+
+	// worktree returns a clean worktree whose HEAD moves, and catches up with
+	// the base, when rebased
+	var aborted, forcePushed []string
+	worktree := func(branch string, behind, dirty bool, conflicts []string) *git.MockGitClient {
+		head := branch + "-1"
+		return &git.MockGitClient{
+			IsAncestorFn: func(ctx context.Context, ancestor, ref string) (bool, error) {
+				if ancestor != "origin/main" || ref != "HEAD" {
+					t.Errorf("%s checked %s against %s", branch, ancestor, ref)
+				}
+				return !behind, nil
+			},
+			HasUncommittedChangesFn: func(ctx context.Context) (bool, error) { return dirty, nil },
+			RevParseFn:              func(ctx context.Context, ref string) (string, error) { return head, nil },
+			RebaseOntoFn: func(ctx context.Context, onto string) error {
+				if !behind {
+					t.Errorf("%s is up to date and should not be rebased", branch)
+				}
+				if conflicts != nil {
+					return &git.MergeConflictError{Branch: branch, ConflictFiles: conflicts}
+				}
+				head, behind = branch+"-2", false
+				return nil
+			},
+			AbortRebaseFn: func(ctx context.Context) error { aborted = append(aborted, branch); return nil },
+			ForcePushFn:   func(ctx context.Context, b string) error { forcePushed = append(forcePushed, b); return nil },
+		}
+	}
+	clients := map[string]git.GitClient{
+		"/repo":    repo,
+		"/clean":   worktree("clean", true, false, nil),
+		"/dirty":   worktree("dirty", true, true, nil),
+		"/conf":    worktree("conf", true, false, []string{"a.go"}),
+		"/push":    worktree("push", true, false, nil),
+		"/current": worktree("current", false, false, nil),
+		"/stuck":   worktree("stuck", true, false, []string{"b.go"}),
+	}
+	clientFor := func(path string) git.GitClient { return clients[path] }
+	candidates := []autoRebaseCandidate{
+		{ID: "clean", Branch: "clean", WorktreePath: "/clean"},
+		{ID: "dirty", Branch: "dirty", WorktreePath: "/dirty"},
+		{ID: "conf", Branch: "conf", WorktreePath: "/conf", ConflictedAt: "base0"},
+		{ID: "push", Branch: "push", WorktreePath: "/push", ForcePush: true},
+		{ID: "current", Branch: "current", WorktreePath: "/current"},
+		{ID: "stuck", Branch: "stuck", WorktreePath: "/stuck", ConflictedAt: "base1"},
+	}
+
+	// The fetch brings nothing new, but branches behind the base still catch up
+	msg := autoRebase(ctx, "/repo", clientFor, candidates)
+	if msg.Upstream != "origin/main" || msg.UpstreamSHA != "base1" || len(msg.Results) != 5 {
+		t.Fatalf("msg = %+v, want 5 results against origin/main at base1", msg)
+	}
+	byID := make(map[string]AutoRebaseResult)
+	for _, r := range msg.Results {
+		byID[r.WorkstreamID] = r
+	}
+	if r := byID["clean"]; !r.Rebased || r.Error != nil || r.ForcePushed {
+		t.Errorf("clean = %+v, want rebased without push", r)
+	}
+	if r := byID["dirty"]; r.Rebased || r.Error != nil {
+		t.Errorf("dirty = %+v, want skipped", r)
+	}
+	if r := byID["conf"]; r.Rebased || len(r.ConflictFiles) != 1 || strings.Join(aborted, ",") != "conf" {
+		t.Errorf("conf = %+v, aborted %v; want the conflict retried on the new base and aborted", r, aborted)
+	}
+	if r := byID["push"]; !r.Rebased || !r.ForcePushed || strings.Join(forcePushed, ",") != "push" {
+		t.Errorf("push = %+v, force-pushed %v; want rebased and force-pushed", r, forcePushed)
+	}
+	if r := byID["current"]; r.Rebased || r.Error != nil {
+		t.Errorf("current = %+v, want left alone", r)
+	}
+	if _, ok := byID["stuck"]; ok {
+		t.Error("a branch that conflicted with this base commit should not be retried")
+	}
+
+	// Rebased branches now contain the base and are left alone
+	msg = autoRebase(ctx, "/repo", clientFor, candidates[:1])
+	if len(msg.Results) != 1 || msg.Results[0].Rebased {
+		t.Errorf("results = %+v, want clean left alone", msg.Results)
+	}
+}
+
+func TestAppModel_AutoRebase(t *testing.T) {
+	cellsDir := t.TempDir()
+	docker.SetTestCellsDir(cellsDir)
+	defer docker.SetTestCellsDir("")
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte("auto_rebase:\n  enabled: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := newTestApp(t)
+	repoPath := t.TempDir()
+	for i := range app.panes {
+		ws := app.panes[i].Workstream()
+		ws.RepoPath = repoPath
+		ws.WorktreePath = t.TempDir()
+		ws.SetState(workstream.StateIdle)
+	}
+	alpha, beta, gamma := app.panes[0].Workstream(), app.panes[1].Workstream(), app.panes[2].Workstream()
+	beta.SetHasBeenPushed(true)             // Skipped: force pushes aren't allowed
+	gamma.SetState(workstream.StateRunning) // Skipped: Claude is working
+
+	if cmds := app.autoRebaseCmds(); len(cmds) != 1 || !app.autoRebasing[repoPath] {
+		t.Fatalf("cmds = %d, want one rebase of the repository", len(cmds))
+	}
+	if cmds := app.autoRebaseCmds(); len(cmds) != 0 {
+		t.Error("a repository being rebased should not be rebased again")
+	}
+
+	model, _ := app.Update(AutoRebaseMsg{RepoPath: repoPath, Upstream: "origin/main", UpstreamSHA: "base1", Results: []AutoRebaseResult{
+		{WorkstreamID: alpha.ID, Rebased: true},
+		{WorkstreamID: beta.ID, ConflictFiles: []string{"a.go"}, Error: &git.MergeConflictError{Branch: beta.BranchName, ConflictFiles: []string{"a.go"}}},
+	}})
+	app = model.(AppModel)
+	if app.autoRebasing[repoPath] {
+		t.Error("the repository should no longer be marked busy")
+	}
+	if events := alpha.GetEvents(); len(events) == 0 || events[len(events)-1].Type != workstream.EventAutoRebase {
+		t.Error("the rebase should be added to the timeline")
+	}
+	if out := app.panes[0].output.String(); !strings.Contains(out, "Auto-rebased onto origin/main") {
+		t.Errorf("pane output = %q, want a rebase notice", out)
+	}
+	if d := app.panes[1].GetInPaneDialog(); d == nil || d.Type != DialogMergeConflict {
+		t.Error("a conflict should be flagged with the conflict dialog")
+	}
+	if got := app.autoRebaseConflicts[beta.ID]; got != "base1" {
+		t.Errorf("conflicted base = %q, want base1 so the rebase isn't retried until the base moves", got)
+	}
+}
+
+func TestAppModel_AutoRebaseConflictResumesClaude(t *testing.T) {
+	app := newTestApp(t)
+	beta := app.panes[1].Workstream()
+	beta.SetState(workstream.StateIdle)
+	dialog := NewMergeConflictDialog(beta.BranchName, beta.ID, []string{"a.go"})
+	app.panes[1].SetInPaneDialog(&dialog)
+	confirm := DialogConfirmMsg{Type: DialogMergeConflict, WorkstreamID: beta.ID, Value: "0", ConflictFiles: []string{"a.go"}}
+
+	// No session and no container: the failure is reported instead of claimed
+	model, cmd := app.Update(confirm)
+	app = model.(AppModel)
+	if cmd != nil || !strings.Contains(app.panes[1].output.String(), "Resolve the conflicts manually") {
+		t.Errorf("output = %q, want the failure reported", app.panes[1].output.String())
+	}
+
+	// Claude's session ended after the rebase: it is resumed with the prompt
+	beta.SetContainerID("container-beta")
+	model, cmd = app.Update(confirm)
+	app = model.(AppModel)
+	if cmd == nil || !strings.Contains(app.panes[1].output.String(), "Resuming Claude's session") {
+		t.Errorf("output = %q, want the ended session resumed with the prompt", app.panes[1].output.String())
+	}
+}
+
+func TestAppModel_AutoRebaseDisabled(t *testing.T) {
+	docker.SetTestCellsDir(t.TempDir())
+	defer docker.SetTestCellsDir("")
+
+	app := newTestApp(t)
+	for i := range app.panes {
+		ws := app.panes[i].Workstream()
+		ws.RepoPath = t.TempDir()
+		ws.SetState(workstream.StateIdle)
+	}
+	if cmds := app.autoRebaseCmds(); len(cmds) != 0 {
+		t.Errorf("cmds = %d, want none when auto-rebase is off", len(cmds))
+	}
+}
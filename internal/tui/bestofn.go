@@ -0,0 +1,404 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/hooks"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// bestOfNPreset is a choice in the Best-of-N dialog.
+type bestOfNPreset struct {
+	Label    string
+	Runtimes []string // One runtime per cell ("" = session default)
+}
+
+// bestOfNPresets lists the Best-of-N configurations offered to the user.
+var bestOfNPresets = []bestOfNPreset{
+	{Label: "2 cells", Runtimes: []string{"", ""}},
+	{Label: "3 cells", Runtimes: []string{"", "", ""}},
+	{Label: "4 cells", Runtimes: []string{"", "", "", ""}},
+	{Label: "2 cells: claude vs claudesp", Runtimes: []string{"claude", "claudesp"}},
+}
+
+// groupBadgeColors are the marker colors used to tell Best-of-N groups apart.
+var groupBadgeColors = []string{"#FFB86C", "#8BE9FD", "#FF79C6", "#50FA7B", "#F1FA8C", "#BD93F9"}
+
+// BestOfNSelectMsg is sent when a Best-of-N configuration is chosen.
+type BestOfNSelectMsg struct {
+	Runtimes []string
+}
+
+// GroupCompareEntry holds the comparison data for one workstream in a group.
+type GroupCompareEntry struct {
+	WorkstreamID string
+	BranchName   string
+	Runtime      string
+	State        workstream.State
+	Commits      string // e.g. "3 commits"
+	DiffStat     string // e.g. "4 files changed, 120 insertions(+)"
+	TestsRun     bool   // True if the verification command was run
+	TestsPassed  bool
+	TestsSummary string // e.g. "passed" or "failed (exit 2)"
+	Synopsis     string
+}
+
+// GroupComparisonMsg is sent when comparison data for a group has been gathered.
+type GroupComparisonMsg struct {
+	GroupID string
+	Entries []GroupCompareEntry
+}
+
+// BestOfNWinnerMsg is sent when the user picks a winner in the comparison dialog.
+type BestOfNWinnerMsg struct {
+	GroupID  string
+	WinnerID string
+}
+
+// newGroupID returns a new Best-of-N group identifier.
+func newGroupID() string {
+	return fmt.Sprintf("bon-%d", time.Now().UnixNano())
+}
+
+// groupBadge renders a colored marker shared by all panes in a group.
+func groupBadge(groupID string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(groupID))
+	color := groupBadgeColors[h.Sum32()%uint32(len(groupBadgeColors))]
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render("◆")
+}
+
+// bestOfNTitle builds a pane title for member n of a Best-of-N group.
+func bestOfNTitle(prompt string, n, total int) string {
+	title := strings.Join(strings.Fields(prompt), " ")
+	if len(title) > 40 {
+		title = title[:37] + "..."
+	}
+	return fmt.Sprintf("%s [%d/%d]", title, n, total)
+}
+
+// summarizeBranch returns the number of commits a branch has on top of the
+// base branch and the shortstat summary of its changes. Either is empty if
+// git fails.
+func summarizeBranch(ctx context.Context, g git.GitClient, branch string) (commits, diffStat string) {
+	if n, err := g.CommitsAhead(ctx, branch); err == nil {
+		if n == 1 {
+			commits = "1 commit"
+		} else {
+			commits = fmt.Sprintf("%d commits", n)
+		}
+	}
+	if stat, err := g.DiffShortStat(ctx, branch); err == nil {
+		diffStat = stat
+	}
+	return commits, diffStat
+}
+
+// CompareGroupCmd gathers diff stats, test results and synopses for every
+// workstream in a Best-of-N group. If a verification command is configured,
+// it is run in each container in parallel.
+func CompareGroupCmd(groupID string, members []*workstream.Workstream, verify docker.VerifyConfig) tea.Cmd {
+	return func() tea.Msg {
+		var client docker.DockerClient
+		if verify.Command != "" {
+			if c, err := docker.NewClient(); err == nil {
+				defer c.Close()
+				client = c
+			} else {
+				LogWarn("Best-of-N compare: docker unavailable, skipping tests: %v", err)
+			}
+		}
+		return GroupComparisonMsg{
+			GroupID: groupID,
+			Entries: compareGroup(members, client, verify),
+		}
+	}
+}
+
+// compareGroup builds comparison entries for the given workstreams.
+// Tests are run only when client is non-nil and a verification command is set.
+func compareGroup(members []*workstream.Workstream, client docker.DockerClient, verify docker.VerifyConfig) []GroupCompareEntry {
+	entries := make([]GroupCompareEntry, len(members))
+	var wg sync.WaitGroup
+	for i, ws := range members {
+		wg.Add(1)
+		go func(i int, ws *workstream.Workstream) {
+			defer wg.Done()
+			entry := GroupCompareEntry{
+				WorkstreamID: ws.ID,
+				BranchName:   ws.BranchName,
+				Runtime:      ws.Runtime,
+				State:        ws.GetState(),
+				Synopsis:     ws.GetSynopsis(),
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if worktreePath := resolveWorktreePath(ws); worktreePath != "" {
+				entry.Commits, entry.DiffStat = summarizeBranch(ctx, GitClientFactory(worktreePath), ws.BranchName)
+			}
+			cancel()
+
+			if client != nil && verify.Command != "" && ws.ContainerID != "" {
+				ctx, cancel := context.WithTimeout(context.Background(), verify.GetTimeout())
+				var out bytes.Buffer
+				code, err := runVerification(ctx, client, ws.ContainerID, verify.Command, &out)
+				cancel()
+				entry.TestsRun = true
+				switch {
+				case err != nil:
+					entry.TestsSummary = fmt.Sprintf("error: %v", err)
+				case code == 0:
+					entry.TestsPassed = true
+					entry.TestsSummary = "passed"
+				default:
+					entry.TestsSummary = fmt.Sprintf("failed (exit %d)", code)
+				}
+			}
+			entries[i] = entry
+		}(i, ws)
+	}
+	wg.Wait()
+	return entries
+}
+
+// groupMembers returns the pane indices of all workstreams in a group.
+func (m *AppModel) groupMembers(groupID string) []int {
+	if groupID == "" {
+		return nil
+	}
+	var idxs []int
+	for i := range m.panes {
+		if m.panes[i].Workstream().GetGroupID() == groupID {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs
+}
+
+// groupAllIdle reports whether every workstream in a group has finished.
+func (m *AppModel) groupAllIdle(groupID string) bool {
+	idxs := m.groupMembers(groupID)
+	if len(idxs) < 2 {
+		return false
+	}
+	for _, i := range idxs {
+		if m.panes[i].Workstream().GetState() != workstream.StateIdle {
+			return false
+		}
+	}
+	return true
+}
+
+// startBestOfN creates one workstream per runtime from the same prompt,
+// grouped so their results can be compared. The cells are created in the
+// repository of the focused pane.
+func (m *AppModel) startBestOfN(prompt string, runtimes []string) tea.Cmd {
+	groupID := newGroupID()
+	var repoPath string
+	if m.focusedPane < len(m.panes) {
+		repoPath = m.panes[m.focusedPane].Workstream().RepoPath
+	}
+	var existingBranches []string
+	for _, pane := range m.panes {
+		if bn := pane.Workstream().BranchName; bn != "" {
+			existingBranches = append(existingBranches, bn)
+		}
+	}
+
+	var cmds []tea.Cmd
+	for n, runtime := range runtimes {
+		ws := workstream.NewWithUniqueBranch(prompt, existingBranches)
+		existingBranches = append(existingBranches, ws.BranchName)
+		ws.Runtime = globalRuntime
+		if runtime != "" {
+			ws.Runtime = normalizeRuntime(runtime)
+		}
+		ws.SetGroupID(groupID)
+		ws.SetTitle(bestOfNTitle(prompt, n+1, len(runtimes)))
+		ws.RepoPath = repoPath
+		if err := m.managerFor(ws).Add(ws); err != nil {
+			m.toast = fmt.Sprintf("Cannot create workstream: %v", err)
+			m.toastExpiry = time.Now().Add(toastDuration * 2)
+			break
+		}
+
+		pane := NewPaneModel(ws)
+		pane.SetIndex(m.nextPaneIndex)
+		m.nextPaneIndex++
+		pane.SetInitializing(true)
+		pane.SetInitStatus("Starting container...")
+		m.panes = append(m.panes, pane)
+		m.revealPane(len(m.panes) - 1)
+		// Untracked files are not prompted for per cell; config provisioning still applies
+		cmds = append(cmds, StartContainerWithCopyUntrackedFilesCmd(ws, false))
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	m.updateLayoutQuiet()
+	if m.focusedPane < len(m.panes) {
+		m.panes[m.focusedPane].SetFocused(false)
+	}
+	m.setFocusedPane(len(m.panes) - len(cmds))
+	m.panes[m.focusedPane].SetFocused(true)
+	m.keepPanesSorted()
+	LogInfo("Started Best-of-%d group %s", len(cmds), groupID)
+
+	cmds = append(cmds, spinnerTickCmd())
+	return tea.Batch(cmds...)
+}
+
+// renderGroupComparison renders comparison entries as side-by-side columns,
+// highlighting the selected one.
+func renderGroupComparison(entries []GroupCompareEntry, selected, width int) string {
+	if len(entries) == 0 {
+		return "No cells to compare."
+	}
+	colWidth := width/len(entries) - 1
+	if colWidth < 20 {
+		colWidth = 20
+	}
+	textWidth := colWidth - 4 // border (2) + padding (2)
+
+	passStyle := lipgloss.NewStyle().Foreground(ColorRunning)
+	failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorPairingConflict))
+
+	columns := make([]string, len(entries))
+	for i, e := range entries {
+		var b strings.Builder
+		b.WriteString(PaneTitle.Render(lipgloss.NewStyle().MaxWidth(textWidth).Render(e.BranchName)))
+		b.WriteString("\n")
+		b.WriteString(KeyHintStyle.Render(fmt.Sprintf("%s · %s", e.Runtime, e.State)))
+		b.WriteString("\n\n")
+
+		commits := e.Commits
+		if commits == "" {
+			commits = "unknown"
+		}
+		b.WriteString(commits)
+		b.WriteString("\n")
+		if e.DiffStat != "" {
+			b.WriteString(e.DiffStat)
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\nTests: ")
+		switch {
+		case !e.TestsRun:
+			b.WriteString(KeyHintStyle.Render("not configured"))
+		case e.TestsPassed:
+			b.WriteString(passStyle.Render("✓ " + e.TestsSummary))
+		default:
+			b.WriteString(failStyle.Render("✗ " + e.TestsSummary))
+		}
+		b.WriteString("\n")
+
+		if e.Synopsis != "" {
+			b.WriteString("\n")
+			b.WriteString(e.Synopsis)
+		}
+
+		style := PaneBorderInactive.Width(colWidth)
+		if i == selected {
+			style = PaneBorderActive.BorderForeground(ColorAccent).Width(colWidth)
+		}
+		columns[i] = style.Render(b.String())
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+}
+
+// openGroupComparison shows the comparison dialog for a group and starts
+// gathering its results.
+func (m *AppModel) openGroupComparison(groupID string) tea.Cmd {
+	idxs := m.groupMembers(groupID)
+	members := make([]*workstream.Workstream, 0, len(idxs))
+	for _, i := range idxs {
+		members = append(members, m.panes[i].Workstream())
+	}
+
+	dialog := NewBestOfNCompareDialog(groupID, len(members))
+	dialog.SetSize(m.width-10, m.height-6)
+	m.dialog = &dialog
+	// Cells of a group share a repository, and its verify command
+	configDir := m.workingDir
+	if len(members) > 0 {
+		configDir = m.repoDir(members[0])
+	}
+	return CompareGroupCmd(groupID, members, docker.LoadConfig(configDir).Verify)
+}
+
+// finishBestOfN resolves a Best-of-N group in favor of the winner, optionally
+// destroying the other cells, then opens the merge/PR flow for the winner.
+func (m *AppModel) finishBestOfN(winnerID string, destroyRest bool) tea.Cmd {
+	var winner *workstream.Workstream
+	for i := range m.panes {
+		if m.panes[i].Workstream().ID == winnerID {
+			winner = m.panes[i].Workstream()
+			break
+		}
+	}
+	if winner == nil {
+		return nil
+	}
+	groupID := winner.GetGroupID()
+
+	var cmds []tea.Cmd
+	if destroyRest {
+		// Collect losers first since removePane shifts indices
+		var losers []string
+		for _, i := range m.groupMembers(groupID) {
+			if id := m.panes[i].Workstream().ID; id != winnerID {
+				losers = append(losers, id)
+			}
+		}
+		for _, id := range losers {
+			for i := range m.panes {
+				if m.panes[i].Workstream().ID == id {
+					ws := m.removePane(i)
+					cmds = append(cmds, ArchiveAndStopContainerCmd(ws, m.stateDirFor(ws)), m.runHooks(hooks.EventDestroy, ws))
+					break
+				}
+			}
+		}
+		m.toast = fmt.Sprintf("Destroying %d other cell(s)...", len(losers))
+		m.toastExpiry = time.Now().Add(toastDuration)
+	} else {
+		// Surviving cells are no longer grouped with the winner
+		for _, i := range m.groupMembers(groupID) {
+			ws := m.panes[i].Workstream()
+			ws.SetGroupID("")
+			m.managerFor(ws).UpdateWorkstream(ws.ID)
+		}
+	}
+	winner.SetGroupID("")
+	m.managerFor(winner).UpdateWorkstream(winner.ID)
+
+	// Focus the winner and open the merge/PR options
+	for i := range m.panes {
+		if m.panes[i].Workstream().ID == winnerID {
+			if m.focusedPane < len(m.panes) {
+				m.panes[m.focusedPane].SetFocused(false)
+			}
+			m.setFocusedPane(i)
+			m.panes[i].SetFocused(true)
+			cmds = append(cmds, CheckUncommittedChangesCmd(winner))
+			if winner.PRURL != "" {
+				m.panes[i].SetPRStatusLoading(true)
+				cmds = append(cmds, FetchPRStatusCmd(winner))
+			}
+			break
+		}
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestSummarizeBranch(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		count       int
+		stat        string
+		err         error
+		wantCommits string
+		wantStat    string
+	}{
+		{name: "multiple commits", count: 3, stat: "4 files changed, 120 insertions(+), 8 deletions(-)", wantCommits: "3 commits", wantStat: "4 files changed, 120 insertions(+), 8 deletions(-)"},
+		{name: "single commit", count: 1, stat: "1 file changed, 2 insertions(+)", wantCommits: "1 commit", wantStat: "1 file changed, 2 insertions(+)"},
+		{name: "no commits", wantCommits: "0 commits"},
+		{name: "git error", err: errors.New("not a git repository")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &git.MockGitClient{
+				Err:             tt.err,
+				CommitsAheadFn:  func(ctx context.Context, branch string) (int, error) { return tt.count, nil },
+				DiffShortStatFn: func(ctx context.Context, branch string) (string, error) { return tt.stat, nil },
+			}
+			commits, stat := summarizeBranch(ctx, g, "feature")
+			if commits != tt.wantCommits {
+				t.Errorf("commits = %q, want %q", commits, tt.wantCommits)
+			}
+			if stat != tt.wantStat {
+				t.Errorf("diffStat = %q, want %q", stat, tt.wantStat)
+			}
+		})
+	}
+}
+
+func TestCompareGroup(t *testing.T) {
+	mockGit := git.NewMockGitClient()
+	mockGit.CommitsAheadFn = func(ctx context.Context, branch string) (int, error) { return 2, nil }
+	mockGit.DiffShortStatFn = func(ctx context.Context, branch string) (string, error) {
+		return "3 files changed, 10 insertions(+)", nil
+	}
+	restore := SetGitClientFactory(func(path string) git.GitClient { return mockGit })
+	defer restore()
+
+	client := docker.NewMockClient()
+	ctx := context.Background()
+	passID, _ := client.CreateContainer(ctx, &docker.ContainerConfig{Name: "ccells-test-pass"})
+	failID, _ := client.CreateContainer(ctx, &docker.ContainerConfig{Name: "ccells-test-fail"})
+	client.ExecStreamFn = func(ctx context.Context, id string, cmd []string, output io.Writer) (int, error) {
+		if id == failID {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	pass := workstream.New("task")
+	pass.WorktreePath = "/tmp/pass"
+	pass.ContainerID = passID
+	pass.SetSynopsis("Did it one way")
+	fail := workstream.New("task")
+	fail.WorktreePath = "/tmp/fail"
+	fail.ContainerID = failID
+
+	entries := compareGroup([]*workstream.Workstream{pass, fail}, client, docker.VerifyConfig{Command: "make test"})
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].WorkstreamID != pass.ID || entries[1].WorkstreamID != fail.ID {
+		t.Error("entries should keep member order")
+	}
+	if entries[0].Commits != "2 commits" || entries[0].DiffStat != "3 files changed, 10 insertions(+)" {
+		t.Errorf("unexpected diff summary: %+v", entries[0])
+	}
+	if entries[0].Synopsis != "Did it one way" {
+		t.Errorf("Synopsis = %q", entries[0].Synopsis)
+	}
+	if !entries[0].TestsRun || !entries[0].TestsPassed {
+		t.Errorf("first cell should pass tests: %+v", entries[0])
+	}
+	if !entries[1].TestsRun || entries[1].TestsPassed || entries[1].TestsSummary != "failed (exit 1)" {
+		t.Errorf("second cell should fail tests: %+v", entries[1])
+	}
+
+	t.Run("no verify command skips tests", func(t *testing.T) {
+		entries := compareGroup([]*workstream.Workstream{pass}, client, docker.VerifyConfig{})
+		if entries[0].TestsRun {
+			t.Error("tests should not run without a verification command")
+		}
+	})
+}
+
+func TestBestOfNCompareDialog(t *testing.T) {
+	d := NewBestOfNCompareDialog("bon-1", 2)
+	d.SetSize(100, 30)
+
+	// Enter does nothing while loading
+	if _, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter}); cmd != nil {
+		t.Error("enter should be ignored while results are loading")
+	}
+
+	d.SetCompareEntries([]GroupCompareEntry{
+		{WorkstreamID: "ws-1", BranchName: "task"},
+		{WorkstreamID: "ws-2", BranchName: "task-2"},
+	})
+	if d.View() == "" {
+		t.Error("comparison should render")
+	}
+
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyRight}) // Stays on last column
+	_, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should pick a winner")
+	}
+	msg, ok := cmd().(BestOfNWinnerMsg)
+	if !ok {
+		t.Fatalf("expected BestOfNWinnerMsg, got %T", cmd())
+	}
+	if msg.GroupID != "bon-1" || msg.WinnerID != "ws-2" {
+		t.Errorf("unexpected winner message: %+v", msg)
+	}
+}
+
+// newBestOfNApp creates an app with a Best-of-N group of the given size.
+func newBestOfNApp(t *testing.T, n int) AppModel {
+	t.Helper()
+	app := NewAppModel(context.Background())
+	app.width = 120
+	app.height = 40
+
+	runtimes := make([]string, n)
+	model, _ := app.Update(BestOfNSelectMsg{Runtimes: runtimes})
+	app = model.(AppModel)
+	if app.dialog == nil || app.dialog.Type != DialogBestOfNPrompt {
+		t.Fatalf("expected prompt dialog, got %+v", app.dialog)
+	}
+
+	model, cmd := app.Update(DialogConfirmMsg{Type: DialogBestOfNPrompt, Value: "fix the flaky test"})
+	app = model.(AppModel)
+	if cmd == nil {
+		t.Fatal("expected commands to start containers")
+	}
+	return app
+}
+
+func TestAppModel_BestOfN_CreatesGroup(t *testing.T) {
+	app := newBestOfNApp(t, 3)
+
+	if len(app.panes) != 3 {
+		t.Fatalf("expected 3 panes, got %d", len(app.panes))
+	}
+	groupID := app.panes[0].Workstream().GetGroupID()
+	if groupID == "" {
+		t.Fatal("panes should share a group ID")
+	}
+	branches := make(map[string]bool)
+	for _, pane := range app.panes {
+		ws := pane.Workstream()
+		if ws.GetGroupID() != groupID {
+			t.Errorf("pane %s has group %q, want %q", ws.ID, ws.GetGroupID(), groupID)
+		}
+		if ws.Prompt != "fix the flaky test" {
+			t.Errorf("Prompt = %q", ws.Prompt)
+		}
+		if branches[ws.BranchName] {
+			t.Errorf("duplicate branch name %q", ws.BranchName)
+		}
+		branches[ws.BranchName] = true
+	}
+	if app.pendingBestOfN != nil {
+		t.Error("pending runtimes should be cleared")
+	}
+}
+
+func TestAppModel_BestOfN_RuntimePreset(t *testing.T) {
+	app := NewAppModel(context.Background())
+	app.width = 120
+	app.height = 40
+
+	model, _ := app.Update(BestOfNSelectMsg{Runtimes: []string{"claude", "claudesp"}})
+	app = model.(AppModel)
+	model, _ = app.Update(DialogConfirmMsg{Type: DialogBestOfNPrompt, Value: "task"})
+	app = model.(AppModel)
+
+	if len(app.panes) != 2 {
+		t.Fatalf("expected 2 panes, got %d", len(app.panes))
+	}
+	if app.panes[0].Workstream().Runtime != "claude" || app.panes[1].Workstream().Runtime != "claudesp" {
+		t.Errorf("runtimes = %q, %q", app.panes[0].Workstream().Runtime, app.panes[1].Workstream().Runtime)
+	}
+}
+
+func TestAppModel_BestOfN_AllIdleOpensComparison(t *testing.T) {
+	app := newBestOfNApp(t, 2)
+	app.dialog = nil
+
+	model, _ := app.Update(PTYClosedMsg{WorkstreamID: app.panes[0].Workstream().ID})
+	app = model.(AppModel)
+	if app.dialog != nil {
+		t.Fatal("comparison should wait until all cells are idle")
+	}
+
+	model, cmd := app.Update(PTYClosedMsg{WorkstreamID: app.panes[1].Workstream().ID})
+	app = model.(AppModel)
+	if app.dialog == nil || app.dialog.Type != DialogBestOfNCompare {
+		t.Fatalf("expected comparison dialog, got %+v", app.dialog)
+	}
+	if cmd == nil {
+		t.Error("expected command gathering comparison results")
+	}
+
+	groupID := app.panes[0].Workstream().GetGroupID()
+	model, _ = app.Update(GroupComparisonMsg{GroupID: groupID, Entries: []GroupCompareEntry{{WorkstreamID: "a"}, {WorkstreamID: "b"}}})
+	app = model.(AppModel)
+	if len(app.dialog.compareEntries) != 2 {
+		t.Error("comparison results should be shown in the dialog")
+	}
+}
+
+func TestAppModel_BestOfN_WinnerDestroysRest(t *testing.T) {
+	app := newBestOfNApp(t, 3)
+	winner := app.panes[1].Workstream()
+	groupID := winner.GetGroupID()
+
+	model, _ := app.Update(BestOfNWinnerMsg{GroupID: groupID, WinnerID: winner.ID})
+	app = model.(AppModel)
+	if app.dialog == nil || app.dialog.Type != DialogBestOfNDestroyRest {
+		t.Fatalf("expected destroy-rest dialog, got %+v", app.dialog)
+	}
+
+	model, cmd := app.Update(DialogConfirmMsg{Type: DialogBestOfNDestroyRest, WorkstreamID: winner.ID, Value: "0"})
+	app = model.(AppModel)
+	if cmd == nil {
+		t.Error("expected commands to stop containers and open merge options")
+	}
+	if len(app.panes) != 1 {
+		t.Fatalf("expected only the winner to remain, got %d panes", len(app.panes))
+	}
+	if app.panes[0].Workstream().ID != winner.ID {
+		t.Error("remaining pane should be the winner")
+	}
+	if winner.GetGroupID() != "" {
+		t.Error("winner should no longer be grouped")
+	}
+}
+
+func TestAppModel_BestOfN_WinnerKeepsRest(t *testing.T) {
+	app := newBestOfNApp(t, 2)
+	winner := app.panes[0].Workstream()
+
+	model, _ := app.Update(DialogConfirmMsg{Type: DialogBestOfNDestroyRest, WorkstreamID: winner.ID, Value: "1"})
+	app = model.(AppModel)
+	if len(app.panes) != 2 {
+		t.Fatalf("expected both panes to remain, got %d", len(app.panes))
+	}
+	for _, pane := range app.panes {
+		if pane.Workstream().GetGroupID() != "" {
+			t.Error("group should be dissolved after picking a winner")
+		}
+	}
+}
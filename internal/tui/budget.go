@@ -0,0 +1,323 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/claude"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// budgetExceededBadge renders the pane header marker for a spent budget.
+func budgetExceededBadge() string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444")).Bold(true).Render("budget exceeded")
+}
+
+// budgetConfig returns the budgets configured for a repository ("" for the
+// one ccells was started in).
+func (m *AppModel) budgetConfig(repoPath string) docker.BudgetConfig {
+	if r := m.repoByPath(repoPath); r != nil {
+		return r.Budget
+	}
+	return m.budget
+}
+
+// setBudgetConfig records the budgets configured for a repository.
+func (m *AppModel) setBudgetConfig(repoPath string, budget docker.BudgetConfig) {
+	if r := m.repoByPath(repoPath); r != nil {
+		r.Budget = budget
+		return
+	}
+	if repoPath == "" {
+		m.budget = budget
+	}
+}
+
+// reloadBudgetConfig reads a repository's budgets from its config.
+func (m *AppModel) reloadBudgetConfig(repoPath string) docker.BudgetConfig {
+	budget := docker.LoadConfig(m.repoPathDir(repoPath)).Budget
+	m.setBudgetConfig(repoPath, budget)
+	return budget
+}
+
+// spentUsage returns the usage of a repository's destroyed and archived
+// workstreams by workstream ID, creating the map if needed.
+func (m *AppModel) spentUsage(repoPath string) map[string]claude.Usage {
+	if r := m.repoByPath(repoPath); r != nil {
+		if r.Spent == nil {
+			r.Spent = make(map[string]claude.Usage)
+		}
+		return r.Spent
+	}
+	if m.spent == nil {
+		m.spent = make(map[string]claude.Usage)
+	}
+	return m.spent
+}
+
+// retireUsage keeps the usage of a workstream being removed counting toward
+// its project budget. ArchiveAndStopContainerCmd persists it.
+func (m *AppModel) retireUsage(ws *workstream.Workstream) {
+	if usage := ws.GetUsage(); !usage.IsZero() {
+		m.spentUsage(ws.RepoPath)[ws.ID] = usage
+	}
+}
+
+// recordSpend persists the usage of a workstream being removed.
+func recordSpend(ws *workstream.Workstream, stateDir string) {
+	if stateDir == "" {
+		return
+	}
+	if err := workstream.RecordSpend(stateDir, ws.ID, ws.GetUsage()); err != nil {
+		LogWarn("Failed to record the usage of %s: %v", ws.BranchName, err)
+	}
+}
+
+// RecordSpendCmd returns a command persisting the usage of a workstream being removed.
+func RecordSpendCmd(ws *workstream.Workstream, stateDir string) tea.Cmd {
+	return func() tea.Msg {
+		recordSpend(ws, stateDir)
+		return nil
+	}
+}
+
+// projectSpend returns the usage of a repository's live workstreams plus
+// that of its destroyed and archived ones.
+func (m *AppModel) projectSpend(repoPath string) claude.Usage {
+	total := repoUsage(m.panes, repoPath)
+	for _, u := range m.spentUsage(repoPath) {
+		total = total.Add(u)
+	}
+	return total
+}
+
+// workstreamBudget returns the budget that applies to a workstream: its own
+// override if set, otherwise its repository's configured default.
+func (m *AppModel) workstreamBudget(ws *workstream.Workstream) claude.Budget {
+	if b := ws.GetBudget(); !b.IsZero() {
+		return b
+	}
+	return m.budgetConfig(ws.RepoPath).Workstream
+}
+
+// checkBudgets updates the budget state of every pane, warns when a budget
+// crosses the warning threshold, and interrupts Claude in panes that are over
+// budget and still using tokens. grown holds the workstreams whose usage
+// changed since the last check.
+func (m *AppModel) checkBudgets(grown map[string]bool) tea.Cmd {
+	if m.budgetWarned == nil {
+		m.budgetWarned = make(map[string]bool)
+	}
+	if m.projectBudgetWarned == nil {
+		m.projectBudgetWarned = make(map[string]bool)
+	}
+	var warnings []string
+	var cmds []tea.Cmd
+
+	// Each repository's project budget covers its own workstreams, including
+	// destroyed and archived ones
+	projectExceeded := make(map[string]bool)
+	for _, repoPath := range m.repoPaths() {
+		cfg := m.budgetConfig(repoPath)
+		project := cfg.Project
+		total := m.projectSpend(repoPath)
+		projectFraction := project.Fraction(total)
+		projectExceeded[repoPath] = project.Exceeded(total)
+		warned := !project.IsZero() && projectFraction >= cfg.GetWarnAt()
+		if warned && !projectExceeded[repoPath] && !m.projectBudgetWarned[repoPath] {
+			name := "Project"
+			if len(m.repos) > 0 {
+				name = m.repoName(repoPath)
+			}
+			warnings = append(warnings, fmt.Sprintf("%s has used %.0f%% of its budget", name, projectFraction*100))
+		}
+		m.projectBudgetWarned[repoPath] = warned
+	}
+
+	for i := range m.panes {
+		ws := m.panes[i].Workstream()
+		cfg := m.budgetConfig(ws.RepoPath)
+		warnAt := cfg.GetWarnAt()
+		budget := m.workstreamBudget(ws)
+		usage := ws.GetUsage()
+		fraction := budget.Fraction(usage)
+		exceeded := budget.Exceeded(usage) || projectExceeded[ws.RepoPath]
+
+		if exceeded && !m.panes[i].IsBudgetExceeded() {
+			key := "$"
+			if !budget.Exceeded(usage) {
+				key = "B" // Only the project budget is spent
+			}
+			warnings = append(warnings, fmt.Sprintf("Budget exceeded: stopped %s (press %s to raise)", ws.BranchName, key))
+			ws.RecordEvent(workstream.EventBudgetExceeded, formatCost(usage.CostUSD))
+		}
+		if exceeded && grown[ws.ID] && ws.ContainerID != "" {
+			cmds = append(cmds, InterruptClaudeCmd(ws))
+		}
+		m.panes[i].SetBudgetExceeded(exceeded)
+
+		if budget.IsZero() || fraction < warnAt {
+			delete(m.budgetWarned, ws.ID)
+		} else if !exceeded && !m.budgetWarned[ws.ID] {
+			m.budgetWarned[ws.ID] = true
+			warnings = append(warnings, fmt.Sprintf("%s has used %.0f%% of its budget", ws.BranchName, fraction*100))
+		}
+	}
+
+	if len(warnings) > 0 {
+		m.toast = strings.Join(warnings, "; ")
+		m.toastExpiry = time.Now().Add(toastDuration * 3)
+	}
+	return tea.Batch(cmds...)
+}
+
+// InterruptClaudeCmd sends Ctrl+C (SIGINT) to Claude in a workstream's
+// container, stopping its current turn without ending the session.
+func InterruptClaudeCmd(ws *workstream.Workstream) tea.Cmd {
+	containerID := ws.ContainerID
+	branch := ws.BranchName
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		dockerClient, err := docker.NewClient()
+		if err != nil {
+			LogWarn("Failed to interrupt %s: %v", branch, err)
+			return nil
+		}
+		defer dockerClient.Close()
+
+		if err := dockerClient.SignalProcess(ctx, containerID, "claude", "INT"); err != nil {
+			LogWarn("Failed to interrupt %s: %v", branch, err)
+			return nil
+		}
+		LogInfo("Interrupted %s: token budget exceeded", branch)
+		return nil
+	}
+}
+
+// parseBudgetInput parses a budget such as "$5", "2M", "500k" or "$5 2M".
+// Values starting or ending with "$" are costs in USD; other values are token
+// counts with an optional k or M suffix. Empty input clears the budget.
+func parseBudgetInput(input string) (claude.Budget, error) {
+	var b claude.Budget
+	for _, field := range strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return r == ' ' || r == ','
+	}) {
+		if strings.HasPrefix(field, "$") || strings.HasSuffix(field, "$") {
+			cost, err := strconv.ParseFloat(strings.Trim(field, "$"), 64)
+			if err != nil || cost < 0 {
+				return claude.Budget{}, fmt.Errorf("invalid cost %q", field)
+			}
+			b.CostUSD = cost
+			continue
+		}
+		field = strings.TrimSuffix(field, "tokens")
+		if field == "" {
+			continue // "2M tokens"
+		}
+		multiplier := 1.0
+		switch {
+		case strings.HasSuffix(field, "k"):
+			multiplier = 1e3
+		case strings.HasSuffix(field, "m"):
+			multiplier = 1e6
+		}
+		if multiplier > 1 {
+			field = field[:len(field)-1]
+		}
+		n, err := strconv.ParseFloat(field, 64)
+		if err != nil || n < 0 {
+			return claude.Budget{}, fmt.Errorf("invalid token count %q", field)
+		}
+		b.Tokens = int64(n * multiplier)
+	}
+	return b, nil
+}
+
+// formatBudgetInput formats a budget as parseBudgetInput reads it.
+func formatBudgetInput(b claude.Budget) string {
+	var parts []string
+	if b.CostUSD > 0 {
+		parts = append(parts, "$"+strconv.FormatFloat(b.CostUSD, 'f', -1, 64))
+	}
+	switch {
+	case b.Tokens <= 0:
+	case b.Tokens%1_000_000 == 0:
+		parts = append(parts, fmt.Sprintf("%dM", b.Tokens/1_000_000))
+	case b.Tokens%1_000 == 0:
+		parts = append(parts, fmt.Sprintf("%dk", b.Tokens/1_000))
+	default:
+		parts = append(parts, strconv.FormatInt(b.Tokens, 10))
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeBudget formats a budget and how much of it is used.
+func describeBudget(b claude.Budget, u claude.Usage) string {
+	if b.IsZero() {
+		return fmt.Sprintf("unlimited (used %s, %s tokens)", formatCost(u.CostUSD), formatTokens(u.TotalTokens()))
+	}
+	var limits []string
+	if b.CostUSD > 0 {
+		limits = append(limits, fmt.Sprintf("%s of %s", formatCost(u.CostUSD), formatCost(b.CostUSD)))
+	}
+	if b.Tokens > 0 {
+		limits = append(limits, fmt.Sprintf("%s of %s tokens", formatTokens(u.TotalTokens()), formatTokens(b.Tokens)))
+	}
+	return fmt.Sprintf("%s (%.0f%%)", strings.Join(limits, ", "), b.Fraction(u)*100)
+}
+
+const budgetInputHelp = `Enter a cost ($5), a token count (2M, 500k) or both.
+Claude is interrupted when either limit is reached.`
+
+// NewBudgetDialog creates the budget dialog for a workstream, prefilled with
+// the budget that currently applies to it.
+func NewBudgetDialog(ws *workstream.Workstream, current, configured claude.Budget) DialogModel {
+	body := fmt.Sprintf(`Budget for %s: %s
+
+%s
+Leave empty to use the configured default (%s).`,
+		ws.BranchName, describeBudget(current, ws.GetUsage()), budgetInputHelp, describeBudgetLimit(configured))
+
+	return DialogModel{
+		Type:         DialogBudget,
+		Title:        "Workstream Budget",
+		Body:         body,
+		Input:        newOptionalInput("e.g. $5 2M", formatBudgetInput(current)),
+		WorkstreamID: ws.ID,
+		allowEmpty:   true,
+	}
+}
+
+// NewProjectBudgetDialog creates the project budget dialog. The new budget is
+// saved to the project config.
+func NewProjectBudgetDialog(current claude.Budget, usage claude.Usage) DialogModel {
+	body := fmt.Sprintf(`Project budget: %s
+
+%s
+Saved to .claude-cells/config.yaml. Leave empty to remove it.`,
+		describeBudget(current, usage), budgetInputHelp)
+
+	return DialogModel{
+		Type:       DialogProjectBudget,
+		Title:      "Project Budget",
+		Body:       body,
+		Input:      newOptionalInput("e.g. $50", formatBudgetInput(current)),
+		allowEmpty: true,
+	}
+}
+
+// describeBudgetLimit formats a budget limit without usage.
+func describeBudgetLimit(b claude.Budget) string {
+	if b.IsZero() {
+		return "unlimited"
+	}
+	return formatBudgetInput(b)
+}
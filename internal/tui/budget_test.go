@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/claude"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestParseBudgetInput(t *testing.T) {
+	tests := []struct {
+		input string
+		want  claude.Budget
+	}{
+		{"", claude.Budget{}},
+		{"$5", claude.Budget{CostUSD: 5}},
+		{"2.5$", claude.Budget{CostUSD: 2.5}},
+		{"2M", claude.Budget{Tokens: 2_000_000}},
+		{"500k", claude.Budget{Tokens: 500_000}},
+		{"$10, 1.5m", claude.Budget{CostUSD: 10, Tokens: 1_500_000}},
+		{"750000 tokens", claude.Budget{Tokens: 750_000}},
+	}
+	for _, tt := range tests {
+		got, err := parseBudgetInput(tt.input)
+		if err != nil {
+			t.Errorf("parseBudgetInput(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseBudgetInput(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+		// Formatting round-trips
+		if again, _ := parseBudgetInput(formatBudgetInput(got)); again != got {
+			t.Errorf("formatBudgetInput(%+v) = %q does not round-trip", got, formatBudgetInput(got))
+		}
+	}
+
+	for _, bad := range []string{"$abc", "lots", "-5k"} {
+		if _, err := parseBudgetInput(bad); err == nil {
+			t.Errorf("parseBudgetInput(%q) should fail", bad)
+		}
+	}
+}
+
+func TestAppModel_CheckBudgets(t *testing.T) {
+	app := newTestApp(t)
+	alpha := app.panes[0].Workstream()
+	alpha.ContainerID = "container-alpha"
+	app.budget = docker.BudgetConfig{Workstream: claude.Budget{CostUSD: 1}}
+
+	// Crossing the warning threshold shows a toast once
+	alpha.MergeSessionUsage(map[string]claude.Usage{"s1": {CostUSD: 0.85}})
+	if cmd := app.checkBudgets(map[string]bool{alpha.ID: true}); cmd != nil {
+		t.Error("no interrupt expected below the budget")
+	}
+	if !strings.Contains(app.toast, "alpha has used 85%") {
+		t.Errorf("toast = %q, want budget warning", app.toast)
+	}
+	app.toast = ""
+	app.checkBudgets(nil)
+	if app.toast != "" {
+		t.Errorf("warning should not repeat, got %q", app.toast)
+	}
+
+	// Crossing the hard limit interrupts Claude and marks the pane
+	alpha.MergeSessionUsage(map[string]claude.Usage{"s1": {CostUSD: 1.2}})
+	if cmd := app.checkBudgets(map[string]bool{alpha.ID: true}); cmd == nil {
+		t.Error("expected an interrupt command")
+	}
+	if !app.panes[0].IsBudgetExceeded() {
+		t.Error("alpha should be marked budget exceeded")
+	}
+	if app.panes[1].IsBudgetExceeded() {
+		t.Error("beta has no usage and should not be marked")
+	}
+	if !strings.Contains(app.toast, "Budget exceeded") {
+		t.Errorf("toast = %q, want budget exceeded", app.toast)
+	}
+	events := alpha.GetEvents()
+	if len(events) == 0 || events[len(events)-1].Type != workstream.EventBudgetExceeded {
+		t.Error("expected a budget exceeded timeline event")
+	}
+
+	// Without new usage there is nothing to interrupt
+	if cmd := app.checkBudgets(nil); cmd != nil {
+		t.Error("no interrupt expected when usage did not grow")
+	}
+
+	// Input mode stays blocked until the budget is raised
+	model, _ := app.Update(keyPress('i'))
+	app = model.(AppModel)
+	if app.inputMode {
+		t.Error("input mode should be blocked while over budget")
+	}
+
+	model, _ = app.Update(DialogConfirmMsg{Type: DialogBudget, WorkstreamID: alpha.ID, Value: "$5"})
+	app = model.(AppModel)
+	if alpha.GetBudget().CostUSD != 5 {
+		t.Errorf("budget override = %+v, want $5", alpha.GetBudget())
+	}
+	if app.panes[0].IsBudgetExceeded() {
+		t.Error("raising the budget should clear the exceeded state")
+	}
+}
+
+func TestAppModel_ProjectBudgetExceeded(t *testing.T) {
+	app := newTestApp(t)
+	app.budget = docker.BudgetConfig{Project: claude.Budget{Tokens: 1000}}
+	app.panes[0].Workstream().MergeSessionUsage(map[string]claude.Usage{"s1": {OutputTokens: 600}})
+	app.panes[1].Workstream().MergeSessionUsage(map[string]claude.Usage{"s2": {OutputTokens: 500}})
+
+	app.checkBudgets(nil)
+	for i := range app.panes {
+		if !app.panes[i].IsBudgetExceeded() {
+			t.Errorf("pane %d should be stopped by the project budget", i)
+		}
+	}
+	if !strings.Contains(app.toast, "press B") {
+		t.Errorf("toast = %q, want hint to raise the project budget", app.toast)
+	}
+}
+
+func TestAppModel_ProjectBudgetCountsRemovedWorkstreams(t *testing.T) {
+	app := newTestApp(t)
+	app.budget = docker.BudgetConfig{Project: claude.Budget{Tokens: 1000}}
+	app.panes[0].Workstream().MergeSessionUsage(map[string]claude.Usage{"s1": {OutputTokens: 600}})
+	app.panes[1].Workstream().MergeSessionUsage(map[string]claude.Usage{"s2": {OutputTokens: 500}})
+
+	// Destroying a cell doesn't reset the project's spend
+	app.removePane(0)
+	app.checkBudgets(nil)
+	if got := app.projectSpend("").OutputTokens; got != 1100 {
+		t.Errorf("project spend = %d tokens, want 1100 including the destroyed cell", got)
+	}
+	if !app.panes[0].IsBudgetExceeded() {
+		t.Error("the remaining cells should still be stopped by the project budget")
+	}
+
+	// Recorded spend is persisted and dropped again when a workstream is restored
+	stateDir := t.TempDir()
+	ws := workstream.NewWithID("delta", "delta", "task delta")
+	ws.MergeSessionUsage(map[string]claude.Usage{"s3": {OutputTokens: 200}})
+	RecordSpendCmd(ws, stateDir)()
+	if spent, err := workstream.LoadSpend(stateDir); err != nil || spent["delta"].OutputTokens != 200 {
+		t.Errorf("LoadSpend() = %v, %v; want delta's usage recorded", spent, err)
+	}
+}
+
+func TestAppModel_RestoresBudgetOverride(t *testing.T) {
+	app := newTestApp(t)
+	state := &workstream.AppState{Workstreams: []workstream.SavedWorkstream{
+		{ID: "delta", BranchName: "delta", Prompt: "task delta", Budget: &claude.Budget{CostUSD: 3}},
+	}}
+
+	model, _ := app.Update(StateLoadedMsg{State: state})
+	app = model.(AppModel)
+	restored := app.panes[len(app.panes)-1].Workstream()
+	if restored.GetBudget().CostUSD != 3 {
+		t.Errorf("restored budget = %+v, want $3", restored.GetBudget())
+	}
+}
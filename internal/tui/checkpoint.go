@@ -0,0 +1,463 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// checkpointPollInterval is how often repositories are checked for a due
+// checkpoint; each repository's own interval comes from its config.
+const checkpointPollInterval = time.Minute
+
+// checkpointTimeout bounds checkpointing and pruning one repository's workstreams.
+const checkpointTimeout = 2 * time.Minute
+
+// checkpointPollTickMsg is sent periodically to checkpoint worktrees.
+type checkpointPollTickMsg struct{}
+
+// checkpointPollTickCmd returns a command that sends a checkpoint poll tick after a delay
+func checkpointPollTickCmd() tea.Cmd {
+	return tea.Tick(checkpointPollInterval, func(t time.Time) tea.Msg {
+		return checkpointPollTickMsg{}
+	})
+}
+
+// checkpointCandidate is an active workstream whose worktree is checkpointed.
+type checkpointCandidate struct {
+	ID           string
+	Branch       string
+	WorktreePath string
+}
+
+// CheckpointResult is the outcome of checkpointing one workstream.
+type CheckpointResult struct {
+	WorkstreamID string
+	Checkpoint   *git.Checkpoint // nil if nothing changed since the last one
+	Error        error
+}
+
+// CheckpointsMsg is sent when a repository's workstreams were checkpointed
+// and its old checkpoints pruned.
+type CheckpointsMsg struct {
+	RepoPath string
+	Results  []CheckpointResult
+	Pruned   int
+	Error    error // Pruning failed
+}
+
+// CheckpointCmd returns a command that checkpoints the candidates' worktrees
+// and prunes the repository's checkpoints down to keep per branch and maxAge.
+func CheckpointCmd(repoPath string, candidates []checkpointCandidate, keep int, maxAge time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), checkpointTimeout)
+		defer cancel()
+		return takeCheckpoints(ctx, repoPath, GitClientFactory, candidates, keep, maxAge)
+	}
+}
+
+// takeCheckpoints checkpoints each candidate, then prunes the repository.
+func takeCheckpoints(ctx context.Context, repoPath string, clientFor func(string) git.GitClient, candidates []checkpointCandidate, keep int, maxAge time.Duration) CheckpointsMsg {
+	msg := CheckpointsMsg{RepoPath: repoPath}
+	for _, c := range candidates {
+		cp, err := clientFor(c.WorktreePath).CreateCheckpoint(ctx, c.Branch)
+		msg.Results = append(msg.Results, CheckpointResult{WorkstreamID: c.ID, Checkpoint: cp, Error: err})
+	}
+	msg.Pruned, msg.Error = clientFor(repoPath).PruneCheckpoints(ctx, keep, maxAge)
+	return msg
+}
+
+// checkpointCmds starts a checkpoint round for each repository that has
+// checkpoints enabled and whose interval has passed, covering its active
+// workstreams. Repositories with a round still running are skipped.
+func (m *AppModel) checkpointCmds(now time.Time) []tea.Cmd {
+	byRepo := make(map[string][]checkpointCandidate)
+	configs := make(map[string]docker.CheckpointConfig)
+	for i := range m.panes {
+		ws := m.panes[i].Workstream()
+		if ws.BranchName == "" || !ws.GetState().IsActive() {
+			continue
+		}
+		worktreePath := resolveWorktreePath(ws)
+		if worktreePath == "" {
+			continue
+		}
+		repoPath, err := workstreamRepoPath(ws)
+		if err != nil || m.checkpointing[repoPath] {
+			continue
+		}
+		cfg, ok := configs[repoPath]
+		if !ok {
+			cfg = docker.LoadConfig(repoPath).Checkpoint
+			configs[repoPath] = cfg
+		}
+		if !cfg.IsEnabled() || now.Sub(m.lastCheckpoint[repoPath]) < cfg.GetInterval() {
+			continue
+		}
+		byRepo[repoPath] = append(byRepo[repoPath], checkpointCandidate{
+			ID:           ws.ID,
+			Branch:       ws.BranchName,
+			WorktreePath: worktreePath,
+		})
+	}
+
+	if len(byRepo) == 0 {
+		return nil
+	}
+	if m.checkpointing == nil {
+		m.checkpointing = make(map[string]bool)
+		m.lastCheckpoint = make(map[string]time.Time)
+	}
+	var cmds []tea.Cmd
+	for repoPath, candidates := range byRepo {
+		cfg := configs[repoPath]
+		m.checkpointing[repoPath] = true
+		m.lastCheckpoint[repoPath] = now
+		cmds = append(cmds, CheckpointCmd(repoPath, candidates, cfg.GetKeep(), cfg.GetMaxAge()))
+	}
+	return cmds
+}
+
+// handleCheckpoints logs the outcome of a checkpoint round. Checkpoints are
+// taken quietly; only failures are surfaced in the log.
+func (m *AppModel) handleCheckpoints(msg CheckpointsMsg) {
+	delete(m.checkpointing, msg.RepoPath)
+	for _, r := range msg.Results {
+		branch := r.WorkstreamID
+		if i := m.paneIndexByID(r.WorkstreamID); i >= 0 {
+			branch = m.panes[i].Workstream().BranchName
+		}
+		switch {
+		case r.Error != nil:
+			LogWarn("Checkpoint of %s failed: %v", branch, r.Error)
+		case r.Checkpoint != nil:
+			LogDebug("Checkpointed %s as %s", branch, shortSHA(r.Checkpoint.SHA))
+		}
+	}
+	if msg.Error != nil {
+		LogWarn("Pruning checkpoints in %s failed: %v", msg.RepoPath, msg.Error)
+	} else if msg.Pruned > 0 {
+		LogDebug("Pruned %d checkpoint(s) in %s", msg.Pruned, msg.RepoPath)
+	}
+}
+
+// checkpointBrowser is the state of the checkpoint browser dialog.
+type checkpointBrowser struct {
+	checkpoints []git.Checkpoint // Newest first
+	loading     bool
+	confirm     bool // Asking whether to restore the selected checkpoint
+	err         string
+}
+
+// CheckpointsLoadedMsg carries the checkpoints of a workstream's branch.
+type CheckpointsLoadedMsg struct {
+	WorkstreamID string
+	Checkpoints  []git.Checkpoint
+	Error        error
+}
+
+// CheckpointDiffRequestMsg is sent when a checkpoint's diff is asked for in
+// the checkpoint browser.
+type CheckpointDiffRequestMsg struct {
+	WorkstreamID string
+	Checkpoint   git.Checkpoint
+}
+
+// CheckpointDiffMsg carries the diff between a checkpoint and the worktree.
+type CheckpointDiffMsg struct {
+	WorkstreamID string
+	Checkpoint   git.Checkpoint
+	Diff         string
+	Error        error
+}
+
+// CheckpointRestoreMsg is sent when restoring a checkpoint is confirmed in
+// the checkpoint browser.
+type CheckpointRestoreMsg struct {
+	WorkstreamID string
+	Checkpoint   git.Checkpoint
+}
+
+// CheckpointRestoredMsg is sent when a checkpoint was restored.
+type CheckpointRestoredMsg struct {
+	WorkstreamID string
+	Checkpoint   git.Checkpoint
+	Saved        *git.Checkpoint // The work replaced by the restore; nil if there was none
+	Error        error
+}
+
+// LoadCheckpointsCmd returns a command that lists a workstream's checkpoints.
+func LoadCheckpointsCmd(ws *workstream.Workstream) tea.Cmd {
+	worktree := resolveWorktreePath(ws)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		msg := CheckpointsLoadedMsg{WorkstreamID: ws.ID}
+		if worktree == "" {
+			msg.Error = fmt.Errorf("no worktree path")
+			return msg
+		}
+		msg.Checkpoints, msg.Error = GitClientFactory(worktree).ListCheckpoints(ctx, ws.BranchName)
+		return msg
+	}
+}
+
+// CheckpointDiffCmd returns a command that diffs a checkpoint against the
+// workstream's worktree.
+func CheckpointDiffCmd(ws *workstream.Workstream, cp git.Checkpoint) tea.Cmd {
+	worktree := resolveWorktreePath(ws)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		msg := CheckpointDiffMsg{WorkstreamID: ws.ID, Checkpoint: cp}
+		if worktree == "" {
+			msg.Error = fmt.Errorf("no worktree path")
+			return msg
+		}
+		msg.Diff, msg.Error = GitClientFactory(worktree).Diff(ctx, cp.SHA, "")
+		return msg
+	}
+}
+
+// RestoreCheckpointCmd returns a command that restores a checkpoint into the
+// workstream's worktree. The current work is checkpointed first, so the
+// restore can itself be undone from the browser.
+func RestoreCheckpointCmd(ws *workstream.Workstream, cp git.Checkpoint) tea.Cmd {
+	worktree := resolveWorktreePath(ws)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		msg := CheckpointRestoredMsg{WorkstreamID: ws.ID, Checkpoint: cp}
+		if worktree == "" {
+			msg.Error = fmt.Errorf("no worktree path")
+			return msg
+		}
+		g := GitClientFactory(worktree)
+		saved, err := g.CreateCheckpoint(ctx, ws.BranchName)
+		if err != nil {
+			msg.Error = fmt.Errorf("failed to save the current work first: %w", err)
+			return msg
+		}
+		msg.Saved = saved
+		msg.Error = g.RestoreCheckpoint(ctx, cp)
+		return msg
+	}
+}
+
+// formatCheckpointTime describes when a checkpoint was taken.
+func formatCheckpointTime(t time.Time) string {
+	return fmt.Sprintf("%s (%s ago)", t.Format("2006-01-02 15:04:05"), formatTimelineDuration(time.Since(t)))
+}
+
+// NewCheckpointDialog creates the checkpoint browser for a workstream; its
+// checkpoints are filled in by SetCheckpoints.
+func NewCheckpointDialog(ws *workstream.Workstream) DialogModel {
+	return DialogModel{
+		Type:         DialogCheckpoints,
+		Title:        fmt.Sprintf("Checkpoints: %s", ws.BranchName),
+		Body:         "Loading checkpoints...",
+		WorkstreamID: ws.ID,
+		checkpoints:  checkpointBrowser{loading: true},
+	}
+}
+
+// SetCheckpoints fills the checkpoint browser with loaded checkpoints.
+func (d *DialogModel) SetCheckpoints(msg CheckpointsLoadedMsg) {
+	b := &d.checkpoints
+	b.loading, b.confirm = false, false
+	b.checkpoints = msg.Checkpoints
+	d.MenuSelection = 0
+	d.MenuItems = make([]string, len(msg.Checkpoints))
+	for i, cp := range msg.Checkpoints {
+		d.MenuItems[i] = fmt.Sprintf("%s  %s", formatCheckpointTime(cp.Time), shortSHA(cp.SHA))
+	}
+	switch {
+	case msg.Error != nil:
+		d.Body = ""
+		b.err = msg.Error.Error()
+	case len(msg.Checkpoints) == 0:
+		d.Body = "No checkpoints yet. They are taken every few minutes while there is uncommitted work."
+	default:
+		d.Body = "Snapshots of the worktree, newest first:"
+	}
+}
+
+// updateCheckpoints handles keys in the checkpoint browser: ↑/↓ move, Enter
+// or 'd' shows the diff against the worktree, and 'r' restores after a y/n
+// confirmation. It reports false for keys the dialog handles itself
+// (closing it).
+func (d *DialogModel) updateCheckpoints(msg tea.KeyMsg) (tea.Cmd, bool) {
+	b := &d.checkpoints
+	if b.confirm {
+		switch msg.String() {
+		case "y", "Y":
+			b.confirm = false
+			wsID, cp := d.WorkstreamID, b.checkpoints[d.MenuSelection]
+			return func() tea.Msg { return CheckpointRestoreMsg{WorkstreamID: wsID, Checkpoint: cp} }, true
+		case "n", "N", "esc":
+			b.confirm = false
+		}
+		return nil, true
+	}
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		return nil, false
+	case "up", "k":
+		if d.MenuSelection > 0 {
+			d.MenuSelection--
+		}
+	case "down", "j":
+		if d.MenuSelection < len(d.MenuItems)-1 {
+			d.MenuSelection++
+		}
+	case "enter", "d":
+		if d.MenuSelection < len(b.checkpoints) {
+			wsID, cp := d.WorkstreamID, b.checkpoints[d.MenuSelection]
+			return func() tea.Msg { return CheckpointDiffRequestMsg{WorkstreamID: wsID, Checkpoint: cp} }, true
+		}
+	case "r":
+		if d.MenuSelection < len(b.checkpoints) {
+			b.confirm = true
+		}
+	}
+	return nil, true
+}
+
+// viewCheckpoints renders the checkpoint browser.
+func (d DialogModel) viewCheckpoints() string {
+	b := d.checkpoints
+	var content strings.Builder
+	content.WriteString(DialogTitle.Render(d.Title))
+	content.WriteString("\n\n")
+	if d.Body != "" {
+		content.WriteString(d.Body)
+		content.WriteString("\n\n")
+	}
+	for i, item := range d.MenuItems {
+		if i == d.MenuSelection {
+			content.WriteString("→ ")
+		} else {
+			content.WriteString("  ")
+		}
+		content.WriteString(truncatePrompt(item, max(d.width-10, 20)))
+		content.WriteString("\n")
+	}
+	if len(d.MenuItems) > 0 {
+		content.WriteString("\n")
+	}
+	if b.err != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorPairingConflict)).Render("✗ " + b.err))
+		content.WriteString("\n\n")
+	}
+	switch {
+	case b.confirm:
+		cp := b.checkpoints[d.MenuSelection]
+		content.WriteString(fmt.Sprintf("Restore the worktree to %s? The current work is checkpointed first.\n\n", cp.Time.Format("15:04:05")))
+		content.WriteString(KeyHint("y", " restore") + "  " + KeyHint("n", " cancel"))
+	case len(b.checkpoints) > 0:
+		content.WriteString(KeyHint("↑/↓", " navigate") + "  " + KeyHint("d", " diff") + "  " + KeyHint("r", " restore") + "  " + KeyHintStyle.Render("[Esc] Close"))
+	default:
+		content.WriteString(KeyHintStyle.Render("[Esc] Close"))
+	}
+	return DialogBox.Width(d.width).Render(content.String())
+}
+
+// openCheckpoints opens the checkpoint browser for the workstream in pane i.
+func (m *AppModel) openCheckpoints(i int) tea.Cmd {
+	ws := m.panes[i].Workstream()
+	if ws.BranchName == "" {
+		m.toast = "Focused workstream has no branch yet"
+		m.toastExpiry = time.Now().Add(toastDuration)
+		return nil
+	}
+	dialog := NewCheckpointDialog(ws)
+	dialog.SetSize(min(m.width-10, 80), min(m.height-6, 30))
+	m.dialog = &dialog
+	return LoadCheckpointsCmd(ws)
+}
+
+// showCheckpointDiff replaces the checkpoint browser with the diff between
+// a checkpoint and the worktree.
+func (m *AppModel) showCheckpointDiff(msg CheckpointDiffMsg) {
+	if m.dialog == nil || m.dialog.Type != DialogCheckpoints || m.dialog.WorkstreamID != msg.WorkstreamID {
+		return // The browser was closed meanwhile
+	}
+	if msg.Error != nil {
+		m.dialog.checkpoints.err = msg.Error.Error()
+		return
+	}
+	diff := msg.Diff
+	if diff == "" {
+		diff = "The worktree matches this checkpoint."
+	}
+	title := fmt.Sprintf("Checkpoint %s vs worktree", formatCheckpointTime(msg.Checkpoint.Time))
+	dialog := NewLogDialog(title, "", diff)
+	dialog.SetSize(m.width-10, m.height-6)
+	m.dialog = &dialog
+}
+
+// restoreCheckpoint starts restoring a checkpoint into its workstream.
+func (m *AppModel) restoreCheckpoint(msg CheckpointRestoreMsg) tea.Cmd {
+	m.dialog = nil
+	i := m.paneIndexByID(msg.WorkstreamID)
+	if i < 0 {
+		return nil
+	}
+	ws := m.panes[i].Workstream()
+	m.panes[i].AppendOutput(fmt.Sprintf("\nRestoring checkpoint from %s...\n", formatCheckpointTime(msg.Checkpoint.Time)))
+	dialog := NewProgressDialog("Restoring Checkpoint", fmt.Sprintf("Restoring %s to %s...", ws.BranchName, formatCheckpointTime(msg.Checkpoint.Time)), ws.ID)
+	m.panes[i].SetInPaneDialog(&dialog)
+	return RestoreCheckpointCmd(ws, msg.Checkpoint)
+}
+
+// handleCheckpointRestored reports a restore and records it in the
+// workstream's history.
+func (m *AppModel) handleCheckpointRestored(msg CheckpointRestoredMsg) {
+	i := m.paneIndexByID(msg.WorkstreamID)
+	if i < 0 {
+		return
+	}
+	ws := m.panes[i].Workstream()
+	dialog := m.panes[i].GetInPaneDialog()
+	if dialog != nil && dialog.Type != DialogProgress {
+		dialog = nil
+	}
+
+	if msg.Error != nil {
+		m.panes[i].AppendOutput(fmt.Sprintf("Restoring checkpoint failed: %v\n", msg.Error))
+		if dialog != nil {
+			dialog.SetComplete(fmt.Sprintf("Restore Failed\n\n%v", msg.Error))
+		}
+		return
+	}
+
+	detail := fmt.Sprintf("restored checkpoint %s from %s", shortSHA(msg.Checkpoint.SHA), msg.Checkpoint.Time.Format("2006-01-02 15:04:05"))
+	if msg.Saved != nil {
+		detail += fmt.Sprintf(", previous work saved as %s", shortSHA(msg.Saved.SHA))
+	}
+	ws.RecordEvent(workstream.EventCheckpointRestored, detail)
+	m.managerFor(ws).UpdateWorkstream(ws.ID)
+
+	m.panes[i].AppendOutput(fmt.Sprintf("Restored checkpoint from %s\n", formatCheckpointTime(msg.Checkpoint.Time)))
+	// Tell Claude its files changed (don't press Enter - avoids submitting Claude's pending input)
+	if err := m.panes[i].SendInput(fmt.Sprintf("[ccells] ✓ Restored the worktree of '%s' to the checkpoint from %s", ws.BranchName, msg.Checkpoint.Time.Format("15:04:05")), false); err != nil {
+		LogWarn("Failed to notify Claude about checkpoint restore for %s (pane %d): %v", ws.BranchName, i, err)
+	}
+	if dialog != nil {
+		body := fmt.Sprintf("Restored the checkpoint from %s.", formatCheckpointTime(msg.Checkpoint.Time))
+		if msg.Saved != nil {
+			body += fmt.Sprintf("\nThe replaced work was saved as checkpoint %s.", shortSHA(msg.Saved.SHA))
+		}
+		dialog.SetComplete(body + "\n\nPress Enter or Esc to close.")
+	}
+}
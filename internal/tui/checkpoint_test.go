@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestTakeCheckpoints(t *testing.T) {
+	var prunedWith []any
+	clients := map[string]git.GitClient{
+		"/repo": &git.MockGitClient{
+			PruneCheckpointsFn: func(ctx context.Context, keep int, maxAge time.Duration) (int, error) {
+				prunedWith = []any{keep, maxAge}
+				return 3, nil
+			},
+		},
+		"/dirty": &git.MockGitClient{
+			CreateCheckpointFn: func(ctx context.Context, branch string) (*git.Checkpoint, error) {
+				return &git.Checkpoint{SHA: "abc1234", Branch: branch}, nil
+			},
+		},
+		"/clean":  &git.MockGitClient{},
+		"/broken": &git.MockGitClient{Err: errors.New("boom")},
+	}
+	clientFor := func(path string) git.GitClient { return clients[path] }
+
+	msg := takeCheckpoints(context.Background(), "/repo", clientFor, []checkpointCandidate{
+		{ID: "dirty", Branch: "dirty", WorktreePath: "/dirty"},
+		{ID: "clean", Branch: "clean", WorktreePath: "/clean"},
+		{ID: "broken", Branch: "broken", WorktreePath: "/broken"},
+	}, 5, time.Hour)
+	if len(msg.Results) != 3 || msg.Pruned != 3 || msg.Error != nil {
+		t.Fatalf("msg = %+v, want 3 results and 3 pruned", msg)
+	}
+	if r := msg.Results[0]; r.Checkpoint == nil || r.Checkpoint.Branch != "dirty" {
+		t.Errorf("dirty = %+v, want a checkpoint", r)
+	}
+	if r := msg.Results[1]; r.Checkpoint != nil || r.Error != nil {
+		t.Errorf("clean = %+v, want nothing saved", r)
+	}
+	if r := msg.Results[2]; r.Error == nil {
+		t.Errorf("broken = %+v, want the error", r)
+	}
+	if len(prunedWith) != 2 || prunedWith[0] != 5 || prunedWith[1] != time.Hour {
+		t.Errorf("pruned with %v, want keep 5 and max age 1h", prunedWith)
+	}
+}
+
+func TestAppModel_CheckpointInterval(t *testing.T) {
+	cellsDir := t.TempDir()
+	docker.SetTestCellsDir(cellsDir)
+	defer docker.SetTestCellsDir("")
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte("checkpoints:\n  interval: 5m\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := newTestApp(t)
+	repoPath := t.TempDir()
+	for i := range app.panes {
+		ws := app.panes[i].Workstream()
+		ws.RepoPath = repoPath
+		ws.WorktreePath = t.TempDir()
+		ws.SetState(workstream.StateRunning)
+	}
+	app.panes[2].Workstream().SetState(workstream.StateStopped) // Skipped: not running
+
+	now := time.Now()
+	if cmds := app.checkpointCmds(now); len(cmds) != 1 || !app.checkpointing[repoPath] {
+		t.Fatalf("cmds = %d, want one checkpoint round for the repository", len(cmds))
+	}
+	if cmds := app.checkpointCmds(now.Add(10 * time.Minute)); len(cmds) != 0 {
+		t.Error("a repository being checkpointed should not be checkpointed again")
+	}
+
+	model, _ := app.Update(CheckpointsMsg{RepoPath: repoPath})
+	app = model.(AppModel)
+	if app.checkpointing[repoPath] {
+		t.Error("the repository should no longer be marked busy")
+	}
+	if cmds := app.checkpointCmds(now.Add(4 * time.Minute)); len(cmds) != 0 {
+		t.Error("the next round should wait for the configured interval")
+	}
+	if cmds := app.checkpointCmds(now.Add(5 * time.Minute)); len(cmds) != 1 {
+		t.Error("a round should start once the interval has passed")
+	}
+}
+
+func TestAppModel_CheckpointsDisabled(t *testing.T) {
+	cellsDir := t.TempDir()
+	docker.SetTestCellsDir(cellsDir)
+	defer docker.SetTestCellsDir("")
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte("checkpoints:\n  enabled: false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := newTestApp(t)
+	for i := range app.panes {
+		ws := app.panes[i].Workstream()
+		ws.RepoPath = t.TempDir()
+		ws.WorktreePath = t.TempDir()
+		ws.SetState(workstream.StateRunning)
+	}
+	if cmds := app.checkpointCmds(time.Now()); len(cmds) != 0 {
+		t.Errorf("cmds = %d, want none when checkpoints are off", len(cmds))
+	}
+}
+
+func TestCheckpointDialog(t *testing.T) {
+	ws := workstream.NewWithID("ws", "feature", "task")
+	d := NewCheckpointDialog(ws)
+	d.SetSize(80, 20)
+
+	older := git.Checkpoint{SHA: "bbbbbbb2222", Branch: "feature", Time: time.Now().Add(-time.Hour)}
+	newer := git.Checkpoint{SHA: "aaaaaaa1111", Branch: "feature", Time: time.Now().Add(-time.Minute)}
+	d.SetCheckpoints(CheckpointsLoadedMsg{WorkstreamID: ws.ID, Checkpoints: []git.Checkpoint{newer, older}})
+	if view := d.View(); !strings.Contains(view, "aaaaaaa") || !strings.Contains(view, "1h 0m ago") {
+		t.Errorf("view should list the checkpoints:\n%s", view)
+	}
+
+	// d asks for the diff of the selected checkpoint
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	d, cmd := d.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	if cmd == nil {
+		t.Fatal("d should request the diff")
+	}
+	if msg, ok := cmd().(CheckpointDiffRequestMsg); !ok || msg.Checkpoint.SHA != older.SHA {
+		t.Errorf("d sent %+v, want the older checkpoint", msg)
+	}
+
+	// r asks for confirmation first; n backs out
+	d, cmd = d.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	if cmd != nil || !strings.Contains(d.View(), "Restore the worktree") {
+		t.Fatal("r should ask for confirmation")
+	}
+	d, _ = d.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	if d.checkpoints.confirm {
+		t.Fatal("n should cancel the restore")
+	}
+	d, _ = d.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	_, cmd = d.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if cmd == nil {
+		t.Fatal("y should restore")
+	}
+	if msg, ok := cmd().(CheckpointRestoreMsg); !ok || msg.Checkpoint.SHA != older.SHA || msg.WorkstreamID != ws.ID {
+		t.Errorf("y sent %+v, want the older checkpoint restored", msg)
+	}
+}
+
+func TestCheckpointDialog_Empty(t *testing.T) {
+	ws := workstream.NewWithID("ws", "feature", "task")
+	d := NewCheckpointDialog(ws)
+	d.SetSize(80, 20)
+	d.SetCheckpoints(CheckpointsLoadedMsg{WorkstreamID: ws.ID})
+	if view := d.View(); !strings.Contains(view, "No checkpoints yet") {
+		t.Errorf("view should say there are no checkpoints:\n%s", view)
+	}
+	if _, cmd := d.Update(tea.KeyPressMsg{Code: 'r', Text: "r"}); cmd != nil || d.checkpoints.confirm {
+		t.Error("r should do nothing without checkpoints")
+	}
+}
+
+func TestAppModel_CheckpointRestored(t *testing.T) {
+	app := newTestApp(t)
+	alpha := app.panes[0].Workstream()
+	stdin := &mockWriteCloser{}
+	app.panes[0].SetPTY(&PTYSession{workstreamID: alpha.ID, done: make(chan struct{}), stdin: stdin})
+
+	cp := git.Checkpoint{SHA: "abc1234567", Branch: alpha.BranchName, Time: time.Now().Add(-time.Hour)}
+	model, cmd := app.Update(CheckpointRestoreMsg{WorkstreamID: alpha.ID, Checkpoint: cp})
+	app = model.(AppModel)
+	if cmd == nil {
+		t.Fatal("confirming should start the restore")
+	}
+	if d := app.panes[0].GetInPaneDialog(); d == nil || d.Type != DialogProgress {
+		t.Fatal("the pane should show a progress dialog")
+	}
+
+	model, _ = app.Update(CheckpointRestoredMsg{WorkstreamID: alpha.ID, Checkpoint: cp, Saved: &git.Checkpoint{SHA: "def7654321"}})
+	app = model.(AppModel)
+	events := alpha.GetEvents()
+	if len(events) == 0 || events[len(events)-1].Type != workstream.EventCheckpointRestored {
+		t.Fatal("the restore should be added to the timeline")
+	}
+	if detail := events[len(events)-1].Detail; !strings.Contains(detail, "abc1234") || !strings.Contains(detail, "saved as def7654") {
+		t.Errorf("event detail = %q", detail)
+	}
+	if sent := string(stdin.Bytes()); !strings.Contains(sent, "Restored the worktree") {
+		t.Errorf("Claude should be told about the restore, got %q", sent)
+	}
+}
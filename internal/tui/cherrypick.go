@@ -0,0 +1,393 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// cherryPickSource is a workstream commits can be picked from.
+type cherryPickSource struct {
+	ID     string
+	Branch string
+}
+
+// cherryPickForm is the state of the cherry-pick dialog: first the source
+// workstream is chosen, then the commits to pick from its branch.
+type cherryPickForm struct {
+	sources  []cherryPickSource
+	source   *cherryPickSource // nil while choosing the source
+	commits  []git.Commit      // Source commits, oldest first
+	selected []bool            // Parallel to commits
+	loading  bool
+	err      string
+}
+
+// CherryPickSourceMsg is sent when a source workstream is chosen in the
+// cherry-pick dialog.
+type CherryPickSourceMsg struct {
+	WorkstreamID string // Target
+	SourceID     string
+}
+
+// CherryPickCommitsMsg carries the commits of a cherry-pick source.
+type CherryPickCommitsMsg struct {
+	WorkstreamID string // Target
+	SourceID     string
+	Commits      []git.Commit
+	Error        error
+}
+
+// CherryPickApplyMsg is sent when commits are chosen in the cherry-pick dialog.
+type CherryPickApplyMsg struct {
+	WorkstreamID string // Target
+	SourceID     string
+	Commits      []git.Commit
+}
+
+// CherryPickResultMsg is sent when a cherry-pick completes or stops on conflicts.
+type CherryPickResultMsg struct {
+	WorkstreamID  string // Target
+	SourceID      string
+	Commits       []git.Commit
+	ConflictFiles []string // Files that conflicted; the cherry-pick is left in progress
+	Error         error
+}
+
+// LoadCherryPickCommitsCmd returns a command that lists the commits on a
+// source workstream's branch.
+func LoadCherryPickCommitsCmd(targetID string, source *workstream.Workstream) tea.Cmd {
+	worktree := resolveWorktreePath(source)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		msg := CherryPickCommitsMsg{WorkstreamID: targetID, SourceID: source.ID}
+		if worktree == "" {
+			msg.Error = fmt.Errorf("no worktree path")
+			return msg
+		}
+		msg.Commits, msg.Error = GitClientFactory(worktree).BranchCommits(ctx, source.BranchName)
+		return msg
+	}
+}
+
+// CherryPickCmd returns a command that cherry-picks commits into the target
+// workstream's worktree. The worktree must have no uncommitted changes.
+func CherryPickCmd(target *workstream.Workstream, sourceID string, commits []git.Commit) tea.Cmd {
+	worktree := resolveWorktreePath(target)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		msg := CherryPickResultMsg{WorkstreamID: target.ID, SourceID: sourceID, Commits: commits}
+		if worktree == "" {
+			msg.Error = fmt.Errorf("no worktree path")
+			return msg
+		}
+		g := GitClientFactory(worktree)
+		if dirty, err := g.HasUncommittedChanges(ctx); err != nil {
+			msg.Error = err
+			return msg
+		} else if dirty {
+			msg.Error = fmt.Errorf("%s has uncommitted changes; commit or stash them first", target.BranchName)
+			return msg
+		}
+
+		hashes := make([]string, len(commits))
+		for i, c := range commits {
+			hashes[i] = c.Hash
+		}
+		msg.Error = g.CherryPick(ctx, hashes)
+		var conflictErr *git.MergeConflictError
+		if errors.As(msg.Error, &conflictErr) {
+			msg.ConflictFiles = conflictErr.ConflictFiles
+		}
+		return msg
+	}
+}
+
+// NewCherryPickDialog creates the dialog for picking commits from one of
+// sources into the target workstream.
+func NewCherryPickDialog(target *workstream.Workstream, sources []cherryPickSource) DialogModel {
+	items := make([]string, len(sources))
+	for i, s := range sources {
+		items[i] = s.Branch
+	}
+	return DialogModel{
+		Type:         DialogCherryPick,
+		Title:        fmt.Sprintf("Cherry-Pick into %s", target.BranchName),
+		Body:         "Pick commits from which workstream?",
+		WorkstreamID: target.ID,
+		MenuItems:    items,
+		cherryPick:   cherryPickForm{sources: sources},
+	}
+}
+
+// SetCherryPickCommits shows the commits of the chosen source for selection.
+func (d *DialogModel) SetCherryPickCommits(msg CherryPickCommitsMsg) {
+	f := &d.cherryPick
+	if f.source == nil || f.source.ID != msg.SourceID {
+		return
+	}
+	f.loading = false
+	if msg.Error != nil || len(msg.Commits) == 0 {
+		// Back to the source list to choose another
+		if msg.Error != nil {
+			f.err = msg.Error.Error()
+		} else {
+			f.err = fmt.Sprintf("%s has no commits of its own", f.source.Branch)
+		}
+		f.source = nil
+		d.Body = "Pick commits from which workstream?"
+		d.MenuItems = make([]string, len(f.sources))
+		for i, s := range f.sources {
+			d.MenuItems[i] = s.Branch
+		}
+		return
+	}
+	f.commits = msg.Commits
+	f.selected = make([]bool, len(msg.Commits))
+	d.Body = fmt.Sprintf("Commits on %s (oldest first):", f.source.Branch)
+	d.MenuSelection = 0
+	d.refreshCherryPickItems()
+}
+
+// refreshCherryPickItems renders the commit list with its check boxes.
+func (d *DialogModel) refreshCherryPickItems() {
+	f := &d.cherryPick
+	d.MenuItems = make([]string, len(f.commits))
+	for i, c := range f.commits {
+		box := "[ ]"
+		if f.selected[i] {
+			box = "[x]"
+		}
+		d.MenuItems[i] = fmt.Sprintf("%s %s %s", box, c.Hash, c.Subject)
+	}
+}
+
+// updateCherryPick handles keys in the cherry-pick dialog: ↑/↓ move, Enter
+// chooses the source and then picks the checked commits, Space checks a
+// commit and 'a' checks all. It reports false for keys the dialog handles
+// itself (closing it).
+func (d *DialogModel) updateCherryPick(msg tea.KeyMsg) (tea.Cmd, bool) {
+	f := &d.cherryPick
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		return nil, false
+	case "up", "k":
+		if d.MenuSelection > 0 {
+			d.MenuSelection--
+		}
+	case "down", "j":
+		if d.MenuSelection < len(d.MenuItems)-1 {
+			d.MenuSelection++
+		}
+	case "space":
+		if f.commits != nil {
+			f.selected[d.MenuSelection] = !f.selected[d.MenuSelection]
+			d.refreshCherryPickItems()
+		}
+	case "a":
+		if f.commits != nil {
+			all := slices.Contains(f.selected, false)
+			for i := range f.selected {
+				f.selected[i] = all
+			}
+			d.refreshCherryPickItems()
+		}
+	case "enter":
+		if f.source == nil {
+			if f.loading || d.MenuSelection >= len(f.sources) {
+				return nil, true
+			}
+			source := f.sources[d.MenuSelection]
+			f.source, f.loading, f.err = &source, true, ""
+			d.Body = fmt.Sprintf("Loading commits on %s...", source.Branch)
+			d.MenuItems = nil
+			targetID := d.WorkstreamID
+			return func() tea.Msg { return CherryPickSourceMsg{WorkstreamID: targetID, SourceID: source.ID} }, true
+		}
+		if f.commits == nil {
+			return nil, true
+		}
+		var commits []git.Commit
+		for i, c := range f.commits {
+			if f.selected[i] {
+				commits = append(commits, c)
+			}
+		}
+		if len(commits) == 0 {
+			f.err = "Select at least one commit with Space"
+			return nil, true
+		}
+		targetID, sourceID := d.WorkstreamID, f.source.ID
+		return func() tea.Msg {
+			return CherryPickApplyMsg{WorkstreamID: targetID, SourceID: sourceID, Commits: commits}
+		}, true
+	}
+	return nil, true
+}
+
+// viewCherryPick renders the cherry-pick dialog.
+func (d DialogModel) viewCherryPick() string {
+	var content strings.Builder
+	content.WriteString(DialogTitle.Render(d.Title))
+	content.WriteString("\n\n")
+	content.WriteString(d.Body)
+	content.WriteString("\n\n")
+	for i, item := range d.MenuItems {
+		if i == d.MenuSelection {
+			content.WriteString("→ ")
+		} else {
+			content.WriteString("  ")
+		}
+		content.WriteString(truncatePrompt(item, max(d.width-10, 20)))
+		content.WriteString("\n")
+	}
+	if len(d.MenuItems) > 0 {
+		content.WriteString("\n")
+	}
+	if d.cherryPick.err != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorPairingConflict)).Render("✗ " + d.cherryPick.err))
+		content.WriteString("\n\n")
+	}
+	if d.cherryPick.commits != nil {
+		content.WriteString(KeyHint("Space", " toggle") + "  " + KeyHint("a", " all") + "  " + KeyHint("Enter", " cherry-pick") + "  " + KeyHintStyle.Render("[Esc] Cancel"))
+	} else {
+		content.WriteString(KeyHint("↑/↓", " navigate") + "  " + KeyHint("Enter", " select") + "  " + KeyHintStyle.Render("[Esc] Cancel"))
+	}
+	return DialogBox.Width(d.width).Render(content.String())
+}
+
+// openCherryPick opens the cherry-pick dialog for the workstream in pane i,
+// offering the other workstreams of its repository as sources.
+func (m *AppModel) openCherryPick(i int) {
+	target := m.panes[i].Workstream()
+	if target.BranchName == "" {
+		m.toast = "Focused workstream has no branch yet"
+		m.toastExpiry = time.Now().Add(toastDuration)
+		return
+	}
+	var sources []cherryPickSource
+	for j := range m.panes {
+		ws := m.panes[j].Workstream()
+		if j == i || ws.BranchName == "" || ws.RepoPath != target.RepoPath {
+			continue
+		}
+		sources = append(sources, cherryPickSource{ID: ws.ID, Branch: ws.BranchName})
+	}
+	if len(sources) == 0 {
+		m.toast = "No other workstreams in this repository to pick commits from"
+		m.toastExpiry = time.Now().Add(toastDuration)
+		return
+	}
+	dialog := NewCherryPickDialog(target, sources)
+	dialog.SetSize(min(m.width-10, 90), min(m.height-6, 24))
+	m.dialog = &dialog
+}
+
+// applyCherryPick starts cherry-picking the chosen commits into the target.
+func (m *AppModel) applyCherryPick(msg CherryPickApplyMsg) tea.Cmd {
+	m.dialog = nil
+	i := m.paneIndexByID(msg.WorkstreamID)
+	src := m.paneIndexByID(msg.SourceID)
+	if i < 0 || src < 0 {
+		return nil
+	}
+	target, source := m.panes[i].Workstream(), m.panes[src].Workstream()
+	m.panes[i].AppendOutput(fmt.Sprintf("\nCherry-picking %d commit(s) from %s...\n", len(msg.Commits), source.BranchName))
+	dialog := NewProgressDialog("Cherry-Picking", fmt.Sprintf("Picking %d commit(s) from %s into %s...", len(msg.Commits), source.BranchName, target.BranchName), target.ID)
+	m.panes[i].SetInPaneDialog(&dialog)
+	return CherryPickCmd(target, source.ID, msg.Commits)
+}
+
+// handleCherryPickResult reports a cherry-pick and records it in the history
+// of both workstreams. Conflicts are handed to the target's Claude, resuming
+// its session if it ended; if that fails they are left for the user.
+func (m *AppModel) handleCherryPickResult(msg CherryPickResultMsg) tea.Cmd {
+	i := m.paneIndexByID(msg.WorkstreamID)
+	if i < 0 {
+		return nil
+	}
+	target := m.panes[i].Workstream()
+	sourceBranch := msg.SourceID
+	var source *workstream.Workstream
+	if src := m.paneIndexByID(msg.SourceID); src >= 0 {
+		source = m.panes[src].Workstream()
+		sourceBranch = source.BranchName
+	}
+	dialog := m.panes[i].GetInPaneDialog()
+	if dialog != nil && dialog.Type != DialogProgress {
+		dialog = nil
+	}
+
+	if msg.Error != nil && len(msg.ConflictFiles) == 0 {
+		m.panes[i].AppendOutput(fmt.Sprintf("Cherry-pick failed: %v\n", msg.Error))
+		if dialog != nil {
+			dialog.SetComplete(fmt.Sprintf("Cherry-Pick Failed\n\n%v", msg.Error))
+		}
+		return nil
+	}
+
+	hashes := make([]string, len(msg.Commits))
+	for j, c := range msg.Commits {
+		hashes[j] = c.Hash
+	}
+	picked := fmt.Sprintf("%d commit(s) (%s)", len(msg.Commits), strings.Join(hashes, ", "))
+	targetDetail := fmt.Sprintf("picked %s from %s", picked, sourceBranch)
+
+	var cmd tea.Cmd
+	var handoffErr error
+	if len(msg.ConflictFiles) > 0 {
+		prompt := fmt.Sprintf("I cherry-picked %s from the branch %s into this branch, and it stopped on conflicts in these files: %s. Please resolve the conflicts, `git add` the files and run `git cherry-pick --continue`, repeating until all commits are applied. Let me know when done.",
+			picked, sourceBranch, formatFileList(msg.ConflictFiles))
+		cmd, handoffErr = m.promptClaude(i, prompt)
+		if handoffErr != nil {
+			LogWarn("Failed to send cherry-pick conflicts to pane %d: %v", i, handoffErr)
+			targetDetail += ", conflicts left for manual resolution"
+		} else {
+			targetDetail += ", conflicts handed to Claude"
+		}
+	}
+	target.RecordEvent(workstream.EventCherryPick, targetDetail)
+	m.managerFor(target).UpdateWorkstream(target.ID)
+	if source != nil {
+		source.RecordEvent(workstream.EventCherryPick, fmt.Sprintf("%s picked into %s", picked, target.BranchName))
+		m.managerFor(source).UpdateWorkstream(source.ID)
+	}
+
+	if len(msg.ConflictFiles) > 0 {
+		next := "Claude was asked to resolve them."
+		if handoffErr != nil {
+			next = fmt.Sprintf("Claude could not be asked (%v).\nResolve them in the worktree and run git cherry-pick --continue,\nor git cherry-pick --abort to undo.", handoffErr)
+			m.panes[i].AppendOutput(fmt.Sprintf("Cherry-pick has conflicts in %d file(s) that need manual resolution: %v\n", len(msg.ConflictFiles), handoffErr))
+		} else {
+			m.panes[i].AppendOutput(fmt.Sprintf("Cherry-pick has conflicts in %d file(s); asking Claude to resolve them\n", len(msg.ConflictFiles)))
+		}
+		if dialog != nil {
+			dialog.SetComplete(fmt.Sprintf("Cherry-pick stopped on conflicts in:\n  %s\n\n%s\nPress Enter or Esc to close.", strings.Join(msg.ConflictFiles, "\n  "), next))
+		}
+		return cmd
+	}
+
+	m.panes[i].AppendOutput(fmt.Sprintf("Cherry-picked %s from %s\n", picked, sourceBranch))
+	// Tell a running Claude its branch gained commits (don't press Enter - avoids submitting Claude's pending input)
+	if m.panes[i].HasPTY() {
+		if err := m.panes[i].SendInput(fmt.Sprintf("[ccells] ✓ Cherry-picked %s from '%s' into '%s'", picked, sourceBranch, target.BranchName), false); err != nil {
+			LogWarn("Failed to notify Claude about cherry-pick for %s (pane %d): %v", target.BranchName, i, err)
+		}
+	}
+	if dialog != nil {
+		dialog.SetComplete(fmt.Sprintf("Cherry-picked %s from %s.\n\nPress Enter or Esc to close.", picked, sourceBranch))
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestCherryPickDialog(t *testing.T) {
+	target := workstream.NewWithID("target", "target", "task")
+	d := NewCherryPickDialog(target, []cherryPickSource{{ID: "a", Branch: "alpha"}, {ID: "b", Branch: "beta"}})
+	d.SetSize(80, 20)
+
+	// Choosing a source loads its commits
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	d, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter should choose the source")
+	}
+	if msg, ok := cmd().(CherryPickSourceMsg); !ok || msg.SourceID != "b" || msg.WorkstreamID != target.ID {
+		t.Fatalf("Enter sent %+v, want beta chosen", msg)
+	}
+
+	// Commits of another source are ignored
+	d.SetCherryPickCommits(CherryPickCommitsMsg{WorkstreamID: target.ID, SourceID: "a", Commits: []git.Commit{{Hash: "zzz"}}})
+	if d.cherryPick.commits != nil {
+		t.Fatal("commits of another source should be ignored")
+	}
+	d.SetCherryPickCommits(CherryPickCommitsMsg{WorkstreamID: target.ID, SourceID: "b", Commits: []git.Commit{
+		{Hash: "aaa1111", Subject: "Fix parser"},
+		{Hash: "bbb2222", Subject: "Add feature"},
+		{Hash: "ccc3333", Subject: "Fix lexer"},
+	}})
+	if view := d.View(); !strings.Contains(view, "[ ] aaa1111 Fix parser") {
+		t.Errorf("view should list the commits:\n%s", view)
+	}
+
+	// Nothing checked yet
+	d, cmd = d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd != nil || !strings.Contains(d.View(), "Select at least one commit") {
+		t.Error("Enter without checked commits should ask for a selection")
+	}
+
+	// Check the first and third commits
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	if !strings.Contains(d.View(), "[x] ccc3333 Fix lexer") {
+		t.Errorf("Space should check the commit:\n%s", d.View())
+	}
+	_, cmd = d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter should cherry-pick the checked commits")
+	}
+	msg, ok := cmd().(CherryPickApplyMsg)
+	if !ok || msg.SourceID != "b" || len(msg.Commits) != 2 || msg.Commits[0].Hash != "aaa1111" || msg.Commits[1].Hash != "ccc3333" {
+		t.Errorf("Enter sent %+v, want aaa1111 and ccc3333 in order", msg)
+	}
+}
+
+func TestCherryPickDialog_LoadError(t *testing.T) {
+	target := workstream.NewWithID("target", "target", "task")
+	d := NewCherryPickDialog(target, []cherryPickSource{{ID: "a", Branch: "alpha"}})
+	d.SetSize(80, 20)
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	d.SetCherryPickCommits(CherryPickCommitsMsg{WorkstreamID: target.ID, SourceID: "a"})
+	if d.cherryPick.source != nil || len(d.MenuItems) != 1 {
+		t.Fatal("a source without commits should return to the source list")
+	}
+	if view := d.View(); !strings.Contains(view, "alpha has no commits of its own") {
+		t.Errorf("view should explain why:\n%s", view)
+	}
+}
+
+func TestAppModel_CherryPick(t *testing.T) {
+	app := newTestApp(t)
+	alpha, beta := app.panes[0].Workstream(), app.panes[1].Workstream()
+	stdin := &mockWriteCloser{}
+	app.panes[0].SetPTY(&PTYSession{workstreamID: alpha.ID, done: make(chan struct{}), stdin: stdin})
+
+	app.openCherryPick(0)
+	if app.dialog == nil || app.dialog.Type != DialogCherryPick || len(app.dialog.cherryPick.sources) != 2 {
+		t.Fatal("the dialog should offer the other two workstreams as sources")
+	}
+
+	commits := []git.Commit{{Hash: "aaa1111", Subject: "Fix parser"}}
+	model, cmd := app.Update(CherryPickApplyMsg{WorkstreamID: alpha.ID, SourceID: beta.ID, Commits: commits})
+	app = model.(AppModel)
+	if app.dialog != nil || cmd == nil {
+		t.Fatal("applying should close the dialog and start the cherry-pick")
+	}
+	if d := app.panes[0].GetInPaneDialog(); d == nil || d.Type != DialogProgress {
+		t.Fatal("the target should show a progress dialog")
+	}
+
+	// Conflicts are recorded in both histories and handed to the target's Claude
+	model, _ = app.Update(CherryPickResultMsg{
+		WorkstreamID:  alpha.ID,
+		SourceID:      beta.ID,
+		Commits:       commits,
+		ConflictFiles: []string{"parser.go"},
+		Error:         &git.MergeConflictError{Branch: alpha.BranchName, ConflictFiles: []string{"parser.go"}},
+	})
+	app = model.(AppModel)
+	for _, ws := range []*workstream.Workstream{alpha, beta} {
+		if events := ws.GetEvents(); len(events) == 0 || events[len(events)-1].Type != workstream.EventCherryPick {
+			t.Errorf("%s: the cherry-pick should be added to the timeline", ws.BranchName)
+		}
+	}
+	if events := beta.GetEvents(); !strings.Contains(events[len(events)-1].Detail, "picked into "+alpha.BranchName) {
+		t.Errorf("source event = %q", events[len(events)-1].Detail)
+	}
+	if sent := string(stdin.Bytes()); !strings.Contains(sent, "parser.go") || !strings.Contains(sent, "git cherry-pick --continue") {
+		t.Errorf("Claude should be asked to resolve the conflicts, got %q", sent)
+	}
+}
+
+func TestAppModel_CherryPickConflictsWithoutClaude(t *testing.T) {
+	app := newTestApp(t)
+	alpha, beta := app.panes[0].Workstream(), app.panes[1].Workstream()
+	dialog := NewProgressDialog("Cherry-Picking", "Picking...", alpha.ID)
+	app.panes[0].SetInPaneDialog(&dialog)
+
+	// No session and no container to resume it in: the user is told instead
+	model, cmd := app.Update(CherryPickResultMsg{
+		WorkstreamID:  alpha.ID,
+		SourceID:      beta.ID,
+		Commits:       []git.Commit{{Hash: "aaa1111", Subject: "Fix parser"}},
+		ConflictFiles: []string{"parser.go"},
+		Error:         &git.MergeConflictError{Branch: alpha.BranchName, ConflictFiles: []string{"parser.go"}},
+	})
+	app = model.(AppModel)
+	if cmd != nil {
+		t.Error("nothing should be sent to Claude")
+	}
+	if events := alpha.GetEvents(); !strings.Contains(events[len(events)-1].Detail, "manual resolution") {
+		t.Errorf("target event = %q, want the conflicts recorded as left for the user", events[len(events)-1].Detail)
+	}
+	if d := app.panes[0].GetInPaneDialog(); d == nil || !strings.Contains(d.Body, "git cherry-pick --abort") {
+		t.Error("the dialog should explain how to finish or undo the cherry-pick")
+	}
+}
+
+func TestAppModel_CherryPickNeedsSource(t *testing.T) {
+	app := newTestApp(t)
+	app.panes = app.panes[:1]
+	app.openCherryPick(0)
+	if app.dialog != nil || !strings.Contains(app.toast, "No other workstreams") {
+		t.Errorf("toast = %q, want no sources reported", app.toast)
+	}
+}
@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// CIFailuresMsg is sent when the failing checks of a PR are fetched for a
+// CI auto-fix attempt.
+type CIFailuresMsg struct {
+	WorkstreamID string
+	PRNumber     int
+	Attempt      int
+	MaxAttempts  int
+	Checks       []git.FailedCheck
+	Error        error
+}
+
+// FetchFailedChecksCmd returns a command that fetches a PR's failing checks
+// and their log excerpts.
+func FetchFailedChecksCmd(ws *workstream.Workstream, number, attempt int, cfg docker.CIFixConfig) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		msg := CIFailuresMsg{
+			WorkstreamID: ws.ID,
+			PRNumber:     number,
+			Attempt:      attempt,
+			MaxAttempts:  cfg.GetMaxAttempts(),
+		}
+		worktreePath := resolveWorktreePath(ws)
+		if worktreePath == "" {
+			msg.Error = fmt.Errorf("no worktree path")
+			return msg
+		}
+		msg.Checks, msg.Error = git.NewGH().GetFailedChecks(ctx, worktreePath, number, cfg.GetLogLines())
+		return msg
+	}
+}
+
+// checkNames lists the names of failed checks, e.g. "test, lint".
+func checkNames(checks []git.FailedCheck) string {
+	names := make([]string, len(checks))
+	for i, c := range checks {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// sanitizePrompt removes ANSI escape sequences and control characters other
+// than newlines and tabs. Prompts are typed into Claude's terminal, where an
+// escape byte from a CI log would arrive as a keypress.
+func sanitizePrompt(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' || !unicode.IsControl(r) {
+			return r
+		}
+		return -1
+	}, stripANSI(s))
+}
+
+// formatCIFixPrompt formats failing checks into a prompt asking Claude to
+// fix them and push. Check names and logs come from CI, possibly from a fork,
+// so the prompt is sanitized.
+func formatCIFixPrompt(msg CIFailuresMsg) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CI failed on PR #%d (fix attempt %d of %d). Please fix the failing checks below, run the relevant tests locally, then commit and push the fix.\n", msg.PRNumber, msg.Attempt, msg.MaxAttempts)
+	for i, c := range msg.Checks {
+		name := c.Name
+		if c.Workflow != "" {
+			name = fmt.Sprintf("%s (%s)", c.Name, c.Workflow)
+		}
+		fmt.Fprintf(&b, "\n%d. %s\n", i+1, name)
+		if c.Link != "" {
+			fmt.Fprintf(&b, "   %s\n", c.Link)
+		}
+		if c.Log != "" {
+			fmt.Fprintf(&b, "   End of the failed log:\n```\n%s\n```\n", c.Log)
+		}
+	}
+	return sanitizePrompt(strings.TrimRight(b.String(), "\n"))
+}
+
+// checkCIFix starts a CI auto-fix attempt when a PR's checks fail, if the
+// auto-fix loop is enabled. Each failing head commit gets one attempt, so
+// a new attempt is only made after Claude pushes and CI fails again.
+func (m *AppModel) checkCIFix(i int, status *git.PRStatusInfo) tea.Cmd {
+	ws := m.panes[i].Workstream()
+	attempts, sha := ws.GetCIFix()
+
+	switch status.CheckStatus {
+	case git.PRCheckStatusSuccess:
+		if attempts > 0 {
+			ws.SetCIFix(0, "")
+			m.managerFor(ws).UpdateWorkstream(ws.ID)
+			m.panes[i].AppendOutput(fmt.Sprintf("\nCI auto-fix: checks pass on PR #%d after %d attempt(s).\n", status.Number, attempts))
+		}
+		return nil
+	case git.PRCheckStatusFailure:
+	default:
+		return nil
+	}
+
+	cfg := docker.LoadConfig(m.repoDir(ws)).CIFix
+	if !cfg.IsEnabled() || status.HeadSHA == "" || status.HeadSHA == sha {
+		return nil
+	}
+
+	maxAttempts := cfg.GetMaxAttempts()
+	if attempts >= maxAttempts {
+		ws.SetCIFix(attempts, status.HeadSHA)
+		m.managerFor(ws).UpdateWorkstream(ws.ID)
+		m.panes[i].AppendOutput(fmt.Sprintf("\nCI auto-fix: checks still fail on PR #%d after %d attempt(s), giving up.\n", status.Number, attempts))
+		m.toast = fmt.Sprintf("CI auto-fix gave up on %s", ws.BranchName)
+		m.toastExpiry = time.Now().Add(toastDuration * 2)
+		return nil
+	}
+
+	if !m.panes[i].HasPTY() && ws.ContainerID == "" {
+		// Nothing to send the failure to; try again once the cell is running
+		return nil
+	}
+
+	attempt := attempts + 1
+	ws.SetCIFix(attempt, status.HeadSHA)
+	m.managerFor(ws).UpdateWorkstream(ws.ID)
+	m.panes[i].AppendOutput(fmt.Sprintf("\nCI auto-fix: checks failed on PR #%d, fetching logs (attempt %d/%d)...\n", status.Number, attempt, maxAttempts))
+	return FetchFailedChecksCmd(ws, status.Number, attempt, cfg)
+}
+
+// handleCIFailures sends fetched CI failures to the workstream's Claude,
+// resuming its session if it ended.
+func (m *AppModel) handleCIFailures(msg CIFailuresMsg) tea.Cmd {
+	i := m.paneIndexByID(msg.WorkstreamID)
+	if i < 0 {
+		return nil
+	}
+	ws := m.panes[i].Workstream()
+
+	if msg.Error == nil && len(msg.Checks) == 0 {
+		msg.Error = fmt.Errorf("no failing checks found")
+	}
+	if msg.Error != nil {
+		// The attempt stays used up and the commit stays recorded, so a
+		// broken fetch isn't retried on every poll of the same commit
+		LogWarn("CI auto-fix failed for workstream %s: %v", ws.ID, msg.Error)
+		ws.RecordEvent(workstream.EventCIFix, fmt.Sprintf("attempt %d/%d failed: %v", msg.Attempt, msg.MaxAttempts, msg.Error))
+		m.managerFor(ws).UpdateWorkstream(ws.ID)
+		m.panes[i].AppendOutput(fmt.Sprintf("CI auto-fix attempt %d/%d failed: %v\n", msg.Attempt, msg.MaxAttempts, msg.Error))
+		return nil
+	}
+
+	cmd, err := m.promptClaude(i, formatCIFixPrompt(msg))
+	if err != nil {
+		// Claude never saw the failure: give the attempt back and forget the
+		// commit, so the failure is sent again on a later poll
+		LogWarn("Failed to send CI failures to pane %d: %v", i, err)
+		ws.SetCIFix(msg.Attempt-1, "")
+		m.managerFor(ws).UpdateWorkstream(ws.ID)
+		m.panes[i].AppendOutput(fmt.Sprintf("CI auto-fix: could not ask Claude (%v); the failure will be sent again later.\n", err))
+		return nil
+	}
+
+	names := checkNames(msg.Checks)
+	ws.RecordEvent(workstream.EventCIFix, fmt.Sprintf("attempt %d/%d: %s", msg.Attempt, msg.MaxAttempts, names))
+	m.managerFor(ws).UpdateWorkstream(ws.ID)
+	m.panes[i].AppendOutput(fmt.Sprintf("CI auto-fix attempt %d/%d: sent %s to Claude.\n", msg.Attempt, msg.MaxAttempts, names))
+	m.toast = fmt.Sprintf("CI failed on %s, asked Claude to fix it (%d/%d)", ws.BranchName, msg.Attempt, msg.MaxAttempts)
+	m.toastExpiry = time.Now().Add(toastDuration)
+	return cmd
+}
@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestAppModel_CIFixLoop(t *testing.T) {
+	cellsDir := t.TempDir()
+	docker.SetTestCellsDir(cellsDir)
+	defer docker.SetTestCellsDir("")
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte("ci_fix:\n  enabled: true\n  max_attempts: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fakeExecutable(t, "gh", `case "$1 $2" in
+"pr checks") echo '[{"name":"test","bucket":"fail","link":"https://github.com/o/r/actions/runs/1/job/77","workflow":"CI"}]' ;;
+"run view") printf '\033[31mFAIL: TestLogin\033[0m\r\n\033\n' ;;
+esac
+`)
+
+	app := newTestApp(t)
+	ws := app.panes[0].Workstream()
+	ws.WorktreePath = t.TempDir()
+	stdin := &mockWriteCloser{}
+	app.panes[0].SetPTY(&PTYSession{workstreamID: ws.ID, done: make(chan struct{}), stdin: stdin})
+
+	failed := func(sha string) PRStatusMsg {
+		return PRStatusMsg{WorkstreamID: ws.ID, Status: &git.PRStatusInfo{Forge: git.ForgeGitHub, Number: 42, HeadSHA: sha, CheckStatus: git.PRCheckStatusFailure}}
+	}
+	// runCIFix delivers a status and the CI failures it fetches, if any
+	runCIFix := func(msg PRStatusMsg) bool {
+		t.Helper()
+		model, cmd := app.Update(msg)
+		app = model.(AppModel)
+		batch, _ := cmd().(tea.BatchMsg)
+		for _, c := range batch {
+			if c == nil {
+				continue
+			}
+			if failures, ok := c().(CIFailuresMsg); ok {
+				model, _ = app.Update(failures)
+				app = model.(AppModel)
+				return true
+			}
+		}
+		return false
+	}
+
+	if !runCIFix(failed("aaa")) {
+		t.Fatal("a failing PR should start a fix attempt")
+	}
+	sent := strings.TrimSuffix(string(stdin.Bytes()), string(KittyEnterKey))
+	if strings.ContainsAny(sent, "\x1b\r") {
+		t.Errorf("prompt should not contain control characters from the log: %q", sent)
+	}
+	for _, want := range []string{"CI failed on PR #42 (fix attempt 1 of 2)", "1. test (CI)", "FAIL: TestLogin", "commit and push"} {
+		if !strings.Contains(sent, want) {
+			t.Errorf("prompt missing %q:\n%s", want, sent)
+		}
+	}
+	if out := app.panes[0].output.String(); !strings.Contains(out, "CI auto-fix attempt 1/2: sent test to Claude") {
+		t.Errorf("pane output = %q, want the attempt recorded", out)
+	}
+	if events := ws.GetEvents(); len(events) == 0 || events[len(events)-1].Type != workstream.EventCIFix {
+		t.Error("the attempt should be added to the timeline")
+	}
+
+	// Polling the same failing commit again doesn't retry
+	stdin.buf.Reset()
+	model, _ := app.Update(failed("aaa"))
+	app = model.(AppModel)
+	if attempts, _ := ws.GetCIFix(); attempts != 1 || stdin.buf.Len() != 0 {
+		t.Errorf("attempts = %d, want no retry for the same commit", attempts)
+	}
+
+	// Claude pushed, CI fails again: second attempt, then the loop gives up
+	runCIFix(failed("bbb"))
+	runCIFix(failed("ccc"))
+	if attempts, _ := ws.GetCIFix(); attempts != 2 {
+		t.Errorf("attempts = %d, want the limit of 2", attempts)
+	}
+	if out := app.panes[0].output.String(); !strings.Contains(out, "giving up") {
+		t.Errorf("pane output = %q, want the loop to give up", out)
+	}
+
+	// Passing CI resets the count
+	model, _ = app.Update(PRStatusMsg{WorkstreamID: ws.ID, Status: &git.PRStatusInfo{Forge: git.ForgeGitHub, Number: 42, HeadSHA: "ddd", CheckStatus: git.PRCheckStatusSuccess}})
+	app = model.(AppModel)
+	if attempts, sha := ws.GetCIFix(); attempts != 0 || sha != "" {
+		t.Errorf("CI fix = %d %q after passing, want reset", attempts, sha)
+	}
+}
+
+func TestAppModel_CIFixFetchFailure(t *testing.T) {
+	cellsDir := t.TempDir()
+	docker.SetTestCellsDir(cellsDir)
+	defer docker.SetTestCellsDir("")
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte("ci_fix:\n  enabled: true\n  max_attempts: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := newTestApp(t)
+	ws := app.panes[0].Workstream()
+	ws.SetContainerID("container-alpha")
+	status := PRStatusMsg{WorkstreamID: ws.ID, Status: &git.PRStatusInfo{Forge: git.ForgeGitHub, Number: 42, HeadSHA: "aaa", CheckStatus: git.PRCheckStatusFailure}}
+	model, _ := app.Update(status)
+	app = model.(AppModel)
+	model, _ = app.Update(CIFailuresMsg{WorkstreamID: ws.ID, PRNumber: 42, Attempt: 1, MaxAttempts: 2})
+	app = model.(AppModel)
+	if attempts, sha := ws.GetCIFix(); attempts != 1 || sha != "aaa" {
+		t.Fatalf("CI fix = %d %q, want the failed fetch to use up the attempt for aaa", attempts, sha)
+	}
+
+	// Polling the same commit doesn't fetch again
+	if cmd := app.checkCIFix(0, status.Status); cmd != nil {
+		t.Error("a failed fetch should not be retried for the same commit")
+	}
+}
+
+func TestAppModel_CIFixHandoff(t *testing.T) {
+	cellsDir := t.TempDir()
+	docker.SetTestCellsDir(cellsDir)
+	defer docker.SetTestCellsDir("")
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte("ci_fix:\n  enabled: true\n  max_attempts: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := newTestApp(t)
+	ws := app.panes[0].Workstream()
+	status := &git.PRStatusInfo{Forge: git.ForgeGitHub, Number: 42, HeadSHA: "aaa", CheckStatus: git.PRCheckStatusFailure}
+	failures := CIFailuresMsg{WorkstreamID: ws.ID, PRNumber: 42, Attempt: 1, MaxAttempts: 2, Checks: []git.FailedCheck{{Name: "test"}}}
+
+	// No session and no container: nothing is fetched or counted
+	if cmd := app.checkCIFix(0, status); cmd != nil {
+		t.Error("no attempt should start while there is no Claude to send it to")
+	}
+
+	// The container went away after the fetch: the attempt is given back
+	ws.SetCIFix(1, "aaa")
+	model, cmd := app.Update(failures)
+	app = model.(AppModel)
+	if attempts, sha := ws.GetCIFix(); cmd != nil || attempts != 0 || sha != "" {
+		t.Errorf("CI fix = %d %q, want the undelivered attempt given back", attempts, sha)
+	}
+
+	// Claude's session ended: it is resumed with the failures
+	ws.SetContainerID("container-alpha")
+	ws.SetCIFix(1, "aaa")
+	model, cmd = app.Update(failures)
+	app = model.(AppModel)
+	if cmd == nil || !strings.Contains(app.panes[0].output.String(), "Resuming Claude's session") {
+		t.Errorf("output = %q, want the ended session resumed with the failures", app.panes[0].output.String())
+	}
+	if attempts, sha := ws.GetCIFix(); attempts != 1 || sha != "aaa" {
+		t.Errorf("CI fix = %d %q, want the delivered attempt counted", attempts, sha)
+	}
+}
+
+func TestSanitizePrompt(t *testing.T) {
+	in := "\x1b[1;31merror\x1b[0m:\tbad\r\n\x1bq\x03done\u200b"
+	if got, want := sanitizePrompt(in), "error:\tbad\nqdone\u200b"; got != want {
+		t.Errorf("sanitizePrompt() = %q, want %q", got, want)
+	}
+}
+
+func TestAppModel_CIFixDisabled(t *testing.T) {
+	docker.SetTestCellsDir(t.TempDir())
+	defer docker.SetTestCellsDir("")
+
+	app := newTestApp(t)
+	ws := app.panes[0].Workstream()
+	model, _ := app.Update(PRStatusMsg{WorkstreamID: ws.ID, Status: &git.PRStatusInfo{Forge: git.ForgeGitHub, Number: 42, HeadSHA: "aaa", CheckStatus: git.PRCheckStatusFailure}})
+	app = model.(AppModel)
+	if attempts, sha := ws.GetCIFix(); attempts != 0 || sha != "" {
+		t.Errorf("CI fix = %d %q, want no attempt when the loop is off", attempts, sha)
+	}
+}
+
+func TestAppModel_CIFixSkipsGitLab(t *testing.T) {
+	cellsDir := t.TempDir()
+	docker.SetTestCellsDir(cellsDir)
+	defer docker.SetTestCellsDir("")
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte("ci_fix:\n  enabled: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := newTestApp(t)
+	ws := app.panes[0].Workstream()
+	model, cmd := app.Update(PRStatusMsg{WorkstreamID: ws.ID, Status: &git.PRStatusInfo{Forge: git.ForgeGitLab, Number: 42, HeadSHA: "aaa", CheckStatus: git.PRCheckStatusFailure}})
+	app = model.(AppModel)
+	if attempts, _ := ws.GetCIFix(); attempts != 0 || cmd != nil {
+		t.Errorf("attempts = %d, want GitLab merge requests left alone", attempts)
+	}
+}
@@ -0,0 +1,244 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+const conflictPollInterval = 2 * time.Minute
+
+// maxConflictFiles bounds the files listed per pair in the conflict matrix.
+const maxConflictFiles = 5
+
+// ConflictPair is the predicted outcome of merging two workstream branches.
+type ConflictPair struct {
+	A, B      string   // Workstream IDs
+	Overlap   []string // Files changed on both branches since their merge base
+	Conflicts []string // Files a simulated merge could not resolve
+	Simulated bool     // False if the merge could not be simulated (overlap only)
+}
+
+// Likely reports whether merging both branches is likely to conflict.
+// Without a simulated merge, any overlap counts.
+func (p ConflictPair) Likely() bool {
+	if p.Simulated {
+		return len(p.Conflicts) > 0
+	}
+	return len(p.Overlap) > 0
+}
+
+// conflictPollTickMsg is sent periodically to re-analyze cross-workstream conflicts
+type conflictPollTickMsg struct{}
+
+// conflictPollTickCmd returns a command that sends a conflict poll tick after a delay
+func conflictPollTickCmd() tea.Cmd {
+	return tea.Tick(conflictPollInterval, func(t time.Time) tea.Msg {
+		return conflictPollTickMsg{}
+	})
+}
+
+// ConflictsAnalyzedMsg carries the overlapping pairs among active workstreams.
+type ConflictsAnalyzedMsg struct {
+	Pairs []ConflictPair
+	Show  bool // Open the conflict matrix when done
+}
+
+// conflictCandidate is a workstream branch taking part in the analysis.
+type conflictCandidate struct {
+	ID     string
+	Branch string
+}
+
+// AnalyzeConflictsCmd compares the branches of all active workstreams pairwise
+// and predicts which merges would conflict. Only workstreams of the same
+// repository are compared.
+func AnalyzeConflictsCmd(workstreams []*workstream.Workstream, show bool) tea.Cmd {
+	byRepo := make(map[string][]conflictCandidate)
+	for _, ws := range workstreams {
+		if ws.BranchName == "" || !ws.GetState().IsActive() {
+			continue
+		}
+		repoPath, err := workstreamRepoPath(ws)
+		if err != nil {
+			continue
+		}
+		byRepo[repoPath] = append(byRepo[repoPath], conflictCandidate{ID: ws.ID, Branch: ws.BranchName})
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		var pairs []ConflictPair
+		for repoPath, candidates := range byRepo {
+			pairs = append(pairs, predictConflicts(ctx, GitClientFactory(repoPath), candidates)...)
+		}
+		return ConflictsAnalyzedMsg{Pairs: pairs, Show: show}
+	}
+}
+
+// predictConflicts returns the pairs of branches that changed the same files,
+// with the files a simulated merge of each pair would conflict on.
+func predictConflicts(ctx context.Context, g git.GitClient, candidates []conflictCandidate) []ConflictPair {
+	if len(candidates) < 2 {
+		return nil
+	}
+	changed := make([]map[string]bool, len(candidates))
+	for i, c := range candidates {
+		files, err := g.ChangedFiles(ctx, c.Branch)
+		if err != nil {
+			LogDebug("Conflict analysis: cannot diff %s: %v", c.Branch, err)
+			continue
+		}
+		changed[i] = make(map[string]bool, len(files))
+		for _, f := range files {
+			changed[i][f] = true
+		}
+	}
+
+	var pairs []ConflictPair
+	for i := range candidates {
+		for j := i + 1; j < len(candidates); j++ {
+			var overlap []string
+			for f := range changed[i] {
+				if changed[j][f] {
+					overlap = append(overlap, f)
+				}
+			}
+			if len(overlap) == 0 {
+				continue // Disjoint changes merge cleanly
+			}
+			sort.Strings(overlap)
+			pair := ConflictPair{A: candidates[i].ID, B: candidates[j].ID, Overlap: overlap}
+			conflicts, err := g.MergeTreeConflicts(ctx, candidates[i].Branch, candidates[j].Branch)
+			if err != nil {
+				LogDebug("Conflict analysis: cannot simulate merge of %s and %s: %v", candidates[i].Branch, candidates[j].Branch, err)
+			} else {
+				pair.Conflicts = conflicts
+				pair.Simulated = true
+			}
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
+
+// conflictPeers returns the IDs of the workstreams id is likely to conflict with.
+func conflictPeers(pairs []ConflictPair, id string) []string {
+	var peers []string
+	for _, p := range pairs {
+		if !p.Likely() {
+			continue
+		}
+		switch id {
+		case p.A:
+			peers = append(peers, p.B)
+		case p.B:
+			peers = append(peers, p.A)
+		}
+	}
+	return peers
+}
+
+// conflictPeerNames returns the branch names of the panes a workstream is
+// likely to conflict with.
+func (m *AppModel) conflictPeerNames(ws *workstream.Workstream) []string {
+	peers := conflictPeers(m.conflicts, ws.ID)
+	if len(peers) == 0 {
+		return nil
+	}
+	var names []string
+	for _, id := range peers {
+		for i := range m.panes {
+			if other := m.panes[i].Workstream(); other.ID == id {
+				names = append(names, other.BranchName)
+			}
+		}
+	}
+	return names
+}
+
+// conflictBadge renders the pane header warning naming likely conflicts.
+func conflictBadge(names []string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Bold(true).Render("⚠ conflicts: " + strings.Join(names, ", "))
+}
+
+// findConflictPair returns the analyzed pair of two workstreams, if any.
+func findConflictPair(pairs []ConflictPair, a, b string) (ConflictPair, bool) {
+	for _, p := range pairs {
+		if (p.A == a && p.B == b) || (p.A == b && p.B == a) {
+			return p, true
+		}
+	}
+	return ConflictPair{}, false
+}
+
+// renderConflictMatrix renders every pair of panes with its predicted merge
+// outcome, followed by the files involved.
+func renderConflictMatrix(panes []PaneModel, pairs []ConflictPair) string {
+	if len(panes) < 2 {
+		return "Conflicts are predicted once two or more workstreams are running."
+	}
+
+	var b strings.Builder
+	b.WriteString("     ")
+	for i := range panes {
+		fmt.Fprintf(&b, "%3d", panes[i].Index())
+	}
+	b.WriteString("\n")
+	for i := range panes {
+		fmt.Fprintf(&b, "%3d  ", panes[i].Index())
+		for j := range panes {
+			mark := "·"
+			if i == j {
+				mark = "-"
+			} else if p, ok := findConflictPair(pairs, panes[i].Workstream().ID, panes[j].Workstream().ID); ok {
+				mark = "~"
+				if p.Likely() {
+					mark = "✗"
+				}
+			}
+			fmt.Fprintf(&b, "%3s", mark)
+		}
+		fmt.Fprintf(&b, "  %s\n", panes[i].Workstream().BranchName)
+	}
+	b.WriteString("\n✗ likely conflict   ~ same files, merges cleanly   · no overlap\n")
+
+	names := make(map[string]string, len(panes))
+	for i := range panes {
+		names[panes[i].Workstream().ID] = panes[i].Workstream().BranchName
+	}
+	for _, p := range pairs {
+		if names[p.A] == "" || names[p.B] == "" {
+			continue
+		}
+		files, verdict := p.Overlap, "overlap"
+		switch {
+		case p.Likely() && p.Simulated:
+			files, verdict = p.Conflicts, "conflict"
+		case p.Likely():
+			verdict = "overlap (merge not simulated)"
+		}
+		listed := files
+		if len(listed) > maxConflictFiles {
+			listed = append(append([]string{}, listed[:maxConflictFiles]...), fmt.Sprintf("+%d more", len(files)-maxConflictFiles))
+		}
+		fmt.Fprintf(&b, "\n%s ↔ %s: %s\n  %s", names[p.A], names[p.B], verdict, strings.Join(listed, ", "))
+	}
+	return b.String()
+}
+
+// NewConflictMatrixDialog creates a scrollable dialog showing the conflict matrix.
+func NewConflictMatrixDialog(panes []PaneModel, pairs []ConflictPair) DialogModel {
+	d := NewLogDialog("", "", renderConflictMatrix(panes, pairs))
+	d.Title = "Conflict Matrix"
+	return d
+}
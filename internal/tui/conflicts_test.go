@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/git"
+)
+
+func TestPredictConflicts(t *testing.T) {
+	mock := git.NewMockGitClient()
+	changed := map[string][]string{
+		"alpha": {"api.go", "README.md"},
+		"beta":  {"api.go", "ui.go"},
+		"gamma": {"README.md"},
+		"delta": {"docs.md"},
+	}
+	mock.ChangedFilesFn = func(ctx context.Context, branch string) ([]string, error) {
+		return changed[branch], nil
+	}
+	mock.MergeTreeConflictsFn = func(ctx context.Context, a, b string) ([]string, error) {
+		if a == "alpha" && b == "beta" {
+			return []string{"api.go"}, nil
+		}
+		return nil, nil
+	}
+
+	candidates := []conflictCandidate{{"1", "alpha"}, {"2", "beta"}, {"3", "gamma"}, {"4", "delta"}}
+	pairs := predictConflicts(context.Background(), mock, candidates)
+	if len(pairs) != 2 {
+		t.Fatalf("got %d pairs, want alpha/beta and alpha/gamma: %+v", len(pairs), pairs)
+	}
+	if p := pairs[0]; p.A != "1" || p.B != "2" || !p.Likely() || !reflect.DeepEqual(p.Conflicts, []string{"api.go"}) {
+		t.Errorf("alpha/beta = %+v, want a likely conflict on api.go", p)
+	}
+	if p := pairs[1]; p.A != "1" || p.B != "3" || p.Likely() || !reflect.DeepEqual(p.Overlap, []string{"README.md"}) {
+		t.Errorf("alpha/gamma = %+v, want an overlap that merges cleanly", p)
+	}
+
+	if got := conflictPeers(pairs, "1"); !reflect.DeepEqual(got, []string{"2"}) {
+		t.Errorf("conflictPeers(alpha) = %v, want [2]", got)
+	}
+	if got := conflictPeers(pairs, "3"); len(got) != 0 {
+		t.Errorf("conflictPeers(gamma) = %v, want none", got)
+	}
+}
+
+func TestPredictConflicts_WithoutMergeTree(t *testing.T) {
+	mock := git.NewMockGitClient()
+	mock.ChangedFilesFn = func(ctx context.Context, branch string) ([]string, error) {
+		return []string{"main.go"}, nil
+	}
+	mock.MergeTreeConflictsFn = func(ctx context.Context, a, b string) ([]string, error) {
+		return nil, errors.New("unknown option --write-tree")
+	}
+
+	pairs := predictConflicts(context.Background(), mock, []conflictCandidate{{"1", "a"}, {"2", "b"}})
+	if len(pairs) != 1 || pairs[0].Simulated || !pairs[0].Likely() {
+		t.Errorf("pairs = %+v, want an unsimulated overlap counted as likely", pairs)
+	}
+}
+
+func TestAppModel_ConflictsAnalyzed(t *testing.T) {
+	app := newTestApp(t)
+	pairs := []ConflictPair{
+		{A: "alpha", B: "beta", Overlap: []string{"api.go"}, Conflicts: []string{"api.go"}, Simulated: true},
+		{A: "alpha", B: "gamma", Overlap: []string{"README.md"}, Simulated: true},
+	}
+
+	model, _ := app.Update(ConflictsAnalyzedMsg{Pairs: pairs, Show: true})
+	app = model.(AppModel)
+	if got := app.conflictPeerNames(app.panes[1].Workstream()); !reflect.DeepEqual(got, []string{"alpha"}) {
+		t.Errorf("beta conflicts with %v, want [alpha]", got)
+	}
+	if got := app.conflictPeerNames(app.panes[2].Workstream()); len(got) != 0 {
+		t.Errorf("gamma should not warn, got %v", got)
+	}
+
+	if app.dialog == nil || app.dialog.Title != "Conflict Matrix" {
+		t.Fatal("expected the conflict matrix dialog")
+	}
+	body := app.dialog.Body
+	for _, want := range []string{"✗", "~", "alpha ↔ beta: conflict", "alpha ↔ gamma: overlap"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("matrix missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -154,6 +154,7 @@ type ContainerOutputMsg struct {
 // ContainerStoppedMsg is sent when a container stops.
 type ContainerStoppedMsg struct {
 	WorkstreamID string
+	KeptWorktree string // Worktree left on disk because its uncommitted changes couldn't be saved
 }
 
 // ContainerNotFoundMsg is sent when a container no longer exists but can be rebuilt.
@@ -356,8 +357,7 @@ func CheckUntrackedFilesCmd(ws *workstream.Workstream) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		// Get current working directory as repo path
-		repoPath, err := os.Getwd()
+		repoPath, err := workstreamRepoPath(ws)
 		if err != nil {
 			// On error, proceed without copying (fail gracefully)
 			return startContainerWithFullOptions(ws, false, false)()
@@ -365,6 +365,10 @@ func CheckUntrackedFilesCmd(ws *workstream.Workstream) tea.Cmd {
 
 		gitRepo := GitClientFactory(repoPath)
 		untrackedFiles, err := gitRepo.GetUntrackedFiles(ctx)
+		if err == nil {
+			// Files provisioned by config are handled without asking
+			untrackedFiles = filterProvisionedFiles(untrackedFiles, docker.LoadConfig(repoPath).Provision)
+		}
 		if err != nil || len(untrackedFiles) == 0 {
 			// No untracked files or error checking - proceed normally
 			return startContainerWithFullOptions(ws, false, false)()
@@ -378,6 +382,40 @@ func CheckUntrackedFilesCmd(ws *workstream.Workstream) tea.Cmd {
 	}
 }
 
+// filterProvisionedFiles removes files covered by the provisioning config.
+func filterProvisionedFiles(files []string, provision docker.ProvisionConfig) []string {
+	if provision.IsEmpty() {
+		return files
+	}
+	var remaining []string
+	for _, f := range files {
+		if !provision.Covers(f) {
+			remaining = append(remaining, f)
+		}
+	}
+	return remaining
+}
+
+// loadTemplate looks up a template by name in the repo's cells config.
+func loadTemplate(repoPath, name string) (docker.TemplateConfig, bool) {
+	cfg := docker.LoadConfig(repoPath)
+	return cfg.Template(name)
+}
+
+// workstreamPreset resolves a workstream's template from the cells config.
+// Returns nil if the workstream has no template or it is no longer configured.
+func workstreamPreset(cfg docker.CellsConfig, name string) *docker.TemplateConfig {
+	if name == "" {
+		return nil
+	}
+	tmpl, ok := cfg.Template(name)
+	if !ok {
+		LogWarn("Template %q not found in config, using defaults", name)
+		return nil
+	}
+	return &tmpl
+}
+
 // StartContainerWithCopyUntrackedFilesCmd starts container and copies untracked files to the worktree.
 func StartContainerWithCopyUntrackedFilesCmd(ws *workstream.Workstream, copyFiles bool) tea.Cmd {
 	return startContainerWithFullOptions(ws, false, copyFiles)
@@ -416,7 +454,7 @@ func StartContainerWithNewBranchCmd(ws *workstream.Workstream, existingBranches
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		repoPath, err := os.Getwd()
+		repoPath, err := workstreamRepoPath(ws)
 		if err != nil {
 			return ContainerErrorMsg{
 				WorkstreamID: ws.ID,
@@ -448,7 +486,7 @@ func DeleteAndRestartContainerCmd(ws *workstream.Workstream) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		repoPath, err := os.Getwd()
+		repoPath, err := workstreamRepoPath(ws)
 		if err != nil {
 			return ContainerErrorMsg{
 				WorkstreamID: ws.ID,
@@ -460,7 +498,7 @@ func DeleteAndRestartContainerCmd(ws *workstream.Workstream) tea.Cmd {
 
 		// With worktrees, the branch may be checked out in a worktree
 		// First remove any worktree using this branch
-		cleanupWorktree(ctx, gitRepo, ws.BranchName)
+		cleanupWorktree(ctx, gitRepo, resolveWorktreePath(ws))
 
 		// Now we can delete the branch (it's no longer checked out anywhere)
 		if err := gitRepo.DeleteBranch(ctx, ws.BranchName); err != nil {
@@ -482,8 +520,7 @@ func RebuildContainerCmd(ws *workstream.Workstream) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		// Get current working directory as repo path
-		repoPath, err := os.Getwd()
+		repoPath, err := workstreamRepoPath(ws)
 		if err != nil {
 			return ContainerErrorMsg{
 				WorkstreamID: ws.ID,
@@ -502,15 +539,15 @@ func RebuildContainerCmd(ws *workstream.Workstream) tea.Cmd {
 		defer dockerClient.Close()
 
 		// Create orchestrator
-		gitFactory := func(path string) git.GitClient {
-			return GitClientFactory(path)
-		}
-		orch := orchestrator.New(dockerClient, gitFactory, repoPath)
+		orch := workstreamOrchestrator(dockerClient, ws, repoPath)
 
 		// Use orchestrator to rebuild workstream
+		cellsCfg := docker.LoadConfig(repoPath)
 		opts := orchestrator.CreateOptions{
 			RepoPath:          repoPath,
 			UseExistingBranch: true, // Rebuild uses existing branch
+			Provision:         cellsCfg.Provision,
+			Preset:            workstreamPreset(cellsCfg, ws.Template),
 		}
 
 		result, err := orch.RebuildWorkstream(ctx, ws, opts)
@@ -556,7 +593,12 @@ func resolveWorktreePath(ws *workstream.Workstream) string {
 		return ws.WorktreePath
 	}
 	if ws.BranchName != "" {
-		return getWorktreePath(ws.BranchName)
+		path := getWorktreePath(ws.BranchName)
+		if ws.RepoPath != "" {
+			// Worktrees of additionally opened repositories live in their own directory
+			path = filepath.Join(repoWorktreeBaseDir(ws.RepoPath), filepath.Base(path))
+		}
+		return path
 	}
 	return ""
 }
@@ -601,8 +643,7 @@ func copyUntrackedFilesToWorktree(srcRepo, dstWorktree string, files []string) e
 
 // cleanupWorktree removes a worktree and its directory.
 // Errors are logged but not returned since cleanup is best-effort.
-func cleanupWorktree(ctx context.Context, gitRepo git.GitClient, branchName string) {
-	worktreePath := getWorktreePath(branchName)
+func cleanupWorktree(ctx context.Context, gitRepo git.GitClient, worktreePath string) {
 	if err := gitRepo.RemoveWorktree(ctx, worktreePath); err != nil {
 		LogWarn("RemoveWorktree failed for %s: %v", worktreePath, err)
 	}
@@ -637,8 +678,7 @@ func startContainerWithFullOptions(ws *workstream.Workstream, useExistingBranch
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		// Get current working directory as repo path
-		repoPath, err := os.Getwd()
+		repoPath, err := workstreamRepoPath(ws)
 		if err != nil {
 			return ContainerErrorMsg{
 				WorkstreamID: ws.ID,
@@ -657,10 +697,7 @@ func startContainerWithFullOptions(ws *workstream.Workstream, useExistingBranch
 		defer dockerClient.Close()
 
 		// Create orchestrator
-		gitFactory := func(path string) git.GitClient {
-			return GitClientFactory(path)
-		}
-		orch := orchestrator.New(dockerClient, gitFactory, repoPath)
+		orch := workstreamOrchestrator(dockerClient, ws, repoPath)
 
 		// Check for branch conflict before creating (if not using existing branch)
 		if !useExistingBranch {
@@ -682,6 +719,8 @@ func startContainerWithFullOptions(ws *workstream.Workstream, useExistingBranch
 		}
 
 		// Get untracked files if needed
+		cellsCfg := docker.LoadConfig(repoPath)
+		provision := cellsCfg.Provision
 		var untrackedFiles []string
 		if copyUntrackedFiles && !useExistingBranch {
 			gitRepo := GitClientFactory(repoPath)
@@ -690,7 +729,8 @@ func startContainerWithFullOptions(ws *workstream.Workstream, useExistingBranch
 				LogWarn("Failed to get untracked files: %v", err)
 				// Continue without copying untracked files rather than failing
 			} else {
-				untrackedFiles = files
+				// Provisioned files are copied or linked according to config instead
+				untrackedFiles = filterProvisionedFiles(files, provision)
 			}
 		}
 
@@ -701,6 +741,8 @@ func startContainerWithFullOptions(ws *workstream.Workstream, useExistingBranch
 			UpdateMain:        !useExistingBranch, // Auto-pull main for new branches
 			CopyUntracked:     copyUntrackedFiles,
 			UntrackedFiles:    untrackedFiles,
+			Provision:         provision,
+			Preset:            workstreamPreset(cellsCfg, ws.Template),
 		}
 
 		result, err := orch.CreateWorkstream(ctx, ws, opts)
@@ -733,6 +775,16 @@ func startContainerWithFullOptions(ws *workstream.Workstream, useExistingBranch
 // StartPTYCmd returns a command that starts a PTY session in a container.
 // If isResume is true, uses 'claude --resume <session_id>' (or --continue as fallback).
 func StartPTYCmd(ws *workstream.Workstream, initialPrompt string, width, height int, isResume bool) tea.Cmd {
+	return startPTYCmd(ws, initialPrompt, "", width, height, isResume)
+}
+
+// ResumePTYWithPromptCmd returns a command that resumes a workstream's ended
+// Claude session and submits prompt in it.
+func ResumePTYWithPromptCmd(ws *workstream.Workstream, prompt string, width, height int) tea.Cmd {
+	return startPTYCmd(ws, "", prompt, width, height, true)
+}
+
+func startPTYCmd(ws *workstream.Workstream, initialPrompt, resumePrompt string, width, height int, isResume bool) tea.Cmd {
 	return func() tea.Msg {
 		// Use a timeout for PTY session creation
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -747,7 +799,7 @@ func StartPTYCmd(ws *workstream.Workstream, initialPrompt string, width, height
 		}
 
 		// Get host project path for session data copying
-		hostProjectPath, _ := os.Getwd()
+		hostProjectPath, _ := workstreamRepoPath(ws)
 
 		// Build PTY options with terminal size
 		opts := &PTYOptions{
@@ -755,6 +807,7 @@ func StartPTYCmd(ws *workstream.Workstream, initialPrompt string, width, height
 			Height:          height,
 			IsResume:        isResume,
 			ClaudeSessionID: ws.GetClaudeSessionID(), // Pass session ID for --resume
+			ResumePrompt:    resumePrompt,
 			HostProjectPath: hostProjectPath,
 			Runtime:         ws.Runtime, // Pass runtime selection (claude or claudesp)
 		}
@@ -792,13 +845,19 @@ func StartPTYCmd(ws *workstream.Workstream, initialPrompt string, width, height
 
 // StopContainerCmd returns a command that stops and removes a container.
 func StopContainerCmd(ws *workstream.Workstream) tea.Cmd {
+	return stopContainerCmd(ws, "")
+}
+
+// stopContainerCmd stops and removes a container. If keptWorktree is set,
+// the workstream's worktree (and so its branch) is left in place there.
+func stopContainerCmd(ws *workstream.Workstream, keptWorktree string) tea.Cmd {
 	return func() tea.Msg {
 		LogDebug("StopContainerCmd started for %s", ws.BranchName)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		repoPath, err := os.Getwd()
+		repoPath, err := workstreamRepoPath(ws)
 		if err != nil {
 			LogWarn("Failed to get cwd: %v", err)
 			return ContainerStoppedMsg{WorkstreamID: ws.ID}
@@ -813,14 +872,11 @@ func StopContainerCmd(ws *workstream.Workstream) tea.Cmd {
 		defer dockerClient.Close()
 
 		// Create orchestrator
-		gitFactory := func(path string) git.GitClient {
-			return GitClientFactory(path)
-		}
-		orch := orchestrator.New(dockerClient, gitFactory, repoPath)
+		orch := workstreamOrchestrator(dockerClient, ws, repoPath)
 
 		// Check if we should delete the branch (only if it has no commits)
 		deleteBranch := false
-		if ws.BranchName != "" {
+		if ws.BranchName != "" && keptWorktree == "" {
 			gitRepo := GitClientFactory(repoPath)
 			hasCommits, err := gitRepo.BranchHasCommits(ctx, ws.BranchName)
 			if err != nil {
@@ -833,7 +889,7 @@ func StopContainerCmd(ws *workstream.Workstream) tea.Cmd {
 		// Use orchestrator to destroy workstream
 		destroyOpts := orchestrator.DestroyOptions{
 			DeleteBranch: deleteBranch,
-			KeepWorktree: false,
+			KeepWorktree: keptWorktree != "",
 		}
 		if err := orch.DestroyWorkstream(ctx, ws, destroyOpts); err != nil {
 			LogWarn("DestroyWorkstream error: %v", err)
@@ -842,12 +898,13 @@ func StopContainerCmd(ws *workstream.Workstream) tea.Cmd {
 		// Untrack the container (TUI-layer concern)
 		if ws.ContainerID != "" {
 			untrackContainer(ws.ContainerID)
+			forgetContainerUsage(ws.ContainerID)
 			unregisterContainerCredentials(ws.ContainerID)
 			stopGitProxySocket(ws.ContainerID)
 		}
 
 		LogDebug("StopContainerCmd completed for %s", ws.BranchName)
-		return ContainerStoppedMsg{WorkstreamID: ws.ID}
+		return ContainerStoppedMsg{WorkstreamID: ws.ID, KeptWorktree: keptWorktree}
 	}
 }
 
@@ -1164,7 +1221,7 @@ func ResumeContainerCmd(ws *workstream.Workstream, width, height int) tea.Cmd {
 		dockerClient.Close()
 
 		// Track the resumed container for crash recovery
-		repoPath, _ := os.Getwd()
+		repoPath, _ := workstreamRepoPath(ws)
 		trackContainer(ws.ContainerID, ws.ID, ws.BranchName, repoPath)
 
 		// Start git proxy socket for the resumed container
@@ -1256,8 +1313,9 @@ type PRCreatedMsg struct {
 	Error        error
 }
 
-// CreatePRCmd returns a command that creates a pull request.
-func CreatePRCmd(ws *workstream.Workstream) tea.Cmd {
+// CreatePRCmd returns a command that pushes the branch and creates a pull
+// request. The title and description are generated when req has no title.
+func CreatePRCmd(ws *workstream.Workstream, req git.PRRequest) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 		defer cancel()
@@ -1275,17 +1333,16 @@ func CreatePRCmd(ws *workstream.Workstream) tea.Cmd {
 			return PRCreatedMsg{WorkstreamID: ws.ID, Error: fmt.Errorf("failed to push branch: %w", err)}
 		}
 
-		// Create PR using gh CLI - run from worktree so it picks up the right branch
-		gh := git.NewGH()
+		// Create the PR (or GitLab MR) from the worktree so it picks up the right branch
+		forge := forgeFor(ctx, ws, gitRepo)
 
 		// Generate PR title and body using Claude
-		prTitle, prBody := git.GeneratePRContent(ctx, gitRepo, ws.BranchName, ws.Prompt)
+		if req.Title == "" {
+			req.Title, req.Body = git.GeneratePRContent(ctx, gitRepo, ws.BranchName, ws.Prompt, "")
+		}
+		req.Head = ws.BranchName // Explicitly specify branch for worktrees
 
-		pr, err := gh.CreatePR(ctx, worktreePath, &git.PRRequest{
-			Title: prTitle,
-			Body:  prBody,
-			Head:  ws.BranchName, // Explicitly specify branch for worktrees
-		})
+		pr, err := forge.CreatePR(ctx, worktreePath, &req)
 		if err != nil {
 			return PRCreatedMsg{WorkstreamID: ws.ID, Error: err}
 		}
@@ -1322,7 +1379,7 @@ func MergeBranchWithOptionsCmd(ws *workstream.Workstream, squash bool) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 		defer cancel()
 
-		repoPath, err := os.Getwd()
+		repoPath, err := workstreamRepoPath(ws)
 		if err != nil {
 			return MergeBranchMsg{WorkstreamID: ws.ID, Error: err}
 		}
@@ -1355,14 +1412,15 @@ func MergeBranchWithOptionsCmd(ws *workstream.Workstream, squash bool) tea.Cmd {
 	}
 }
 
-// GHMergePRResultMsg is sent when a GitHub PR merge completes.
+// GHMergePRResultMsg is sent when a PR (or GitLab MR) merge completes.
 type GHMergePRResultMsg struct {
 	WorkstreamID string
 	MergeMethod  string // "squash", "merge", or "rebase"
 	Error        error
 }
 
-// GHMergePRCmd returns a command that merges a PR via GitHub's gh CLI.
+// GHMergePRCmd returns a command that merges a PR via the gh CLI, or a
+// GitLab MR via glab.
 // mergeMethod should be "squash", "merge", or "rebase".
 func GHMergePRCmd(ws *workstream.Workstream, mergeMethod string) tea.Cmd {
 	return func() tea.Msg {
@@ -1375,7 +1433,7 @@ func GHMergePRCmd(ws *workstream.Workstream, mergeMethod string) tea.Cmd {
 			return GHMergePRResultMsg{WorkstreamID: ws.ID, MergeMethod: mergeMethod, Error: fmt.Errorf("no worktree path")}
 		}
 
-		gh := git.NewGH()
+		forge := forgeFor(ctx, ws, GitClientFactory(worktreePath))
 
 		// Build merge options
 		opts := &git.PRMergeOptions{
@@ -1383,7 +1441,7 @@ func GHMergePRCmd(ws *workstream.Workstream, mergeMethod string) tea.Cmd {
 			DeleteBranch: false, // Don't delete - let the user decide via destroy dialog
 		}
 
-		if err := gh.MergePR(ctx, worktreePath, opts); err != nil {
+		if err := forge.MergePR(ctx, worktreePath, opts); err != nil {
 			return GHMergePRResultMsg{WorkstreamID: ws.ID, MergeMethod: mergeMethod, Error: err}
 		}
 
@@ -1452,9 +1510,7 @@ func FetchPRStatusCmd(ws *workstream.Workstream) tea.Cmd {
 		}
 
 		gitRepo := GitClientFactory(worktreePath)
-		gh := git.NewGH()
-
-		status, err := gh.GetPRStatus(ctx, worktreePath, gitRepo)
+		status, err := forgeFor(ctx, ws, gitRepo).GetPRStatus(ctx, worktreePath, gitRepo)
 		if err != nil {
 			return PRStatusMsg{WorkstreamID: ws.ID, Error: err}
 		}
@@ -1518,7 +1574,7 @@ func EnablePairingCmd(orchestrator *sync.Pairing, ws *workstream.Workstream, pre
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		repoPath, err := os.Getwd()
+		repoPath, err := workstreamRepoPath(ws)
 		if err != nil {
 			return PairingEnabledMsg{WorkstreamID: ws.ID, Error: err}
 		}
@@ -1652,90 +1708,3 @@ func FetchResourceStatsCmd(global bool, projectContainerIDs []string) tea.Cmd {
 		}
 	}
 }
-
-// ClaudeUsageMsg is sent when Claude usage information is fetched.
-type ClaudeUsageMsg struct {
-	ContainerID string
-	Usage       string
-	Error       error
-}
-
-// usageParseScript is a shell script that parses Claude session JSONL files
-// and sums up token usage. It finds the most recent session file and extracts
-// usage data from .message.usage fields. Uses portable grep/sed/awk (no gawk).
-const usageParseScript = `#!/bin/sh
-SESSION_DIR="$HOME/.claude/projects"
-if [ ! -d "$SESSION_DIR" ]; then
-  echo "No session data"
-  exit 0
-fi
-
-# Find most recently modified .jsonl file across all project dirs
-LATEST=""
-LATEST_TIME=0
-for dir in "$SESSION_DIR"/*/; do
-  for f in "$dir"*.jsonl; do
-    [ -f "$f" ] || continue
-    if stat --version 2>/dev/null | grep -q GNU; then
-      MTIME=$(stat -c %Y "$f" 2>/dev/null)
-    else
-      MTIME=$(stat -f %m "$f" 2>/dev/null)
-    fi
-    if [ -n "$MTIME" ] && [ "$MTIME" -gt "$LATEST_TIME" ] 2>/dev/null; then
-      LATEST_TIME=$MTIME
-      LATEST=$f
-    fi
-  done
-done
-
-if [ -z "$LATEST" ]; then
-  echo "No session files"
-  exit 0
-fi
-
-# Parse usage with portable grep/sed/awk (works with busybox)
-input=$(grep -o '"input_tokens":[0-9]*' "$LATEST" 2>/dev/null | sed 's/[^0-9]//g' | awk '{s+=$1} END {print s+0}')
-output=$(grep -o '"output_tokens":[0-9]*' "$LATEST" 2>/dev/null | sed 's/[^0-9]//g' | awk '{s+=$1} END {print s+0}')
-cache_create=$(grep -o '"cache_creation_input_tokens":[0-9]*' "$LATEST" 2>/dev/null | sed 's/[^0-9]//g' | awk '{s+=$1} END {print s+0}')
-cache_read=$(grep -o '"cache_read_input_tokens":[0-9]*' "$LATEST" 2>/dev/null | sed 's/[^0-9]//g' | awk '{s+=$1} END {print s+0}')
-
-total=$((input + output + cache_create))
-printf "Session Tokens\n"
-printf "----------------------\n"
-printf "Input:        %8d\n" "$input"
-printf "Output:       %8d\n" "$output"
-printf "Cache Create: %8d\n" "$cache_create"
-printf "Cache Read:   %8d\n" "$cache_read"
-printf "----------------------\n"
-printf "Total:        %8d\n" "$total"
-`
-
-// FetchClaudeUsageCmd returns a command that fetches Claude usage from a container.
-// It parses session JSONL files to extract and sum token usage.
-func FetchClaudeUsageCmd(containerID string) tea.Cmd {
-	return func() tea.Msg {
-		if containerID == "" {
-			return ClaudeUsageMsg{Error: fmt.Errorf("no container ID")}
-		}
-
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		client, err := docker.NewClient()
-		if err != nil {
-			return ClaudeUsageMsg{ContainerID: containerID, Error: err}
-		}
-		defer client.Close()
-
-		// Execute the usage parsing script in the container
-		usage, err := client.ExecInContainer(ctx, containerID, []string{"sh", "-c", usageParseScript})
-		if err != nil {
-			return ClaudeUsageMsg{ContainerID: containerID, Error: err}
-		}
-
-		return ClaudeUsageMsg{
-			ContainerID: containerID,
-			Usage:       usage,
-		}
-	}
-}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
 	"github.com/STRML/claude-cells/internal/workstream"
 )
 
@@ -804,7 +806,7 @@ func TestCreatePRCmd_NoWorktree(t *testing.T) {
 	ws := workstream.New("test prompt")
 	// No worktree path or branch name
 
-	cmd := CreatePRCmd(ws)
+	cmd := CreatePRCmd(ws, git.PRRequest{})
 	msg := cmd()
 
 	switch m := msg.(type) {
@@ -966,3 +968,19 @@ func TestCopyUntrackedFilesToWorktree_EmptyList(t *testing.T) {
 		t.Errorf("Expected no error for empty file list, got: %v", err)
 	}
 }
+
+func TestFilterProvisionedFiles(t *testing.T) {
+	files := []string{".env", "fixtures/large/a.json", "notes.txt"}
+
+	if got := filterProvisionedFiles(files, docker.ProvisionConfig{}); len(got) != len(files) {
+		t.Errorf("empty config should keep all files, got %v", got)
+	}
+
+	got := filterProvisionedFiles(files, docker.ProvisionConfig{
+		Copy: []string{".env*"},
+		Link: []string{"fixtures/large"},
+	})
+	if len(got) != 1 || got[0] != "notes.txt" {
+		t.Errorf("filterProvisionedFiles() = %v, want [notes.txt]", got)
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/docker"
 	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
 )
 
 // DialogType represents the type of dialog
@@ -34,6 +36,22 @@ const (
 	DialogQuitConfirm          // Confirm quit with y/n
 	DialogCopyUntrackedFiles   // Prompt to copy untracked files to worktree
 	DialogForcePushConfirm     // Confirm force push by typing "force push"
+	DialogVerifyFailed         // Pre-merge verification failed - offer to send output to Claude
+	DialogBestOfN              // Choose how many cells to run for a Best-of-N prompt
+	DialogBestOfNPrompt        // Enter the prompt shared by a Best-of-N group
+	DialogBestOfNCompare       // Compare Best-of-N results side by side and pick a winner
+	DialogBestOfNDestroyRest   // Offer to destroy the non-winning cells of a group
+	DialogArchive              // Browse archived workstreams and restore one
+	DialogFilter               // Filter which panes are shown
+	DialogLabels               // Edit a workstream's labels and priority
+	DialogBudget               // Edit a workstream's token budget
+	DialogProjectBudget        // Edit the project's token budget
+	DialogOpenRepo             // Open an additional repository
+	DialogDiff                 // Browse a workstream's diff against its merge base
+	DialogHistoryCleanup       // Review and edit a proposed cleanup of a branch's commits
+	DialogCherryPick           // Pick commits from another workstream's branch
+	DialogCheckpoints          // Browse, diff and restore a workstream's WIP checkpoints
+	DialogSparseWiden          // Add directories to a workstream's sparse checkout
 )
 
 // DialogModel represents a modal dialog
@@ -63,8 +81,44 @@ type DialogModel struct {
 	statsLoading bool   // True while fetching stats
 	statsError   string // Error message if stats fetch failed
 	claudeUsage  string // Claude token usage information
+	// Streamed command output (verification progress)
+	outputHeader string // Fixed text shown above the streamed output
+	outputBuf    string // Accumulated output; only the tail is displayed
+	// Verification failed dialog
+	sendPrompt string // Prompt sent to Claude when the user asks for a fix
+	// Best-of-N comparison dialog
+	groupID        string
+	compareEntries []GroupCompareEntry // nil while results are loading
+	// New workstream template picker
+	templates   []docker.TemplateConfig // Configured templates; empty hides the picker
+	templateIdx int                     // 0 = no template, otherwise templates[templateIdx-1]
+	// New workstream sparse checkout directories (empty = full checkout)
+	sparse        textinput.Model
+	sparseFocused bool
+	sparseErr     string
+	// New workstream repository picker (shown when several repositories are open)
+	repos         []string                  // Repository names, the primary repository first
+	repoTemplates [][]docker.TemplateConfig // Templates of each repository
+	repoIdx       int
+	// Archive browser dialog
+	archiveEntries []workstream.ArchivedWorkstream
+	// Diff viewer dialog
+	diff diffView
+	// PR preview dialog fields
+	pr prForm
+	// History cleanup dialog
+	history historyEdit
+	// Cherry-pick dialog
+	cherryPick cherryPickForm
+	// Checkpoint browser dialog
+	checkpoints checkpointBrowser
+	// Text input dialogs that accept an empty value (e.g. to clear a filter)
+	allowEmpty bool
 }
 
+// streamOutputLines is the number of trailing output lines shown while streaming.
+const streamOutputLines = 15
+
 // NewDestroyDialog creates a destroy confirmation dialog
 func NewDestroyDialog(branchName, workstreamID string) DialogModel {
 	ti := textinput.New()
@@ -137,7 +191,39 @@ func NewWorkstreamDialog() DialogModel {
 		Body:        "Enter a prompt for Claude:",
 		TextArea:    ta,
 		useTextArea: true,
+		sparse:      newOptionalInput("full checkout", ""),
+	}
+}
+
+// SetTemplates sets the templates offered by the new workstream dialog.
+func (d *DialogModel) SetTemplates(templates []docker.TemplateConfig) {
+	d.templates = templates
+	d.templateIdx = 0
+}
+
+// SelectedTemplate returns the chosen template name, or "" for none.
+func (d *DialogModel) SelectedTemplate() string {
+	if d.templateIdx == 0 || d.templateIdx > len(d.templates) {
+		return ""
+	}
+	return d.templates[d.templateIdx-1].Name
+}
+
+// SetRepos sets the repositories offered by the new workstream dialog and
+// the templates configured in each. Switching repositories offers that
+// repository's templates.
+func (d *DialogModel) SetRepos(repos []string, templates [][]docker.TemplateConfig) {
+	d.repos = repos
+	d.repoTemplates = templates
+	d.repoIdx = 0
+}
+
+// SelectedRepo returns the index of the chosen repository (0 = primary).
+func (d *DialogModel) SelectedRepo() int {
+	if d.repoIdx >= len(d.repos) {
+		return 0
 	}
+	return d.repoIdx
 }
 
 // NewPRDialog creates a PR preview/edit dialog
@@ -193,6 +279,7 @@ const (
 	MergeActionPush          MergeAction = "push"
 	MergeActionForcePush     MergeAction = "force_push"
 	MergeActionFetchRebase   MergeAction = "fetch_rebase"
+	MergeActionCleanHistory  MergeAction = "clean_history"   // Restructure commits with Claude
 	MergeActionGHMergeSquash MergeAction = "gh_merge_squash" // Merge PR via GitHub (squash)
 	MergeActionGHMergeMerge  MergeAction = "gh_merge_merge"  // Merge PR via GitHub (merge commit)
 	MergeActionGHMergeRebase MergeAction = "gh_merge_rebase" // Merge PR via GitHub (rebase)
@@ -327,6 +414,7 @@ func NewMergeDialog(branchName, workstreamID, branchInfo, synopsis string, hasBe
 		if hasBeenPushed {
 			menuItems = append(menuItems, "Force push (--force-with-lease)")
 		}
+		menuItems = append(menuItems, "Clean up history with Claude")
 		// Separator before GitHub merge options
 		menuItems = append(menuItems, "───────────────────────────")
 		menuItems = append(menuItems, "Merge PR via GitHub (squash)")
@@ -348,6 +436,7 @@ func NewMergeDialog(branchName, workstreamID, branchInfo, synopsis string, hasBe
 		if hasBeenPushed {
 			menuItems = append(menuItems, "Force push (--force-with-lease)")
 		}
+		menuItems = append(menuItems, "Clean up history with Claude")
 	}
 
 	menuItems = append(menuItems, "Cancel")
@@ -698,6 +787,113 @@ func NewProgressDialog(title, message string, workstreamID string) DialogModel {
 	}
 }
 
+// NewVerifyProgressDialog creates a progress dialog that streams the output
+// of the pre-merge verification command.
+func NewVerifyProgressDialog(branchName, command, workstreamID string) DialogModel {
+	header := fmt.Sprintf("Branch: %s\n\nRunning verification before merge:\n  $ %s\n\n", branchName, command)
+	return DialogModel{
+		Type:         DialogProgress,
+		Title:        "Verifying Branch",
+		Body:         header,
+		WorkstreamID: workstreamID,
+		inProgress:   true,
+		outputHeader: header,
+	}
+}
+
+// AppendStreamOutput appends streamed command output, showing only the tail
+// below the dialog's header.
+func (d *DialogModel) AppendStreamOutput(text string) {
+	d.outputBuf += text
+	if len(d.outputBuf) > verifyOutputMaxBytes {
+		d.outputBuf = d.outputBuf[len(d.outputBuf)-verifyOutputMaxBytes:]
+	}
+	d.Body = d.outputHeader + tailLines(d.outputBuf, streamOutputLines)
+}
+
+// NewVerifyFailedDialog creates a dialog shown when pre-merge verification fails.
+// The merge is blocked; the user can send the failure to Claude with one key.
+func NewVerifyFailedDialog(branchName, workstreamID, command string, exitCode int, output string) DialogModel {
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("Branch: %s\n", branchName))
+	body.WriteString(fmt.Sprintf("Command: %s (exit code %d)\n\n", command, exitCode))
+	body.WriteString("The merge was blocked. Last lines of output:\n\n")
+	body.WriteString(tailLines(output, streamOutputLines))
+
+	return DialogModel{
+		Type:         DialogVerifyFailed,
+		Title:        "Verification Failed",
+		Body:         body.String(),
+		WorkstreamID: workstreamID,
+		sendPrompt:   verifyFixPrompt(command, exitCode),
+		MenuItems: []string{
+			"Send failure output to Claude",
+			"Close",
+		},
+		MenuSelection: 0,
+	}
+}
+
+// NewBestOfNDialog creates a dialog for choosing a Best-of-N configuration
+func NewBestOfNDialog() DialogModel {
+	items := make([]string, 0, len(bestOfNPresets)+1)
+	for _, p := range bestOfNPresets {
+		items = append(items, p.Label)
+	}
+	items = append(items, "Cancel")
+
+	return DialogModel{
+		Type:          DialogBestOfN,
+		Title:         "Best-of-N",
+		Body:          "Run the same prompt in several cells, then compare\nthe results and keep the best one.\n\nHow many cells?",
+		MenuItems:     items,
+		MenuSelection: 0,
+	}
+}
+
+// NewBestOfNPromptDialog creates the prompt dialog for a Best-of-N group of n cells
+func NewBestOfNPromptDialog(n int) DialogModel {
+	d := NewWorkstreamDialog()
+	d.Type = DialogBestOfNPrompt
+	d.Title = fmt.Sprintf("Best-of-%d", n)
+	d.Body = fmt.Sprintf("Enter a prompt to run in %d cells:", n)
+	return d
+}
+
+// NewBestOfNCompareDialog creates the comparison dialog for a Best-of-N group.
+// It shows a loading message until SetCompareEntries is called.
+func NewBestOfNCompareDialog(groupID string, size int) DialogModel {
+	return DialogModel{
+		Type:    DialogBestOfNCompare,
+		Title:   fmt.Sprintf("Compare %d Cells", size),
+		Body:    "Gathering diffs and test results...",
+		groupID: groupID,
+	}
+}
+
+// SetCompareEntries fills the comparison dialog with gathered results
+func (d *DialogModel) SetCompareEntries(entries []GroupCompareEntry) {
+	d.compareEntries = entries
+	d.MenuSelection = 0
+}
+
+// NewBestOfNDestroyRestDialog asks whether to destroy the cells that lost a Best-of-N comparison
+func NewBestOfNDestroyRestDialog(winnerBranch, winnerID string, others int) DialogModel {
+	body := fmt.Sprintf("Winner: %s\n\nDestroy the other %d cell(s) in this group?\nTheir containers and local branches will be removed.", winnerBranch, others)
+
+	return DialogModel{
+		Type:         DialogBestOfNDestroyRest,
+		Title:        "Winner Selected",
+		Body:         body,
+		WorkstreamID: winnerID,
+		MenuItems: []string{
+			fmt.Sprintf("Yes, destroy %d other cell(s)", others),
+			"No, keep them",
+		},
+		MenuSelection: 0,
+	}
+}
+
 // SetComplete marks the progress dialog as complete with a result message
 func (d *DialogModel) SetComplete(message string) {
 	d.inProgress = false
@@ -746,7 +942,10 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.PasteMsg:
 		// Handle paste into dialog input fields
-		if d.useTextArea {
+		if d.sparseFocused {
+			d.sparse.SetValue(d.sparse.Value() + msg.Content)
+			d.sparse.CursorEnd()
+		} else if d.useTextArea {
 			d.TextArea.InsertString(msg.Content)
 		} else {
 			// For textinput, insert pasted content at cursor position
@@ -763,6 +962,42 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 		if keyStr == "enter" || keyStr == "shift+enter" {
 			LogDebug("Dialog received key: %q", keyStr)
 		}
+		// The diff viewer handles its own navigation and review keys
+		if d.Type == DialogDiff {
+			if cmd, handled := d.updateDiff(msg); handled {
+				return d, cmd
+			}
+		}
+		// The PR preview dialog moves between its fields
+		if d.Type == DialogPRPreview && d.pr.fields != nil {
+			if cmd, handled := d.updatePRForm(msg); handled {
+				return d, cmd
+			}
+		}
+		// The history cleanup dialog edits its plan
+		if d.Type == DialogHistoryCleanup {
+			if cmd, handled := d.updateHistory(msg); handled {
+				return d, cmd
+			}
+		}
+		// The cherry-pick dialog chooses a source, then its commits
+		if d.Type == DialogCherryPick {
+			if cmd, handled := d.updateCherryPick(msg); handled {
+				return d, cmd
+			}
+		}
+		// The new workstream dialog edits its sparse paths field
+		if d.Type == DialogNewWorkstream {
+			if cmd, handled := d.updateSparseField(msg); handled {
+				return d, cmd
+			}
+		}
+		// The checkpoint browser diffs and restores checkpoints
+		if d.Type == DialogCheckpoints {
+			if cmd, handled := d.updateCheckpoints(msg); handled {
+				return d, cmd
+			}
+		}
 		switch keyStr {
 		case "esc", "ctrl+c":
 			// Progress dialog can't be dismissed while in progress
@@ -784,6 +1019,20 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 				d.Body = "Loading..."
 				return d, func() tea.Msg { return ResourceStatsToggleMsg{IsGlobal: d.isGlobalView} }
 			}
+			// Tab cycles through templates in the new workstream dialog
+			if d.Type == DialogNewWorkstream && len(d.templates) > 0 {
+				d.templateIdx = (d.templateIdx + 1) % (len(d.templates) + 1)
+				return d, nil
+			}
+		case "ctrl+r":
+			// Ctrl+R cycles through open repositories in the new workstream dialog
+			if d.Type == DialogNewWorkstream && len(d.repos) > 1 {
+				d.repoIdx = (d.repoIdx + 1) % len(d.repos)
+				if d.repoIdx < len(d.repoTemplates) {
+					d.SetTemplates(d.repoTemplates[d.repoIdx])
+				}
+				return d, nil
+			}
 		case "r":
 			// 'r' refreshes in resource usage dialog
 			if d.Type == DialogResourceUsage && !d.statsLoading {
@@ -798,6 +1047,11 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 					return DialogConfirmMsg{Type: d.Type}
 				}
 			}
+		case "s", "S":
+			// 's' sends the verification failure to Claude
+			if d.Type == DialogVerifyFailed {
+				return d, d.verifyFailedConfirm()
+			}
 		case "n", "N":
 			// 'n' cancels quit dialog
 			if d.Type == DialogQuitConfirm {
@@ -883,6 +1137,8 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 					action = MergeActionPush
 				case strings.HasPrefix(selectedItem, "Rebase on main"):
 					action = MergeActionFetchRebase
+				case strings.HasPrefix(selectedItem, "Clean up history"):
+					action = MergeActionCleanHistory
 				case strings.HasPrefix(selectedItem, "Force push"):
 					action = MergeActionForcePush
 				default:
@@ -982,6 +1238,49 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 				}
 			}
 
+			if d.Type == DialogVerifyFailed {
+				// Selection 0 = "Send to Claude", 1 = "Close"
+				if d.MenuSelection == 1 {
+					return d, func() tea.Msg { return DialogCancelMsg{} }
+				}
+				return d, d.verifyFailedConfirm()
+			}
+
+			if d.Type == DialogBestOfN {
+				if d.MenuSelection >= len(bestOfNPresets) {
+					return d, func() tea.Msg { return DialogCancelMsg{} }
+				}
+				runtimes := bestOfNPresets[d.MenuSelection].Runtimes
+				return d, func() tea.Msg { return BestOfNSelectMsg{Runtimes: runtimes} }
+			}
+
+			if d.Type == DialogBestOfNCompare {
+				// Nothing to pick until results have loaded
+				if len(d.compareEntries) == 0 {
+					return d, nil
+				}
+				groupID := d.groupID
+				winnerID := d.compareEntries[d.MenuSelection].WorkstreamID
+				return d, func() tea.Msg { return BestOfNWinnerMsg{GroupID: groupID, WinnerID: winnerID} }
+			}
+
+			if d.Type == DialogArchive {
+				id := d.archiveEntries[d.MenuSelection].ID
+				return d, func() tea.Msg { return DialogConfirmMsg{Type: DialogArchive, Value: id} }
+			}
+
+			if d.Type == DialogBestOfNDestroyRest {
+				// Selection 0 = "Yes, destroy others", 1 = "No, keep them"
+				selection := d.MenuSelection
+				return d, func() tea.Msg {
+					return DialogConfirmMsg{
+						Type:         d.Type,
+						WorkstreamID: d.WorkstreamID,
+						Value:        fmt.Sprintf("%d", selection),
+					}
+				}
+			}
+
 			if d.Type == DialogQuitConfirm {
 				// Selection 0 = "Yes", 1 = "No"
 				if d.MenuSelection == 1 {
@@ -1008,18 +1307,28 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 				// For textarea dialogs, get value from textarea
 				value := strings.TrimSpace(d.TextArea.Value())
 				if value != "" {
+					template := d.SelectedTemplate()
+					repo := d.SelectedRepo()
+					sparsePaths, err := d.SparsePaths()
+					if err != nil {
+						d.sparseErr = err.Error()
+						return d, nil
+					}
 					return d, func() tea.Msg {
 						return DialogConfirmMsg{
 							Type:         d.Type,
 							WorkstreamID: d.WorkstreamID,
 							Value:        value,
+							Template:     template,
+							Repo:         repo,
+							SparsePaths:  sparsePaths,
 						}
 					}
 				}
 				// Enter pressed but input is empty - ignore
 				return d, nil
 			} else {
-				if d.Input.Value() != "" {
+				if d.Input.Value() != "" || d.allowEmpty {
 					return d, func() tea.Msg {
 						return DialogConfirmMsg{
 							Type:         d.Type,
@@ -1031,6 +1340,20 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 				// Enter pressed but input is empty - ignore
 				return d, nil
 			}
+		case "left", "h", "shift+tab":
+			if d.Type == DialogBestOfNCompare {
+				if d.MenuSelection > 0 {
+					d.MenuSelection--
+				}
+				return d, nil
+			}
+		case "right", "l":
+			if d.Type == DialogBestOfNCompare {
+				if d.MenuSelection < len(d.compareEntries)-1 {
+					d.MenuSelection++
+				}
+				return d, nil
+			}
 		case "up", "k":
 			// Handle scrollable dialog scrolling
 			if d.Type == DialogLog || d.Type == DialogFirstRunIntroduction {
@@ -1040,7 +1363,7 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 				return d, nil
 			}
 			// Only handle for menu dialogs, otherwise pass to input
-			if d.Type == DialogSettings || d.Type == DialogMerge || d.Type == DialogBranchConflict || d.Type == DialogCommitBeforeMerge || d.Type == DialogPostMergeDestroy || d.Type == DialogMergeConflict || d.Type == DialogQuitConfirm || d.Type == DialogCopyUntrackedFiles {
+			if d.Type == DialogSettings || d.Type == DialogMerge || d.Type == DialogBranchConflict || d.Type == DialogCommitBeforeMerge || d.Type == DialogPostMergeDestroy || d.Type == DialogMergeConflict || d.Type == DialogQuitConfirm || d.Type == DialogCopyUntrackedFiles || d.Type == DialogVerifyFailed || d.Type == DialogBestOfN || d.Type == DialogBestOfNDestroyRest || d.Type == DialogArchive {
 				if d.MenuSelection > 0 {
 					d.MenuSelection--
 					// Skip separator items (start with ───)
@@ -1059,7 +1382,7 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 				return d, nil
 			}
 			// Only handle for menu dialogs, otherwise pass to input
-			if d.Type == DialogSettings || d.Type == DialogMerge || d.Type == DialogBranchConflict || d.Type == DialogCommitBeforeMerge || d.Type == DialogPostMergeDestroy || d.Type == DialogMergeConflict || d.Type == DialogQuitConfirm || d.Type == DialogCopyUntrackedFiles {
+			if d.Type == DialogSettings || d.Type == DialogMerge || d.Type == DialogBranchConflict || d.Type == DialogCommitBeforeMerge || d.Type == DialogPostMergeDestroy || d.Type == DialogMergeConflict || d.Type == DialogQuitConfirm || d.Type == DialogCopyUntrackedFiles || d.Type == DialogVerifyFailed || d.Type == DialogBestOfN || d.Type == DialogBestOfNDestroyRest || d.Type == DialogArchive {
 				if d.MenuSelection < len(d.MenuItems)-1 {
 					d.MenuSelection++
 					// Skip separator items (start with ───)
@@ -1111,7 +1434,7 @@ func (d DialogModel) Update(msg tea.Msg) (DialogModel, tea.Cmd) {
 	}
 
 	// For menu-style, log, progress, resource, and introduction dialogs, don't pass keys to input
-	if d.Type == DialogSettings || d.Type == DialogMerge || d.Type == DialogBranchConflict || d.Type == DialogCommitBeforeMerge || d.Type == DialogPostMergeDestroy || d.Type == DialogMergeConflict || d.Type == DialogQuitConfirm || d.Type == DialogCopyUntrackedFiles || d.Type == DialogLog || d.Type == DialogProgress || d.Type == DialogResourceUsage || d.Type == DialogFirstRunIntroduction {
+	if d.Type == DialogSettings || d.Type == DialogMerge || d.Type == DialogBranchConflict || d.Type == DialogCommitBeforeMerge || d.Type == DialogPostMergeDestroy || d.Type == DialogMergeConflict || d.Type == DialogQuitConfirm || d.Type == DialogCopyUntrackedFiles || d.Type == DialogVerifyFailed || d.Type == DialogBestOfN || d.Type == DialogBestOfNDestroyRest || d.Type == DialogArchive || d.Type == DialogBestOfNCompare || d.Type == DialogLog || d.Type == DialogProgress || d.Type == DialogResourceUsage || d.Type == DialogFirstRunIntroduction {
 		return d, nil
 	}
 
@@ -1147,7 +1470,7 @@ func (d DialogModel) View() string {
 		// Add Claude usage section if available
 		if d.claudeUsage != "" {
 			content.WriteString("\n\n")
-			content.WriteString("Claude Usage (focused pane)\n")
+			content.WriteString("Claude Usage (estimated cost)\n")
 			content.WriteString(strings.Repeat("─", 56))
 			content.WriteString("\n")
 			content.WriteString(d.claudeUsage)
@@ -1251,11 +1574,50 @@ func (d DialogModel) View() string {
 		return DialogBox.Width(d.width).Render(content.String())
 	}
 
+	// Diff viewer renders a file tree next to the selected file's diff
+	if d.Type == DialogDiff {
+		return d.viewDiff()
+	}
+
+	// PR preview shows its fields above the description
+	if d.Type == DialogPRPreview && d.pr.fields != nil {
+		return d.viewPRForm()
+	}
+
+	// History cleanup shows the editable plan
+	if d.Type == DialogHistoryCleanup {
+		return d.viewHistory()
+	}
+
+	// Cherry-pick lists sources, then commits with check boxes
+	if d.Type == DialogCherryPick {
+		return d.viewCherryPick()
+	}
+
+	// Checkpoint browser lists checkpoints, newest first
+	if d.Type == DialogCheckpoints {
+		return d.viewCheckpoints()
+	}
+
+	// Best-of-N comparison renders one column per cell
+	if d.Type == DialogBestOfNCompare {
+		if d.compareEntries == nil {
+			content.WriteString(d.Body)
+			content.WriteString("\n\n")
+			content.WriteString(KeyHintStyle.Render("Loading...") + "  " + KeyHintStyle.Render("[Esc] Close"))
+		} else {
+			content.WriteString(renderGroupComparison(d.compareEntries, d.MenuSelection, d.width-6))
+			content.WriteString("\n\n")
+			content.WriteString(KeyHint("←/→", " select") + "  " + KeyHint("Enter", " pick winner") + "  " + KeyHintStyle.Render("[Esc] Close"))
+		}
+		return DialogBox.Width(d.width).Render(content.String())
+	}
+
 	content.WriteString(d.Body)
 	content.WriteString("\n\n")
 
 	// Menu-style dialogs render a selection list
-	if d.Type == DialogSettings || d.Type == DialogMerge || d.Type == DialogBranchConflict || d.Type == DialogCommitBeforeMerge || d.Type == DialogPostMergeDestroy || d.Type == DialogMergeConflict || d.Type == DialogQuitConfirm || d.Type == DialogCopyUntrackedFiles {
+	if d.Type == DialogSettings || d.Type == DialogMerge || d.Type == DialogBranchConflict || d.Type == DialogCommitBeforeMerge || d.Type == DialogPostMergeDestroy || d.Type == DialogMergeConflict || d.Type == DialogQuitConfirm || d.Type == DialogCopyUntrackedFiles || d.Type == DialogVerifyFailed || d.Type == DialogBestOfN || d.Type == DialogBestOfNDestroyRest || d.Type == DialogArchive {
 		for i, item := range d.MenuItems {
 			// Separator items render without selection prefix
 			if strings.HasPrefix(item, "───") {
@@ -1270,15 +1632,54 @@ func (d DialogModel) View() string {
 			content.WriteString("\n")
 		}
 		content.WriteString("\n")
+		if d.Type == DialogArchive && d.MenuSelection < len(d.archiveEntries) {
+			content.WriteString(renderArchiveEntry(d.archiveEntries[d.MenuSelection]))
+			content.WriteString("\n\n")
+		}
 		if d.Type == DialogQuitConfirm {
 			content.WriteString(KeyHint("y", " yes") + "  " + KeyHint("n", " no") + "  " + KeyHint("↑/↓", " navigate") + "  " + KeyHint("Enter", " select"))
+		} else if d.Type == DialogVerifyFailed {
+			content.WriteString(KeyHint("s", " send to Claude") + "  " + KeyHint("↑/↓", " navigate") + "  " + KeyHint("Enter", " select") + "  " + KeyHintStyle.Render("[Esc] Close"))
+		} else if d.Type == DialogArchive {
+			content.WriteString(KeyHint("↑/↓", " navigate") + "  " + KeyHint("Enter", " restore") + "  " + KeyHintStyle.Render("[Esc] Close"))
 		} else {
 			content.WriteString(KeyHint("↑/↓", " navigate") + "  " + KeyHint("Enter", " select") + "  " + KeyHintStyle.Render("[Esc] Cancel"))
 		}
 	} else if d.useTextArea {
 		content.WriteString(inputStyle.Render(d.TextArea.View()))
 		content.WriteString("\n\n")
-		content.WriteString(KeyHint("Shift+Enter", " newline") + "  " + KeyHint("Enter", " create") + "  " + KeyHintStyle.Render("[Esc] Cancel"))
+		hints := KeyHint("Shift+Enter", " newline") + "  " + KeyHint("Enter", " create") + "  " + KeyHintStyle.Render("[Esc] Cancel")
+		if d.Type == DialogNewWorkstream && len(d.templates) > 0 {
+			template := "none"
+			if d.templateIdx > 0 && d.templateIdx <= len(d.templates) {
+				tmpl := d.templates[d.templateIdx-1]
+				template = tmpl.Name
+				if tmpl.Description != "" {
+					template += " - " + tmpl.Description
+				}
+			}
+			content.WriteString("Template: " + DialogInputText.Render(template) + "\n\n")
+			hints = KeyHint("Tab", " template") + "  " + hints
+		}
+		if d.Type == DialogNewWorkstream && len(d.repos) > 1 {
+			content.WriteString("Repository: " + DialogInputText.Render(d.repos[d.SelectedRepo()]) + "\n\n")
+			hints = KeyHint("Ctrl+R", " repo") + "  " + hints
+		}
+		if d.Type == DialogNewWorkstream {
+			sparse := d.sparse.View()
+			if d.sparse.Value() == "" && !d.sparseFocused && d.templateIdx > 0 && d.templateIdx <= len(d.templates) {
+				if paths := d.templates[d.templateIdx-1].SparsePaths; len(paths) > 0 {
+					sparse = DialogInputPlaceholder.Render(strings.Join(paths, ", ") + " (template)")
+				}
+			}
+			content.WriteString("Sparse paths: " + sparse + "\n")
+			if d.sparseErr != "" {
+				content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorPairingConflict)).Render("✗ "+d.sparseErr) + "\n")
+			}
+			content.WriteString("\n")
+			hints = KeyHint("Ctrl+S", " sparse paths") + "  " + hints
+		}
+		content.WriteString(hints)
 	} else {
 		content.WriteString(inputStyle.Render(d.Input.View()))
 		content.WriteString("\n\n")
@@ -1292,6 +1693,10 @@ func (d DialogModel) View() string {
 			hints = KeyHint("Enter", " create") + "  " + KeyHintStyle.Render("[Esc] Cancel")
 		case DialogPRPreview:
 			hints = KeyHint("Enter", " create") + "  " + KeyHintStyle.Render("[Esc] Cancel")
+		case DialogOpenRepo:
+			hints = KeyHint("Enter", " open") + "  " + KeyHintStyle.Render("[Esc] Cancel")
+		case DialogFilter, DialogLabels, DialogBudget, DialogProjectBudget, DialogSparseWiden:
+			hints = KeyHint("Enter", " apply") + "  " + KeyHintStyle.Render("[Esc] Cancel")
 		}
 		content.WriteString(hints)
 	}
@@ -1299,6 +1704,15 @@ func (d DialogModel) View() string {
 	return DialogBox.Width(d.width).Render(content.String())
 }
 
+// verifyFailedConfirm returns the command confirming a verification-failed dialog.
+func (d DialogModel) verifyFailedConfirm() tea.Cmd {
+	prompt := d.sendPrompt
+	workstreamID := d.WorkstreamID
+	return func() tea.Msg {
+		return DialogConfirmMsg{Type: DialogVerifyFailed, WorkstreamID: workstreamID, Value: prompt}
+	}
+}
+
 // SetSize sets the dialog dimensions
 func (d *DialogModel) SetSize(width, height int) {
 	d.width = width
@@ -1315,6 +1729,9 @@ func (d *DialogModel) SetSize(width, height int) {
 		}
 		d.TextArea.SetWidth(textareaWidth)
 	}
+	if d.Type == DialogHistoryCleanup {
+		d.TextArea.SetHeight(max(height-12, 5))
+	}
 
 	// Recalculate scrollMax for scrollable dialogs based on actual visible lines
 	if d.Type == DialogLog || d.Type == DialogFirstRunIntroduction {
@@ -1353,7 +1770,7 @@ func (d DialogModel) ViewInPane() string {
 		} else {
 			content.WriteString(KeyHint("Enter/Esc", " close"))
 		}
-	} else if d.Type == DialogSettings || d.Type == DialogMerge || d.Type == DialogBranchConflict || d.Type == DialogCommitBeforeMerge || d.Type == DialogPostMergeDestroy || d.Type == DialogMergeConflict || d.Type == DialogQuitConfirm || d.Type == DialogCopyUntrackedFiles {
+	} else if d.Type == DialogSettings || d.Type == DialogMerge || d.Type == DialogBranchConflict || d.Type == DialogCommitBeforeMerge || d.Type == DialogPostMergeDestroy || d.Type == DialogMergeConflict || d.Type == DialogQuitConfirm || d.Type == DialogCopyUntrackedFiles || d.Type == DialogVerifyFailed || d.Type == DialogBestOfN || d.Type == DialogBestOfNDestroyRest || d.Type == DialogArchive {
 		// Menu items (for menu-style dialogs like merge) - same styling as View()
 		for i, item := range d.MenuItems {
 			// Separator items render without selection prefix
@@ -1371,6 +1788,8 @@ func (d DialogModel) ViewInPane() string {
 		content.WriteString("\n")
 		if d.Type == DialogQuitConfirm {
 			content.WriteString(KeyHint("y", " yes") + "  " + KeyHint("n", " no") + "  " + KeyHint("↑/↓", " navigate") + "  " + KeyHint("Enter", " select"))
+		} else if d.Type == DialogVerifyFailed {
+			content.WriteString(KeyHint("s", " send to Claude") + "  " + KeyHint("↑/↓", " navigate") + "  " + KeyHint("Enter", " select") + "  " + KeyHintStyle.Render("[Esc] Close"))
 		} else {
 			content.WriteString(KeyHint("↑/↓", " navigate") + "  " + KeyHint("Enter", " select") + "  " + KeyHintStyle.Render("[Esc] Cancel"))
 		}
@@ -1401,6 +1820,9 @@ type DialogConfirmMsg struct {
 	WorkstreamID  string
 	Value         string
 	ConflictFiles []string // Files with merge/rebase conflicts (for DialogMergeConflict)
+	Template      string   // Selected template name (for DialogNewWorkstream)
+	Repo          int      // Selected repository, 0 = primary (for DialogNewWorkstream)
+	SparsePaths   []string // Sparse checkout directories, nil = full checkout (for DialogNewWorkstream)
 }
 
 // DialogCancelMsg is sent when dialog is cancelled
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
 )
 
 // Test helpers for creating key messages in bubbletea v2
@@ -127,6 +128,47 @@ func TestDialogModel_Update_Enter_WithValue(t *testing.T) {
 	}
 }
 
+func TestNewWorkstreamDialog_TemplatePicker(t *testing.T) {
+	d := NewWorkstreamDialog()
+	d.SetSize(70, 15)
+	if strings.Contains(d.View(), "Template:") {
+		t.Error("template picker should be hidden without templates")
+	}
+
+	d.SetTemplates([]docker.TemplateConfig{{Name: "bugfix"}, {Name: "docs", Description: "Documentation only"}})
+	if d.SelectedTemplate() != "" {
+		t.Errorf("SelectedTemplate() = %q, want none by default", d.SelectedTemplate())
+	}
+	if !strings.Contains(d.View(), "Template:") {
+		t.Error("template picker should be shown")
+	}
+
+	d, _ = d.Update(dSpecialKey(tea.KeyTab))
+	d, _ = d.Update(dSpecialKey(tea.KeyTab))
+	if d.SelectedTemplate() != "docs" {
+		t.Errorf("SelectedTemplate() = %q, want %q", d.SelectedTemplate(), "docs")
+	}
+	if !strings.Contains(d.View(), "Documentation only") {
+		t.Error("picker should show the template description")
+	}
+
+	d.TextArea.SetValue("update the README")
+	_, cmd := d.Update(dSpecialKey(tea.KeyEnter))
+	if cmd == nil {
+		t.Fatal("Should return a command on enter with value")
+	}
+	confirmMsg, ok := cmd().(DialogConfirmMsg)
+	if !ok || confirmMsg.Template != "docs" {
+		t.Errorf("expected confirm with template docs, got %+v", cmd())
+	}
+
+	// Tab wraps back to no template
+	d, _ = d.Update(dSpecialKey(tea.KeyTab))
+	if d.SelectedTemplate() != "" {
+		t.Errorf("SelectedTemplate() = %q, want none after wrapping", d.SelectedTemplate())
+	}
+}
+
 func TestDialogModel_Update_DestroyConfirm_WrongWord(t *testing.T) {
 	d := NewDestroyDialog("test-branch", "ws-123")
 	d.Input.SetValue("delete") // Wrong word
@@ -1592,9 +1634,9 @@ func TestNewMergeDialog_NoPR(t *testing.T) {
 		t.Error("Type should be DialogMerge")
 	}
 
-	// Without PR, order should be: squash, merge, create PR, push, rebase, cancel
-	if len(d.MenuItems) != 6 {
-		t.Errorf("Should have 6 menu items without PR, got %d", len(d.MenuItems))
+	// Without PR, order should be: squash, merge, create PR, push, rebase, clean up history, cancel
+	if len(d.MenuItems) != 7 {
+		t.Errorf("Should have 7 menu items without PR, got %d", len(d.MenuItems))
 	}
 	if !strings.Contains(d.MenuItems[0], "squash") {
 		t.Errorf("First item should be squash, got %s", d.MenuItems[0])
@@ -1605,6 +1647,9 @@ func TestNewMergeDialog_NoPR(t *testing.T) {
 	if !strings.Contains(d.MenuItems[3], "Push branch only") {
 		t.Errorf("Fourth item should be Push branch only, got %s", d.MenuItems[3])
 	}
+	if !strings.Contains(d.MenuItems[5], "Clean up history") {
+		t.Errorf("Sixth item should be Clean up history, got %s", d.MenuItems[5])
+	}
 }
 
 func TestNewMergeDialog_WithPR(t *testing.T) {
@@ -1618,16 +1663,17 @@ func TestNewMergeDialog_WithPR(t *testing.T) {
 	// 0. Push to open PR
 	// 1. Rebase on main
 	// 2. Force push
-	// 3. Separator (for GitHub merge options)
-	// 4. Merge PR via GitHub (squash)
-	// 5. Merge PR via GitHub (merge)
-	// 6. Merge PR via GitHub (rebase)
-	// 7. Separator (for local merge options)
-	// 8. Merge into main locally (squash)
-	// 9. Merge into main locally (merge)
-	// 10. Cancel
-	if len(d.MenuItems) != 11 {
-		t.Errorf("Should have 11 menu items with PR, got %d", len(d.MenuItems))
+	// 3. Clean up history with Claude
+	// 4. Separator (for GitHub merge options)
+	// 5. Merge PR via GitHub (squash)
+	// 6. Merge PR via GitHub (merge)
+	// 7. Merge PR via GitHub (rebase)
+	// 8. Separator (for local merge options)
+	// 9. Merge into main locally (squash)
+	// 10. Merge into main locally (merge)
+	// 11. Cancel
+	if len(d.MenuItems) != 12 {
+		t.Errorf("Should have 12 menu items with PR, got %d", len(d.MenuItems))
 	}
 	if !strings.Contains(d.MenuItems[0], "Push into open PR") {
 		t.Errorf("First item should be Push into open PR, got %s", d.MenuItems[0])
@@ -1639,13 +1685,16 @@ func TestNewMergeDialog_WithPR(t *testing.T) {
 	if !strings.Contains(d.MenuItems[2], "Force push") {
 		t.Errorf("Third item should be Force push, got %s", d.MenuItems[2])
 	}
-	// Fourth item should be separator
-	if !strings.HasPrefix(d.MenuItems[3], "───") {
-		t.Errorf("Fourth item should be separator, got %s", d.MenuItems[3])
+	if !strings.Contains(d.MenuItems[3], "Clean up history") {
+		t.Errorf("Fourth item should be Clean up history, got %s", d.MenuItems[3])
+	}
+	// Fifth item should be separator
+	if !strings.HasPrefix(d.MenuItems[4], "───") {
+		t.Errorf("Fifth item should be separator, got %s", d.MenuItems[4])
 	}
-	// Fifth item should be GitHub merge squash
-	if !strings.Contains(d.MenuItems[4], "Merge PR via GitHub (squash)") {
-		t.Errorf("Fifth item should be Merge PR via GitHub (squash), got %s", d.MenuItems[4])
+	// Sixth item should be GitHub merge squash
+	if !strings.Contains(d.MenuItems[5], "Merge PR via GitHub (squash)") {
+		t.Errorf("Sixth item should be Merge PR via GitHub (squash), got %s", d.MenuItems[5])
 	}
 }
 
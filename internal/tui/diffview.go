@@ -0,0 +1,613 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// diffTreeWidth is the width of the file tree column in the diff viewer.
+const diffTreeWidth = 38
+
+// diffFile is one file of a unified diff.
+type diffFile struct {
+	Path        string
+	Status      string // "A" added, "D" deleted, "M" modified, "R" renamed, "?" untracked
+	Added       int
+	Removed     int
+	Lines       []string // Hunks, starting at the first "@@" line
+	Uncommitted bool
+}
+
+// DiffLoadedMsg carries a workstream's branch diff against its merge base.
+type DiffLoadedMsg struct {
+	WorkstreamID string
+	MergeBase    string
+	Commits      []git.Commit // Oldest first
+	Committed    []diffFile
+	Uncommitted  []diffFile // Uncommitted and untracked changes in the worktree
+	Error        error
+}
+
+// DiffRangeMsg carries the diff of a commit range selected in the viewer.
+type DiffRangeMsg struct {
+	WorkstreamID string
+	From, To     int // Commit indices, inclusive
+	Files        []diffFile
+	Error        error
+}
+
+// diffView is the state of the diff viewer dialog.
+type diffView struct {
+	worktree    string
+	mergeBase   string
+	commits     []git.Commit
+	from, to    int // Selected commit range, inclusive
+	committed   []diffFile
+	uncommitted []diffFile
+	selected    int  // Index into files()
+	focusDiff   bool // Keys move the line cursor instead of the file selection
+	cursor      int  // Diff line under the cursor
+	scroll      int  // First diff line shown
+	loading     bool
+	err         error
+	// Review comments of the workstream and the comment being typed
+	comments   []workstream.ReviewComment
+	commenting bool
+}
+
+// files returns the committed files followed by the uncommitted ones.
+func (v *diffView) files() []diffFile {
+	return append(append([]diffFile{}, v.committed...), v.uncommitted...)
+}
+
+// parseUnifiedDiff splits the output of git diff into files.
+func parseUnifiedDiff(text string, uncommitted bool) []diffFile {
+	var files []diffFile
+	var cur *diffFile
+	inHunks := false
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			files = append(files, diffFile{Status: "M", Uncommitted: uncommitted})
+			cur = &files[len(files)-1]
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				cur.Path = line[i+3:]
+			}
+			inHunks = false
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if !inHunks {
+			switch {
+			case strings.HasPrefix(line, "new file mode"):
+				cur.Status = "A"
+			case strings.HasPrefix(line, "deleted file mode"):
+				cur.Status = "D"
+			case strings.HasPrefix(line, "rename to "):
+				cur.Status = "R"
+				cur.Path = strings.TrimPrefix(line, "rename to ")
+			case strings.HasPrefix(line, "+++ b/"):
+				cur.Path = strings.TrimPrefix(line, "+++ b/")
+			case strings.HasPrefix(line, "Binary files"):
+				cur.Lines = append(cur.Lines, line)
+			case strings.HasPrefix(line, "@@"):
+				inHunks = true
+			}
+			if !inHunks {
+				continue
+			}
+		}
+		cur.Lines = append(cur.Lines, line)
+		switch {
+		case strings.HasPrefix(line, "+"):
+			cur.Added++
+		case strings.HasPrefix(line, "-"):
+			cur.Removed++
+		}
+	}
+	return files
+}
+
+// LoadDiffCmd returns a command that reads a workstream's changes from its
+// worktree: the branch diff against its merge base, its commits, and any
+// uncommitted or untracked files.
+func LoadDiffCmd(ws *workstream.Workstream) tea.Cmd {
+	worktree := resolveWorktreePath(ws)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		msg := DiffLoadedMsg{WorkstreamID: ws.ID}
+		if worktree == "" {
+			msg.Error = fmt.Errorf("no worktree path")
+			return msg
+		}
+		g := GitClientFactory(worktree)
+		if msg.MergeBase, msg.Error = g.MergeBase(ctx, ws.BranchName); msg.Error != nil {
+			return msg
+		}
+		if msg.Commits, msg.Error = g.BranchCommits(ctx, ws.BranchName); msg.Error != nil {
+			return msg
+		}
+		committed, err := g.Diff(ctx, msg.MergeBase, ws.BranchName)
+		if err != nil {
+			msg.Error = err
+			return msg
+		}
+		msg.Committed = parseUnifiedDiff(committed, false)
+
+		if uncommitted, err := g.Diff(ctx, "HEAD", ""); err == nil {
+			msg.Uncommitted = parseUnifiedDiff(uncommitted, true)
+		} else {
+			LogDebug("Diff viewer: cannot read uncommitted changes of %s: %v", ws.BranchName, err)
+		}
+		untracked, _ := g.GetUntrackedFiles(ctx)
+		for _, f := range untracked {
+			msg.Uncommitted = append(msg.Uncommitted, diffFile{Path: f, Status: "?", Uncommitted: true, Lines: []string{"(untracked file)"}})
+		}
+		return msg
+	}
+}
+
+// loadDiffRangeCmd returns a command that diffs the commits from..to
+// (inclusive indices into commits).
+func loadDiffRangeCmd(workstreamID, worktree, mergeBase string, commits []git.Commit, from, to int) tea.Cmd {
+	fromRef := mergeBase
+	if from > 0 {
+		fromRef = commits[from-1].Hash
+	}
+	toRef := commits[to].Hash
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		out, err := GitClientFactory(worktree).Diff(ctx, fromRef, toRef)
+		return DiffRangeMsg{WorkstreamID: workstreamID, From: from, To: to, Files: parseUnifiedDiff(out, false), Error: err}
+	}
+}
+
+// NewDiffDialog creates the diff viewer for a workstream. Its contents arrive
+// with DiffLoadedMsg.
+func NewDiffDialog(ws *workstream.Workstream) DialogModel {
+	input := newOptionalInput("comment, then Enter", "")
+	input.CharLimit = reviewCommentMaxLen
+	return DialogModel{
+		Type:         DialogDiff,
+		Title:        fmt.Sprintf("Diff: %s", ws.BranchName),
+		WorkstreamID: ws.ID,
+		Input:        input,
+		diff: diffView{
+			worktree: resolveWorktreePath(ws),
+			loading:  true,
+			comments: ws.GetReviewComments(),
+		},
+	}
+}
+
+// SetDiff fills the diff viewer with a loaded diff, selecting all commits.
+func (d *DialogModel) SetDiff(msg DiffLoadedMsg) {
+	v := &d.diff
+	v.loading, v.err = false, msg.Error
+	v.mergeBase, v.commits = msg.MergeBase, msg.Commits
+	v.from, v.to = 0, len(msg.Commits)-1
+	v.committed, v.uncommitted = msg.Committed, msg.Uncommitted
+	v.selected, v.cursor, v.scroll = 0, 0, 0
+}
+
+// SetDiffRange replaces the committed files with the diff of a commit range.
+func (d *DialogModel) SetDiffRange(msg DiffRangeMsg) {
+	v := &d.diff
+	if msg.From != v.from || msg.To != v.to {
+		return // Superseded by a newer selection
+	}
+	v.loading, v.err = false, msg.Error
+	v.committed = msg.Files
+	v.selected, v.cursor, v.scroll = 0, 0, 0
+}
+
+// diffPageSize returns the number of diff lines visible at once.
+func (d *DialogModel) diffPageSize() int {
+	return max(d.height-10, 5)
+}
+
+// updateDiff handles a key press in the diff viewer. It reports false for
+// keys the dialog handles itself (closing it).
+func (d *DialogModel) updateDiff(msg tea.KeyMsg) (tea.Cmd, bool) {
+	v := &d.diff
+	key := msg.String()
+	if v.commenting {
+		switch key {
+		case "esc":
+			v.commenting = false
+		case "enter":
+			v.commenting = false
+			return d.addReviewComment(strings.TrimSpace(d.Input.Value())), true
+		default:
+			var cmd tea.Cmd
+			d.Input, cmd = d.Input.Update(msg)
+			return cmd, true
+		}
+		return nil, true
+	}
+
+	workstreamID := d.WorkstreamID
+	switch key {
+	case "esc", "ctrl+c":
+		return nil, false
+	case "tab":
+		v.focusDiff = !v.focusDiff
+	case "up", "k":
+		if v.focusDiff {
+			d.moveDiffCursor(-1)
+		} else if v.selected > 0 {
+			v.selected--
+			v.cursor, v.scroll = 0, 0
+		}
+	case "down", "j":
+		if v.focusDiff {
+			d.moveDiffCursor(1)
+		} else if v.selected < len(v.files())-1 {
+			v.selected++
+			v.cursor, v.scroll = 0, 0
+		}
+	case "pgup", "ctrl+u":
+		d.moveDiffCursor(-d.diffPageSize() / 2)
+	case "pgdown", "ctrl+d", "space":
+		d.moveDiffCursor(d.diffPageSize() / 2)
+	case "[":
+		return d.setDiffRange(v.from-1, v.to), true
+	case "]":
+		return d.setDiffRange(v.from+1, v.to), true
+	case "{":
+		return d.setDiffRange(v.from, v.to-1), true
+	case "}":
+		return d.setDiffRange(v.from, v.to+1), true
+	case "a":
+		return d.setDiffRange(0, len(v.commits)-1), true
+	case "c":
+		// Comment on the line under the cursor, or on the whole file
+		if v.selected < len(v.files()) {
+			v.commenting = true
+			d.Input.SetValue("")
+			d.Input.Focus()
+		}
+	case "x":
+		if c, ok := v.commentAtCursor(); ok {
+			id := c.ID
+			return func() tea.Msg { return ReviewAddressedMsg{WorkstreamID: workstreamID, CommentID: id} }, true
+		}
+	case "S":
+		for _, c := range v.comments {
+			if !c.Submitted() {
+				return func() tea.Msg { return ReviewSubmitMsg{WorkstreamID: workstreamID} }, true
+			}
+		}
+	}
+	return nil, true
+}
+
+// moveDiffCursor moves the line cursor and scrolls to keep it visible.
+func (d *DialogModel) moveDiffCursor(delta int) {
+	v := &d.diff
+	files := v.files()
+	if v.selected >= len(files) || len(files[v.selected].Lines) == 0 {
+		return
+	}
+	f := files[v.selected]
+	v.cursor = max(0, min(v.cursor+delta, len(f.Lines)-1))
+	if v.cursor < v.scroll {
+		v.scroll = v.cursor
+	}
+	// Rows above the diff lines: the title and whole-file comments
+	rows := 1 + len(v.lineComments(f, diffLineRef{}))
+	refs := diffLineRefs(f.Lines)
+	for v.scroll < v.cursor {
+		used := rows
+		for i := v.scroll; i <= v.cursor; i++ {
+			used += 1 + len(v.lineComments(f, refs[i]))
+		}
+		if used <= d.diffPageSize() {
+			break
+		}
+		v.scroll++
+	}
+}
+
+// commentTarget returns where a new comment is anchored: the line under the
+// cursor when the diff has focus, otherwise the whole file.
+func (v *diffView) commentTarget() (diffFile, diffLineRef, string) {
+	f := v.files()[v.selected]
+	if !v.focusDiff || v.cursor >= len(f.Lines) {
+		return f, diffLineRef{}, ""
+	}
+	ref := diffLineRefs(f.Lines)[v.cursor]
+	if ref.Line == 0 {
+		return f, ref, ""
+	}
+	return f, ref, f.Lines[v.cursor]
+}
+
+// addReviewComment returns the command recording a comment at the cursor.
+func (d *DialogModel) addReviewComment(body string) tea.Cmd {
+	if body == "" {
+		return nil
+	}
+	f, ref, code := d.diff.commentTarget()
+	comment := workstream.ReviewComment{File: f.Path, Line: ref.Line, Removed: ref.Removed, Code: code, Body: body}
+	workstreamID := d.WorkstreamID
+	return func() tea.Msg { return ReviewCommentMsg{WorkstreamID: workstreamID, Comment: comment} }
+}
+
+// lineComments returns the comments left on a line of a file (ref.Line 0
+// selects the comments on the whole file).
+func (v *diffView) lineComments(f diffFile, ref diffLineRef) []workstream.ReviewComment {
+	var comments []workstream.ReviewComment
+	for _, c := range v.comments {
+		if c.File == f.Path && c.Line == ref.Line && (ref.Line == 0 || c.Removed == ref.Removed) {
+			comments = append(comments, c)
+		}
+	}
+	return comments
+}
+
+// commentAtCursor returns the comment "x" toggles: the latest one on the
+// cursor line, or with the file tree focused, the latest open one on the file.
+func (v *diffView) commentAtCursor() (workstream.ReviewComment, bool) {
+	files := v.files()
+	if v.selected >= len(files) {
+		return workstream.ReviewComment{}, false
+	}
+	f := files[v.selected]
+	var candidates []workstream.ReviewComment
+	if v.focusDiff {
+		_, ref, _ := v.commentTarget()
+		candidates = v.lineComments(f, ref)
+	} else {
+		for _, c := range v.comments {
+			if c.File == f.Path {
+				candidates = append(candidates, c)
+			}
+		}
+		for i := len(candidates) - 1; i >= 0; i-- {
+			if !candidates[i].Addressed {
+				return candidates[i], true
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return workstream.ReviewComment{}, false
+	}
+	return candidates[len(candidates)-1], true
+}
+
+// setDiffRange selects the commits from..to and reloads the committed diff.
+func (d *DialogModel) setDiffRange(from, to int) tea.Cmd {
+	v := &d.diff
+	if v.loading || len(v.commits) == 0 || from < 0 || to >= len(v.commits) || from > to || (from == v.from && to == v.to) {
+		return nil
+	}
+	v.from, v.to = from, to
+	v.loading = true
+	return loadDiffRangeCmd(d.WorkstreamID, v.worktree, v.mergeBase, v.commits, from, to)
+}
+
+// rangeLabel describes the selected commit range.
+func (v *diffView) rangeLabel() string {
+	if len(v.commits) == 0 {
+		return "No commits ahead of the base branch"
+	}
+	if v.from == 0 && v.to == len(v.commits)-1 {
+		return fmt.Sprintf("All %d commit(s) since the merge base", len(v.commits))
+	}
+	return fmt.Sprintf("Commits %d-%d of %d: %s..%s", v.from+1, v.to+1, len(v.commits), v.commits[v.from].Hash, v.commits[v.to].Hash)
+}
+
+// treeLines renders the commit selector and the file tree, returning the
+// lines and the line index of each file.
+func (v *diffView) treeLines() ([]string, []int) {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+	heading := lipgloss.NewStyle().Bold(true)
+
+	var lines []string
+	lines = append(lines, heading.Render("Commits"))
+	for i, c := range v.commits {
+		mark, style := "  ", dim
+		if i >= v.from && i <= v.to {
+			mark, style = "▌ ", lipgloss.NewStyle()
+		}
+		lines = append(lines, style.Render(mark+c.Hash+" "+c.Subject))
+	}
+	if len(v.commits) == 0 {
+		lines = append(lines, dim.Render("  (none)"))
+	}
+
+	var fileLines []int
+	section := func(title string, files []diffFile) {
+		lines = append(lines, "", heading.Render(title))
+		if len(files) == 0 {
+			lines = append(lines, dim.Render("  (none)"))
+			return
+		}
+		var prevDirs []string
+		for _, f := range files {
+			dirs := strings.Split(path.Dir(f.Path), "/")
+			if dirs[0] == "." {
+				dirs = nil
+			}
+			// Print the directories not shared with the previous file
+			common := 0
+			for common < len(dirs) && common < len(prevDirs) && dirs[common] == prevDirs[common] {
+				common++
+			}
+			for depth := common; depth < len(dirs); depth++ {
+				lines = append(lines, dim.Render(strings.Repeat("  ", depth+1)+dirs[depth]+"/"))
+			}
+			prevDirs = dirs
+			fileLines = append(fileLines, len(lines))
+			line := fmt.Sprintf("%s%s %s %s", strings.Repeat("  ", len(dirs)+1), f.Status, path.Base(f.Path), diffStatLabel(f))
+			if n := v.openComments(f.Path); n > 0 {
+				line += fmt.Sprintf(" ●%d", n)
+			}
+			lines = append(lines, line)
+		}
+	}
+	section("Files", v.committed)
+	section("Uncommitted changes", v.uncommitted)
+
+	if len(v.comments) > 0 {
+		lines = append(lines, "", heading.Render("Review"))
+		for _, c := range v.comments {
+			lines = append(lines, "  "+reviewMarker(c)+" "+reviewLocation(c)+" "+dim.Render(c.Body))
+		}
+	}
+	return lines, fileLines
+}
+
+// openComments counts the comments on a file that are not yet addressed.
+func (v *diffView) openComments(file string) int {
+	n := 0
+	for _, c := range v.comments {
+		if c.File == file && !c.Addressed {
+			n++
+		}
+	}
+	return n
+}
+
+// diffStatLabel renders a file's added and removed line counts.
+func diffStatLabel(f diffFile) string {
+	if f.Added == 0 && f.Removed == 0 {
+		return ""
+	}
+	return diffAddStyle.Render(fmt.Sprintf("+%d", f.Added)) + " " + diffDelStyle.Render(fmt.Sprintf("-%d", f.Removed))
+}
+
+var (
+	diffAddStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#22C55E"))
+	diffDelStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444"))
+	diffHunkStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8"))
+)
+
+// colorDiffLine colors a unified diff line, truncated to width.
+func colorDiffLine(line string, width int) string {
+	line = ansi.Truncate(strings.ReplaceAll(line, "\t", "    "), width, "…")
+	switch {
+	case strings.HasPrefix(line, "+"):
+		return diffAddStyle.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return diffDelStyle.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return diffHunkStyle.Render(line)
+	}
+	return line
+}
+
+// renderDiffView renders the file tree next to the selected file's diff.
+func renderDiffView(v *diffView, width, height int) string {
+	if v.err != nil {
+		return fmt.Sprintf("Cannot read the diff: %v", v.err)
+	}
+	if v.loading && v.commits == nil && v.committed == nil {
+		return "Loading..."
+	}
+
+	tree, fileLines := v.treeLines()
+	files := v.files()
+	selectedStyle := lipgloss.NewStyle().Reverse(true)
+	// Keep the selected file visible in the tree
+	treeStart := 0
+	if v.selected < len(fileLines) && fileLines[v.selected] >= height {
+		treeStart = fileLines[v.selected] - height + 1
+	}
+
+	var diffLines []string
+	diffWidth := width - diffTreeWidth - 3
+	if v.selected < len(files) {
+		f := files[v.selected]
+		title := f.Path
+		if f.Uncommitted {
+			title += " (uncommitted)"
+		}
+		diffLines = append(diffLines, lipgloss.NewStyle().Bold(true).Render(ansi.Truncate(title, diffWidth, "…")))
+		commentRow := func(c workstream.ReviewComment) string {
+			return "    " + reviewMarker(c) + " " + ansi.Truncate(c.Body, diffWidth-6, "…")
+		}
+		for _, c := range v.lineComments(f, diffLineRef{}) {
+			diffLines = append(diffLines, commentRow(c))
+		}
+		refs := diffLineRefs(f.Lines)
+		for i := v.scroll; i < len(f.Lines) && len(diffLines) < height; i++ {
+			comments := v.lineComments(f, refs[i])
+			gutter := "  "
+			if len(comments) > 0 {
+				gutter = reviewMarker(comments[len(comments)-1]) + " "
+			}
+			line := colorDiffLine(f.Lines[i], diffWidth-2)
+			if v.focusDiff && i == v.cursor {
+				line = selectedStyle.Render(ansi.Truncate(strings.ReplaceAll(f.Lines[i], "\t", "    "), diffWidth-2, "…"))
+			}
+			diffLines = append(diffLines, gutter+line)
+			for _, c := range comments {
+				diffLines = append(diffLines, commentRow(c))
+			}
+		}
+	} else {
+		diffLines = append(diffLines, "No changes.")
+	}
+	if v.loading {
+		diffLines[0] += "  " + KeyHintStyle.Render("(loading...)")
+	}
+
+	var b strings.Builder
+	for row := 0; row < height; row++ {
+		left := ""
+		if i := treeStart + row; i < len(tree) {
+			left = ansi.Truncate(tree[i], diffTreeWidth, "…")
+			if v.selected < len(fileLines) && i == fileLines[v.selected] && !v.focusDiff {
+				left = selectedStyle.Render(ansi.Strip(left))
+			}
+		}
+		right := ""
+		if row < len(diffLines) {
+			right = diffLines[row]
+		}
+		b.WriteString(left + strings.Repeat(" ", max(diffTreeWidth-ansi.StringWidth(left), 0)) + " │ " + right)
+		if row < height-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// viewDiff renders the diff viewer dialog.
+func (d DialogModel) viewDiff() string {
+	var content strings.Builder
+	content.WriteString(DialogTitle.Render(d.Title))
+	content.WriteString("\n")
+	content.WriteString(d.diff.rangeLabel())
+	if summary := reviewSummary(d.diff.comments); summary != "" {
+		content.WriteString("   " + summary)
+	}
+	content.WriteString("\n\n")
+	content.WriteString(renderDiffView(&d.diff, d.width-8, d.diffPageSize()))
+	content.WriteString("\n\n")
+	if d.diff.commenting {
+		f, ref, _ := d.diff.commentTarget()
+		location := reviewLocation(workstream.ReviewComment{File: f.Path, Line: ref.Line, Removed: ref.Removed})
+		content.WriteString(fmt.Sprintf("Comment on %s %s", location, d.Input.View()))
+	} else {
+		content.WriteString(KeyHint("Tab", " files/lines") + "  " + KeyHint("↑/↓", " move") + "  " + KeyHint("PgUp/PgDn", " scroll") + "  " +
+			KeyHint("c", " comment") + "  " + KeyHint("x", " addressed") + "  " + KeyHint("S", " send review") + "  " +
+			KeyHint("[ ] { }", " range") + "  " + KeyHint("a", " all") + "  " + KeyHintStyle.Render("[Esc] Close"))
+	}
+	return DialogBox.Width(d.width).Render(content.String())
+}
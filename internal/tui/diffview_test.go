@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const sampleDiff = `diff --git a/internal/api/handler.go b/internal/api/handler.go
+index 1111111..2222222 100644
+--- a/internal/api/handler.go
++++ b/internal/api/handler.go
+@@ -1,3 +1,4 @@
+ package api
+-func old() {}
++func handler() {}
++func helper() {}
+diff --git a/internal/api/new.go b/internal/api/new.go
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/internal/api/new.go
+@@ -0,0 +1 @@
++package api
+diff --git a/README.md b/README.md
+deleted file mode 100644
+index 4444444..0000000
+--- a/README.md
++++ /dev/null
+@@ -1 +0,0 @@
+-# Old`
+
+func TestParseUnifiedDiff(t *testing.T) {
+	files := parseUnifiedDiff(sampleDiff, false)
+	if len(files) != 3 {
+		t.Fatalf("got %d files, want 3", len(files))
+	}
+	want := []struct {
+		path, status   string
+		added, removed int
+	}{
+		{"internal/api/handler.go", "M", 2, 1},
+		{"internal/api/new.go", "A", 1, 0},
+		{"README.md", "D", 0, 1},
+	}
+	for i, w := range want {
+		f := files[i]
+		if f.Path != w.path || f.Status != w.status || f.Added != w.added || f.Removed != w.removed {
+			t.Errorf("file %d = %s %s +%d -%d, want %s %s +%d -%d", i, f.Status, f.Path, f.Added, f.Removed, w.status, w.path, w.added, w.removed)
+		}
+		if !strings.HasPrefix(f.Lines[0], "@@") {
+			t.Errorf("file %d lines should start at the hunk header, got %q", i, f.Lines[0])
+		}
+	}
+}
+
+func TestLoadDiffCmd(t *testing.T) {
+	var diffs [][2]string
+	restore := SetGitClientFactory(func(path string) git.GitClient {
+		mock := git.NewMockGitClient()
+		mock.MergeBaseFn = func(ctx context.Context, branch string) (string, error) { return "base123", nil }
+		mock.BranchCommitsFn = func(ctx context.Context, branch string) ([]git.Commit, error) {
+			return []git.Commit{{Hash: "aaa", Subject: "first"}, {Hash: "bbb", Subject: "second"}}, nil
+		}
+		mock.DiffFn = func(ctx context.Context, from, to string) (string, error) {
+			diffs = append(diffs, [2]string{from, to})
+			if to == "" {
+				return "diff --git a/wip.go b/wip.go\n--- a/wip.go\n+++ b/wip.go\n@@ -1 +1 @@\n-a\n+b", nil
+			}
+			return sampleDiff, nil
+		}
+		mock.GetUntrackedFilesFn = func(ctx context.Context) ([]string, error) { return []string{"notes.txt"}, nil }
+		return mock
+	})
+	defer restore()
+
+	ws := workstream.NewWithID("1", "feature", "task")
+	msg := LoadDiffCmd(ws)().(DiffLoadedMsg)
+	if msg.Error != nil {
+		t.Fatalf("LoadDiffCmd() error = %v", msg.Error)
+	}
+	if diffs[0] != [2]string{"base123", "feature"} || diffs[1] != [2]string{"HEAD", ""} {
+		t.Errorf("diffed %v, want the branch against its merge base, then the worktree", diffs)
+	}
+	if len(msg.Committed) != 3 || len(msg.Uncommitted) != 2 || msg.Uncommitted[1].Status != "?" {
+		t.Errorf("got %d committed and %+v uncommitted files", len(msg.Committed), msg.Uncommitted)
+	}
+
+	// The dialog shows both sections and reloads when the range changes
+	d := NewDiffDialog(ws)
+	d.SetSize(140, 40)
+	d.SetDiff(msg)
+	view := ansi.Strip(d.View())
+	for _, want := range []string{"All 2 commit(s)", "api/", "M handler.go", "Uncommitted changes", "? notes.txt", "+func handler() {}"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	d, _ = d.Update(specialKey(tea.KeyDown))
+	if !strings.Contains(ansi.Strip(d.View()), "internal/api/new.go") {
+		t.Error("moving down should show the next file's diff")
+	}
+
+	d, cmd := d.Update(keyPress(']'))
+	if cmd == nil {
+		t.Fatal("narrowing the range should reload the diff")
+	}
+	rangeMsg := cmd().(DiffRangeMsg)
+	if got := diffs[len(diffs)-1]; got != [2]string{"aaa", "bbb"} {
+		t.Errorf("range diff = %v, want aaa..bbb", got)
+	}
+	d.SetDiffRange(rangeMsg)
+	if label := d.diff.rangeLabel(); !strings.Contains(label, "Commits 2-2 of 2") {
+		t.Errorf("rangeLabel() = %q", label)
+	}
+	if _, cmd := d.Update(keyPress('{')); cmd != nil {
+		t.Error("the range cannot end before it starts")
+	}
+}
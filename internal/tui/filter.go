@@ -0,0 +1,359 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// paneFilter selects which panes are shown in nav mode.
+// Panes that don't match keep running in the background.
+type paneFilter struct {
+	query  string             // Raw query as typed
+	labels []string           // label:<name> terms (all must match)
+	states []workstream.State // state:<state> terms (any may match)
+	terms  []string           // Free text terms (all must match)
+}
+
+// parsePaneFilter parses a filter query. Terms are separated by spaces:
+// "label:ui" (or "#ui") matches a label, "state:running" matches a state,
+// and any other word matches the title, branch, prompt or labels.
+func parsePaneFilter(query string) paneFilter {
+	f := paneFilter{query: strings.TrimSpace(query)}
+	for _, term := range strings.Fields(strings.ToLower(f.query)) {
+		switch {
+		case strings.HasPrefix(term, "label:"):
+			if l := strings.TrimPrefix(term, "label:"); l != "" {
+				f.labels = append(f.labels, l)
+			}
+		case strings.HasPrefix(term, "#") && len(term) > 1:
+			f.labels = append(f.labels, term[1:])
+		case strings.HasPrefix(term, "state:"):
+			if s := strings.TrimPrefix(term, "state:"); s != "" {
+				f.states = append(f.states, workstream.State(s))
+			}
+		default:
+			f.terms = append(f.terms, term)
+		}
+	}
+	return f
+}
+
+// Active reports whether the filter hides anything.
+func (f paneFilter) Active() bool {
+	return f.query != ""
+}
+
+// Matches reports whether a workstream passes the filter.
+func (f paneFilter) Matches(ws *workstream.Workstream) bool {
+	for _, l := range f.labels {
+		if !ws.HasLabel(l) {
+			return false
+		}
+	}
+	if len(f.states) > 0 {
+		state := ws.GetState()
+		found := false
+		for _, s := range f.states {
+			if s == state {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.terms) > 0 {
+		haystack := strings.ToLower(strings.Join(append([]string{ws.GetTitle(), ws.BranchName, ws.Prompt}, ws.GetLabels()...), "\n"))
+		for _, t := range f.terms {
+			if !strings.Contains(haystack, t) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// priorityColors colors priority badges from most to least urgent.
+var priorityColors = []string{"#EF4444", "#F97316", "#EAB308", "#22C55E", "#6B7280"}
+
+// labelBadges renders a workstream's priority and labels for the pane header.
+func labelBadges(ws *workstream.Workstream) string {
+	var parts []string
+	if p := ws.GetPriority(); p > 0 {
+		color := priorityColors[min(p, len(priorityColors))-1]
+		parts = append(parts, lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(fmt.Sprintf("P%d", p)))
+	}
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#A78BFA"))
+	for _, l := range ws.GetLabels() {
+		parts = append(parts, labelStyle.Render("#"+l))
+	}
+	return strings.Join(parts, " ")
+}
+
+// visiblePaneIndices returns the indices of the panes that pass the filter,
+// in display order.
+func (m AppModel) visiblePaneIndices() []int {
+	indices := make([]int, 0, len(m.panes))
+	for i := range m.panes {
+		if m.paneFilter.Matches(m.panes[i].Workstream()) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// isPaneVisible reports whether the pane at index i passes the filter.
+func (m AppModel) isPaneVisible(i int) bool {
+	return i >= 0 && i < len(m.panes) && m.paneFilter.Matches(m.panes[i].Workstream())
+}
+
+// applyPaneFilter sets the pane filter and moves focus to a visible pane.
+func (m *AppModel) applyPaneFilter(query string) {
+	m.paneFilter = parsePaneFilter(query)
+	if len(m.panes) > 0 && !m.isPaneVisible(m.focusedPane) {
+		if visible := m.visiblePaneIndices(); len(visible) > 0 {
+			m.panes[m.focusedPane].SetFocused(false)
+			m.setFocusedPane(visible[0])
+			m.panes[m.focusedPane].SetFocused(true)
+		}
+	}
+	m.lastSwapPosition = 0
+	m.updateLayout()
+}
+
+// revealPane clears the filter if it hides the pane at index i, so a newly
+// created pane is never focused while hidden.
+func (m *AppModel) revealPane(i int) {
+	if m.paneFilter.Active() && !m.isPaneVisible(i) {
+		m.paneFilter = paneFilter{}
+	}
+}
+
+// paneSortMode is how panes are ordered by the sort key.
+type paneSortMode int
+
+const (
+	paneSortCreated  paneSortMode = iota // Creation order (default)
+	paneSortPriority                     // Most urgent first, unset last
+	paneSortActivity                     // Most recently active first
+)
+
+// String returns the sort mode name for display.
+func (s paneSortMode) String() string {
+	switch s {
+	case paneSortPriority:
+		return "priority"
+	case paneSortActivity:
+		return "last activity"
+	default:
+		return "created"
+	}
+}
+
+// Next returns the next sort mode in the cycle.
+func (s paneSortMode) Next() paneSortMode {
+	return (s + 1) % 3
+}
+
+// paneLess reports whether workstream a sorts before b in the given mode.
+func paneLess(mode paneSortMode, a, b *workstream.Workstream) bool {
+	switch mode {
+	case paneSortPriority:
+		pa, pb := a.GetPriority(), b.GetPriority()
+		if pa == 0 {
+			pa = workstream.MaxPriority + 1
+		}
+		if pb == 0 {
+			pb = workstream.MaxPriority + 1
+		}
+		return pa < pb
+	case paneSortActivity:
+		return a.GetLastActivity().After(b.GetLastActivity())
+	default:
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+}
+
+// sortPanes reorders panes by the given mode, keeping focus on the same
+// workstream. Ties keep their current order.
+func (m *AppModel) sortPanes(mode paneSortMode) {
+	m.orderPanes(mode)
+	m.updateLayout()
+}
+
+// keepPanesSorted re-applies a priority or activity sort once panes are out
+// of order. It runs when a pane is added or its labels and priority are
+// edited, not on every activity change, so panes don't jump around while
+// the user navigates. In creation order, panes may be arranged by hand and
+// are left alone.
+func (m *AppModel) keepPanesSorted() {
+	if m.paneSort == paneSortCreated {
+		return
+	}
+	for i := 1; i < len(m.panes); i++ {
+		if paneLess(m.paneSort, m.panes[i].Workstream(), m.panes[i-1].Workstream()) {
+			m.orderPanes(m.paneSort)
+			// Quiet, like other layout changes the user didn't ask for
+			m.updateLayoutQuiet()
+			return
+		}
+	}
+}
+
+// orderPanes sorts the panes for sortPanes and keepPanesSorted.
+func (m *AppModel) orderPanes(mode paneSortMode) {
+	if len(m.panes) == 0 {
+		return
+	}
+	focusedID := ""
+	if m.focusedPane < len(m.panes) {
+		focusedID = m.panes[m.focusedPane].Workstream().ID
+	}
+
+	sort.SliceStable(m.panes, func(i, j int) bool {
+		return paneLess(mode, m.panes[i].Workstream(), m.panes[j].Workstream())
+	})
+
+	for i := range m.panes {
+		focused := m.panes[i].Workstream().ID == focusedID
+		if focused {
+			m.setFocusedPane(i)
+		}
+		m.panes[i].SetFocused(focused)
+	}
+	m.lastSwapPosition = 0
+}
+
+// filterBarHeight returns the number of lines the filter bar takes.
+func (m AppModel) filterBarHeight() int {
+	if len(m.panes) > 0 && (m.paneFilter.Active() || m.paneSort != paneSortCreated) {
+		return 1
+	}
+	return 0
+}
+
+// renderFilterBar renders the bar above the panes that shows the active
+// filter and sort for as long as either is set.
+func (m AppModel) renderFilterBar() string {
+	barStyle := lipgloss.NewStyle().Background(lipgloss.Color("#1F2937"))
+	keyStyle := barStyle.Foreground(lipgloss.Color("#FBBF24")).Bold(true)
+	textStyle := barStyle.Foreground(lipgloss.Color("#E5E7EB"))
+	dimStyle := barStyle.Foreground(lipgloss.Color("#9CA3AF"))
+
+	left := barStyle.Render(" ")
+	if m.paneFilter.Active() {
+		shown := len(m.visiblePaneIndices())
+		left += keyStyle.Render("/"+m.paneFilter.query) +
+			textStyle.Render(fmt.Sprintf("  %d of %d shown", shown, len(m.panes)))
+		if hidden := len(m.panes) - shown; hidden > 0 {
+			left += dimStyle.Render(fmt.Sprintf(", %d hidden still running", hidden))
+		}
+	}
+	if m.paneSort != paneSortCreated {
+		if m.paneFilter.Active() {
+			left += dimStyle.Render("  ·  ")
+		}
+		left += textStyle.Render("Sorted by ") + keyStyle.Render(m.paneSort.String())
+	}
+	right := keyStyle.Render("/") + textStyle.Render(" filter  ") + keyStyle.Render("o") + textStyle.Render(" sort ")
+
+	spacing := max(m.width-lipgloss.Width(left)-lipgloss.Width(right), 0)
+	return lipgloss.NewStyle().Width(m.width).MaxWidth(m.width).Background(lipgloss.Color("#1F2937")).
+		Render(left + barStyle.Render(strings.Repeat(" ", spacing)) + right)
+}
+
+// parseLabelInput parses the labels dialog input: space or comma separated
+// labels plus an optional priority written as P1-P5.
+func parseLabelInput(input string) (labels []string, priority int) {
+	seen := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return r == ' ' || r == ','
+	}) {
+		field = strings.TrimPrefix(field, "#")
+		if len(field) == 2 && field[0] == 'p' {
+			if n, err := strconv.Atoi(field[1:]); err == nil && n >= 1 && n <= workstream.MaxPriority {
+				priority = n
+				continue
+			}
+		}
+		if field != "" && !seen[field] {
+			seen[field] = true
+			labels = append(labels, field)
+		}
+	}
+	return labels, priority
+}
+
+// formatLabelInput formats labels and priority as parseLabelInput reads them.
+func formatLabelInput(labels []string, priority int) string {
+	parts := make([]string, 0, len(labels)+1)
+	if priority > 0 {
+		parts = append(parts, fmt.Sprintf("P%d", priority))
+	}
+	parts = append(parts, labels...)
+	return strings.Join(parts, " ")
+}
+
+// newOptionalInput creates a dialog text input that may be submitted empty.
+func newOptionalInput(placeholder, value string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.SetWidth(50)
+	ti.CharLimit = 200
+	ti.Prompt = "› "
+	ti.SetStyles(textinput.Styles{
+		Focused: textinput.StyleState{
+			Prompt:      DialogInputPrompt,
+			Text:        DialogInputText,
+			Placeholder: DialogInputPlaceholder,
+		},
+		Blurred: textinput.StyleState{
+			Prompt:      DialogInputPrompt,
+			Text:        DialogInputText,
+			Placeholder: DialogInputPlaceholder,
+		},
+	})
+	ti.SetValue(value)
+	ti.Focus()
+	return ti
+}
+
+// NewFilterDialog creates the pane filter dialog, prefilled with the current query.
+func NewFilterDialog(query string) DialogModel {
+	body := `Show only panes matching all terms:
+  label:ui or #ui    has label "ui"
+  state:running      is in the given state
+  any other word     in title, branch, prompt or labels
+
+Leave empty to show all panes.`
+
+	return DialogModel{
+		Type:       DialogFilter,
+		Title:      "Filter Panes",
+		Body:       body,
+		Input:      newOptionalInput("e.g. #backend state:idle", query),
+		allowEmpty: true,
+	}
+}
+
+// NewLabelsDialog creates the labels and priority dialog for a workstream.
+func NewLabelsDialog(ws *workstream.Workstream) DialogModel {
+	body := fmt.Sprintf(`Labels for %s, separated by spaces or commas.
+Add P1 (most urgent) to P%d to set a priority.`, ws.BranchName, workstream.MaxPriority)
+
+	return DialogModel{
+		Type:         DialogLabels,
+		Title:        "Labels & Priority",
+		Body:         body,
+		Input:        newOptionalInput("e.g. P2 backend auth", formatLabelInput(ws.GetLabels(), ws.GetPriority())),
+		WorkstreamID: ws.ID,
+		allowEmpty:   true,
+	}
+}
@@ -0,0 +1,246 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/workstream"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPaneFilter_Matches(t *testing.T) {
+	ws := workstream.NewWithID("1", "fix-login", "Fix the Safari login bug")
+	ws.SetTitle("Login fix")
+	ws.SetLabels([]string{"auth", "frontend"})
+	ws.SetState(workstream.StateIdle)
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"label:auth", true},
+		{"#frontend", true},
+		{"#auth #backend", false},
+		{"state:idle", true},
+		{"state:running state:idle", true},
+		{"state:running", false},
+		{"safari", true},
+		{"LOGIN", true},
+		{"safari chrome", false},
+		{"#auth state:idle login", true},
+	}
+	for _, tt := range tests {
+		if got := parsePaneFilter(tt.query).Matches(ws); got != tt.want {
+			t.Errorf("parsePaneFilter(%q).Matches() = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseLabelInput(t *testing.T) {
+	labels, priority := parseLabelInput("P2 backend, Auth #backend p9")
+	if priority != 2 {
+		t.Errorf("priority = %d, want 2", priority)
+	}
+	if want := []string{"backend", "auth", "p9"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("labels = %v, want %v", labels, want)
+	}
+
+	if got := formatLabelInput([]string{"backend", "auth"}, 2); got != "P2 backend auth" {
+		t.Errorf("formatLabelInput() = %q", got)
+	}
+	if labels, priority := parseLabelInput(""); labels != nil || priority != 0 {
+		t.Errorf("empty input should clear labels and priority, got %v, %d", labels, priority)
+	}
+}
+
+// newFilterTestApp returns a test app whose beta and gamma panes are
+// labelled "ui".
+func newFilterTestApp(t *testing.T) AppModel {
+	t.Helper()
+	app := newTestApp(t)
+	app.panes[1].Workstream().SetLabels([]string{"ui"})
+	app.panes[2].Workstream().SetLabels([]string{"ui"})
+	return app
+}
+
+func TestAppModel_FilterHidesPanes(t *testing.T) {
+	app := newFilterTestApp(t)
+
+	model, _ := app.Update(DialogConfirmMsg{Type: DialogFilter, Value: "#ui"})
+	app = model.(AppModel)
+	if got := app.visiblePaneIndices(); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Fatalf("visible panes = %v, want [1 2]", got)
+	}
+	if app.focusedPane != 1 {
+		t.Errorf("focus should move to the first visible pane, got %d", app.focusedPane)
+	}
+
+	// Tab cycles through visible panes only
+	model, _ = app.Update(specialKey(tea.KeyTab))
+	app = model.(AppModel)
+	if app.focusedPane != 2 {
+		t.Errorf("tab should focus pane 2, got %d", app.focusedPane)
+	}
+	model, _ = app.Update(specialKey(tea.KeyTab))
+	app = model.(AppModel)
+	if app.focusedPane != 1 {
+		t.Errorf("tab should wrap to pane 1, got %d", app.focusedPane)
+	}
+
+	// Number keys don't focus hidden panes
+	model, _ = app.Update(keyPress('1'))
+	app = model.(AppModel)
+	if app.focusedPane != 1 {
+		t.Errorf("hidden pane should not be focusable, focus = %d", app.focusedPane)
+	}
+
+	// Hidden panes are still managed (and keep running)
+	if app.manager.Count() != 3 {
+		t.Errorf("manager count = %d, want 3", app.manager.Count())
+	}
+
+	model, _ = app.Update(DialogConfirmMsg{Type: DialogFilter, Value: ""})
+	app = model.(AppModel)
+	if len(app.visiblePaneIndices()) != 3 {
+		t.Error("empty filter should show all panes")
+	}
+}
+
+func TestAppModel_LabelsDialogSetsLabelsAndPriority(t *testing.T) {
+	app := newFilterTestApp(t)
+	id := app.panes[0].Workstream().ID
+
+	model, _ := app.Update(keyPress('#'))
+	app = model.(AppModel)
+	if app.dialog == nil || app.dialog.Type != DialogLabels {
+		t.Fatalf("expected labels dialog, got %+v", app.dialog)
+	}
+
+	model, _ = app.Update(DialogConfirmMsg{Type: DialogLabels, WorkstreamID: id, Value: "P1 urgent"})
+	app = model.(AppModel)
+	ws := app.panes[0].Workstream()
+	if ws.GetPriority() != 1 || !ws.HasLabel("urgent") {
+		t.Errorf("labels = %v, priority = %d", ws.GetLabels(), ws.GetPriority())
+	}
+}
+
+func TestAppModel_SortPanes(t *testing.T) {
+	app := newFilterTestApp(t)
+	app.panes[2].Workstream().SetPriority(1)
+	app.panes[1].Workstream().SetPriority(3)
+	app.setFocusedPane(0)
+	alphaID := app.panes[0].Workstream().ID
+
+	app.sortPanes(paneSortPriority)
+	order := []string{app.panes[0].Workstream().ID, app.panes[1].Workstream().ID, app.panes[2].Workstream().ID}
+	if want := []string{"gamma", "beta", "alpha"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("priority order = %v, want %v", order, want)
+	}
+	if app.panes[app.focusedPane].Workstream().ID != alphaID {
+		t.Error("focus should stay on the same workstream after sorting")
+	}
+
+	app.panes[1].Workstream().LastActivity = time.Now().Add(time.Hour)
+	app.sortPanes(paneSortActivity)
+	if app.panes[0].Workstream().ID != "beta" {
+		t.Errorf("most recently active pane should be first, got %s", app.panes[0].Workstream().ID)
+	}
+}
+
+func TestAppModel_SortStaysApplied(t *testing.T) {
+	app := newFilterTestApp(t)
+	past := time.Now().Add(-time.Hour)
+	for i := range app.panes {
+		app.panes[i].Workstream().LastActivity = past.Add(time.Duration(i) * time.Minute)
+	}
+
+	// o cycles to priority (all unset, order kept), then to last activity
+	for range 2 {
+		model, _ := app.Update(keyPress('o'))
+		app = model.(AppModel)
+	}
+	if app.paneSort != paneSortActivity || app.panes[0].Workstream().ID != "gamma" {
+		t.Fatalf("sort = %s, first = %s; want gamma first by activity", app.paneSort, app.panes[0].Workstream().ID)
+	}
+
+	// alpha's session ends: activity alone doesn't move panes under the user
+	model, _ := app.Update(PTYClosedMsg{WorkstreamID: "alpha"})
+	app = model.(AppModel)
+	if app.panes[0].Workstream().ID != "gamma" {
+		t.Errorf("first = %s, want gamma kept in place on an activity change", app.panes[0].Workstream().ID)
+	}
+
+	// A new pane re-applies the sort, and alpha moves up
+	model, _ = app.Update(DialogConfirmMsg{Type: DialogNewWorkstream, Value: "task delta"})
+	app = model.(AppModel)
+	if got := app.panes[0].Workstream().Prompt; got != "task delta" || app.panes[1].Workstream().ID != "alpha" {
+		t.Errorf("order = %s, %s; want the new pane, then alpha re-sorted by activity", got, app.panes[1].Workstream().ID)
+	}
+	if app.focusedPane != 0 {
+		t.Error("the new pane should keep focus after the sort")
+	}
+
+	// Editing a priority re-applies a priority sort
+	model, _ = app.Update(keyPress('o')) // Back to creation order
+	app = model.(AppModel)
+	app.paneSort = paneSortPriority
+	model, _ = app.Update(DialogConfirmMsg{Type: DialogLabels, WorkstreamID: "beta", Value: "p1"})
+	app = model.(AppModel)
+	if got := app.panes[0].Workstream().ID; got != "beta" {
+		t.Errorf("first = %s, want beta after setting its priority", got)
+	}
+
+	// Moving a pane by hand turns the sort off
+	app.setFocusedPane(2)
+	model, _ = app.Update(specialKey(tea.KeySpace))
+	app = model.(AppModel)
+	if app.paneSort != paneSortCreated {
+		t.Errorf("sort = %s, want it off after arranging panes by hand", app.paneSort)
+	}
+}
+
+func TestAppModel_FilterBar(t *testing.T) {
+	app := newFilterTestApp(t)
+	app.updateLayout()
+	view := ansi.Strip(viewString(app.View()))
+	if app.filterBarHeight() != 0 || strings.Contains(view, "shown") {
+		t.Error("no filter bar should be shown without a filter or sort")
+	}
+	lines := strings.Count(view, "\n")
+
+	// The bar shows while a sort is set, and the panes make room for it
+	app.paneSort = paneSortPriority
+	app.updateLayout()
+	view = ansi.Strip(viewString(app.View()))
+	if !strings.Contains(view, "Sorted by priority") {
+		t.Errorf("view should show the sort in the filter bar:\n%s", view)
+	}
+	if got := strings.Count(view, "\n"); got != lines {
+		t.Errorf("view has %d lines, want %d: the panes should shrink to make room for the bar", got, lines)
+	}
+
+	model, _ := app.Update(DialogConfirmMsg{Type: DialogFilter, Value: "#ui"})
+	app = model.(AppModel)
+	view = ansi.Strip(viewString(app.View()))
+	if !strings.Contains(view, "/#ui  2 of 3 shown, 1 hidden still running  ·  Sorted by priority") {
+		t.Errorf("view should show the filter and the sort in the filter bar:\n%s", view)
+	}
+}
+
+func TestAppModel_RestoredActivityDefaultsToCreation(t *testing.T) {
+	app := newFilterTestApp(t)
+	created := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	state := &workstream.AppState{Workstreams: []workstream.SavedWorkstream{
+		{ID: "delta", BranchName: "delta", Prompt: "task delta", CreatedAt: created},
+	}}
+
+	model, _ := app.Update(StateLoadedMsg{State: state})
+	app = model.(AppModel)
+	if got := app.panes[len(app.panes)-1].Workstream().GetLastActivity(); !got.Equal(created) {
+		t.Errorf("LastActivity = %v, want the creation time for state saved without activity", got)
+	}
+}
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"context"
+
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// forgeFor returns the forge (GitHub or GitLab) a workstream's PRs live on:
+// the configured forge type if set, else detected from the origin remote.
+func forgeFor(ctx context.Context, ws *workstream.Workstream, gitRepo git.GitClient) git.Forge {
+	configDir, err := workstreamRepoPath(ws)
+	if err != nil {
+		configDir = resolveWorktreePath(ws)
+	}
+	cfg := docker.LoadConfig(configDir).Forge
+	if cfg.Type != "" {
+		return git.NewForge(cfg.Type)
+	}
+
+	remoteURL, err := gitRepo.RemoteURL(ctx, "origin")
+	if err != nil {
+		LogDebug("No origin remote for %s, assuming GitHub: %v", ws.BranchName, err)
+		return git.NewGH()
+	}
+	return git.NewForge(git.DetectForge(remoteURL, cfg.GitLabHosts))
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestForgeFor(t *testing.T) {
+	cellsDir := t.TempDir()
+	docker.SetTestCellsDir(cellsDir)
+	defer docker.SetTestCellsDir("")
+
+	ws := workstream.NewWithID("1", "feature", "Add login")
+	ws.RepoPath = t.TempDir()
+	remote := "git@github.com:o/r.git"
+	gitRepo := git.NewMockGitClient()
+	gitRepo.RemoteURLFn = func(ctx context.Context, name string) (string, error) { return remote, nil }
+
+	if got := forgeFor(context.Background(), ws, gitRepo).Name(); got != git.ForgeGitHub {
+		t.Errorf("forge = %q, want github", got)
+	}
+
+	// A self-hosted GitLab listed in the config
+	remote = "https://code.example.com/group/project.git"
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte("forge:\n  gitlab_hosts: [code.example.com]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := forgeFor(context.Background(), ws, gitRepo).Name(); got != git.ForgeGitLab {
+		t.Errorf("forge = %q, want gitlab for a configured host", got)
+	}
+
+	// A configured type wins over the remote
+	remote = "git@gitlab.com:group/project.git"
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte("forge:\n  type: github\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := forgeFor(context.Background(), ws, gitRepo).Name(); got != git.ForgeGitHub {
+		t.Errorf("forge = %q, want the configured github", got)
+	}
+}
@@ -0,0 +1,216 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/textarea"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// historyEdit is the state of the history cleanup dialog.
+type historyEdit struct {
+	plan *git.HistoryPlan // The proposal the edited text is checked against
+	err  string           // Why the edited plan can't be applied
+}
+
+// HistoryProposalMsg is sent when Claude has proposed a cleaned up history.
+type HistoryProposalMsg struct {
+	WorkstreamID string
+	Plan         *git.HistoryPlan
+	Error        error
+}
+
+// HistoryApplyMsg is sent when the history cleanup dialog is confirmed.
+type HistoryApplyMsg struct {
+	WorkstreamID string
+	Plan         *git.HistoryPlan
+}
+
+// HistoryRewriteMsg is sent when a history rewrite completes.
+type HistoryRewriteMsg struct {
+	WorkstreamID string
+	Before       int // Commits before the rewrite
+	After        int // Commits after the rewrite
+	Error        error
+}
+
+// ProposeHistoryCmd returns a command that asks Claude to restructure a
+// workstream's commits.
+func ProposeHistoryCmd(ws *workstream.Workstream) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+		defer cancel()
+
+		worktreePath := resolveWorktreePath(ws)
+		if worktreePath == "" {
+			return HistoryProposalMsg{WorkstreamID: ws.ID, Error: fmt.Errorf("no worktree path")}
+		}
+		plan, err := git.ProposeHistory(ctx, GitClientFactory(worktreePath), ws.BranchName)
+		return HistoryProposalMsg{WorkstreamID: ws.ID, Plan: plan, Error: err}
+	}
+}
+
+// RewriteHistoryCmd returns a command that applies a history plan in the
+// workstream's worktree.
+func RewriteHistoryCmd(ws *workstream.Workstream, plan *git.HistoryPlan) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+		defer cancel()
+
+		msg := HistoryRewriteMsg{WorkstreamID: ws.ID, Before: len(plan.Original), After: len(plan.Commits)}
+		worktreePath := resolveWorktreePath(ws)
+		if worktreePath == "" {
+			msg.Error = fmt.Errorf("no worktree path")
+			return msg
+		}
+		msg.Error = GitClientFactory(worktreePath).RewriteHistory(ctx, plan)
+		return msg
+	}
+}
+
+// NewHistoryDialog creates the dialog for reviewing and editing a proposed
+// history before it is applied.
+func NewHistoryDialog(ws *workstream.Workstream, plan *git.HistoryPlan) DialogModel {
+	ta := textarea.New()
+	ta.ShowLineNumbers = false
+	ta.MaxHeight = 0 // Plans can be longer than the default limit
+	ta.Prompt = ""
+	styleState := textarea.StyleState{
+		Base:        lipgloss.NewStyle(),
+		Text:        DialogInputText,
+		Placeholder: DialogInputPlaceholder,
+		CursorLine:  DialogInputText,
+		EndOfBuffer: lipgloss.NewStyle().Foreground(lipgloss.Color("#333333")),
+	}
+	ta.SetStyles(textarea.Styles{Focused: styleState, Blurred: styleState})
+	ta.SetValue(git.FormatHistoryPlan(plan))
+	ta.MoveToBegin()
+	ta.Focus()
+
+	return DialogModel{
+		Type:         DialogHistoryCleanup,
+		Title:        fmt.Sprintf("Clean Up History: %s", ws.BranchName),
+		Body:         fmt.Sprintf("%d commit(s) → %d", len(plan.Original), len(plan.Commits)),
+		WorkstreamID: ws.ID,
+		TextArea:     ta,
+		useTextArea:  true,
+		history:      historyEdit{plan: plan},
+	}
+}
+
+// updateHistory handles keys in the history cleanup dialog. Enter adds a
+// line; Ctrl+S applies the edited plan. It reports false for keys the
+// dialog handles itself (closing it).
+func (d *DialogModel) updateHistory(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		return nil, false
+	case "enter":
+		d.TextArea.InsertRune('\n')
+		return textarea.Blink, true
+	case "ctrl+s":
+		plan, err := git.ParseHistoryPlan(d.history.plan, d.TextArea.Value())
+		if err != nil {
+			d.history.err = err.Error()
+			return nil, true
+		}
+		d.history.err = ""
+		workstreamID := d.WorkstreamID
+		return func() tea.Msg { return HistoryApplyMsg{WorkstreamID: workstreamID, Plan: plan} }, true
+	}
+	var cmd tea.Cmd
+	d.TextArea, cmd = d.TextArea.Update(msg)
+	return cmd, true
+}
+
+// viewHistory renders the history cleanup dialog.
+func (d DialogModel) viewHistory() string {
+	var content strings.Builder
+	content.WriteString(DialogTitle.Render(d.Title))
+	content.WriteString("\n\n")
+	content.WriteString(d.Body)
+	content.WriteString("\n\n")
+	content.WriteString(DialogInputFocused.Width(d.width - 10).Render(d.TextArea.View()))
+	content.WriteString("\n\n")
+	if d.history.err != "" {
+		content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(ColorPairingConflict)).Render("✗ " + d.history.err))
+		content.WriteString("\n\n")
+	}
+	content.WriteString(KeyHint("Ctrl+S", " apply") + "  " + KeyHintStyle.Render("[Esc] Cancel"))
+	return DialogBox.Width(d.width).Render(content.String())
+}
+
+// handleHistoryProposal opens the history cleanup dialog for a proposal.
+func (m *AppModel) handleHistoryProposal(msg HistoryProposalMsg) {
+	i := m.paneIndexByID(msg.WorkstreamID)
+	if i < 0 {
+		return
+	}
+	if msg.Error != nil {
+		m.panes[i].AppendOutput(fmt.Sprintf("History cleanup failed: %v\n", msg.Error))
+		if dialog := m.panes[i].GetInPaneDialog(); dialog != nil && dialog.Type == DialogProgress {
+			dialog.SetComplete(fmt.Sprintf("History Cleanup Failed\n\n%v", msg.Error))
+		}
+		return
+	}
+	m.panes[i].ClearInPaneDialog()
+	dialog := NewHistoryDialog(m.panes[i].Workstream(), msg.Plan)
+	dialog.SetSize(min(m.width-4, 100), m.height-2)
+	m.dialog = &dialog
+}
+
+// applyHistory starts rewriting a workstream's history with a confirmed plan.
+func (m *AppModel) applyHistory(msg HistoryApplyMsg) tea.Cmd {
+	m.dialog = nil
+	i := m.paneIndexByID(msg.WorkstreamID)
+	if i < 0 {
+		return nil
+	}
+	ws := m.panes[i].Workstream()
+	m.panes[i].AppendOutput(fmt.Sprintf("\nRewriting history: %d commit(s) → %d...\n", len(msg.Plan.Original), len(msg.Plan.Commits)))
+	dialog := NewProgressDialog("Cleaning Up History", fmt.Sprintf("Branch: %s\n\nRewriting %d commit(s) into %d...", ws.BranchName, len(msg.Plan.Original), len(msg.Plan.Commits)), ws.ID)
+	m.panes[i].SetInPaneDialog(&dialog)
+	return RewriteHistoryCmd(ws, msg.Plan)
+}
+
+// handleHistoryRewrite reports the result of a history rewrite.
+func (m *AppModel) handleHistoryRewrite(msg HistoryRewriteMsg) {
+	i := m.paneIndexByID(msg.WorkstreamID)
+	if i < 0 {
+		return
+	}
+	ws := m.panes[i].Workstream()
+	dialog := m.panes[i].GetInPaneDialog()
+	if dialog != nil && dialog.Type != DialogProgress {
+		dialog = nil
+	}
+	if msg.Error != nil {
+		m.panes[i].AppendOutput(fmt.Sprintf("History rewrite failed: %v\n", msg.Error))
+		if dialog != nil {
+			dialog.SetComplete(fmt.Sprintf("History Rewrite Failed\n\n%v", msg.Error))
+		}
+		return
+	}
+
+	summary := fmt.Sprintf("%d commit(s) → %d", msg.Before, msg.After)
+	ws.RecordEvent(workstream.EventHistoryRewritten, summary)
+	m.managerFor(ws).UpdateWorkstream(ws.ID)
+	m.panes[i].AppendOutput(fmt.Sprintf("History rewritten: %s\n", summary))
+	// Tell Claude its commits changed (don't press Enter - avoids submitting Claude's pending input)
+	if err := m.panes[i].SendInput(fmt.Sprintf("[ccells] ✓ Rewrote the history of '%s': %s", ws.BranchName, summary), false); err != nil {
+		LogWarn("Failed to notify Claude about history rewrite for %s (pane %d): %v", ws.BranchName, i, err)
+	}
+	if dialog != nil {
+		done := fmt.Sprintf("History rewritten: %s\n\n", summary)
+		if ws.GetHasBeenPushed() {
+			done += "The branch was already pushed; use Force push to update the remote.\n"
+		}
+		dialog.SetComplete(done + "Press Enter or Esc to close.")
+	}
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func testHistoryPlan() *git.HistoryPlan {
+	return &git.HistoryPlan{
+		Base: "base",
+		Head: "head",
+		Original: []git.Commit{
+			{Hash: "aaaa111", Subject: "Add login form"},
+			{Hash: "bbbb222", Subject: "wip"},
+		},
+		Commits: []git.PlannedCommit{
+			{Message: "feat(auth): add login form", Sources: []string{"aaaa111", "bbbb222"}, Fixups: []string{"bbbb222"}},
+		},
+	}
+}
+
+func TestHistoryDialog(t *testing.T) {
+	ws := workstream.New("add login")
+	d := NewHistoryDialog(ws, testHistoryPlan())
+	d.SetSize(100, 40)
+
+	if d.Type != DialogHistoryCleanup {
+		t.Fatalf("Type = %v, want DialogHistoryCleanup", d.Type)
+	}
+	if view := d.View(); !strings.Contains(view, "2 commit(s) → 1") || !strings.Contains(view, "fixup bbbb222 wip") {
+		t.Errorf("view should show the summary and plan:\n%s", view)
+	}
+
+	// Ctrl+S applies the plan
+	d, cmd := d.Update(tea.KeyPressMsg{Code: 's', Mod: tea.ModCtrl})
+	if cmd == nil {
+		t.Fatal("Ctrl+S should apply the plan")
+	}
+	msg, ok := cmd().(HistoryApplyMsg)
+	if !ok || msg.WorkstreamID != ws.ID || len(msg.Plan.Commits) != 1 {
+		t.Errorf("Ctrl+S sent %+v, want a HistoryApplyMsg with the plan", msg)
+	}
+
+	// A plan that drops a commit is rejected in the dialog
+	d.TextArea.SetValue("pick aaaa111 Add login form\nfeat: form\n")
+	d, cmd = d.Update(tea.KeyPressMsg{Code: 's', Mod: tea.ModCtrl})
+	if cmd != nil {
+		t.Error("an invalid plan should not be applied")
+	}
+	if !strings.Contains(d.View(), "bbbb222 (wip) is missing") {
+		t.Errorf("view should show why the plan is invalid:\n%s", d.View())
+	}
+}
+
+func TestAppModel_HistoryCleanupFlow(t *testing.T) {
+	app := newTestApp(t)
+	app.width, app.height = 120, 40
+	ws := app.panes[0].Workstream()
+	ws.WorktreePath = t.TempDir()
+
+	model, cmd := app.Update(MergeConfirmMsg{WorkstreamID: ws.ID, Action: MergeActionCleanHistory})
+	app = model.(AppModel)
+	if cmd == nil {
+		t.Fatal("cleaning up history should ask Claude for a proposal")
+	}
+	if d := app.panes[0].GetInPaneDialog(); d == nil || d.Type != DialogProgress {
+		t.Fatal("a progress dialog should be shown while Claude proposes a history")
+	}
+
+	// The proposal opens the edit dialog
+	model, _ = app.Update(HistoryProposalMsg{WorkstreamID: ws.ID, Plan: testHistoryPlan()})
+	app = model.(AppModel)
+	if app.dialog == nil || app.dialog.Type != DialogHistoryCleanup {
+		t.Fatal("a proposal should open the history cleanup dialog")
+	}
+
+	// Applying it starts the rewrite
+	model, cmd = app.Update(HistoryApplyMsg{WorkstreamID: ws.ID, Plan: testHistoryPlan()})
+	app = model.(AppModel)
+	if app.dialog != nil || cmd == nil {
+		t.Fatal("applying should close the dialog and start the rewrite")
+	}
+
+	model, _ = app.Update(HistoryRewriteMsg{WorkstreamID: ws.ID, Before: 2, After: 1})
+	app = model.(AppModel)
+	if events := ws.GetEvents(); len(events) == 0 || events[len(events)-1].Type != workstream.EventHistoryRewritten {
+		t.Error("the rewrite should be added to the timeline")
+	}
+	if d := app.panes[0].GetInPaneDialog(); d == nil || !strings.Contains(d.Body, "2 commit(s) → 1") {
+		t.Error("the progress dialog should report the rewrite")
+	}
+}
+
+func TestAppModel_HistoryProposalError(t *testing.T) {
+	app := newTestApp(t)
+	ws := app.panes[0].Workstream()
+	dialog := NewProgressDialog("Cleaning Up History", "", ws.ID)
+	app.panes[0].SetInPaneDialog(&dialog)
+
+	model, _ := app.Update(HistoryProposalMsg{WorkstreamID: ws.ID, Error: errors.New("claude unavailable")})
+	app = model.(AppModel)
+	if app.dialog != nil {
+		t.Error("a failed proposal should not open the edit dialog")
+	}
+	if d := app.panes[0].GetInPaneDialog(); d == nil || !strings.Contains(d.Body, "claude unavailable") {
+		t.Error("the progress dialog should report the failure")
+	}
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/hooks"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// HookEventMsg requests that lifecycle hooks run for a workstream.
+// This is used by the git proxy to report pushes and PRs created from containers.
+type HookEventMsg struct {
+	WorkstreamID string
+	Event        hooks.Event
+	PRURL        string // Optional: overrides the workstream's PR URL in the payload
+}
+
+// RequestHookEvent sends a message to run lifecycle hooks for a workstream.
+// This can be called from any goroutine (e.g., git proxy callback).
+// Returns true if the message was sent, false if the program is not available.
+func RequestHookEvent(workstreamID string, event hooks.Event, prURL string) bool {
+	return sendMsg(HookEventMsg{WorkstreamID: workstreamID, Event: event, PRURL: prURL})
+}
+
+// hookPayload builds the hook payload for a workstream.
+func hookPayload(event hooks.Event, ws *workstream.Workstream) hooks.Payload {
+	_, prURL := ws.GetPRInfo()
+	return hooks.Payload{
+		Event:        event,
+		WorkstreamID: ws.ID,
+		Branch:       ws.BranchName,
+		WorktreePath: ws.WorktreePath,
+		PRURL:        prURL,
+		Synopsis:     ws.GetSynopsis(),
+	}
+}
+
+// RunHooksCmd returns a command that runs the hooks for an event and reports
+// their output to the log panel. Returns nil if no hooks are configured.
+func RunHooksCmd(runner *hooks.Runner, payload hooks.Payload) tea.Cmd {
+	if !runner.HasHooks(payload.Event) {
+		return nil
+	}
+	return func() tea.Msg {
+		results := runner.Run(context.Background(), payload)
+		for _, r := range results {
+			if r.Err != nil {
+				LogWarn("Hook %s (%s) failed for %s: %v", payload.Event, r.Command, payload.Branch, r.Err)
+			} else {
+				LogInfo("Hook %s (%s) completed for %s in %v", payload.Event, r.Command, payload.Branch, r.Duration.Round(time.Millisecond))
+			}
+			if r.Output != "" {
+				LogInfo("[hook %s] %s", payload.Event, r.Output)
+			}
+		}
+		return nil
+	}
+}
+
+// runHooks returns a command running the hooks of the workstream's repository
+// for an event on it.
+func (m *AppModel) runHooks(event hooks.Event, ws *workstream.Workstream) tea.Cmd {
+	return RunHooksCmd(m.hooksFor(ws), hookPayload(event, ws))
+}
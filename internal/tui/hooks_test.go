@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/hooks"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestHookPayload(t *testing.T) {
+	ws := workstream.New("add feature")
+	ws.WorktreePath = "/tmp/ccells/worktrees/add-feature"
+	ws.SetPRInfo(12, "https://github.com/o/r/pull/12")
+	ws.SetSynopsis("Implemented the feature")
+
+	p := hookPayload(hooks.EventMerge, ws)
+
+	if p.Event != hooks.EventMerge {
+		t.Errorf("Event = %q, want %q", p.Event, hooks.EventMerge)
+	}
+	if p.WorkstreamID != ws.ID || p.Branch != ws.BranchName || p.WorktreePath != ws.WorktreePath {
+		t.Errorf("unexpected workstream fields: %+v", p)
+	}
+	if p.PRURL != "https://github.com/o/r/pull/12" {
+		t.Errorf("PRURL = %q", p.PRURL)
+	}
+	if p.Synopsis != "Implemented the feature" {
+		t.Errorf("Synopsis = %q", p.Synopsis)
+	}
+}
+
+func TestRunHooksCmd_NoHooks(t *testing.T) {
+	runner := hooks.NewRunner(docker.HooksConfig{}, t.TempDir())
+	if cmd := RunHooksCmd(runner, hooks.Payload{Event: hooks.EventCreate}); cmd != nil {
+		t.Error("RunHooksCmd should return nil when no hooks are configured")
+	}
+	if cmd := RunHooksCmd(nil, hooks.Payload{Event: hooks.EventCreate}); cmd != nil {
+		t.Error("RunHooksCmd should return nil for a nil runner")
+	}
+}
+
+func TestRunHooksCmd_RunsHooks(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	runner := hooks.NewRunner(docker.HooksConfig{Destroy: []string{"touch " + marker}}, dir)
+
+	cmd := RunHooksCmd(runner, hooks.Payload{Event: hooks.EventDestroy})
+	if cmd == nil {
+		t.Fatal("RunHooksCmd should return a command when hooks are configured")
+	}
+	cmd()
+
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("hook did not run: %v", err)
+	}
+}
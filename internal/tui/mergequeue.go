@@ -0,0 +1,282 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/hooks"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// mergeQueueStepTimeout bounds the rebase and the merge of a queue entry.
+const mergeQueueStepTimeout = 120 * time.Second
+
+// Queue steps a merge queue entry can fail at.
+const (
+	mergeStageRebase = "rebase"
+	mergeStageVerify = "verify"
+	mergeStageMerge  = "merge"
+)
+
+// MergeQueueResultMsg is sent when the merge queue has processed one entry.
+type MergeQueueResultMsg struct {
+	WorkstreamID  string
+	BaseBranch    string
+	Stage         string // Step that failed; empty if the branch was merged
+	Error         error
+	ConflictFiles []string // Files that conflicted during the rebase or merge
+	Command       string   // Verification command, if one ran
+	ExitCode      int
+	Output        string // Verification output (tail)
+}
+
+// Merged reports whether the entry was merged into the base branch.
+func (m MergeQueueResultMsg) Merged() bool {
+	return m.Stage == ""
+}
+
+// NeedsClaude reports whether the failure is one the cell's Claude can fix:
+// a conflict or failing verification. Other errors are left to the user.
+func (m MergeQueueResultMsg) NeedsClaude() bool {
+	return len(m.ConflictFiles) > 0 || (m.Stage == mergeStageVerify && m.ExitCode != 0)
+}
+
+// queueVerifyFunc runs the verification command of a queued workstream.
+type queueVerifyFunc func(ctx context.Context) (exitCode int, output string, err error)
+
+// runMergeQueueEntry rebases a ready workstream onto the updated base branch,
+// verifies it and merges it. worktree operates on the workstream's worktree,
+// repo on the repository the branch is merged in; verify may be nil.
+func runMergeQueueEntry(ctx context.Context, worktree, repo git.GitClient, ws *workstream.Workstream, command string, verify queueVerifyFunc) MergeQueueResultMsg {
+	result := MergeQueueResultMsg{WorkstreamID: ws.ID, Command: command}
+	result.BaseBranch, _ = repo.GetBaseBranch(ctx)
+	if result.BaseBranch == "" {
+		result.BaseBranch = "main"
+	}
+	fail := func(stage string, err error) MergeQueueResultMsg {
+		result.Stage, result.Error = stage, err
+		var conflictErr *git.MergeConflictError
+		if errors.As(err, &conflictErr) {
+			result.ConflictFiles = conflictErr.ConflictFiles
+		}
+		return result
+	}
+
+	// Rebasing would fail or sweep in Claude's work in progress
+	if dirty, err := worktree.HasUncommittedChanges(ctx); err != nil {
+		return fail(mergeStageRebase, err)
+	} else if dirty {
+		return fail(mergeStageRebase, &git.DirtyWorktreeError{Operation: "rebase"})
+	}
+
+	rebaseCtx, cancel := context.WithTimeout(ctx, mergeQueueStepTimeout)
+	err := worktree.RebaseOntoBase(rebaseCtx, ws.BranchName)
+	cancel()
+	if err != nil {
+		return fail(mergeStageRebase, err)
+	}
+
+	if verify != nil {
+		exitCode, output, err := verify(ctx)
+		result.ExitCode, result.Output = exitCode, output
+		if err == nil && exitCode != 0 {
+			err = fmt.Errorf("%s exited with code %d", command, exitCode)
+		}
+		if err != nil {
+			return fail(mergeStageVerify, err)
+		}
+	}
+
+	mergeCtx, cancel := context.WithTimeout(ctx, mergeQueueStepTimeout)
+	defer cancel()
+	if err := repo.MergeBranchWithOptions(mergeCtx, ws.BranchName, false); err != nil {
+		return fail(mergeStageMerge, err)
+	}
+	return result
+}
+
+// MergeQueueCmd returns a command that processes one merge queue entry,
+// running the project's verification command (if any) in the cell.
+func MergeQueueCmd(ws *workstream.Workstream, cfg docker.VerifyConfig) tea.Cmd {
+	return func() tea.Msg {
+		repoPath, err := workstreamRepoPath(ws)
+		if err != nil {
+			return MergeQueueResultMsg{WorkstreamID: ws.ID, Stage: mergeStageRebase, Error: err}
+		}
+
+		var verify queueVerifyFunc
+		if cfg.Command != "" {
+			verify = func(ctx context.Context) (int, string, error) {
+				if ws.ContainerID == "" {
+					return 0, "", fmt.Errorf("no container for workstream")
+				}
+				ctx, cancel := context.WithTimeout(ctx, cfg.GetTimeout())
+				defer cancel()
+				client, err := docker.NewClient()
+				if err != nil {
+					return 0, "", err
+				}
+				defer client.Close()
+
+				w := &verifyOutputWriter{workstreamID: ws.ID, send: sendMsg}
+				exitCode, err := runVerification(ctx, client, ws.ContainerID, cfg.Command, w)
+				if ctx.Err() == context.DeadlineExceeded {
+					err = fmt.Errorf("verification timed out after %v", cfg.GetTimeout())
+				}
+				return exitCode, w.String(), err
+			}
+		}
+
+		return runMergeQueueEntry(context.Background(), GitClientFactory(resolveWorktreePath(ws)), GitClientFactory(repoPath), ws, cfg.Command, verify)
+	}
+}
+
+// mergeQueueFixPrompt builds the prompt handing a conflict or failed
+// verification back to the cell's Claude.
+func mergeQueueFixPrompt(msg MergeQueueResultMsg) string {
+	if len(msg.ConflictFiles) > 0 {
+		return fmt.Sprintf("The merge queue could not %s this branch onto %s because these files conflict: %s. Please run `git rebase %s`, resolve the conflicts, make sure the tests pass, and commit. Let me know when done so the branch can be queued again.",
+			msg.Stage, msg.BaseBranch, strings.Join(msg.ConflictFiles, ", "), msg.BaseBranch)
+	}
+	return fmt.Sprintf("The merge queue rebased this branch onto %s, but the verification command `%s` then failed with exit code %d, so the merge was skipped. The full output is in %s. Please read it, fix the failures, and commit the fixes so the branch can be queued again.",
+		msg.BaseBranch, msg.Command, msg.ExitCode, verifyLogPath)
+}
+
+// mergeQueuePosition returns the 1-based queue position of a workstream, or 0
+// if it is not queued. Position 1 is the entry being merged while busy.
+func (m *AppModel) mergeQueuePosition(id string) int {
+	for i, queued := range m.mergeQueue {
+		if queued == id {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// toggleMergeReady marks the workstream in pane i ready for the merge queue,
+// or takes it out of the queue again.
+func (m *AppModel) toggleMergeReady(i int) tea.Cmd {
+	ws := m.panes[i].Workstream()
+	if pos := m.mergeQueuePosition(ws.ID); pos > 0 {
+		if pos == 1 && m.mergeQueueBusy {
+			m.toast = fmt.Sprintf("%s is being merged", ws.BranchName)
+			m.toastExpiry = time.Now().Add(toastDuration)
+			return nil
+		}
+		m.mergeQueue = append(m.mergeQueue[:pos-1], m.mergeQueue[pos:]...)
+		m.toast = fmt.Sprintf("Removed %s from the merge queue", ws.BranchName)
+		m.toastExpiry = time.Now().Add(toastDuration)
+		return nil
+	}
+	if ws.BranchName == "" || !ws.GetState().IsActive() {
+		m.toast = "Only running workstreams can be queued for merge"
+		m.toastExpiry = time.Now().Add(toastDuration)
+		return nil
+	}
+
+	m.mergeQueue = append(m.mergeQueue, ws.ID)
+	m.toast = fmt.Sprintf("Queued %s for merge (position %d)", ws.BranchName, len(m.mergeQueue))
+	m.toastExpiry = time.Now().Add(toastDuration)
+	return m.advanceMergeQueue()
+}
+
+// advanceMergeQueue starts merging the entry at the head of the queue unless
+// one is already in progress. Entries whose pane is gone are dropped.
+func (m *AppModel) advanceMergeQueue() tea.Cmd {
+	for !m.mergeQueueBusy && len(m.mergeQueue) > 0 {
+		i := m.paneIndexByID(m.mergeQueue[0])
+		if i < 0 {
+			m.mergeQueue = m.mergeQueue[1:]
+			continue
+		}
+		ws := m.panes[i].Workstream()
+		verify := docker.LoadConfig(m.repoDir(ws)).Verify
+		m.mergeQueueBusy = true
+		m.panes[i].AppendOutput("\nMerge queue: rebasing, verifying and merging...\n")
+		dialog := NewProgressDialog("Merge Queue", fmt.Sprintf("Branch: %s\n\nRebasing onto the base branch, verifying and merging...", ws.BranchName), ws.ID)
+		m.panes[i].SetInPaneDialog(&dialog)
+		return MergeQueueCmd(ws, verify)
+	}
+	return nil
+}
+
+// paneIndexByID returns the index of the pane showing a workstream, or -1.
+func (m *AppModel) paneIndexByID(id string) int {
+	for i := range m.panes {
+		if m.panes[i].Workstream().ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// handleMergeQueueResult reports the outcome of a queue entry, hands fixable
+// failures back to the cell's Claude, and moves on to the next entry.
+func (m *AppModel) handleMergeQueueResult(msg MergeQueueResultMsg) tea.Cmd {
+	if len(m.mergeQueue) > 0 && m.mergeQueue[0] == msg.WorkstreamID {
+		m.mergeQueue = m.mergeQueue[1:]
+	}
+	m.mergeQueueBusy = false
+
+	var cmds []tea.Cmd
+	if i := m.paneIndexByID(msg.WorkstreamID); i >= 0 {
+		ws := m.panes[i].Workstream()
+		switch {
+		case msg.Merged():
+			m.panes[i].AppendOutput(fmt.Sprintf("Merge queue: merged into %s.\n", msg.BaseBranch))
+			ws.RecordEvent(workstream.EventMerged, "merged into "+msg.BaseBranch+" by the merge queue")
+			// Don't press Enter - avoids submitting Claude's pending input
+			if err := m.panes[i].SendInput(fmt.Sprintf("[ccells] ✓ Branch '%s' merged into %s by the merge queue", ws.BranchName, msg.BaseBranch), false); err != nil {
+				LogWarn("Failed to notify Claude about merge for %s (pane %d): %v", ws.BranchName, i, err)
+			}
+			dialog := NewPostMergeDestroyDialog(ws.BranchName, ws.ID)
+			m.panes[i].SetInPaneDialog(&dialog)
+			m.toast = fmt.Sprintf("Merge queue: merged %s", ws.BranchName)
+			cmds = append(cmds, m.runHooks(hooks.EventMerge, ws))
+		case msg.NeedsClaude():
+			m.panes[i].ClearInPaneDialog()
+			cmd, err := m.promptClaude(i, mergeQueueFixPrompt(msg))
+			if err != nil {
+				LogWarn("Failed to send merge queue failure to pane %d: %v", i, err)
+				m.panes[i].AppendOutput(fmt.Sprintf("Merge queue: %s failed (%v). Claude could not be asked to fix it (%v); fix it by hand, then queue the branch again.\n", msg.Stage, msg.Error, err))
+				m.toast = fmt.Sprintf("Merge queue: %s failed for %s", msg.Stage, ws.BranchName)
+				break
+			}
+			m.panes[i].AppendOutput(fmt.Sprintf("Merge queue: %s failed (%v). Asking Claude to fix it...\n", msg.Stage, msg.Error))
+			m.toast = fmt.Sprintf("Merge queue: %s failed for %s, sent to Claude", msg.Stage, ws.BranchName)
+			cmds = append(cmds, cmd)
+		case errors.As(msg.Error, new(*git.DirtyWorktreeError)):
+			m.panes[i].AppendOutput("Merge queue: skipped, the worktree has uncommitted changes. Commit or stash them, then queue the branch again.\n")
+			if dialog := m.panes[i].GetInPaneDialog(); dialog != nil && dialog.Type == DialogProgress {
+				dialog.SetComplete("Merge Queue Skipped\n\nThe worktree has uncommitted changes.\nCommit or stash them, then queue the branch again.")
+			}
+			m.toast = fmt.Sprintf("Merge queue: skipped %s, uncommitted changes", ws.BranchName)
+		default:
+			m.panes[i].AppendOutput(fmt.Sprintf("Merge queue: %s failed: %v\n", msg.Stage, msg.Error))
+			if dialog := m.panes[i].GetInPaneDialog(); dialog != nil && dialog.Type == DialogProgress {
+				dialog.SetComplete(fmt.Sprintf("Merge Queue Failed\n\n%s: %v", msg.Stage, msg.Error))
+			}
+			m.toast = fmt.Sprintf("Merge queue: %s failed for %s", msg.Stage, ws.BranchName)
+		}
+		m.toastExpiry = time.Now().Add(toastDuration)
+	}
+
+	cmds = append(cmds, m.advanceMergeQueue())
+	return tea.Batch(cmds...)
+}
+
+// mergeQueueBadge renders the pane header tag of a queued workstream.
+func mergeQueueBadge(position int, merging bool) string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#22C55E")).Bold(true)
+	if merging {
+		return style.Render("⇢ merging")
+	}
+	return style.Render(fmt.Sprintf("⇢ queued #%d", position))
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestRunMergeQueueEntry(t *testing.T) {
+	ws := workstream.NewWithID("1", "feature", "task")
+	passing := func(ctx context.Context) (int, string, error) { return 0, "ok", nil }
+
+	var merged []string
+	repo := git.NewMockGitClient()
+	repo.MergeBranchWithOptionsFn = func(ctx context.Context, branch string, squash bool) error {
+		merged = append(merged, branch)
+		return nil
+	}
+
+	result := runMergeQueueEntry(context.Background(), git.NewMockGitClient(), repo, ws, "make test", passing)
+	if !result.Merged() || len(merged) != 1 {
+		t.Fatalf("result = %+v, merged %v; want feature merged", result, merged)
+	}
+
+	// A conflicting rebase stops the entry before verification
+	worktree := git.NewMockGitClient()
+	worktree.RebaseOntoBaseFn = func(ctx context.Context, branch string) error {
+		return &git.MergeConflictError{Branch: branch, ConflictFiles: []string{"api.go"}}
+	}
+	verified := false
+	result = runMergeQueueEntry(context.Background(), worktree, repo, ws, "make test", func(ctx context.Context) (int, string, error) {
+		verified = true
+		return 0, "", nil
+	})
+	if result.Stage != mergeStageRebase || !result.NeedsClaude() || verified {
+		t.Errorf("result = %+v, verified = %v; want a rebase conflict for Claude", result, verified)
+	}
+
+	// A dirty worktree is skipped before anything is rebased
+	dirty := git.NewMockGitClient()
+	dirty.HasUncommittedChangesFn = func(ctx context.Context) (bool, error) { return true, nil }
+	dirty.RebaseOntoBaseFn = func(ctx context.Context, branch string) error {
+		t.Error("a dirty worktree should not be rebased")
+		return nil
+	}
+	result = runMergeQueueEntry(context.Background(), dirty, repo, ws, "make test", passing)
+	if result.Stage != mergeStageRebase || result.NeedsClaude() || !errors.As(result.Error, new(*git.DirtyWorktreeError)) {
+		t.Errorf("result = %+v, want the dirty worktree left to the user", result)
+	}
+
+	// Failing verification skips the merge
+	merged = nil
+	result = runMergeQueueEntry(context.Background(), git.NewMockGitClient(), repo, ws, "make test", func(ctx context.Context) (int, string, error) {
+		return 2, "FAIL TestX", nil
+	})
+	if result.Stage != mergeStageVerify || result.ExitCode != 2 || !result.NeedsClaude() || len(merged) != 0 {
+		t.Errorf("result = %+v, merged %v; want a verification failure and no merge", result, merged)
+	}
+	if prompt := mergeQueueFixPrompt(result); !strings.Contains(prompt, "`make test`") || !strings.Contains(prompt, verifyLogPath) {
+		t.Errorf("prompt should name the command and the log: %q", prompt)
+	}
+
+	// Errors Claude cannot fix are left to the user
+	repo.MergeBranchWithOptionsFn = func(ctx context.Context, branch string, squash bool) error {
+		return &git.DirtyWorktreeError{Operation: "merge"}
+	}
+	result = runMergeQueueEntry(context.Background(), git.NewMockGitClient(), repo, ws, "", nil)
+	if result.Stage != mergeStageMerge || result.NeedsClaude() || !errors.As(result.Error, new(*git.DirtyWorktreeError)) {
+		t.Errorf("result = %+v, want a merge error for the user", result)
+	}
+}
+
+func TestAppModel_MergeQueue(t *testing.T) {
+	app := newTestApp(t)
+
+	// Marking alpha ready starts merging it right away
+	cmd := app.toggleMergeReady(0)
+	if cmd == nil || !app.mergeQueueBusy {
+		t.Fatal("expected alpha to start merging")
+	}
+	if d := app.panes[0].GetInPaneDialog(); d == nil || d.Title != "Merge Queue" {
+		t.Error("expected a merge queue progress dialog in alpha's pane")
+	}
+
+	// beta and gamma wait their turn; gamma changes its mind
+	if cmd := app.toggleMergeReady(1); cmd != nil {
+		t.Error("beta should wait while alpha is merging")
+	}
+	app.toggleMergeReady(2)
+	if got := app.mergeQueuePosition("gamma"); got != 3 {
+		t.Errorf("gamma position = %d, want 3", got)
+	}
+	app.toggleMergeReady(2)
+	if got := app.mergeQueuePosition("gamma"); got != 0 {
+		t.Errorf("gamma should be unqueued, position = %d", got)
+	}
+	app.toggleMergeReady(0)
+	if got := app.mergeQueuePosition("alpha"); got != 1 {
+		t.Error("the entry being merged cannot be unqueued")
+	}
+
+	// alpha fails verification: it leaves the queue and beta starts, and
+	// alpha's ended session is resumed with the failure
+	app.panes[0].Workstream().SetContainerID("container-alpha")
+	model, cmd := app.Update(MergeQueueResultMsg{
+		WorkstreamID: "alpha", BaseBranch: "main", Stage: mergeStageVerify,
+		Error: errors.New("make test exited with code 1"), Command: "make test", ExitCode: 1,
+	})
+	app = model.(AppModel)
+	if cmd == nil || !app.mergeQueueBusy || app.mergeQueuePosition("beta") != 1 || app.mergeQueuePosition("alpha") != 0 {
+		t.Fatalf("queue = %v, busy = %v; want beta merging", app.mergeQueue, app.mergeQueueBusy)
+	}
+	if !strings.Contains(app.toast, "sent to Claude") || !strings.Contains(app.panes[0].output.String(), "Resuming Claude's session") {
+		t.Errorf("toast = %q, output = %q; want the failure sent to Claude", app.toast, app.panes[0].output.String())
+	}
+
+	// beta has uncommitted changes: it is skipped with a hint
+	app.toggleMergeReady(2)
+	model, _ = app.Update(MergeQueueResultMsg{
+		WorkstreamID: "beta", BaseBranch: "main", Stage: mergeStageRebase,
+		Error: &git.DirtyWorktreeError{Operation: "rebase"},
+	})
+	app = model.(AppModel)
+	if app.mergeQueuePosition("beta") != 0 || !strings.Contains(app.panes[1].output.String(), "Commit or stash them") {
+		t.Errorf("output = %q, want beta skipped with a hint", app.panes[1].output.String())
+	}
+
+	// gamma merges and the queue drains
+	model, _ = app.Update(MergeQueueResultMsg{WorkstreamID: "gamma", BaseBranch: "main"})
+	app = model.(AppModel)
+	if app.mergeQueueBusy || len(app.mergeQueue) != 0 {
+		t.Errorf("queue = %v, busy = %v; want it drained", app.mergeQueue, app.mergeQueueBusy)
+	}
+	if d := app.panes[2].GetInPaneDialog(); d == nil || d.Type != DialogPostMergeDestroy {
+		t.Error("expected the post-merge destroy dialog in gamma's pane")
+	}
+}
+
+func TestAppModel_MergeQueueHandoffFailed(t *testing.T) {
+	app := newTestApp(t)
+	app.toggleMergeReady(0)
+
+	// No session and no container: the failure is reported instead of claimed
+	model, _ := app.Update(MergeQueueResultMsg{
+		WorkstreamID: "alpha", BaseBranch: "main", Stage: mergeStageRebase,
+		Error: errors.New("conflicts"), ConflictFiles: []string{"a.go"},
+	})
+	app = model.(AppModel)
+	out := app.panes[0].output.String()
+	if strings.Contains(out, "Asking Claude") || !strings.Contains(out, "Claude could not be asked") {
+		t.Errorf("output = %q, want the failed hand-off reported", out)
+	}
+	if strings.Contains(app.toast, "sent to Claude") {
+		t.Errorf("toast = %q, should not claim the failure was sent", app.toast)
+	}
+}
@@ -70,12 +70,26 @@ type PaneModel struct {
 	// PR status for merge dialog enhancement
 	prStatus        *git.PRStatusInfo
 	prStatusLoading bool
+	prReviews       *git.PRReviews // Latest review feedback on the PR
 
 	// Synopsis display
 	synopsisHidden bool // True to hide synopsis in header (app-level toggle)
 
 	// Pairing state (set by app from pairingOrchestrator)
 	pairingState *sync.PairingState
+
+	// Budget state (set by app after each usage refresh)
+	budgetExceeded bool
+
+	// Repository tag (set by app when several repositories are open)
+	repoName string
+
+	// Branches this workstream is likely to conflict with (set by app)
+	conflictsWith []string
+
+	// Merge queue position (0 = not queued) and whether it is being merged
+	mergeQueuePos int
+	merging       bool
 }
 
 // Width returns the pane width
@@ -342,6 +356,39 @@ func (p PaneModel) View() string {
 		headerLeft = fmt.Sprintf("%s %s %s %s", indexLabel, status, title, stateLabel)
 	}
 
+	// Best-of-N group marker (same color for every cell in the group)
+	if groupID := p.workstream.GetGroupID(); groupID != "" {
+		headerLeft += " " + groupBadge(groupID)
+	}
+
+	// Repository (only when several are open)
+	if p.repoName != "" {
+		headerLeft += " " + repoBadge(p.repoName)
+	}
+
+	// Priority and labels
+	if badges := labelBadges(p.workstream); badges != "" {
+		headerLeft += " " + badges
+	}
+
+	// Estimated token cost
+	if cost := usageBadge(p.workstream); cost != "" {
+		headerLeft += " " + cost
+	}
+	if p.budgetExceeded {
+		headerLeft += " " + budgetExceededBadge()
+	}
+
+	// Predicted merge conflicts with other cells
+	if len(p.conflictsWith) > 0 {
+		headerLeft += " " + conflictBadge(p.conflictsWith)
+	}
+
+	// Merge queue
+	if p.mergeQueuePos > 0 {
+		headerLeft += " " + mergeQueueBadge(p.mergeQueuePos, p.merging)
+	}
+
 	// Pairing status badges (shown after state label when this pane is being paired)
 	if p.pairingState != nil && p.pairingState.Active {
 		// Pairing mode label
@@ -403,6 +450,16 @@ func (p PaneModel) View() string {
 			parts = append(parts, unpushedStyle.Render(fmt.Sprintf("↑%d", p.prStatus.UnpushedCount)))
 		}
 
+		// Unresolved review comments - amber
+		if p.prReviews != nil {
+			if n := len(p.prReviews.Unresolved()); n > 0 {
+				reviewStyle := lipgloss.NewStyle().
+					Foreground(lipgloss.Color("#F59E0B")).
+					Bold(true)
+				parts = append(parts, reviewStyle.Render(fmt.Sprintf("💬%d", n)))
+			}
+		}
+
 		// Divergence warning - bright orange/red
 		if p.prStatus.IsDiverged {
 			divergedStyle := lipgloss.NewStyle().
@@ -859,6 +916,9 @@ func (p PaneModel) viewWithInPaneDialog() string {
 		Render("DIALOG")
 
 	header := fmt.Sprintf("%s %s %s %s %s", indexLabel, modeIndicator, status, title, stateLabel)
+	if groupID := p.workstream.GetGroupID(); groupID != "" {
+		header += " " + groupBadge(groupID)
+	}
 
 	// Render the dialog content to fill the pane
 	dialogContent := p.inPaneDialog.ViewInPane()
@@ -1404,6 +1464,16 @@ func (p *PaneModel) SetPRStatus(status *git.PRStatusInfo) {
 	p.prStatusLoading = false
 }
 
+// GetPRReviews returns the latest review feedback on the PR, if any
+func (p *PaneModel) GetPRReviews() *git.PRReviews {
+	return p.prReviews
+}
+
+// SetPRReviews sets the latest review feedback on the PR
+func (p *PaneModel) SetPRReviews(reviews *git.PRReviews) {
+	p.prReviews = reviews
+}
+
 // SetPRStatusLoading sets whether PR status is currently being loaded
 func (p *PaneModel) SetPRStatusLoading(loading bool) {
 	p.prStatusLoading = loading
@@ -1419,6 +1489,33 @@ func (p *PaneModel) SetSynopsisHidden(hidden bool) {
 	p.synopsisHidden = hidden
 }
 
+// SetBudgetExceeded marks the pane as having used its (or the project's) budget.
+func (p *PaneModel) SetBudgetExceeded(exceeded bool) {
+	p.budgetExceeded = exceeded
+}
+
+// IsBudgetExceeded returns true if the pane has used its budget
+func (p *PaneModel) IsBudgetExceeded() bool {
+	return p.budgetExceeded
+}
+
+// SetRepoName sets the repository tag shown in the header ("" hides it).
+func (p *PaneModel) SetRepoName(name string) {
+	p.repoName = name
+}
+
+// SetConflictsWith sets the branches this workstream is likely to conflict with.
+func (p *PaneModel) SetConflictsWith(branches []string) {
+	p.conflictsWith = branches
+}
+
+// SetMergeQueue sets the pane's merge queue position (0 if not queued) and
+// whether its branch is being merged.
+func (p *PaneModel) SetMergeQueue(position int, merging bool) {
+	p.mergeQueuePos = position
+	p.merging = merging
+}
+
 // SetPairingState sets the pairing state for this pane.
 // Pass nil to clear pairing status (pane is not being paired).
 // Makes a defensive copy to avoid holding a pointer to caller's stack variable.
@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// PR preview dialog fields after the title (which is DialogModel.Input).
+const (
+	prFieldBase = iota
+	prFieldReviewers
+	prFieldLabels
+	prFieldAssignees
+	prFieldMilestone
+	prFieldCount
+)
+
+var prFieldNames = [prFieldCount]string{"Base", "Reviewers", "Labels", "Assignees", "Milestone"}
+
+// prForm is the state of the PR preview dialog.
+type prForm struct {
+	fields      []textinput.Model // Indexed by prField*; nil for the plain preview dialog
+	focus       int               // 0 = title, otherwise fields[focus-1]
+	draft       bool
+	templates   []git.PRTemplate
+	templateIdx int // Index into templates; len(templates) = no template
+	generating  bool
+}
+
+// PRDraftMsg is sent when a PR title and description have been generated.
+type PRDraftMsg struct {
+	WorkstreamID string
+	Template     string // Name of the template used, "" for none
+	Title        string
+	Body         string
+}
+
+// PRTemplateSelectMsg is sent when a different PR template is picked, so the
+// description can be generated again.
+type PRTemplateSelectMsg struct {
+	WorkstreamID string
+	Template     *git.PRTemplate // nil for no template
+}
+
+// PRCreateConfirmMsg is sent when the PR preview dialog is confirmed.
+type PRCreateConfirmMsg struct {
+	WorkstreamID string
+	Request      git.PRRequest
+}
+
+// GeneratePRDraftCmd returns a command that generates a PR title and
+// description, filled into tmpl when set.
+func GeneratePRDraftCmd(ws *workstream.Workstream, tmpl *git.PRTemplate) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+		defer cancel()
+
+		msg := PRDraftMsg{WorkstreamID: ws.ID}
+		var content string
+		if tmpl != nil {
+			msg.Template = tmpl.Name
+			content = tmpl.Content
+		}
+		gitRepo := GitClientFactory(resolveWorktreePath(ws))
+		msg.Title, msg.Body = git.GeneratePRContent(ctx, gitRepo, ws.BranchName, ws.Prompt, content)
+		return msg
+	}
+}
+
+// NewPRCreateDialog creates the PR preview dialog for a workstream. The
+// fields start with the configured defaults; the title and description are
+// filled in by SetPRDraft once generated.
+func NewPRCreateDialog(ws *workstream.Workstream, templates []git.PRTemplate, cfg docker.PRConfig) DialogModel {
+	d := NewPRDialog(ws.BranchName, "", "")
+	d.WorkstreamID = ws.ID
+	d.Input.Placeholder = "PR title..."
+
+	values := [prFieldCount]string{
+		prFieldBase:      cfg.Base,
+		prFieldReviewers: strings.Join(cfg.Reviewers, ", "),
+		prFieldLabels:    strings.Join(cfg.Labels, ", "),
+		prFieldAssignees: strings.Join(cfg.Assignees, ", "),
+		prFieldMilestone: cfg.Milestone,
+	}
+	placeholders := [prFieldCount]string{
+		prFieldBase:      "default branch",
+		prFieldReviewers: "user, org/team",
+		prFieldLabels:    "label, label",
+		prFieldAssignees: "@me, user",
+		prFieldMilestone: "milestone name",
+	}
+	for i := range prFieldCount {
+		input := newOptionalInput(placeholders[i], values[i])
+		input.Blur()
+		d.pr.fields = append(d.pr.fields, input)
+	}
+
+	d.pr.draft = cfg.IsDraft()
+	d.pr.templates = templates
+	for i, t := range templates {
+		if cfg.Template != "" && (t.Name == cfg.Template || filepath.Base(t.Name) == cfg.Template) {
+			d.pr.templateIdx = i
+		}
+	}
+	d.pr.generating = true
+	d.Body = "Generating PR description..."
+	return d
+}
+
+// SelectedPRTemplate returns the chosen PR template, or nil for none.
+func (d *DialogModel) SelectedPRTemplate() *git.PRTemplate {
+	if d.pr.templateIdx >= len(d.pr.templates) {
+		return nil
+	}
+	return &d.pr.templates[d.pr.templateIdx]
+}
+
+// SetPRDraft fills in a generated title and description. Drafts generated
+// for a template that is no longer selected are ignored.
+func (d *DialogModel) SetPRDraft(msg PRDraftMsg) {
+	selected := ""
+	if t := d.SelectedPRTemplate(); t != nil {
+		selected = t.Name
+	}
+	if msg.Template != selected {
+		return
+	}
+	d.pr.generating = false
+	d.Input.SetValue(msg.Title)
+	d.Body = msg.Body
+}
+
+// splitPRList splits a comma or space separated list of names.
+func splitPRList(input string) []string {
+	var names []string
+	for _, name := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+		names = append(names, name)
+	}
+	return names
+}
+
+// prRequest builds the PR request from the dialog's fields.
+func (d *DialogModel) prRequest() git.PRRequest {
+	return git.PRRequest{
+		Title:     strings.TrimSpace(d.Input.Value()),
+		Body:      d.Body,
+		Base:      strings.TrimSpace(d.pr.fields[prFieldBase].Value()),
+		Draft:     d.pr.draft,
+		Reviewers: splitPRList(d.pr.fields[prFieldReviewers].Value()),
+		Labels:    splitPRList(d.pr.fields[prFieldLabels].Value()),
+		Assignees: splitPRList(d.pr.fields[prFieldAssignees].Value()),
+		Milestone: strings.TrimSpace(d.pr.fields[prFieldMilestone].Value()),
+	}
+}
+
+// setPRFocus moves the cursor to a field (0 = title).
+func (d *DialogModel) setPRFocus(focus int) {
+	d.pr.focus = (focus + prFieldCount + 1) % (prFieldCount + 1)
+	d.Input.Blur()
+	for i := range d.pr.fields {
+		d.pr.fields[i].Blur()
+	}
+	if d.pr.focus == 0 {
+		d.Input.Focus()
+	} else {
+		d.pr.fields[d.pr.focus-1].Focus()
+	}
+}
+
+// updatePRForm handles keys in the PR preview dialog. It reports false for
+// keys the dialog handles itself (closing it).
+func (d *DialogModel) updatePRForm(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		return nil, false
+	case "tab", "down":
+		d.setPRFocus(d.pr.focus + 1)
+		return nil, true
+	case "shift+tab", "up":
+		d.setPRFocus(d.pr.focus - 1)
+		return nil, true
+	case "ctrl+d":
+		d.pr.draft = !d.pr.draft
+		return nil, true
+	case "ctrl+t":
+		if len(d.pr.templates) == 0 {
+			return nil, true
+		}
+		d.pr.templateIdx = (d.pr.templateIdx + 1) % (len(d.pr.templates) + 1)
+		d.pr.generating = true
+		d.Body = "Generating PR description..."
+		msg := PRTemplateSelectMsg{WorkstreamID: d.WorkstreamID, Template: d.SelectedPRTemplate()}
+		return func() tea.Msg { return msg }, true
+	case "enter":
+		req := d.prRequest()
+		if d.pr.generating || req.Title == "" {
+			return nil, true
+		}
+		workstreamID := d.WorkstreamID
+		return func() tea.Msg { return PRCreateConfirmMsg{WorkstreamID: workstreamID, Request: req} }, true
+	}
+
+	var cmd tea.Cmd
+	if d.pr.focus == 0 {
+		d.Input, cmd = d.Input.Update(msg)
+	} else {
+		d.pr.fields[d.pr.focus-1], cmd = d.pr.fields[d.pr.focus-1].Update(msg)
+	}
+	return cmd, true
+}
+
+// viewPRForm renders the PR preview dialog with its fields and a preview of
+// the description.
+func (d DialogModel) viewPRForm() string {
+	var content strings.Builder
+	content.WriteString(DialogTitle.Render(d.Title))
+	content.WriteString("\n\n")
+
+	if len(d.pr.templates) > 0 {
+		name := "none"
+		if t := d.SelectedPRTemplate(); t != nil {
+			name = t.Name
+		}
+		content.WriteString(fmt.Sprintf("%-10s %s  %s\n", "Template", name, KeyHintStyle.Render(fmt.Sprintf("(%d found)", len(d.pr.templates)))))
+	}
+	content.WriteString(fmt.Sprintf("%-10s %s\n", "Title", d.Input.View()))
+	for i, field := range d.pr.fields {
+		content.WriteString(fmt.Sprintf("%-10s %s\n", prFieldNames[i], field.View()))
+	}
+	draft := "[ ]"
+	if d.pr.draft {
+		draft = "[x]"
+	}
+	content.WriteString(fmt.Sprintf("%-10s %s\n\n", "Draft", draft))
+
+	// Preview as much of the description as fits
+	lines := strings.Split(strings.TrimRight(d.Body, "\n"), "\n")
+	maxLines := max(d.height-prFieldCount-14, 3)
+	if len(lines) > maxLines {
+		more := len(lines) - maxLines + 1
+		lines = append(lines[:maxLines-1], fmt.Sprintf("… %d more line(s)", more))
+	}
+	bodyStyle := DialogInputFocused.Width(d.width - 10)
+	content.WriteString(bodyStyle.Render(strings.Join(lines, "\n")))
+	content.WriteString("\n\n")
+
+	hints := KeyHint("Tab", " next field") + "  " + KeyHint("Ctrl+D", " draft") + "  "
+	if len(d.pr.templates) > 0 {
+		hints += KeyHint("Ctrl+T", " template") + "  "
+	}
+	if d.pr.generating {
+		hints += KeyHintStyle.Render("Generating...") + "  "
+	} else {
+		hints += KeyHint("Enter", " create") + "  "
+	}
+	content.WriteString(hints + KeyHintStyle.Render("[Esc] Cancel"))
+	return DialogBox.Width(d.width).Render(content.String())
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPRCreateDialog(t *testing.T) {
+	ws := workstream.NewWithID("1", "feature", "Add login")
+	templates := []git.PRTemplate{
+		{Name: ".github/PULL_REQUEST_TEMPLATE/bug_fix.md", Content: "## Bug"},
+		{Name: ".github/PULL_REQUEST_TEMPLATE/feature.md", Content: "## Feature"},
+	}
+	draft := true
+	d := NewPRCreateDialog(ws, templates, docker.PRConfig{Template: "feature.md", Reviewers: []string{"alice"}, Draft: &draft})
+	d.SetSize(100, 40)
+
+	if tmpl := d.SelectedPRTemplate(); tmpl == nil || tmpl.Name != templates[1].Name {
+		t.Fatalf("SelectedPRTemplate() = %v, want the configured feature.md", tmpl)
+	}
+
+	// Enter does nothing until the description is generated
+	if _, cmd := d.Update(specialKey(tea.KeyEnter)); cmd != nil {
+		t.Error("the PR can't be created while generating")
+	}
+	d.SetPRDraft(PRDraftMsg{Template: templates[0].Name, Title: "stale"})
+	if d.Input.Value() != "" {
+		t.Error("a draft for another template should be ignored")
+	}
+	d.SetPRDraft(PRDraftMsg{Template: templates[1].Name, Title: "Add login", Body: "## Feature\nLogin form"})
+
+	view := ansi.Strip(d.View())
+	for _, want := range []string{"Template   .github/PULL_REQUEST_TEMPLATE/feature.md", "Reviewers", "Draft      [x]", "Login form"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Tab to the labels field, add a label, turn off draft and create
+	for _, key := range []tea.KeyPressMsg{specialKey(tea.KeyTab), specialKey(tea.KeyTab), specialKey(tea.KeyTab), keyPress('u'), keyPress('i'), ctrlKey('d')} {
+		d, _ = d.Update(key)
+	}
+	_, cmd := d.Update(specialKey(tea.KeyEnter))
+	if cmd == nil {
+		t.Fatal("Enter should create the PR")
+	}
+	confirm := cmd().(PRCreateConfirmMsg)
+	want := git.PRRequest{Title: "Add login", Body: "## Feature\nLogin form", Reviewers: []string{"alice"}, Labels: []string{"ui"}}
+	if !reflect.DeepEqual(confirm.Request, want) {
+		t.Errorf("request = %+v, want %+v", confirm.Request, want)
+	}
+
+	// Picking another template regenerates the description
+	d, cmd = d.Update(ctrlKey('t'))
+	if sel := cmd().(PRTemplateSelectMsg); sel.Template != nil {
+		t.Errorf("after the last template comes no template, got %v", sel.Template)
+	}
+	if _, cmd := d.Update(specialKey(tea.KeyEnter)); cmd != nil {
+		t.Error("the PR can't be created while regenerating")
+	}
+}
+
+func TestAppModel_CreatePRPreview(t *testing.T) {
+	app := newTestApp(t)
+	ws := app.panes[0].Workstream()
+
+	model, cmd := app.Update(MergeConfirmMsg{Action: MergeActionCreatePR, WorkstreamID: ws.ID})
+	app = model.(AppModel)
+	if app.dialog == nil || app.dialog.Type != DialogPRPreview || cmd == nil {
+		t.Fatal("creating a PR should open the preview and generate a description")
+	}
+
+	model, _ = app.Update(PRDraftMsg{WorkstreamID: ws.ID, Title: "Add alpha", Body: "body"})
+	app = model.(AppModel)
+	if app.dialog.Input.Value() != "Add alpha" {
+		t.Errorf("title = %q, want the generated title", app.dialog.Input.Value())
+	}
+
+	model, cmd = app.Update(PRCreateConfirmMsg{WorkstreamID: ws.ID, Request: git.PRRequest{Title: "Add alpha"}})
+	app = model.(AppModel)
+	if app.dialog != nil || cmd == nil {
+		t.Error("confirming should close the preview and create the PR")
+	}
+	if dialog := app.panes[0].GetInPaneDialog(); dialog == nil || dialog.Type != DialogProgress {
+		t.Error("PR creation progress should show in the pane")
+	}
+}
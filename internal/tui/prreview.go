@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// PRReviewsMsg is sent when a PR's review feedback is fetched.
+type PRReviewsMsg struct {
+	WorkstreamID string
+	Reviews      *git.PRReviews
+	Error        error
+}
+
+// FetchPRReviewsCmd returns a command that fetches the reviews and review
+// threads of a workstream's PR.
+func FetchPRReviewsCmd(ws *workstream.Workstream, number int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		worktreePath := resolveWorktreePath(ws)
+		if worktreePath == "" {
+			return PRReviewsMsg{WorkstreamID: ws.ID, Error: fmt.Errorf("no worktree path")}
+		}
+
+		reviews, err := git.NewGH().GetPRReviews(ctx, worktreePath, number)
+		if err != nil {
+			return PRReviewsMsg{WorkstreamID: ws.ID, Error: err}
+		}
+		return PRReviewsMsg{WorkstreamID: ws.ID, Reviews: reviews}
+	}
+}
+
+// hasPRFeedback reports whether a PR has feedback for Claude to address:
+// unresolved review threads or reviews requesting changes.
+func hasPRFeedback(r *git.PRReviews) bool {
+	return r != nil && (len(r.Unresolved()) > 0 || len(r.ChangesRequested()) > 0)
+}
+
+// prFeedbackSummary describes a PR's feedback, e.g.
+// "2 unresolved comment(s), changes requested by alice".
+func prFeedbackSummary(r *git.PRReviews) string {
+	var parts []string
+	if n := len(r.Unresolved()); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d unresolved comment(s)", n))
+	}
+	var authors []string
+	for _, rv := range r.ChangesRequested() {
+		authors = append(authors, rv.Author)
+	}
+	if len(authors) > 0 {
+		parts = append(parts, "changes requested by "+strings.Join(authors, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// indentBody indents every line of a multi-line comment body.
+func indentBody(body, indent string) string {
+	return indent + strings.ReplaceAll(strings.TrimSpace(body), "\n", "\n"+indent)
+}
+
+// lastHunkLine returns the diff line a review comment was left on, which
+// GitHub sends as the last line of the comment's diff hunk.
+func lastHunkLine(hunk string) string {
+	lines := strings.Split(strings.TrimRight(hunk, "\n"), "\n")
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, "@@") {
+		return last
+	}
+	return ""
+}
+
+// formatPRFeedbackPrompt formats a PR's unresolved feedback into a single
+// follow-up prompt for Claude.
+func formatPRFeedbackPrompt(r *git.PRReviews) string {
+	var b strings.Builder
+	threads := r.Unresolved()
+	fmt.Fprintf(&b, "Review feedback on PR #%d: %s. Please address each point, then commit and push the fixes.\n", r.Number, prFeedbackSummary(r))
+	for _, rv := range r.ChangesRequested() {
+		if strings.TrimSpace(rv.Body) != "" {
+			fmt.Fprintf(&b, "\n%s requested changes:\n%s\n", rv.Author, indentBody(rv.Body, "   "))
+		}
+	}
+	for i, t := range threads {
+		loc := t.Path
+		if t.Line > 0 {
+			loc = fmt.Sprintf("%s:%d", t.Path, t.Line)
+		}
+		if t.IsOutdated {
+			loc += " (outdated, the code has changed since)"
+		}
+		fmt.Fprintf(&b, "\n%d. %s\n", i+1, loc)
+		if code := lastHunkLine(t.Comments[0].DiffHunk); code != "" {
+			fmt.Fprintf(&b, "   > %s\n", code)
+		}
+		for _, c := range t.Comments {
+			fmt.Fprintf(&b, "   %s:\n%s\n", c.Author, indentBody(c.Body, "     "))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// handlePRReviews stores fetched PR feedback on the pane and notifies the
+// user once about new feedback.
+func (m *AppModel) handlePRReviews(msg PRReviewsMsg) {
+	i := m.paneIndexByID(msg.WorkstreamID)
+	if i < 0 {
+		return
+	}
+	if msg.Error != nil {
+		LogWarn("PR review fetch failed for workstream %s: %v", msg.WorkstreamID, msg.Error)
+		return
+	}
+	m.panes[i].SetPRReviews(msg.Reviews)
+
+	ws := m.panes[i].Workstream()
+	latest := msg.Reviews.LatestActivity()
+	if !hasPRFeedback(msg.Reviews) || !latest.After(ws.GetPRFeedbackSeen()) {
+		return
+	}
+	ws.SetPRFeedbackSeen(latest)
+	m.managerFor(ws).UpdateWorkstream(ws.ID)
+	summary := prFeedbackSummary(msg.Reviews)
+	m.panes[i].AppendOutput(fmt.Sprintf("\nNew review feedback on PR #%d: %s. Press F to send it to Claude.\n", msg.Reviews.Number, summary))
+	m.toast = fmt.Sprintf("PR #%d (%s): %s", msg.Reviews.Number, ws.BranchName, summary)
+	m.toastExpiry = time.Now().Add(toastDuration * 2)
+}
+
+// sendPRFeedback sends a pane's unresolved PR feedback to its Claude,
+// resuming its session if it ended.
+func (m *AppModel) sendPRFeedback(i int) tea.Cmd {
+	reviews := m.panes[i].GetPRReviews()
+	if !hasPRFeedback(reviews) {
+		m.toast = "No unresolved PR review feedback"
+		m.toastExpiry = time.Now().Add(toastDuration)
+		return nil
+	}
+	cmd, err := m.promptClaude(i, formatPRFeedbackPrompt(reviews))
+	if err != nil {
+		m.toast = fmt.Sprintf("Could not send PR feedback: %v", err)
+		m.toastExpiry = time.Now().Add(toastDuration * 2)
+		return nil
+	}
+	m.toast = fmt.Sprintf("Sent PR #%d feedback to Claude", reviews.Number)
+	m.toastExpiry = time.Now().Add(toastDuration)
+	return cmd
+}
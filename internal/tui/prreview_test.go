@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/git"
+)
+
+func TestAppModel_PRReviewFeedback(t *testing.T) {
+	// Shares its fixture with the git package's tests
+	fixture, err := filepath.Abs(filepath.Join("..", "git", "testdata", "pr_reviews.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeExecutable(t, "gh", "cat "+fixture+"\n")
+	app := newTestApp(t)
+	ws := app.panes[0].Workstream()
+	ws.WorktreePath = t.TempDir()
+	ws.SetPRInfo(42, "https://github.com/o/r/pull/42")
+
+	// A successful status poll checks the PR for review feedback
+	model, cmd := app.Update(PRStatusMsg{WorkstreamID: ws.ID, Status: &git.PRStatusInfo{Forge: git.ForgeGitHub, Number: 42}})
+	app = model.(AppModel)
+	if cmd == nil {
+		t.Fatal("PR status should trigger a review fetch")
+	}
+	reviewsMsg := cmd().(PRReviewsMsg)
+	if reviewsMsg.Error != nil {
+		t.Fatalf("fetch error = %v", reviewsMsg.Error)
+	}
+	model, _ = app.Update(reviewsMsg)
+	app = model.(AppModel)
+
+	if !strings.Contains(app.panes[0].output.String(), "New review feedback on PR #42: 2 unresolved comment(s), changes requested by alice. Press F") {
+		t.Errorf("pane output = %q, want a notification", app.panes[0].output.String())
+	}
+	if ws.GetPRFeedbackSeen().IsZero() {
+		t.Error("the notified feedback should be recorded as seen")
+	}
+
+	// The same feedback is not announced twice
+	app.toast = ""
+	model, _ = app.Update(reviewsMsg)
+	app = model.(AppModel)
+	if app.toast != "" {
+		t.Errorf("toast = %q, want no repeat notification", app.toast)
+	}
+
+	// Claude's session ended: it is resumed with the feedback
+	ws.SetContainerID("container-alpha")
+	model, cmd = app.Update(keyPress('F'))
+	app = model.(AppModel)
+	if cmd == nil || !strings.Contains(app.panes[0].output.String(), "Resuming Claude's session") {
+		t.Errorf("output = %q, want the ended session resumed with the feedback", app.panes[0].output.String())
+	}
+
+	stdin := &mockWriteCloser{}
+	app.panes[0].SetPTY(&PTYSession{workstreamID: ws.ID, done: make(chan struct{}), stdin: stdin})
+	model, _ = app.Update(keyPress('F'))
+	app = model.(AppModel)
+	sent := string(stdin.Bytes())
+	for _, want := range []string{
+		"Review feedback on PR #42",
+		"alice requested changes:\n   Needs tests",
+		"1. api.go:12\n   > +x, _ := f()\n   alice:\n     Handle the error\n   bob:\n     Agreed",
+	} {
+		if !strings.Contains(sent, want) {
+			t.Errorf("sent prompt missing %q:\n%s", want, sent)
+		}
+	}
+	if strings.Contains(sent, "done.go") {
+		t.Error("resolved threads should not be sent")
+	}
+}
+
+func TestAppModel_SendPRFeedbackWithoutFeedback(t *testing.T) {
+	app := newTestApp(t)
+	model, _ := app.Update(keyPress('F'))
+	app = model.(AppModel)
+	if !strings.Contains(app.toast, "No unresolved PR review feedback") {
+		t.Errorf("toast = %q", app.toast)
+	}
+}
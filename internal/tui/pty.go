@@ -216,10 +216,36 @@ type PTYOptions struct {
 	EnvVars         []string // Additional environment variables in "KEY=value" format
 	IsResume        bool     // If true, use 'claude --resume' instead of starting new session
 	ClaudeSessionID string   // Claude session ID for --resume (if available)
+	ResumePrompt    string   // Message to submit in the resumed session (resume only)
 	HostProjectPath string   // Host project path for finding session data (encoded for .claude/projects/)
 	Runtime         string   // Runtime to use: "claude" (default) or "claudesp" (experimental)
 }
 
+// claudeCommand returns the shell command that starts Claude Code: a new
+// session with an optional initial prompt, or a resumed one.
+func claudeCommand(initialPrompt string, opts *PTYOptions) string {
+	if opts != nil && opts.IsResume {
+		var cmd string
+		if opts.ClaudeSessionID != "" {
+			// Use --resume with explicit session ID (preferred)
+			cmd = `exec claude --dangerously-skip-permissions --resume "` + escapeShellArg(opts.ClaudeSessionID) + `"`
+		} else {
+			// Fall back to --continue if no session ID available
+			cmd = `exec claude --dangerously-skip-permissions --continue`
+		}
+		if opts.ResumePrompt != "" {
+			cmd += ` "` + escapeShellArg(opts.ResumePrompt) + `"`
+		}
+		return cmd
+	}
+	if initialPrompt != "" {
+		// New session with initial prompt
+		return `exec claude --dangerously-skip-permissions "` + escapeShellArg(initialPrompt) + `"`
+	}
+	// New session without prompt
+	return `exec claude --dangerously-skip-permissions`
+}
+
 // NewPTYSession creates a new PTY session for running Claude Code in a container.
 func NewPTYSession(ctx context.Context, dockerClient *client.Client, containerID, workstreamID, initialPrompt string, opts *PTYOptions) (*PTYSession, error) {
 	// Default terminal size
@@ -235,23 +261,7 @@ func NewPTYSession(ctx context.Context, dockerClient *client.Client, containerID
 	}
 
 	// Build the command to run Claude Code using the shared setup script
-	var setupCmd string
-	if opts != nil && opts.IsResume {
-		// Resume existing session
-		if opts.ClaudeSessionID != "" {
-			// Use --resume with explicit session ID (preferred)
-			setupCmd = containerSetupScript + `exec claude --dangerously-skip-permissions --resume "` + escapeShellArg(opts.ClaudeSessionID) + `"`
-		} else {
-			// Fall back to --continue if no session ID available
-			setupCmd = containerSetupScript + `exec claude --dangerously-skip-permissions --continue`
-		}
-	} else if initialPrompt != "" {
-		// New session with initial prompt
-		setupCmd = containerSetupScript + `exec claude --dangerously-skip-permissions "` + escapeShellArg(initialPrompt) + `"`
-	} else {
-		// New session without prompt
-		setupCmd = containerSetupScript + `exec claude --dangerously-skip-permissions`
-	}
+	setupCmd := containerSetupScript + claudeCommand(initialPrompt, opts)
 	cmd := []string{"/bin/bash", "-c", setupCmd}
 
 	// Build environment variables
@@ -88,6 +88,28 @@ func TestPTYOptions(t *testing.T) {
 	}
 }
 
+func TestClaudeCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt string
+		opts   *PTYOptions
+		want   string
+	}{
+		{"new session", "", nil, `exec claude --dangerously-skip-permissions`},
+		{"initial prompt", `fix "it"`, nil, `exec claude --dangerously-skip-permissions "fix \"it\""`},
+		{"resume ignores initial prompt", "task", &PTYOptions{IsResume: true, ClaudeSessionID: "abc"}, `exec claude --dangerously-skip-permissions --resume "abc"`},
+		{"continue", "", &PTYOptions{IsResume: true}, `exec claude --dangerously-skip-permissions --continue`},
+		{"resume with prompt", "", &PTYOptions{IsResume: true, ClaudeSessionID: "abc", ResumePrompt: "resolve"}, `exec claude --dangerously-skip-permissions --resume "abc" "resolve"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := claudeCommand(tt.prompt, tt.opts); got != tt.want {
+				t.Errorf("claudeCommand() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPTYOutputMsg(t *testing.T) {
 	msg := PTYOutputMsg{
 		WorkstreamID: "test-ws",
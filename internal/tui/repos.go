@@ -0,0 +1,396 @@
+package tui
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/claude"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/hooks"
+	"github.com/STRML/claude-cells/internal/orchestrator"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// repoContext is a repository opened in addition to the one ccells was
+// started in. Each has its own state directory and persistent manager; the
+// primary repository keeps using AppModel's own fields. Container operations
+// create an orchestrator for the workstream's repository as needed (see
+// workstreamOrchestrator).
+type repoContext struct {
+	Name     string
+	Path     string // Repository root on the host
+	StateDir string // ~/.claude-cells/state/<repo-id>/
+	Manager  *workstream.PersistentManager
+	Hooks    *hooks.Runner           // Lifecycle hooks from the repository's config
+	Budget   docker.BudgetConfig     // Budgets from the repository's config
+	Spent    map[string]claude.Usage // Usage of destroyed and archived workstreams by ID
+	lock     *workstream.Lock
+}
+
+// Close flushes the repository's state and releases its lock.
+func (r *repoContext) Close() {
+	r.Manager.Close()
+	r.lock.Release()
+}
+
+// RepoOpenedMsg is sent when an additional repository has been opened.
+type RepoOpenedMsg struct {
+	Path  string // Path as requested
+	Repo  *repoContext
+	Error error
+}
+
+// OpenRepoCmd opens the repository at path.
+func OpenRepoCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		repo, err := openRepoContext(ctx, path)
+		return RepoOpenedMsg{Path: path, Repo: repo, Error: err}
+	}
+}
+
+// loadRepoStateCmd loads the saved state of an opened repository.
+func loadRepoStateCmd(repo *repoContext) tea.Cmd {
+	load := LoadStateCmd(repo.StateDir)
+	return func() tea.Msg {
+		msg := load().(StateLoadedMsg)
+		msg.RepoPath = repo.Path
+		return msg
+	}
+}
+
+// resolveRepoPath expands and cleans a user-entered repository path.
+func resolveRepoPath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return filepath.Abs(path)
+}
+
+// openRepoContext validates the repository at path and sets up its state
+// directory and manager. It locks the state directory like
+// ccells does on startup, so it fails if another instance has the repository
+// open; the lock is released by Close.
+func openRepoContext(ctx context.Context, path string) (*repoContext, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("no repository path given")
+	}
+	root, err := resolveRepoPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(root, ".git")); err != nil {
+		return nil, fmt.Errorf("%s is not the root of a git repository", root)
+	}
+
+	gitOps := GitClientFactory(root)
+	repoID, err := gitOps.RepoID(ctx)
+	if err != nil || repoID == "" {
+		return nil, fmt.Errorf("cannot identify repository %s: %v", root, err)
+	}
+	stateDir, err := workstream.GetStateDir(repoID)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := workstream.AcquireLock(stateDir)
+	if err != nil {
+		if errors.Is(err, workstream.ErrLockedByThisProcess) {
+			return nil, fmt.Errorf("%s is already open", filepath.Base(root))
+		}
+		return nil, fmt.Errorf("%v (if it crashed, delete %s)", err, filepath.Join(stateDir, workstream.LockFileName))
+	}
+
+	remoteURL, _ := gitOps.RemoteURL(ctx, "origin")
+	manager := workstream.NewPersistentManager(stateDir)
+	manager.SetRepoInfo(&workstream.RepoInfo{
+		Name:      filepath.Base(root),
+		Path:      root,
+		Remote:    remoteURL,
+		RepoID:    repoID,
+		CreatedAt: time.Now(),
+	})
+
+	cellsCfg := docker.LoadConfig(root)
+	repo := &repoContext{
+		Name:     filepath.Base(root),
+		Path:     root,
+		StateDir: stateDir,
+		Manager:  manager,
+		Hooks:    hooks.NewRunner(cellsCfg.Hooks, root),
+		Budget:   cellsCfg.Budget,
+		lock:     lock,
+	}
+	if spent, err := workstream.LoadSpend(stateDir); err == nil {
+		repo.Spent = spent
+	}
+	return repo, nil
+}
+
+// repoWorktreeBaseDir returns where worktrees of a repository are created.
+// The primary repository (empty path) uses the default location; other
+// repositories get their own subdirectory so equal branch names don't collide.
+func repoWorktreeBaseDir(repoPath string) string {
+	if repoPath == "" {
+		return orchestrator.DefaultWorktreeBaseDir
+	}
+	sum := sha256.Sum256([]byte(repoPath))
+	return filepath.Join(orchestrator.DefaultWorktreeBaseDir, "repos", fmt.Sprintf("%s-%x", filepath.Base(repoPath), sum[:4]))
+}
+
+// newRepoOrchestrator creates an orchestrator for the repository at repoPath.
+// Repositories other than the primary one keep their worktrees apart.
+func newRepoOrchestrator(dockerClient docker.DockerClient, repoPath string, primary bool) *orchestrator.Orchestrator {
+	gitFactory := func(path string) git.GitClient {
+		return GitClientFactory(path)
+	}
+	orch := orchestrator.New(dockerClient, gitFactory, repoPath)
+	if !primary {
+		orch.SetWorktreeBaseDir(repoWorktreeBaseDir(repoPath))
+	}
+	return orch
+}
+
+// workstreamOrchestrator creates an orchestrator for the repository a
+// workstream belongs to, rooted at repoPath.
+func workstreamOrchestrator(dockerClient docker.DockerClient, ws *workstream.Workstream, repoPath string) *orchestrator.Orchestrator {
+	return newRepoOrchestrator(dockerClient, repoPath, ws.RepoPath == "")
+}
+
+// workstreamRepoPath returns the host repository a workstream belongs to.
+func workstreamRepoPath(ws *workstream.Workstream) (string, error) {
+	if ws.RepoPath != "" {
+		return ws.RepoPath, nil
+	}
+	return os.Getwd()
+}
+
+// repoFor returns the opened repository a workstream belongs to, or nil for
+// the primary repository.
+func (m *AppModel) repoFor(ws *workstream.Workstream) *repoContext {
+	return m.repoByPath(ws.RepoPath)
+}
+
+// repoByPath returns the opened repository with the given root, if any.
+func (m *AppModel) repoByPath(path string) *repoContext {
+	if path == "" {
+		return nil
+	}
+	for _, r := range m.repos {
+		if r.Path == path {
+			return r
+		}
+	}
+	return nil
+}
+
+// managerFor returns the persistent manager holding a workstream.
+func (m *AppModel) managerFor(ws *workstream.Workstream) *workstream.PersistentManager {
+	if r := m.repoFor(ws); r != nil {
+		return r.Manager
+	}
+	return m.manager
+}
+
+// stateDirFor returns the state directory of a workstream's repository.
+func (m *AppModel) stateDirFor(ws *workstream.Workstream) string {
+	if r := m.repoFor(ws); r != nil {
+		return r.StateDir
+	}
+	return m.stateDir
+}
+
+// repoDir returns the host path of a workstream's repository.
+func (m *AppModel) repoDir(ws *workstream.Workstream) string {
+	return m.repoPathDir(ws.RepoPath)
+}
+
+// repoPathDir returns the host path of the repository with the given
+// RepoPath ("" for the one ccells was started in).
+func (m *AppModel) repoPathDir(repoPath string) string {
+	if repoPath != "" {
+		return repoPath
+	}
+	return m.workingDir
+}
+
+// hooksFor returns the hooks runner of a workstream's repository.
+func (m *AppModel) hooksFor(ws *workstream.Workstream) *hooks.Runner {
+	if r := m.repoFor(ws); r != nil {
+		return r.Hooks
+	}
+	return m.hooks
+}
+
+// repoPaths returns the RepoPath of every open repository, "" for the
+// primary one first.
+func (m *AppModel) repoPaths() []string {
+	paths := []string{""}
+	for _, r := range m.repos {
+		paths = append(paths, r.Path)
+	}
+	return paths
+}
+
+// repoName returns the name of the open repository with the given RepoPath.
+func (m *AppModel) repoName(repoPath string) string {
+	if r := m.repoByPath(repoPath); r != nil {
+		return r.Name
+	}
+	return filepath.Base(m.workingDir)
+}
+
+// paneRepoName returns the repository name shown in a pane's header, or ""
+// when only one repository is open.
+func (m *AppModel) paneRepoName(ws *workstream.Workstream) string {
+	if len(m.repos) == 0 {
+		return ""
+	}
+	if r := m.repoFor(ws); r != nil {
+		return r.Name
+	}
+	return filepath.Base(m.workingDir)
+}
+
+// repoNames returns the names of all open repositories, primary first.
+func (m *AppModel) repoNames() []string {
+	names := []string{filepath.Base(m.workingDir)}
+	for _, r := range m.repos {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// workstreamCount returns the number of workstreams across all repositories.
+func (m *AppModel) workstreamCount() int {
+	n := m.manager.Count()
+	for _, r := range m.repos {
+		n += r.Manager.Count()
+	}
+	return n
+}
+
+// closeManagers flushes and stops the persistent managers of all repositories.
+func (m *AppModel) closeManagers() {
+	m.manager.Close()
+	for _, r := range m.repos {
+		r.Close()
+	}
+}
+
+// repoStateDirs maps the root of each opened repository to its state directory.
+func (m *AppModel) repoStateDirs() map[string]string {
+	dirs := make(map[string]string, len(m.repos))
+	for _, r := range m.repos {
+		dirs[r.Path] = r.StateDir
+	}
+	return dirs
+}
+
+// saveRepoList records the opened repositories so they reopen on startup.
+func (m *AppModel) saveRepoList() {
+	paths := make([]string, 0, len(m.repos))
+	for _, r := range m.repos {
+		paths = append(paths, r.Path)
+	}
+	if err := workstream.SaveRepoList(m.stateDir, paths); err != nil {
+		LogWarn("Failed to save repository list: %v", err)
+	}
+}
+
+// reopenReposCmd reopens the repositories saved by a previous session.
+func reopenReposCmd(stateDir string) tea.Cmd {
+	paths, err := workstream.LoadRepoList(stateDir)
+	if err != nil {
+		LogWarn("Failed to load repository list: %v", err)
+		return nil
+	}
+	cmds := make([]tea.Cmd, 0, len(paths))
+	for _, p := range paths {
+		cmds = append(cmds, OpenRepoCmd(p))
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleRepoOpened adds an opened repository and loads its saved workstreams.
+func (m *AppModel) handleRepoOpened(msg RepoOpenedMsg) tea.Cmd {
+	if msg.Error != nil {
+		m.toast = fmt.Sprintf("Cannot open repository: %v", msg.Error)
+		m.toastExpiry = time.Now().Add(toastDuration * 2)
+		return nil
+	}
+	repo := msg.Repo
+	if repo.Path == m.workingDir || m.repoByPath(repo.Path) != nil {
+		repo.Close()
+		m.toast = fmt.Sprintf("%s is already open", repo.Name)
+		m.toastExpiry = time.Now().Add(toastDuration)
+		return nil
+	}
+	m.repos = append(m.repos, repo)
+	m.saveRepoList()
+	LogInfo("Opened repository %s (%s)", repo.Name, repo.Path)
+	return loadRepoStateCmd(repo)
+}
+
+// restoreRepoState restores the saved workstreams of an opened repository.
+// Focus and layout stay as they are.
+func (m *AppModel) restoreRepoState(msg StateLoadedMsg) tea.Cmd {
+	repo := m.repoByPath(msg.RepoPath)
+	if repo == nil {
+		return nil
+	}
+	if msg.Error != nil {
+		m.toast = fmt.Sprintf("Failed to load state of %s: %v", repo.Name, msg.Error)
+		m.toastExpiry = time.Now().Add(toastDuration * 2)
+		return nil
+	}
+	if msg.State == nil || len(msg.State.Workstreams) == 0 {
+		m.toast = fmt.Sprintf("Opened %s", repo.Name)
+		m.toastExpiry = time.Now().Add(toastDuration)
+		return nil
+	}
+
+	cmds := m.restoreWorkstreams(msg.State, msg.Events, repo.Path, repo.Manager)
+	if len(m.panes) > 0 {
+		m.panes[m.focusedPane].SetFocused(true)
+	}
+	m.updateLayoutQuiet()
+	m.toast = fmt.Sprintf("Opened %s: resumed %d workstream(s)", repo.Name, len(msg.State.Workstreams))
+	m.toastExpiry = time.Now().Add(toastDuration)
+	cmds = append(cmds, RefreshUsageCmd(m.workstreams()))
+	return tea.Batch(cmds...)
+}
+
+// NewOpenRepoDialog creates the dialog asking for a repository to open.
+func NewOpenRepoDialog(open []string) DialogModel {
+	body := fmt.Sprintf(`Open: %s
+
+Enter the path of another git repository. Its workstreams get their own
+state, worktrees and git proxy, and reopen on the next start.`, strings.Join(open, ", "))
+
+	return DialogModel{
+		Type:  DialogOpenRepo,
+		Title: "Open Repository",
+		Body:  body,
+		Input: newOptionalInput("e.g. ~/src/other-repo", ""),
+	}
+}
+
+// repoBadge renders the pane header tag naming a workstream's repository.
+func repoBadge(name string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Render("[" + name + "]")
+}
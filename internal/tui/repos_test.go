@@ -0,0 +1,301 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/claude"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/hooks"
+	"github.com/STRML/claude-cells/internal/orchestrator"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// newTestRepoDir creates a directory that looks like a repository root.
+func newTestRepoDir(t *testing.T, name string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), name)
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func mockRepoIDs(t *testing.T) {
+	t.Helper()
+	restore := SetGitClientFactory(func(path string) git.GitClient {
+		mock := git.NewMockGitClient()
+		mock.RepoIDFn = func(ctx context.Context) (string, error) {
+			return "id-" + filepath.Base(path), nil
+		}
+		return mock
+	})
+	t.Cleanup(restore)
+}
+
+func TestRepoWorktreeBaseDir(t *testing.T) {
+	if got := repoWorktreeBaseDir(""); got != orchestrator.DefaultWorktreeBaseDir {
+		t.Errorf("primary base dir = %q, want default", got)
+	}
+	a := repoWorktreeBaseDir("/src/one/api")
+	b := repoWorktreeBaseDir("/src/two/api")
+	if a == b {
+		t.Error("repositories with the same name must not share worktrees")
+	}
+	if !strings.HasPrefix(filepath.Base(a), "api-") {
+		t.Errorf("base dir %q should be named after the repository", a)
+	}
+
+	ws := workstream.NewWithID("1", "feature/x", "task")
+	ws.RepoPath = "/src/one/api"
+	if got, want := resolveWorktreePath(ws), filepath.Join(a, "feature-x"); got != want {
+		t.Errorf("resolveWorktreePath() = %q, want %q", got, want)
+	}
+}
+
+func TestOpenRepoContext(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	mockRepoIDs(t)
+
+	dir := newTestRepoDir(t, "api")
+	repo, err := openRepoContext(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("openRepoContext() error = %v", err)
+	}
+	if repo.Name != "api" || repo.Path != dir {
+		t.Errorf("repo = %s at %s, want api at %s", repo.Name, repo.Path, dir)
+	}
+	if filepath.Base(repo.StateDir) != "id-api" {
+		t.Errorf("StateDir = %q, want one keyed by the repo ID", repo.StateDir)
+	}
+	lockPath := filepath.Join(repo.StateDir, workstream.LockFileName)
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Error("the repository's state directory should be locked")
+	}
+	repo.Close()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Error("Close should release the lock")
+	}
+
+	// Another running instance holds the lock
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(os.Getppid())), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openRepoContext(context.Background(), dir); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("openRepoContext() error = %v, want the repository to be refused", err)
+	}
+
+	if _, err := openRepoContext(context.Background(), t.TempDir()); err == nil {
+		t.Error("expected error for a directory that is not a repository")
+	}
+}
+
+func TestAppModel_OpenRepo(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	mockRepoIDs(t)
+
+	app := newTestApp(t)
+	app.stateDir = t.TempDir()
+	dir := newTestRepoDir(t, "api")
+
+	model, cmd := app.Update(OpenRepoCmd(dir)())
+	app = model.(AppModel)
+	if len(app.repos) != 1 || cmd == nil {
+		t.Fatalf("expected the repository to be opened and its state loaded")
+	}
+	if paths, _ := workstream.LoadRepoList(app.stateDir); len(paths) != 1 || paths[0] != dir {
+		t.Errorf("saved repo list = %v, want [%s]", paths, dir)
+	}
+
+	// Opening it again is refused
+	model, _ = app.Update(OpenRepoCmd(dir)())
+	app = model.(AppModel)
+	if len(app.repos) != 1 || !strings.Contains(app.toast, "already open") {
+		t.Errorf("reopening should be refused, toast = %q", app.toast)
+	}
+
+	// Saved workstreams of the repository are restored into its manager
+	state := &workstream.AppState{Workstreams: []workstream.SavedWorkstream{
+		{ID: "api-1", BranchName: "fix-api", Prompt: "fix the api"},
+	}}
+	model, _ = app.Update(StateLoadedMsg{State: state, RepoPath: dir})
+	app = model.(AppModel)
+	restored := app.panes[len(app.panes)-1].Workstream()
+	if restored.RepoPath != dir {
+		t.Errorf("restored RepoPath = %q, want %q", restored.RepoPath, dir)
+	}
+	if app.managerFor(restored) != app.repos[0].Manager || app.repos[0].Manager.Get("api-1") == nil {
+		t.Error("restored workstream should belong to the repository's manager")
+	}
+	if app.manager.Get("api-1") != nil {
+		t.Error("restored workstream must not be added to the primary manager")
+	}
+	if got := app.paneRepoName(restored); got != "api" {
+		t.Errorf("paneRepoName() = %q, want api", got)
+	}
+	if got := app.workstreamCount(); got != 4 {
+		t.Errorf("workstreamCount() = %d, want 4", got)
+	}
+
+	// New workstreams can be created in the opened repository
+	model, _ = app.Update(DialogConfirmMsg{Type: DialogNewWorkstream, Value: "add endpoint", Repo: 1})
+	app = model.(AppModel)
+	created := app.panes[len(app.panes)-1].Workstream()
+	if created.RepoPath != dir || app.repos[0].Manager.Get(created.ID) == nil {
+		t.Errorf("new workstream should be created in %s, got %q", dir, created.RepoPath)
+	}
+}
+
+func TestSaveAllStates_SplitsByRepository(t *testing.T) {
+	primaryDir, apiDir := t.TempDir(), t.TempDir()
+	local := workstream.NewWithID("1", "local", "local task")
+	api := workstream.NewWithID("2", "api", "api task")
+	api.RepoPath = "/src/api"
+	local2 := workstream.NewWithID("3", "local-2", "another local task")
+
+	err := saveAllStates(primaryDir, map[string]string{"/src/api": apiDir}, []*workstream.Workstream{local, api, local2}, 2, 1)
+	if err != nil {
+		t.Fatalf("saveAllStates() error = %v", err)
+	}
+
+	primary, err := workstream.LoadState(primaryDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primary.Workstreams) != 2 || primary.FocusedIndex != 1 || primary.Layout != 1 {
+		t.Errorf("primary state = %d workstreams, focus %d, layout %d; want 2, 1, 1",
+			len(primary.Workstreams), primary.FocusedIndex, primary.Layout)
+	}
+	other, err := workstream.LoadState(apiDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other.Workstreams) != 1 || other.Workstreams[0].ID != "2" {
+		t.Errorf("api state = %+v, want only workstream 2", other.Workstreams)
+	}
+}
+
+func TestAppModel_ArchiveAcrossRepositories(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	mockRepoIDs(t)
+
+	app := newTestApp(t)
+	app.stateDir = t.TempDir()
+	dir := newTestRepoDir(t, "api")
+	model, _ := app.Update(OpenRepoCmd(dir)())
+	app = model.(AppModel)
+	repo := app.repos[0]
+
+	local := workstream.NewArchivedWorkstream(workstream.NewWithID("local-1", "local-branch", "local task"))
+	local.ArchivedAt = time.Now().Add(-time.Hour)
+	if err := workstream.AddToArchive(app.stateDir, local); err != nil {
+		t.Fatal(err)
+	}
+	if err := workstream.AddToArchive(repo.StateDir, workstream.NewArchivedWorkstream(workstream.NewWithID("api-1", "api-branch", "api task"))); err != nil {
+		t.Fatal(err)
+	}
+
+	// The browser lists both repositories' archives, newest first
+	model, _ = app.Update(keyPress('A'))
+	app = model.(AppModel)
+	if app.dialog == nil || len(app.dialog.archiveEntries) != 2 {
+		t.Fatalf("expected both archives in the browser, got %+v", app.dialog)
+	}
+	if e := app.dialog.archiveEntries[0]; e.ID != "api-1" || e.RepoPath != dir {
+		t.Errorf("first entry = %s in %q, want api-1 in %s", e.ID, e.RepoPath, dir)
+	}
+
+	// Restoring puts the workstream back in its own repository
+	model, cmd := app.Update(DialogConfirmMsg{Type: DialogArchive, Value: "api-1"})
+	app = model.(AppModel)
+	if cmd == nil {
+		t.Fatal("expected restore command")
+	}
+	restored := app.panes[len(app.panes)-1].Workstream()
+	if restored.RepoPath != dir || repo.Manager.Get("api-1") == nil || app.manager.Get("api-1") != nil {
+		t.Errorf("restored RepoPath = %q, want it in the api repository's manager", restored.RepoPath)
+	}
+}
+
+func TestAppModel_BestOfNInFocusedRepository(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	mockRepoIDs(t)
+
+	app := newTestApp(t)
+	app.stateDir = t.TempDir()
+	dir := newTestRepoDir(t, "api")
+	model, _ := app.Update(OpenRepoCmd(dir)())
+	app = model.(AppModel)
+	repo := app.repos[0]
+
+	app.panes[app.focusedPane].Workstream().RepoPath = dir
+	app.startBestOfN("speed up the api", []string{"claude", "claude"})
+	for _, pane := range app.panes[len(app.panes)-2:] {
+		ws := pane.Workstream()
+		if ws.RepoPath != dir || repo.Manager.Get(ws.ID) == nil || app.manager.Get(ws.ID) != nil {
+			t.Errorf("cell %s RepoPath = %q, want it in the focused pane's repository", ws.BranchName, ws.RepoPath)
+		}
+	}
+}
+
+func TestNewWorkstreamDialog_RepoTemplates(t *testing.T) {
+	d := NewWorkstreamDialog()
+	d.SetTemplates([]docker.TemplateConfig{{Name: "bugfix"}})
+	d.SetRepos([]string{"main", "api"}, [][]docker.TemplateConfig{{{Name: "bugfix"}}, {{Name: "endpoint"}}})
+
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	if got := d.SelectedTemplate(); got != "bugfix" {
+		t.Errorf("SelectedTemplate() = %q, want the primary repository's template", got)
+	}
+	d, _ = d.Update(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl})
+	if d.SelectedRepo() != 1 || d.SelectedTemplate() != "" {
+		t.Fatalf("switching repositories should reset the template, got repo %d template %q", d.SelectedRepo(), d.SelectedTemplate())
+	}
+	d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	if got := d.SelectedTemplate(); got != "endpoint" {
+		t.Errorf("SelectedTemplate() = %q, want the api repository's template", got)
+	}
+}
+
+func TestAppModel_PerRepositoryConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	docker.SetTestCellsDir(t.TempDir())
+	defer docker.SetTestCellsDir("")
+	mockRepoIDs(t)
+
+	dir := newTestRepoDir(t, "api")
+	if err := os.MkdirAll(filepath.Join(dir, ".claude-cells"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "hooks:\n  push: [\"true\"]\nbudget:\n  project:\n    tokens: 1000\n"
+	if err := os.WriteFile(filepath.Join(dir, ".claude-cells", "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := newTestApp(t)
+	app.stateDir = t.TempDir()
+	model, _ := app.Update(OpenRepoCmd(dir)())
+	app = model.(AppModel)
+
+	alpha, beta := app.panes[0].Workstream(), app.panes[1].Workstream()
+	beta.RepoPath = dir
+	if app.hooksFor(beta) == app.hooksFor(alpha) || app.hooksFor(beta).HasHooks(hooks.EventPush) {
+		t.Error("hooks should run in each workstream's repository but ignore its project config")
+	}
+
+	// Only the api repository's workstreams count toward, and stop at, its budget
+	alpha.MergeSessionUsage(map[string]claude.Usage{"s1": {OutputTokens: 5000}})
+	beta.MergeSessionUsage(map[string]claude.Usage{"s2": {OutputTokens: 1500}})
+	app.checkBudgets(nil)
+	if app.panes[0].IsBudgetExceeded() || !app.panes[1].IsBudgetExceeded() {
+		t.Errorf("exceeded = %v, %v; want only the api workstream stopped",
+			app.panes[0].IsBudgetExceeded(), app.panes[1].IsBudgetExceeded())
+	}
+}
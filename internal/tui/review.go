@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// reviewCommentMaxLen caps the length of a single review comment.
+const reviewCommentMaxLen = 1000
+
+// ReviewCommentMsg is sent when a comment is added in the diff viewer.
+type ReviewCommentMsg struct {
+	WorkstreamID string
+	Comment      workstream.ReviewComment
+}
+
+// ReviewAddressedMsg is sent to toggle whether a review comment was addressed.
+type ReviewAddressedMsg struct {
+	WorkstreamID string
+	CommentID    int
+}
+
+// ReviewSubmitMsg is sent to send a workstream's pending review to Claude.
+type ReviewSubmitMsg struct {
+	WorkstreamID string
+}
+
+// diffLineRef is the file line a diff line refers to.
+type diffLineRef struct {
+	Line    int  // 0 for hunk headers and other non-code lines
+	Removed bool // Line numbers the old side
+}
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)`)
+
+// diffLineRefs maps each line of a file's hunks to the line it refers to:
+// the new side for added and context lines, the old side for removed lines.
+func diffLineRefs(lines []string) []diffLineRef {
+	refs := make([]diffLineRef, len(lines))
+	oldLine, newLine := 0, 0
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			if m := hunkHeaderRegex.FindStringSubmatch(line); m != nil {
+				oldLine, _ = strconv.Atoi(m[1])
+				newLine, _ = strconv.Atoi(m[2])
+			}
+		case strings.HasPrefix(line, "+"):
+			refs[i] = diffLineRef{Line: newLine}
+			newLine++
+		case strings.HasPrefix(line, "-"):
+			refs[i] = diffLineRef{Line: oldLine, Removed: true}
+			oldLine++
+		case strings.HasPrefix(line, " "):
+			refs[i] = diffLineRef{Line: newLine}
+			oldLine++
+			newLine++
+		}
+	}
+	return refs
+}
+
+// reviewLocation formats where a comment was left, e.g. "api.go:12".
+func reviewLocation(c workstream.ReviewComment) string {
+	switch {
+	case c.Line == 0:
+		return c.File
+	case c.Removed:
+		return fmt.Sprintf("%s:%d (removed line)", c.File, c.Line)
+	default:
+		return fmt.Sprintf("%s:%d", c.File, c.Line)
+	}
+}
+
+// formatReviewPrompt formats review comments into a single prompt for Claude.
+func formatReviewPrompt(branch string, comments []workstream.ReviewComment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Code review of branch %s: %d comment(s). Please address each one, then commit the fixes.\n", branch, len(comments))
+	for i, c := range comments {
+		fmt.Fprintf(&b, "\n%d. %s\n", i+1, reviewLocation(c))
+		if c.Code != "" {
+			fmt.Fprintf(&b, "   > %s\n", c.Code)
+		}
+		fmt.Fprintf(&b, "   %s\n", c.Body)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// reviewMarker renders a comment's status: pending (not yet sent), open
+// (sent, not addressed) or addressed.
+func reviewMarker(c workstream.ReviewComment) string {
+	switch {
+	case c.Addressed:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#22C55E")).Render("✓")
+	case c.Submitted():
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render("●")
+	default:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render("○")
+	}
+}
+
+// reviewSummary describes the state of a workstream's review comments.
+func reviewSummary(comments []workstream.ReviewComment) string {
+	if len(comments) == 0 {
+		return ""
+	}
+	var pending, open, addressed int
+	for _, c := range comments {
+		switch {
+		case c.Addressed:
+			addressed++
+		case c.Submitted():
+			open++
+		default:
+			pending++
+		}
+	}
+	return fmt.Sprintf("Review: %d pending, %d open, %d addressed", pending, open, addressed)
+}
+
+// findWorkstream returns the workstream with the given ID, if it has a pane.
+func (m *AppModel) findWorkstream(id string) *workstream.Workstream {
+	if i := m.paneIndexByID(id); i >= 0 {
+		return m.panes[i].Workstream()
+	}
+	return nil
+}
+
+// refreshReviewDialog shows a workstream's current comments in the open
+// diff viewer.
+func (m *AppModel) refreshReviewDialog(ws *workstream.Workstream) {
+	if m.dialog != nil && m.dialog.Type == DialogDiff && m.dialog.WorkstreamID == ws.ID {
+		m.dialog.diff.comments = ws.GetReviewComments()
+	}
+}
+
+// handleReviewMsg stores review changes made in the diff viewer and sends
+// submitted reviews to the workstream's Claude.
+func (m *AppModel) handleReviewMsg(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case ReviewCommentMsg:
+		if ws := m.findWorkstream(msg.WorkstreamID); ws != nil {
+			ws.AddReviewComment(msg.Comment)
+			m.managerFor(ws).UpdateWorkstream(ws.ID)
+			m.refreshReviewDialog(ws)
+		}
+
+	case ReviewAddressedMsg:
+		if ws := m.findWorkstream(msg.WorkstreamID); ws != nil && ws.ToggleReviewAddressed(msg.CommentID) {
+			m.managerFor(ws).UpdateWorkstream(ws.ID)
+			m.refreshReviewDialog(ws)
+		}
+
+	case ReviewSubmitMsg:
+		i := m.paneIndexByID(msg.WorkstreamID)
+		if i < 0 {
+			return
+		}
+		ws := m.panes[i].Workstream()
+		pending := ws.PendingReviewComments()
+		if len(pending) == 0 {
+			return
+		}
+		if err := m.panes[i].SendToPTYWithEnter(formatReviewPrompt(ws.BranchName, pending)); err != nil {
+			m.toast = fmt.Sprintf("Could not send review: %v", err)
+			m.toastExpiry = time.Now().Add(toastDuration * 2)
+			return
+		}
+		ws.MarkReviewSubmitted(time.Now())
+		m.managerFor(ws).UpdateWorkstream(ws.ID)
+		m.dialog = nil
+		m.panes[i].AppendOutput(fmt.Sprintf("\nSent review with %d comment(s) to Claude\n", len(pending)))
+		m.toast = fmt.Sprintf("Sent %d review comment(s) to Claude", len(pending))
+		m.toastExpiry = time.Now().Add(toastDuration)
+	}
+}
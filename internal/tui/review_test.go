@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestDiffLineRefs(t *testing.T) {
+	lines := []string{"@@ -10,3 +20,3 @@ func x()", " keep", "-old", "+new", " tail"}
+	want := []diffLineRef{{}, {Line: 20}, {Line: 11, Removed: true}, {Line: 21}, {Line: 22}}
+	if got := diffLineRefs(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffLineRefs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFormatReviewPrompt(t *testing.T) {
+	prompt := formatReviewPrompt("feature", []workstream.ReviewComment{
+		{File: "api.go", Line: 12, Code: "+func handler() {}", Body: "Rename to ServeHTTP"},
+		{File: "old.go", Line: 3, Removed: true, Code: "-legacy()", Body: "Keep this call"},
+		{File: "README.md", Body: "Document the new flag"},
+	})
+	for _, want := range []string{
+		"Code review of branch feature: 3 comment(s)",
+		"1. api.go:12\n   > +func handler() {}\n   Rename to ServeHTTP",
+		"2. old.go:3 (removed line)",
+		"3. README.md\n   Document the new flag",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestAppModel_ReviewComments(t *testing.T) {
+	app := newTestApp(t)
+	ws := app.panes[0].Workstream()
+
+	dialog := NewDiffDialog(ws)
+	dialog.SetSize(140, 40)
+	dialog.SetDiff(DiffLoadedMsg{WorkstreamID: ws.ID, Committed: parseUnifiedDiff(sampleDiff, false)})
+	app.dialog = &dialog
+
+	// Move the cursor to the added line and comment on it
+	for _, key := range []tea.KeyPressMsg{specialKey(tea.KeyTab), specialKey(tea.KeyDown), specialKey(tea.KeyDown), specialKey(tea.KeyDown), keyPress('c')} {
+		model, _ := app.Update(key)
+		app = model.(AppModel)
+	}
+	for _, r := range "Rename it" {
+		model, _ := app.Update(keyPress(r))
+		app = model.(AppModel)
+	}
+	_, cmd := app.Update(specialKey(tea.KeyEnter))
+	if cmd == nil {
+		t.Fatal("Enter should record the comment")
+	}
+	model, _ := app.Update(cmd())
+	app = model.(AppModel)
+
+	comments := ws.GetReviewComments()
+	want := workstream.ReviewComment{File: "internal/api/handler.go", Line: 2, Code: "+func handler() {}", Body: "Rename it"}
+	if len(comments) != 1 || comments[0].File != want.File || comments[0].Line != want.Line || comments[0].Code != want.Code || comments[0].Body != want.Body {
+		t.Fatalf("comments = %+v, want %+v", comments, want)
+	}
+	if len(app.dialog.diff.comments) != 1 || !strings.Contains(app.dialog.View(), "Review: 1 pending") {
+		t.Error("the diff viewer should show the new comment")
+	}
+
+	// Without a PTY the review cannot be sent and stays pending
+	model, _ = app.Update(ReviewSubmitMsg{WorkstreamID: ws.ID})
+	app = model.(AppModel)
+	if len(ws.PendingReviewComments()) != 1 || !strings.Contains(app.toast, "Could not send review") {
+		t.Errorf("toast = %q, want the send to fail", app.toast)
+	}
+
+	stdin := &mockWriteCloser{}
+	app.panes[0].SetPTY(&PTYSession{workstreamID: ws.ID, done: make(chan struct{}), stdin: stdin})
+	model, _ = app.Update(ReviewSubmitMsg{WorkstreamID: ws.ID})
+	app = model.(AppModel)
+	if sent := string(stdin.Bytes()); !strings.Contains(sent, "1. internal/api/handler.go:2") || !strings.HasSuffix(sent, "\x1b[13u") {
+		t.Errorf("sent %q, want the formatted review followed by Enter", sent)
+	}
+	if len(ws.PendingReviewComments()) != 0 || app.dialog != nil {
+		t.Error("the review should be marked submitted and the viewer closed")
+	}
+
+	// Later, the comment is checked off as addressed
+	model, _ = app.Update(ReviewAddressedMsg{WorkstreamID: ws.ID, CommentID: comments[0].ID})
+	app = model.(AppModel)
+	if !ws.GetReviewComments()[0].Addressed {
+		t.Error("the comment should be marked addressed")
+	}
+}
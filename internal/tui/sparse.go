@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/git"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// parseSparseInput reads sparse checkout directories separated by spaces or commas.
+func parseSparseInput(input string) ([]string, error) {
+	return git.CleanSparsePaths(strings.FieldsFunc(input, func(r rune) bool {
+		return r == ' ' || r == ','
+	}))
+}
+
+// updateSparseField handles keys for the sparse paths field of the new
+// workstream dialog: Ctrl+S moves between the prompt and the field, and
+// typing edits the field while it has focus. It reports false for keys the
+// dialog handles itself (submitting and closing it).
+func (d *DialogModel) updateSparseField(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "ctrl+s":
+		d.sparseFocused = !d.sparseFocused
+		if d.sparseFocused {
+			d.TextArea.Blur()
+			return d.sparse.Focus(), true
+		}
+		d.sparse.Blur()
+		return d.TextArea.Focus(), true
+	case "enter", "esc", "ctrl+c", "tab", "ctrl+r":
+		return nil, false
+	}
+	if !d.sparseFocused {
+		return nil, false
+	}
+	var cmd tea.Cmd
+	d.sparse, cmd = d.sparse.Update(msg)
+	d.sparseErr = ""
+	return cmd, true
+}
+
+// SparsePaths returns the directories typed into the new workstream dialog,
+// or nil for a full checkout.
+func (d *DialogModel) SparsePaths() ([]string, error) {
+	return parseSparseInput(d.sparse.Value())
+}
+
+// NewSparseWidenDialog creates the dialog for adding directories to a
+// workstream's sparse checkout.
+func NewSparseWidenDialog(ws *workstream.Workstream) DialogModel {
+	body := fmt.Sprintf(`Checked out in %s: %s
+Directories to add, separated by spaces or commas.`, ws.BranchName, strings.Join(ws.GetSparsePaths(), ", "))
+
+	return DialogModel{
+		Type:         DialogSparseWiden,
+		Title:        "Widen Sparse Checkout",
+		Body:         body,
+		Input:        newOptionalInput("e.g. libs/core docs", ""),
+		WorkstreamID: ws.ID,
+	}
+}
+
+// SparseWidenedMsg is sent when directories were added to a workstream's
+// sparse checkout.
+type SparseWidenedMsg struct {
+	WorkstreamID string
+	Added        []string
+	Paths        []string // All directories now checked out
+	Error        error
+}
+
+// WidenSparseCheckoutCmd returns a command that adds directories to a
+// workstream's sparse checkout and updates the CLAUDE.md of its container.
+func WidenSparseCheckoutCmd(ws *workstream.Workstream, add []string) tea.Cmd {
+	worktree := resolveWorktreePath(ws)
+	current := ws.GetSparsePaths()
+	containerID, runtime := ws.ContainerID, ws.Runtime
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		msg := SparseWidenedMsg{WorkstreamID: ws.ID, Added: add}
+		if worktree == "" {
+			msg.Error = fmt.Errorf("no worktree path")
+			return msg
+		}
+		if err := GitClientFactory(worktree).AddSparseCheckoutPaths(ctx, add); err != nil {
+			msg.Error = err
+			return msg
+		}
+		msg.Paths, _ = git.CleanSparsePaths(append(current, add...))
+
+		// Keep Claude's instructions in line with the checkout (non-fatal)
+		if containerID != "" {
+			if dockerClient, err := docker.NewClient(); err == nil {
+				if name, err := dockerClient.GetContainerName(ctx, containerID); err == nil && name != "" {
+					if runtime == "" {
+						runtime = "claude"
+					}
+					if err := docker.UpdateContainerInstructions(name, runtime, msg.Paths); err != nil {
+						LogWarn("Failed to update CLAUDE.md for %s: %v", ws.BranchName, err)
+					}
+				}
+				dockerClient.Close()
+			}
+		}
+		return msg
+	}
+}
+
+// openSparseWiden opens the widen dialog for the workstream in pane i.
+func (m *AppModel) openSparseWiden(i int) {
+	ws := m.panes[i].Workstream()
+	if len(ws.GetSparsePaths()) == 0 {
+		m.toast = "Focused workstream has a full checkout"
+		m.toastExpiry = time.Now().Add(toastDuration)
+		return
+	}
+	dialog := NewSparseWidenDialog(ws)
+	dialog.SetSize(60, 14)
+	m.dialog = &dialog
+}
+
+// widenSparseCheckout starts adding the directories typed into the widen dialog.
+func (m *AppModel) widenSparseCheckout(msg DialogConfirmMsg) tea.Cmd {
+	i := m.paneIndexByID(msg.WorkstreamID)
+	if i < 0 {
+		return nil
+	}
+	add, err := parseSparseInput(msg.Value)
+	if err != nil || len(add) == 0 {
+		if err == nil {
+			err = fmt.Errorf("no directories given")
+		}
+		m.toast = fmt.Sprintf("Sparse checkout not changed: %v", err)
+		m.toastExpiry = time.Now().Add(toastDuration * 2)
+		return nil
+	}
+	ws := m.panes[i].Workstream()
+	m.panes[i].AppendOutput(fmt.Sprintf("\nAdding %s to the sparse checkout...\n", strings.Join(add, ", ")))
+	return WidenSparseCheckoutCmd(ws, add)
+}
+
+// handleSparseWidened records widened directories and tells Claude about them.
+func (m *AppModel) handleSparseWidened(msg SparseWidenedMsg) {
+	i := m.paneIndexByID(msg.WorkstreamID)
+	if i < 0 {
+		return
+	}
+	ws := m.panes[i].Workstream()
+	if msg.Error != nil {
+		m.panes[i].AppendOutput(fmt.Sprintf("Widening the sparse checkout failed: %v\n", msg.Error))
+		m.toast = fmt.Sprintf("Widening %s failed: %v", ws.BranchName, msg.Error)
+		m.toastExpiry = time.Now().Add(toastDuration * 2)
+		return
+	}
+
+	added := strings.Join(msg.Added, ", ")
+	ws.SetSparsePaths(msg.Paths)
+	ws.RecordEvent(workstream.EventSparseWidened, "added "+added)
+	m.managerFor(ws).UpdateWorkstream(ws.ID)
+	m.panes[i].AppendOutput(fmt.Sprintf("Sparse checkout now includes: %s\n", strings.Join(msg.Paths, ", ")))
+	// Tell Claude the directories appeared (don't press Enter - avoids submitting Claude's pending input)
+	if err := m.panes[i].SendInput(fmt.Sprintf("[ccells] ✓ Sparse checkout widened: %s now checked out", added), false); err != nil {
+		LogWarn("Failed to notify Claude about sparse checkout for %s (pane %d): %v", ws.BranchName, i, err)
+	}
+	m.toast = fmt.Sprintf("Checked out %s in %s", added, ws.BranchName)
+	m.toastExpiry = time.Now().Add(toastDuration)
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// typeInto sends text to a dialog one key at a time.
+func typeInto(d DialogModel, text string) DialogModel {
+	for _, r := range text {
+		d, _ = d.Update(keyPress(r))
+	}
+	return d
+}
+
+func TestNewWorkstreamDialog_SparsePaths(t *testing.T) {
+	d := NewWorkstreamDialog()
+	d.SetSize(80, 20)
+	d = typeInto(d, "fix api")
+
+	// Ctrl+S moves to the sparse paths field; typing no longer edits the prompt
+	d, _ = d.Update(tea.KeyPressMsg{Code: 's', Mod: tea.ModCtrl})
+	d = typeInto(d, "services/api, ../x")
+	if got := d.TextArea.Value(); got != "fix api" {
+		t.Errorf("prompt = %q, want it untouched", got)
+	}
+
+	d, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd != nil || !strings.Contains(d.View(), "invalid sparse path") {
+		t.Fatal("an invalid path should keep the dialog open with an error")
+	}
+
+	for range len(", ../x") {
+		d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	}
+	d = typeInto(d, " libs")
+	_, cmd = d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter should create the workstream")
+	}
+	msg, ok := cmd().(DialogConfirmMsg)
+	if !ok || msg.Value != "fix api" || !reflect.DeepEqual(msg.SparsePaths, []string{"services/api", "libs"}) {
+		t.Errorf("Enter sent %+v, want the prompt and two sparse paths", msg)
+	}
+}
+
+func TestAppModel_NewWorkstreamSparsePaths(t *testing.T) {
+	cellsDir := t.TempDir()
+	docker.SetTestCellsDir(cellsDir)
+	defer docker.SetTestCellsDir("")
+	config := `templates:
+  - name: api
+    sparse_paths: [services/api/, libs]
+`
+	if err := os.WriteFile(filepath.Join(cellsDir, "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewAppModel(context.Background())
+	app.width = 100
+	app.height = 40
+
+	// The template's paths apply when none were typed
+	model, _ := app.Update(DialogConfirmMsg{Type: DialogNewWorkstream, Value: "first", Template: "api"})
+	app = model.(AppModel)
+	if got := app.panes[0].Workstream().GetSparsePaths(); !reflect.DeepEqual(got, []string{"services/api", "libs"}) {
+		t.Errorf("SparsePaths = %v, want the template's", got)
+	}
+
+	// Typed paths take precedence
+	model, _ = app.Update(DialogConfirmMsg{Type: DialogNewWorkstream, Value: "second", Template: "api", SparsePaths: []string{"web"}})
+	app = model.(AppModel)
+	if got := app.panes[1].Workstream().GetSparsePaths(); !reflect.DeepEqual(got, []string{"web"}) {
+		t.Errorf("SparsePaths = %v, want [web]", got)
+	}
+}
+
+func TestAppModel_SparseWiden(t *testing.T) {
+	app := newTestApp(t)
+	alpha := app.panes[0].Workstream()
+
+	app.openSparseWiden(0)
+	if app.dialog != nil || !strings.Contains(app.toast, "full checkout") {
+		t.Fatalf("a full checkout cannot be widened, toast = %q", app.toast)
+	}
+
+	alpha.SetSparsePaths([]string{"services/api"})
+	app.openSparseWiden(0)
+	if app.dialog == nil || app.dialog.Type != DialogSparseWiden || !strings.Contains(app.dialog.Body, "services/api") {
+		t.Fatal("the widen dialog should show the current paths")
+	}
+
+	model, cmd := app.Update(DialogConfirmMsg{Type: DialogSparseWiden, WorkstreamID: alpha.ID, Value: "/etc"})
+	app = model.(AppModel)
+	if cmd != nil || !strings.Contains(app.toast, "not changed") {
+		t.Errorf("an invalid path should be rejected, toast = %q", app.toast)
+	}
+
+	stdin := &mockWriteCloser{}
+	app.panes[0].SetPTY(&PTYSession{workstreamID: alpha.ID, done: make(chan struct{}), stdin: stdin})
+	model, _ = app.Update(SparseWidenedMsg{WorkstreamID: alpha.ID, Added: []string{"libs"}, Paths: []string{"services/api", "libs"}})
+	app = model.(AppModel)
+	if got := alpha.GetSparsePaths(); !reflect.DeepEqual(got, []string{"services/api", "libs"}) {
+		t.Errorf("SparsePaths = %v, want both directories", got)
+	}
+	if events := alpha.GetEvents(); len(events) == 0 || events[len(events)-1].Type != workstream.EventSparseWidened {
+		t.Error("widening should be added to the timeline")
+	}
+	if sent := string(stdin.Bytes()); !strings.Contains(sent, "libs now checked out") {
+		t.Errorf("Claude should be told about the new directories, got %q", sent)
+	}
+}
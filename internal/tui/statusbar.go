@@ -5,6 +5,7 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/claude"
 	"github.com/STRML/claude-cells/internal/sync"
 )
 
@@ -18,6 +19,7 @@ type StatusBarModel struct {
 	layoutName      string
 	repoPath        string
 	runtime         string
+	usage           claude.Usage // Token usage summed across workstreams
 
 	// Enhanced pairing status (Phase 4)
 	syncStatus       sync.SyncStatus
@@ -64,6 +66,12 @@ func (s StatusBarModel) View() string {
 	repoPathStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))
 	left := fmt.Sprintf("%s %s ccells: %d workstreams", modeIndicator, repoPathStyle.Render(s.repoPath), s.workstreamCount)
 
+	// Project token cost
+	if !s.usage.IsZero() {
+		costStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))
+		left += costStyle.Render(fmt.Sprintf(" %s (%s tokens)", formatCost(s.usage.CostUSD), formatTokens(s.usage.TotalTokens())))
+	}
+
 	// Runtime indicator
 	if s.runtime != "" {
 		runtimeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
@@ -174,3 +182,8 @@ func (s *StatusBarModel) SetRepoPath(path string) {
 func (s *StatusBarModel) SetRuntime(runtime string) {
 	s.runtime = runtime
 }
+
+// SetUsage sets the project token usage to display
+func (s *StatusBarModel) SetUsage(usage claude.Usage) {
+	s.usage = usage
+}
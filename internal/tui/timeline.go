@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// shortContainerID shortens a container ID for display, like docker ps.
+func shortContainerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// formatTimelineDuration formats a duration compactly (e.g. "45s", "12m", "3h 5m").
+func formatTimelineDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours >= 24 {
+		return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+// renderTimeline renders a workstream's event history as plain text.
+func renderTimeline(ws *workstream.Workstream, now time.Time) string {
+	events := ws.GetEvents()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Created:  %s (%s ago)\n", ws.CreatedAt.Format("2006-01-02 15:04"), formatTimelineDuration(now.Sub(ws.CreatedAt)))
+	fmt.Fprintf(&b, "Running:  %s\n", formatTimelineDuration(workstream.RunningDuration(events, now)))
+	fmt.Fprintf(&b, "State:    %s\n", ws.GetState())
+	if ws.GetState() == workstream.StateError {
+		// Explain why the cell is in the error state
+		for i := len(events) - 1; i >= 0; i-- {
+			if events[i].Type == workstream.EventError {
+				fmt.Fprintf(&b, "Error:    %s\n", events[i].Detail)
+				break
+			}
+		}
+	}
+	b.WriteString("\n")
+
+	if len(events) == 0 {
+		b.WriteString("(No events recorded yet)")
+		return b.String()
+	}
+
+	for _, e := range events {
+		line := fmt.Sprintf("%s  +%-7s  %-18s", e.Time.Format("Jan 02 15:04:05"), formatTimelineDuration(e.Time.Sub(ws.CreatedAt)), e.Label())
+		if e.Detail != "" {
+			line += "  " + e.Detail
+		}
+		b.WriteString(strings.TrimRight(line, " "))
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// NewTimelineDialog creates a scrollable dialog showing a workstream's event timeline.
+func NewTimelineDialog(ws *workstream.Workstream) DialogModel {
+	d := NewLogDialog(ws.BranchName, "", renderTimeline(ws, time.Now()))
+	d.Title = fmt.Sprintf("Timeline: %s", ws.BranchName)
+	return d
+}
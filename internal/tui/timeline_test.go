@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+func TestFormatTimelineDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{45 * time.Second, "45s"},
+		{12 * time.Minute, "12m"},
+		{3*time.Hour + 5*time.Minute, "3h 5m"},
+		{50 * time.Hour, "2d 2h"},
+	}
+	for _, tt := range tests {
+		if got := formatTimelineDuration(tt.d); got != tt.want {
+			t.Errorf("formatTimelineDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTimeline(t *testing.T) {
+	ws := workstream.New("fix the build")
+	if !strings.Contains(renderTimeline(ws, time.Now()), "No events recorded yet") {
+		t.Error("empty timeline should say so")
+	}
+
+	ws.SetState(workstream.StateRunning)
+	ws.RecordEvent(workstream.EventPushed, "1 commit pushed")
+	ws.SetError(errors.New("container exited"))
+
+	out := renderTimeline(ws, time.Now())
+	for _, want := range []string{"→ running", "from starting", "Pushed", "1 commit pushed", "State:    error", "Error:    container exited"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("timeline missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestAppModel_TimelineKeyOpensDialog(t *testing.T) {
+	app := NewAppModel(context.Background())
+	app.width = 100
+	app.height = 40
+
+	model, _ := app.Update(DialogConfirmMsg{Type: DialogNewWorkstream, Value: "test feature"})
+	app = model.(AppModel)
+	model, _ = app.Update(ContainerStartedMsg{WorkstreamID: app.panes[0].Workstream().ID, ContainerID: "0123456789abcdef"})
+	app = model.(AppModel)
+
+	model, _ = app.Update(keyPress('t'))
+	app = model.(AppModel)
+	if app.dialog == nil || !strings.HasPrefix(app.dialog.Title, "Timeline:") {
+		t.Fatalf("expected timeline dialog, got %+v", app.dialog)
+	}
+	if !strings.Contains(app.dialog.Body, "Container created") || !strings.Contains(app.dialog.Body, "0123456789ab") {
+		t.Errorf("timeline should include container creation:\n%s", app.dialog.Body)
+	}
+}
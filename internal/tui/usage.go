@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/STRML/claude-cells/internal/claude"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+const usagePollInterval = 30 * time.Second
+
+// maxUsageRows bounds the per-workstream rows in the usage dialog.
+const maxUsageRows = 8
+
+// usageTracker caches parsed session files between polls.
+var usageTracker = claude.NewUsageTracker()
+
+// containerNames caches container ID -> name lookups for locating session files.
+var containerNames sync.Map
+
+// usagePollTickMsg is sent periodically to refresh token usage for all workstreams
+type usagePollTickMsg struct{}
+
+// usagePollTickCmd returns a command that sends a usage poll tick after a delay
+func usagePollTickCmd() tea.Cmd {
+	return tea.Tick(usagePollInterval, func(t time.Time) tea.Msg {
+		return usagePollTickMsg{}
+	})
+}
+
+// UsageRefreshedMsg carries the per-session usage found for each workstream.
+type UsageRefreshedMsg struct {
+	Sessions map[string]map[string]claude.Usage // Workstream ID -> session ID -> usage
+	Budgets  map[string]docker.BudgetConfig     // RepoPath -> budgets configured when the usage was read
+}
+
+// RefreshUsageCmd scans the host-side session files of every workstream with
+// a container and returns their usage, priced with the configured table.
+func RefreshUsageCmd(workstreams []*workstream.Workstream) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		// Pricing and budgets come from each workstream's repository config
+		configs := make(map[string]docker.CellsConfig)
+		configFor := func(ws *workstream.Workstream) docker.CellsConfig {
+			if cfg, ok := configs[ws.RepoPath]; ok {
+				return cfg
+			}
+			repoPath, _ := workstreamRepoPath(ws)
+			cfg := docker.LoadConfig(repoPath)
+			configs[ws.RepoPath] = cfg
+			return cfg
+		}
+		if cwd, err := os.Getwd(); err == nil {
+			configs[""] = docker.LoadConfig(cwd)
+		}
+
+		var dockerClient *docker.Client
+		sessions := make(map[string]map[string]claude.Usage)
+		for _, ws := range workstreams {
+			if ws.ContainerID == "" {
+				continue
+			}
+			name, ok := containerNames.Load(ws.ContainerID)
+			if !ok {
+				if dockerClient == nil {
+					c, err := docker.NewClient()
+					if err != nil {
+						LogDebug("Usage refresh: docker unavailable: %v", err)
+						break
+					}
+					dockerClient = c
+					defer dockerClient.Close()
+				}
+				n, err := dockerClient.GetContainerName(ctx, ws.ContainerID)
+				if err != nil || n == "" {
+					continue
+				}
+				containerNames.Store(ws.ContainerID, n)
+				name = n
+			}
+
+			dir, err := docker.ContainerSessionDir(name.(string))
+			if err != nil {
+				continue
+			}
+			found, err := usageTracker.ScanDir(dir, configFor(ws).Pricing)
+			if err != nil {
+				LogWarn("Failed to read session usage for %s: %v", ws.BranchName, err)
+				continue
+			}
+			if len(found) > 0 {
+				sessions[ws.ID] = found
+			}
+		}
+		budgets := make(map[string]docker.BudgetConfig, len(configs))
+		for repoPath, cfg := range configs {
+			budgets[repoPath] = cfg.Budget
+		}
+		return UsageRefreshedMsg{Sessions: sessions, Budgets: budgets}
+	}
+}
+
+// forgetContainerUsage drops the cached name and session files of a removed
+// container.
+func forgetContainerUsage(containerID string) {
+	name, ok := containerNames.LoadAndDelete(containerID)
+	if !ok {
+		return
+	}
+	if dir, err := docker.ContainerSessionDir(name.(string)); err == nil {
+		usageTracker.ForgetDir(dir)
+	}
+}
+
+// formatCost formats an estimated cost in USD.
+func formatCost(usd float64) string {
+	if usd > 0 && usd < 0.01 {
+		return "<$0.01"
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
+
+// formatTokens formats a token count compactly (e.g. "850", "12.3k", "4.1M").
+func formatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// usageBadge renders a workstream's estimated cost for the pane header.
+func usageBadge(ws *workstream.Workstream) string {
+	u := ws.GetUsage()
+	if u.IsZero() {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF")).Render(formatCost(u.CostUSD))
+}
+
+// repoUsage sums usage across the panes of one repository ("" for the one
+// ccells was started in).
+func repoUsage(panes []PaneModel, repoPath string) claude.Usage {
+	var total claude.Usage
+	for i := range panes {
+		if ws := panes[i].Workstream(); ws.RepoPath == repoPath {
+			total = total.Add(ws.GetUsage())
+		}
+	}
+	return total
+}
+
+// projectUsage sums usage across the panes.
+func projectUsage(panes []PaneModel) claude.Usage {
+	var total claude.Usage
+	for i := range panes {
+		total = total.Add(panes[i].Workstream().GetUsage())
+	}
+	return total
+}
+
+// renderUsageSummary renders per-workstream token usage and cost, most
+// expensive first, followed by the project total.
+func renderUsageSummary(panes []PaneModel) string {
+	type row struct {
+		name  string
+		usage claude.Usage
+	}
+	var rows []row
+	var total claude.Usage
+	for i := range panes {
+		ws := panes[i].Workstream()
+		u := ws.GetUsage()
+		if u.IsZero() {
+			continue
+		}
+		rows = append(rows, row{name: ws.BranchName, usage: u})
+		total = total.Add(u)
+	}
+	if len(rows) == 0 {
+		return "No token usage recorded yet"
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].usage.CostUSD > rows[j].usage.CostUSD
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-20s %8s %8s %8s %8s\n", "Workstream", "Input", "Output", "Cache", "Cost")
+	for i, r := range rows {
+		if i == maxUsageRows {
+			fmt.Fprintf(&b, "… %d more\n", len(rows)-maxUsageRows)
+			break
+		}
+		b.WriteString(usageRow(truncatePrompt(r.name, 20), r.usage))
+	}
+	b.WriteString(strings.Repeat("─", 56))
+	b.WriteString("\n")
+	b.WriteString(usageRow("Total", total))
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// usageRow formats one line of the usage table.
+func usageRow(name string, u claude.Usage) string {
+	return fmt.Sprintf("%-20s %8s %8s %8s %8s\n", name,
+		formatTokens(u.InputTokens), formatTokens(u.OutputTokens),
+		formatTokens(u.CacheCreationTokens+u.CacheReadTokens), formatCost(u.CostUSD))
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/claude"
+)
+
+func TestFormatTokensAndCost(t *testing.T) {
+	tokens := map[int64]string{0: "0", 850: "850", 12_345: "12.3k", 4_100_000: "4.1M"}
+	for n, want := range tokens {
+		if got := formatTokens(n); got != want {
+			t.Errorf("formatTokens(%d) = %q, want %q", n, got, want)
+		}
+	}
+	costs := map[float64]string{0: "$0.00", 0.004: "<$0.01", 1.234: "$1.23"}
+	for usd, want := range costs {
+		if got := formatCost(usd); got != want {
+			t.Errorf("formatCost(%v) = %q, want %q", usd, got, want)
+		}
+	}
+}
+
+func TestForgetContainerUsage(t *testing.T) {
+	containerNames.Store("container-1", "ccells-test-1")
+	forgetContainerUsage("container-1")
+	if _, ok := containerNames.Load("container-1"); ok {
+		t.Error("the removed container's name should be forgotten")
+	}
+	forgetContainerUsage("unknown") // No-op
+}
+
+func TestAppModel_UsageRefreshed(t *testing.T) {
+	app := newTestApp(t)
+
+	model, _ := app.Update(UsageRefreshedMsg{Sessions: map[string]map[string]claude.Usage{
+		"alpha": {"s1": {InputTokens: 1000, OutputTokens: 500, CostUSD: 0.75}},
+		"gamma": {"s2": {OutputTokens: 100, CostUSD: 2}},
+	}})
+	app = model.(AppModel)
+
+	if got := app.panes[0].Workstream().GetUsage().CostUSD; got != 0.75 {
+		t.Errorf("alpha cost = %v, want 0.75", got)
+	}
+	if !app.panes[1].Workstream().GetUsage().IsZero() {
+		t.Error("beta should have no usage")
+	}
+	if got := projectUsage(app.panes).CostUSD; got != 2.75 {
+		t.Errorf("project cost = %v, want 2.75", got)
+	}
+
+	if badge := usageBadge(app.panes[0].Workstream()); !strings.Contains(badge, "$0.75") {
+		t.Errorf("usageBadge() = %q, want $0.75", badge)
+	}
+	if badge := usageBadge(app.panes[1].Workstream()); badge != "" {
+		t.Errorf("usageBadge() for beta = %q, want empty", badge)
+	}
+
+	// Most expensive workstream is listed first, then the total
+	summary := renderUsageSummary(app.panes)
+	if strings.Index(summary, "gamma") > strings.Index(summary, "alpha") {
+		t.Errorf("gamma should be listed before alpha:\n%s", summary)
+	}
+	if strings.Contains(summary, "beta") {
+		t.Errorf("workstreams without usage should be omitted:\n%s", summary)
+	}
+	if !strings.Contains(summary, "Total") || !strings.Contains(summary, "$2.75") {
+		t.Errorf("summary should include the project total:\n%s", summary)
+	}
+
+	app.statusBar.SetWidth(200)
+	app.statusBar.SetUsage(projectUsage(app.panes))
+	if view := app.statusBar.View(); !strings.Contains(view, "$2.75") {
+		t.Errorf("status bar should show the project cost, got %q", view)
+	}
+}
+
+func TestRenderUsageSummary_Empty(t *testing.T) {
+	app := newTestApp(t)
+	if got := renderUsageSummary(app.panes); got != "No token usage recorded yet" {
+		t.Errorf("renderUsageSummary() = %q", got)
+	}
+}
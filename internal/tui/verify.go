@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+	"github.com/STRML/claude-cells/internal/workstream"
+)
+
+// verifyLogPath is where the full verification output is written inside the
+// container, so Claude can read it when asked to fix failures.
+const verifyLogPath = "/tmp/ccells-verify.log"
+
+// verifyOutputMaxBytes caps the verification output kept in memory.
+const verifyOutputMaxBytes = 64 * 1024
+
+// VerifyOutputMsg carries a chunk of streamed verification output.
+type VerifyOutputMsg struct {
+	WorkstreamID string
+	Output       string
+}
+
+// VerifyResultMsg is sent when the pre-merge verification command finishes.
+type VerifyResultMsg struct {
+	WorkstreamID string
+	Action       MergeAction // Merge to perform if verification passed
+	Command      string
+	ExitCode     int
+	Output       string // Captured output (tail, capped at verifyOutputMaxBytes)
+	Error        error  // Exec failure (not a non-zero exit)
+}
+
+// Passed reports whether verification succeeded.
+func (m VerifyResultMsg) Passed() bool {
+	return m.Error == nil && m.ExitCode == 0
+}
+
+// buildVerifyScript wraps the verification command so that it runs in the
+// workspace, tees its output to verifyLogPath, and preserves its exit code.
+// The status of a previous run is removed first: if the command exits the
+// group itself (exit, set -e), no status is written and the run fails.
+func buildVerifyScript(command string) string {
+	return fmt.Sprintf(`rm -f %s.status
+cd /workspace || exit 1
+{ %s
+echo $? > %s.status; } 2>&1 | tee %s
+exit "$(cat %s.status 2>/dev/null || echo 1)"`, verifyLogPath, command, verifyLogPath, verifyLogPath, verifyLogPath)
+}
+
+// verifyOutputWriter streams output chunks to the TUI and keeps a capped copy.
+type verifyOutputWriter struct {
+	mu           sync.Mutex
+	workstreamID string
+	buf          []byte
+	send         func(tea.Msg) bool
+}
+
+func (w *verifyOutputWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > verifyOutputMaxBytes {
+		w.buf = w.buf[len(w.buf)-verifyOutputMaxBytes:]
+	}
+	w.mu.Unlock()
+	if w.send != nil {
+		w.send(VerifyOutputMsg{WorkstreamID: w.workstreamID, Output: string(p)})
+	}
+	return len(p), nil
+}
+
+func (w *verifyOutputWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return string(w.buf)
+}
+
+// runVerification executes the verification command in the workstream's container.
+func runVerification(ctx context.Context, client docker.DockerClient, containerID, command string, output io.Writer) (int, error) {
+	return client.ExecInContainerStream(ctx, containerID, []string{"sh", "-c", buildVerifyScript(command)}, output)
+}
+
+// VerifyBranchCmd returns a command that runs the pre-merge verification
+// command in the workstream's container, streaming output to the TUI.
+func VerifyBranchCmd(ws *workstream.Workstream, cfg docker.VerifyConfig, action MergeAction) tea.Cmd {
+	return func() tea.Msg {
+		result := VerifyResultMsg{WorkstreamID: ws.ID, Action: action, Command: cfg.Command}
+		if ws.ContainerID == "" {
+			result.Error = fmt.Errorf("no container for workstream")
+			return result
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.GetTimeout())
+		defer cancel()
+
+		client, err := docker.NewClient()
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		defer client.Close()
+
+		w := &verifyOutputWriter{workstreamID: ws.ID, send: sendMsg}
+		result.ExitCode, result.Error = runVerification(ctx, client, ws.ContainerID, cfg.Command, w)
+		if ctx.Err() == context.DeadlineExceeded {
+			result.Error = fmt.Errorf("verification timed out after %v", cfg.GetTimeout())
+		}
+		result.Output = w.String()
+		return result
+	}
+}
+
+// verifyFixPrompt builds the prompt asking Claude to fix verification failures.
+func verifyFixPrompt(command string, exitCode int) string {
+	return fmt.Sprintf("The pre-merge verification command `%s` failed with exit code %d, so the merge was blocked. The full output is in %s. Please read it, fix the failures, and commit the fixes so the branch can be merged.", command, exitCode, verifyLogPath)
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) <= n {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines[len(lines)-n:], "\n")
+}
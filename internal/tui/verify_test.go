@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/STRML/claude-cells/internal/docker"
+)
+
+func TestBuildVerifyScript_PreservesExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		wantCode int
+		wantOut  string
+	}{
+		{"success", "echo ok", 0, "ok"},
+		{"failure", "echo broken >&2; false", 1, "broken"},
+		{"custom exit code", "sh -c 'exit 3'", 3, ""},
+		{"exits the group", "echo stopping; exit 4", 1, "stopping"},
+		{"set -e stops the group", "set -e; false; echo unreachable", 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Run the script locally with the workspace and log path redirected
+			dir := t.TempDir()
+			script := buildVerifyScript(tt.command)
+			script = strings.ReplaceAll(script, "/workspace", dir)
+			script = strings.ReplaceAll(script, verifyLogPath, dir+"/verify.log")
+			// A passing status left by an earlier run must not leak into this one
+			if err := os.WriteFile(dir+"/verify.log.status", []byte("0\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := exec.Command("sh", "-c", script).CombinedOutput()
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("failed to run script: %v", err)
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.Contains(string(out), tt.wantOut) {
+				t.Errorf("output %q should contain %q", out, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestRunVerification(t *testing.T) {
+	client := docker.NewMockClient()
+	ctx := context.Background()
+	containerID, err := client.CreateContainer(ctx, &docker.ContainerConfig{Name: "ccells-test-verify"})
+	if err != nil {
+		t.Fatalf("CreateContainer() error = %v", err)
+	}
+
+	var gotCmd []string
+	client.ExecStreamFn = func(ctx context.Context, id string, cmd []string, output io.Writer) (int, error) {
+		gotCmd = cmd
+		_, _ = io.WriteString(output, "FAIL: TestThing\n")
+		return 2, nil
+	}
+
+	w := &verifyOutputWriter{workstreamID: "ws-1"}
+	code, err := runVerification(ctx, client, containerID, "make test", w)
+	if err != nil {
+		t.Fatalf("runVerification() error = %v", err)
+	}
+	if code != 2 {
+		t.Errorf("exit code = %d, want 2", code)
+	}
+	if len(gotCmd) != 3 || gotCmd[0] != "sh" || !strings.Contains(gotCmd[2], "make test") {
+		t.Errorf("unexpected exec command: %v", gotCmd)
+	}
+	if w.String() != "FAIL: TestThing\n" {
+		t.Errorf("captured output = %q", w.String())
+	}
+}
+
+func TestVerifyOutputWriter_StreamsAndCaps(t *testing.T) {
+	var sent []tea.Msg
+	w := &verifyOutputWriter{
+		workstreamID: "ws-1",
+		send: func(msg tea.Msg) bool {
+			sent = append(sent, msg)
+			return true
+		},
+	}
+
+	_, _ = w.Write([]byte("hello\n"))
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 streamed message, got %d", len(sent))
+	}
+	if msg, ok := sent[0].(VerifyOutputMsg); !ok || msg.Output != "hello\n" || msg.WorkstreamID != "ws-1" {
+		t.Errorf("unexpected streamed message: %#v", sent[0])
+	}
+
+	_, _ = w.Write([]byte(strings.Repeat("x", verifyOutputMaxBytes)))
+	if len(w.String()) != verifyOutputMaxBytes {
+		t.Errorf("captured output should be capped at %d bytes, got %d", verifyOutputMaxBytes, len(w.String()))
+	}
+}
+
+func TestVerifyDialogs(t *testing.T) {
+	t.Run("progress dialog shows output tail", func(t *testing.T) {
+		d := NewVerifyProgressDialog("feature", "make test", "ws-1")
+		for i := 0; i < streamOutputLines+5; i++ {
+			d.AppendStreamOutput("line\n")
+		}
+		d.AppendStreamOutput("last line\n")
+		if !strings.Contains(d.Body, "$ make test") {
+			t.Error("body should keep the command header")
+		}
+		if !strings.HasSuffix(d.Body, "last line") {
+			t.Errorf("body should end with latest output, got %q", d.Body)
+		}
+		if strings.Count(d.Body, "line") > streamOutputLines+1 {
+			t.Error("body should only show the output tail")
+		}
+	})
+
+	t.Run("failed dialog sends prompt with s", func(t *testing.T) {
+		d := NewVerifyFailedDialog("feature", "ws-1", "make test", 2, "FAIL\n")
+		_, cmd := d.Update(tea.KeyPressMsg{Code: 's', Text: "s"})
+		if cmd == nil {
+			t.Fatal("'s' should confirm the dialog")
+		}
+		msg, ok := cmd().(DialogConfirmMsg)
+		if !ok {
+			t.Fatalf("expected DialogConfirmMsg, got %T", cmd())
+		}
+		if msg.Type != DialogVerifyFailed || msg.WorkstreamID != "ws-1" {
+			t.Errorf("unexpected confirm message: %+v", msg)
+		}
+		if !strings.Contains(msg.Value, "make test") || !strings.Contains(msg.Value, verifyLogPath) {
+			t.Errorf("prompt should mention command and log path, got %q", msg.Value)
+		}
+	})
+
+	t.Run("failed dialog close option cancels", func(t *testing.T) {
+		d := NewVerifyFailedDialog("feature", "ws-1", "make test", 2, "FAIL\n")
+		d, _ = d.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		_, cmd := d.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+		if cmd == nil {
+			t.Fatal("enter should produce a command")
+		}
+		if _, ok := cmd().(DialogCancelMsg); !ok {
+			t.Errorf("expected DialogCancelMsg, got %T", cmd())
+		}
+	})
+}
+
+func TestAppModel_Update_VerifyResultMsg(t *testing.T) {
+	app := NewAppModel(context.Background())
+	app.width = 100
+	app.height = 40
+
+	model, _ := app.Update(DialogConfirmMsg{Type: DialogNewWorkstream, Value: "test feature"})
+	app = model.(AppModel)
+	wsID := app.panes[0].Workstream().ID
+
+	t.Run("failure blocks merge", func(t *testing.T) {
+		model, cmd := app.Update(VerifyResultMsg{
+			WorkstreamID: wsID,
+			Action:       MergeActionSquashMain,
+			Command:      "make test",
+			ExitCode:     1,
+			Output:       "FAIL\n",
+		})
+		app = model.(AppModel)
+		if cmd != nil {
+			t.Error("failed verification should not start a merge")
+		}
+		dialog := app.panes[0].GetInPaneDialog()
+		if dialog == nil || dialog.Type != DialogVerifyFailed {
+			t.Fatalf("expected DialogVerifyFailed, got %+v", dialog)
+		}
+	})
+
+	t.Run("failure is sent to Claude", func(t *testing.T) {
+		confirm := DialogConfirmMsg{Type: DialogVerifyFailed, WorkstreamID: wsID, Value: "fix the tests"}
+
+		// No session and no container: the failure is reported instead of claimed
+		model, cmd := app.Update(confirm)
+		app = model.(AppModel)
+		if cmd != nil || !strings.Contains(app.panes[0].output.String(), "Could not ask Claude") {
+			t.Errorf("output = %q, want the failed hand-off reported", app.panes[0].output.String())
+		}
+
+		// Claude's session ended: it is resumed with the failure
+		app.panes[0].Workstream().SetContainerID("container-1")
+		model, cmd = app.Update(confirm)
+		app = model.(AppModel)
+		if cmd == nil || !strings.Contains(app.panes[0].output.String(), "Resuming Claude's session") {
+			t.Errorf("output = %q, want the ended session resumed with the failure", app.panes[0].output.String())
+		}
+		app.panes[0].Workstream().SetContainerID("")
+	})
+
+	t.Run("success starts merge", func(t *testing.T) {
+		model, cmd := app.Update(VerifyResultMsg{
+			WorkstreamID: wsID,
+			Action:       MergeActionSquashMain,
+			Command:      "make test",
+		})
+		app = model.(AppModel)
+		if cmd == nil {
+			t.Error("passed verification should start the merge")
+		}
+		dialog := app.panes[0].GetInPaneDialog()
+		if dialog == nil || dialog.Type != DialogProgress {
+			t.Fatalf("expected merge progress dialog, got %+v", dialog)
+		}
+	})
+
+	t.Run("streamed output updates progress dialog", func(t *testing.T) {
+		d := NewVerifyProgressDialog("feature", "make test", wsID)
+		app.panes[0].SetInPaneDialog(&d)
+		model, _ := app.Update(VerifyOutputMsg{WorkstreamID: wsID, Output: "running tests\n"})
+		app = model.(AppModel)
+		if dialog := app.panes[0].GetInPaneDialog(); !strings.Contains(dialog.Body, "running tests") {
+			t.Errorf("dialog body should include streamed output, got %q", dialog.Body)
+		}
+	})
+}
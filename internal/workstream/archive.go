@@ -0,0 +1,207 @@
+package workstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/STRML/claude-cells/internal/claude"
+)
+
+const archiveFileName = ".claude-cells-archive.json"
+
+// maxArchiveEntries bounds the archive; the oldest entries are dropped first.
+const maxArchiveEntries = 100
+
+// archiveMu serializes archive file updates.
+var archiveMu sync.Mutex
+
+// ArchivedWorkstream is a destroyed workstream kept so it can be browsed and restored.
+type ArchivedWorkstream struct {
+	ID               string                  `json:"id"`
+	BranchName       string                  `json:"branch_name"`
+	Prompt           string                  `json:"prompt"`
+	RepoPath         string                  `json:"repo_path,omitempty"` // Repository root; empty for the one ccells was started in
+	Title            string                  `json:"title,omitempty"`
+	Synopsis         string                  `json:"synopsis,omitempty"`
+	CommitSHA        string                  `json:"commit_sha,omitempty"`        // Branch head when destroyed
+	DiffStat         string                  `json:"diff_stat,omitempty"`         // e.g. "3 commits, 4 files changed, 120 insertions(+)"
+	WorktreeSnapshot string                  `json:"worktree_snapshot,omitempty"` // Commit holding the uncommitted changes when destroyed
+	PRNumber         int                     `json:"pr_number,omitempty"`         // GitHub PR number if created
+	PRURL            string                  `json:"pr_url,omitempty"`            // GitHub PR URL if created
+	ClaudeSessionID  string                  `json:"claude_session_id,omitempty"`
+	Runtime          string                  `json:"runtime,omitempty"`
+	Template         string                  `json:"template,omitempty"`
+	SparsePaths      []string                `json:"sparse_paths,omitempty"`
+	Labels           []string                `json:"labels,omitempty"`
+	Priority         int                     `json:"priority,omitempty"`
+	SessionDir       string                  `json:"session_dir,omitempty"` // Archived Claude session data, if saved
+	Usage            map[string]claude.Usage `json:"usage,omitempty"`
+	Budget           *claude.Budget          `json:"budget,omitempty"`
+	Events           []Event                 `json:"events,omitempty"`
+	CreatedAt        time.Time               `json:"created_at"`
+	ArchivedAt       time.Time               `json:"archived_at"`
+}
+
+// NewArchivedWorkstream captures a workstream's identity and session info for the archive.
+// Git details (CommitSHA, DiffStat, WorktreeSnapshot) and SessionDir are filled in by the caller.
+func NewArchivedWorkstream(ws *Workstream) ArchivedWorkstream {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	events := make([]Event, len(ws.Events))
+	copy(events, ws.Events)
+	var usage map[string]claude.Usage
+	if len(ws.Usage) > 0 {
+		usage = make(map[string]claude.Usage, len(ws.Usage))
+		for id, u := range ws.Usage {
+			usage[id] = u
+		}
+	}
+	return ArchivedWorkstream{
+		ID:              ws.ID,
+		BranchName:      ws.BranchName,
+		Prompt:          ws.Prompt,
+		RepoPath:        ws.RepoPath,
+		Title:           ws.Title,
+		Synopsis:        ws.Synopsis,
+		PRNumber:        ws.PRNumber,
+		PRURL:           ws.PRURL,
+		ClaudeSessionID: ws.ClaudeSessionID,
+		Runtime:         ws.Runtime,
+		Template:        ws.Template,
+		SparsePaths:     append([]string(nil), ws.SparsePaths...),
+		Labels:          append([]string(nil), ws.Labels...),
+		Priority:        ws.Priority,
+		Usage:           usage,
+		Budget:          budgetOverride(ws.Budget),
+		Events:          events,
+		CreatedAt:       ws.CreatedAt,
+		ArchivedAt:      time.Now(),
+	}
+}
+
+// Restore recreates a workstream from the archive entry.
+// The container and worktree still need to be created.
+func (a ArchivedWorkstream) Restore() *Workstream {
+	ws := NewWithID(a.ID, a.BranchName, a.Prompt)
+	ws.RepoPath = a.RepoPath
+	ws.Title = a.Title
+	ws.Synopsis = a.Synopsis
+	ws.ClaudeSessionID = a.ClaudeSessionID
+	ws.Runtime = a.Runtime
+	ws.Template = a.Template
+	ws.SparsePaths = a.SparsePaths
+	ws.Labels = a.Labels
+	ws.Priority = a.Priority
+	ws.Usage = a.Usage
+	if a.Budget != nil {
+		ws.Budget = *a.Budget
+	}
+	ws.PRNumber = a.PRNumber
+	ws.PRURL = a.PRURL
+	ws.HasBeenPushed = a.PRURL != ""
+	ws.CreatedAt = a.CreatedAt
+	ws.Events = a.Events
+	return ws
+}
+
+// ArchiveFilePath returns the path to the archive file in the given state directory.
+func ArchiveFilePath(dir string) string {
+	return filepath.Join(dir, archiveFileName)
+}
+
+// ArchiveSessionDir returns where an archived workstream's session data is kept.
+func ArchiveSessionDir(dir, id string) string {
+	return filepath.Join(dir, "archive", id)
+}
+
+// LoadArchive returns archived workstreams, most recently archived first.
+// A missing archive file yields an empty list.
+func LoadArchive(dir string) ([]ArchivedWorkstream, error) {
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+	return loadArchiveUnsafe(dir)
+}
+
+func loadArchiveUnsafe(dir string) ([]ArchivedWorkstream, error) {
+	data, err := os.ReadFile(ArchiveFilePath(dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []ArchivedWorkstream
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func saveArchiveUnsafe(dir string, entries []ArchivedWorkstream) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	finalPath := ArchiveFilePath(dir)
+	tempPath := fmt.Sprintf("%s.tmp.%d", finalPath, time.Now().UnixNano())
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp archive file: %w", err)
+	}
+	if err := os.Rename(tempPath, finalPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename temp archive file: %w", err)
+	}
+	return nil
+}
+
+// AddToArchive adds an entry to the front of the archive, replacing any
+// entry with the same ID. Entries beyond the limit are dropped with their session data.
+func AddToArchive(dir string, entry ArchivedWorkstream) error {
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+
+	entries, err := loadArchiveUnsafe(dir)
+	if err != nil {
+		return err
+	}
+	result := []ArchivedWorkstream{entry}
+	for _, e := range entries {
+		if e.ID != entry.ID {
+			result = append(result, e)
+		}
+	}
+	for len(result) > maxArchiveEntries {
+		dropped := result[len(result)-1]
+		if dropped.SessionDir != "" {
+			_ = os.RemoveAll(dropped.SessionDir)
+		}
+		result = result[:len(result)-1]
+	}
+	return saveArchiveUnsafe(dir, result)
+}
+
+// RemoveFromArchive removes an entry and its archived session data.
+func RemoveFromArchive(dir, id string) error {
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+
+	entries, err := loadArchiveUnsafe(dir)
+	if err != nil {
+		return err
+	}
+	var result []ArchivedWorkstream
+	for _, e := range entries {
+		if e.ID == id {
+			if e.SessionDir != "" {
+				_ = os.RemoveAll(e.SessionDir)
+			}
+			continue
+		}
+		result = append(result, e)
+	}
+	return saveArchiveUnsafe(dir, result)
+}
@@ -0,0 +1,131 @@
+package workstream
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchive_AddLoadRemove(t *testing.T) {
+	dir := t.TempDir()
+
+	entries, err := LoadArchive(dir)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("LoadArchive() on empty dir = %v, %v", entries, err)
+	}
+
+	first := NewArchivedWorkstream(NewWithID("id-1", "first", "do the first thing"))
+	second := NewArchivedWorkstream(NewWithID("id-2", "second", "do the second thing"))
+	if err := AddToArchive(dir, first); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+	if err := AddToArchive(dir, second); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+
+	entries, err = LoadArchive(dir)
+	if err != nil {
+		t.Fatalf("LoadArchive() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != "id-2" || entries[1].ID != "id-1" {
+		t.Fatalf("expected newest first, got %+v", entries)
+	}
+
+	// Re-archiving the same workstream replaces the old entry
+	first.CommitSHA = "abc123"
+	if err := AddToArchive(dir, first); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+	entries, _ = LoadArchive(dir)
+	if len(entries) != 2 || entries[0].ID != "id-1" || entries[0].CommitSHA != "abc123" {
+		t.Fatalf("expected id-1 replaced and moved to front, got %+v", entries)
+	}
+
+	// Removing an entry also removes its session data
+	sessionDir := ArchiveSessionDir(dir, "id-1")
+	if err := os.MkdirAll(sessionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	first.SessionDir = sessionDir
+	if err := AddToArchive(dir, first); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+	if err := RemoveFromArchive(dir, "id-1"); err != nil {
+		t.Fatalf("RemoveFromArchive() error = %v", err)
+	}
+	entries, _ = LoadArchive(dir)
+	if len(entries) != 1 || entries[0].ID != "id-2" {
+		t.Fatalf("expected only id-2 left, got %+v", entries)
+	}
+	if _, err := os.Stat(sessionDir); !os.IsNotExist(err) {
+		t.Error("session data should be removed with the entry")
+	}
+}
+
+func TestArchive_DropsOldestBeyondLimit(t *testing.T) {
+	dir := t.TempDir()
+	oldestSession := filepath.Join(dir, "archive", "ws-0")
+	if err := os.MkdirAll(oldestSession, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i <= maxArchiveEntries; i++ {
+		entry := ArchivedWorkstream{ID: fmt.Sprintf("ws-%d", i)}
+		if i == 0 {
+			entry.SessionDir = oldestSession
+		}
+		if err := AddToArchive(dir, entry); err != nil {
+			t.Fatalf("AddToArchive() error = %v", err)
+		}
+	}
+
+	entries, err := LoadArchive(dir)
+	if err != nil {
+		t.Fatalf("LoadArchive() error = %v", err)
+	}
+	if len(entries) != maxArchiveEntries {
+		t.Fatalf("expected %d entries, got %d", maxArchiveEntries, len(entries))
+	}
+	if _, err := os.Stat(oldestSession); !os.IsNotExist(err) {
+		t.Error("session data of the dropped entry should be removed")
+	}
+}
+
+func TestArchivedWorkstream_Restore(t *testing.T) {
+	ws := NewWithID("abc", "feature/login", "fix login")
+	ws.Title = "Fix login"
+	ws.Synopsis = "Fixed the Safari login bug"
+	ws.ClaudeSessionID = "session-123"
+	ws.Runtime = "claudesp"
+	ws.Template = "bugfix"
+	ws.PRNumber = 42
+	ws.PRURL = "https://github.com/example/repo/pull/42"
+	ws.RepoPath = "/src/api"
+	ws.RecordEvent(EventPushed, "")
+
+	entry := NewArchivedWorkstream(ws)
+	restored := entry.Restore()
+
+	if restored.ID != "abc" || restored.BranchName != "feature/login" || restored.Prompt != "fix login" {
+		t.Errorf("identity not restored: %+v", restored)
+	}
+	if restored.RepoPath != "/src/api" {
+		t.Errorf("RepoPath = %q, want the workstream's repository", restored.RepoPath)
+	}
+	if restored.Title != ws.Title || restored.Synopsis != ws.Synopsis || restored.Template != "bugfix" {
+		t.Errorf("metadata not restored: %+v", restored)
+	}
+	if restored.GetClaudeSessionID() != "session-123" || restored.Runtime != "claudesp" {
+		t.Errorf("session not restored: %+v", restored)
+	}
+	if restored.PRNumber != 42 || !restored.HasBeenPushed {
+		t.Errorf("PR info not restored: %+v", restored)
+	}
+	if events := restored.GetEvents(); len(events) != 1 || events[0].Type != EventPushed {
+		t.Errorf("events not restored: %+v", events)
+	}
+	if restored.GetState() != StateStarting {
+		t.Errorf("restored state = %s, want %s", restored.GetState(), StateStarting)
+	}
+}
@@ -34,7 +34,13 @@ var nonAlphaNum = regexp.MustCompile(`[^a-z0-9\s-]`)
 // GenerateUniqueBranchName creates a unique git branch name from a prompt,
 // checking against existing branch names and appending a numeric suffix if needed.
 func GenerateUniqueBranchName(prompt string, existingBranches []string) string {
-	baseName := GenerateBranchName(prompt)
+	return GenerateUniqueBranchNameWithPrefix(prompt, "", existingBranches)
+}
+
+// GenerateUniqueBranchNameWithPrefix is like GenerateUniqueBranchName but
+// prepends prefix (e.g. "bugfix/") to the generated name.
+func GenerateUniqueBranchNameWithPrefix(prompt, prefix string, existingBranches []string) string {
+	baseName := strings.TrimSpace(prefix) + GenerateBranchName(prompt)
 
 	// Build a set of existing branches for O(1) lookup
 	existing := make(map[string]bool)
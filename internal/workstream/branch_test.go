@@ -169,6 +169,19 @@ func TestGenerateUniqueBranchName_MaxLength(t *testing.T) {
 	}
 }
 
+func TestGenerateUniqueBranchNameWithPrefix(t *testing.T) {
+	got := GenerateUniqueBranchNameWithPrefix("fix login redirect", "bugfix/", nil)
+	if got != "bugfix/fix-login-redirect" {
+		t.Errorf("GenerateUniqueBranchNameWithPrefix() = %q, want %q", got, "bugfix/fix-login-redirect")
+	}
+
+	// Uniqueness is checked against the prefixed name
+	got = GenerateUniqueBranchNameWithPrefix("fix login redirect", "bugfix/", []string{"bugfix/fix-login-redirect", "fix-login-redirect-2"})
+	if got != "bugfix/fix-login-redirect-2" {
+		t.Errorf("GenerateUniqueBranchNameWithPrefix() = %q, want %q", got, "bugfix/fix-login-redirect-2")
+	}
+}
+
 func TestItoa(t *testing.T) {
 	tests := []struct {
 		input    int
@@ -0,0 +1,207 @@
+package workstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// EventType identifies a kind of workstream event.
+type EventType string
+
+const (
+	EventStateChange        EventType = "state"               // Workstream state transition
+	EventContainerCreated   EventType = "container_created"   // Container created (or rebuilt)
+	EventContainerPaused    EventType = "container_paused"    // Container paused on quit
+	EventContainerResumed   EventType = "container_resumed"   // Container resumed on restart
+	EventPushed             EventType = "pushed"              // Branch pushed to remote
+	EventPRCreated          EventType = "pr_created"          // Pull request created
+	EventMerged             EventType = "merged"              // Branch or PR merged
+	EventError              EventType = "error"               // Workstream entered the error state
+	EventAutoContinue       EventType = "auto_continue"       // Interrupted session continued automatically
+	EventRestored           EventType = "restored"            // Restored from the archive
+	EventBudgetExceeded     EventType = "budget_exceeded"     // Claude interrupted after using its token budget
+	EventCIFix              EventType = "ci_fix"              // Failing CI checks sent to Claude to fix
+	EventHistoryRewritten   EventType = "history_rewritten"   // Branch commits restructured before merge
+	EventAutoRebase         EventType = "auto_rebase"         // Rebased onto new base commits in the background
+	EventCherryPick         EventType = "cherry_pick"         // Commits cherry-picked from or into another workstream
+	EventCheckpointRestored EventType = "checkpoint_restored" // Worktree restored from a WIP checkpoint
+	EventSparseWidened      EventType = "sparse_widened"      // Directories added to the sparse checkout
+)
+
+// maxEvents bounds the history kept per workstream; the oldest events are dropped first.
+const maxEvents = 500
+
+const eventsFileName = ".claude-cells-events.json"
+
+// Event is a single entry in a workstream's timeline.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Type   EventType `json:"type"`
+	State  State     `json:"state,omitempty"`  // New state (for state changes)
+	Detail string    `json:"detail,omitempty"` // Human-readable detail
+}
+
+// Label returns a short description of the event for display.
+func (e Event) Label() string {
+	switch e.Type {
+	case EventStateChange:
+		return "→ " + string(e.State)
+	case EventContainerCreated:
+		return "Container created"
+	case EventContainerPaused:
+		return "Container paused"
+	case EventContainerResumed:
+		return "Container resumed"
+	case EventPushed:
+		return "Pushed"
+	case EventPRCreated:
+		return "PR created"
+	case EventMerged:
+		return "Merged"
+	case EventError:
+		return "Error"
+	case EventAutoContinue:
+		return "Auto-continued"
+	case EventRestored:
+		return "Restored"
+	case EventBudgetExceeded:
+		return "Budget exceeded"
+	case EventCIFix:
+		return "CI fix attempt"
+	case EventHistoryRewritten:
+		return "History cleaned up"
+	case EventAutoRebase:
+		return "Auto-rebase"
+	case EventCherryPick:
+		return "Cherry-pick"
+	case EventCheckpointRestored:
+		return "Checkpoint restored"
+	case EventSparseWidened:
+		return "Sparse checkout widened"
+	default:
+		return string(e.Type)
+	}
+}
+
+// RecordEvent appends an event to the workstream's timeline.
+func (w *Workstream) RecordEvent(eventType EventType, detail string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.appendEventLocked(Event{Time: time.Now(), Type: eventType, Detail: detail})
+}
+
+// appendEventLocked appends an event. Caller must hold w.mu.
+func (w *Workstream) appendEventLocked(e Event) {
+	w.Events = append(w.Events, e)
+	if len(w.Events) > maxEvents {
+		w.Events = append([]Event(nil), w.Events[len(w.Events)-maxEvents:]...)
+	}
+}
+
+// GetEvents returns a copy of the workstream's timeline (thread-safe).
+func (w *Workstream) GetEvents() []Event {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	events := make([]Event, len(w.Events))
+	copy(events, w.Events)
+	return events
+}
+
+// SetEvents replaces the workstream's timeline (for restoring from saved state).
+func (w *Workstream) SetEvents(events []Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Events = events
+}
+
+// RunningDuration returns the total time the workstream spent in StateRunning
+// according to its timeline. A running interval ends at the next state change
+// or container pause; an interval still open is counted up to now.
+func RunningDuration(events []Event, now time.Time) time.Duration {
+	var total time.Duration
+	var start time.Time
+	running := false
+	for _, e := range events {
+		switch {
+		case e.Type == EventStateChange && e.State == StateRunning:
+			if !running {
+				start = e.Time
+				running = true
+			}
+		case e.Type == EventStateChange || e.Type == EventContainerPaused:
+			if running {
+				total += e.Time.Sub(start)
+				running = false
+			}
+		}
+	}
+	if running {
+		total += now.Sub(start)
+	}
+	return total
+}
+
+// savedEvents is the on-disk format of the events file.
+type savedEvents struct {
+	Version     int                `json:"version"`
+	Workstreams map[string][]Event `json:"workstreams"`
+}
+
+// EventsFilePath returns the path to the events file in the given directory.
+func EventsFilePath(dir string) string {
+	return filepath.Join(dir, eventsFileName)
+}
+
+// saveEventsUnsafe writes the timelines of the given workstreams next to the
+// state file. Caller must hold stateMu.
+func saveEventsUnsafe(dir string, workstreams []*Workstream) error {
+	saved := savedEvents{Version: 1, Workstreams: make(map[string][]Event)}
+	for _, ws := range workstreams {
+		if ws.BranchName == "" {
+			continue
+		}
+		if events := ws.GetEvents(); len(events) > 0 {
+			saved.Workstreams[ws.ID] = events
+		}
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	finalPath := EventsFilePath(dir)
+	tempPath := fmt.Sprintf("%s.tmp.%d", finalPath, time.Now().UnixNano())
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp events file: %w", err)
+	}
+	if err := os.Rename(tempPath, finalPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename temp events file: %w", err)
+	}
+	return nil
+}
+
+// LoadEvents loads saved timelines keyed by workstream ID.
+// A missing events file yields an empty map.
+func LoadEvents(dir string) (map[string][]Event, error) {
+	data, err := os.ReadFile(EventsFilePath(dir))
+	if os.IsNotExist(err) {
+		return map[string][]Event{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved savedEvents
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, err
+	}
+	if saved.Workstreams == nil {
+		saved.Workstreams = map[string][]Event{}
+	}
+	return saved.Workstreams, nil
+}
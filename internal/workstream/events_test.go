@@ -0,0 +1,110 @@
+package workstream
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWorkstream_SetStateRecordsTransitions(t *testing.T) {
+	ws := New("test")
+	ws.SetState(StateRunning)
+	ws.SetState(StateRunning) // No-op transition is not recorded
+	ws.SetState(StateIdle)
+
+	events := ws.GetEvents()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d: %+v", len(events), events)
+	}
+	if events[0].Type != EventStateChange || events[0].State != StateRunning || events[0].Detail != "from starting" {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[1].State != StateIdle || events[1].Detail != "from running" {
+		t.Errorf("unexpected second event: %+v", events[1])
+	}
+}
+
+func TestWorkstream_SetErrorRecordsReason(t *testing.T) {
+	ws := New("test")
+	ws.SetError(errors.New("image not found"))
+
+	events := ws.GetEvents()
+	if len(events) != 2 {
+		t.Fatalf("expected state change and error events, got %+v", events)
+	}
+	if events[0].Type != EventStateChange || events[0].State != StateError {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[1].Type != EventError || events[1].Detail != "image not found" {
+		t.Errorf("unexpected error event: %+v", events[1])
+	}
+}
+
+func TestWorkstream_RecordEventCapsHistory(t *testing.T) {
+	ws := New("test")
+	for i := 0; i < maxEvents+10; i++ {
+		ws.RecordEvent(EventPushed, itoa(i))
+	}
+
+	events := ws.GetEvents()
+	if len(events) != maxEvents {
+		t.Fatalf("expected %d events, got %d", maxEvents, len(events))
+	}
+	if events[0].Detail != "10" {
+		t.Errorf("oldest events should be dropped first, first detail = %q", events[0].Detail)
+	}
+}
+
+func TestRunningDuration(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Time: start, Type: EventStateChange, State: StateRunning},
+		{Time: start.Add(10 * time.Minute), Type: EventStateChange, State: StateIdle},
+		{Time: start.Add(20 * time.Minute), Type: EventStateChange, State: StateRunning},
+		{Time: start.Add(25 * time.Minute), Type: EventContainerPaused},
+		{Time: start.Add(60 * time.Minute), Type: EventStateChange, State: StateRunning},
+	}
+
+	got := RunningDuration(events, start.Add(62*time.Minute))
+	if got != 17*time.Minute {
+		t.Errorf("RunningDuration() = %v, want 17m", got)
+	}
+	if RunningDuration(nil, start) != 0 {
+		t.Error("RunningDuration() of no events should be 0")
+	}
+}
+
+func TestSaveStatePersistsEvents(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ws := New("test prompt")
+	ws.SetState(StateRunning)
+	ws.RecordEvent(EventPushed, "1 commit pushed")
+	quiet := New("other prompt")
+
+	if err := SaveState(tmpDir, []*Workstream{ws, quiet}, 0, 0); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	events, err := LoadEvents(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadEvents() error = %v", err)
+	}
+	if len(events[ws.ID]) != 2 {
+		t.Fatalf("expected 2 saved events, got %+v", events[ws.ID])
+	}
+	if events[ws.ID][1].Type != EventPushed || events[ws.ID][1].Detail != "1 commit pushed" {
+		t.Errorf("unexpected saved event: %+v", events[ws.ID][1])
+	}
+	if _, ok := events[quiet.ID]; ok {
+		t.Error("workstreams without events should not be saved")
+	}
+
+	if err := DeleteState(tmpDir); err != nil {
+		t.Fatalf("DeleteState() error = %v", err)
+	}
+	events, err = LoadEvents(tmpDir)
+	if err != nil || len(events) != 0 {
+		t.Errorf("LoadEvents() after delete = %v, %v; want empty", events, err)
+	}
+}
@@ -0,0 +1,67 @@
+package workstream
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// LockFileName is the lock file in a state directory that keeps two ccells
+// instances from managing the same repository.
+const LockFileName = ".ccells.lock"
+
+// ErrLockedByThisProcess is returned when the state directory is already
+// locked by the running ccells, i.e. the repository is already open.
+var ErrLockedByThisProcess = errors.New("repository is already open in this ccells instance")
+
+// Lock is an acquired state directory lock.
+type Lock struct {
+	path string
+}
+
+// AcquireLock attempts to acquire an exclusive lock on a repository's state
+// directory. It returns an error if another live process holds the lock;
+// stale locks left by crashed instances are taken over.
+func AcquireLock(stateDir string) (*Lock, error) {
+	lockPath := filepath.Join(stateDir, LockFileName)
+
+	// Check if lock file exists
+	if data, err := os.ReadFile(lockPath); err == nil {
+		// Lock file exists - check if the process is still running
+		pidStr := strings.TrimSpace(string(data))
+		if pid, err := strconv.Atoi(pidStr); err == nil {
+			if pid == os.Getpid() {
+				return nil, ErrLockedByThisProcess
+			}
+			// Check if process is still alive
+			if process, err := os.FindProcess(pid); err == nil {
+				// On Unix, FindProcess always succeeds, so we need to send signal 0
+				if err := process.Signal(syscall.Signal(0)); err == nil {
+					// Process is still running
+					return nil, fmt.Errorf("another ccells instance is already running (PID %d)", pid)
+				}
+			}
+		}
+		// Stale lock file - remove it
+		os.Remove(lockPath)
+	}
+
+	// Create lock file with our PID
+	pid := os.Getpid()
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(pid)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to create lock file: %w", err)
+	}
+
+	return &Lock{path: lockPath}, nil
+}
+
+// Release removes the lock file
+func (l *Lock) Release() {
+	if l != nil && l.path != "" {
+		os.Remove(l.path)
+	}
+}
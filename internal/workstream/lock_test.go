@@ -0,0 +1,43 @@
+package workstream
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestAcquireLock(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	if _, err := AcquireLock(dir); !errors.Is(err, ErrLockedByThisProcess) {
+		t.Errorf("second AcquireLock() error = %v, want ErrLockedByThisProcess", err)
+	}
+	lock.Release()
+	if _, err := os.Stat(filepath.Join(dir, LockFileName)); !os.IsNotExist(err) {
+		t.Error("Release should remove the lock file")
+	}
+
+	// A lock held by another live process is refused
+	lockPath := filepath.Join(dir, LockFileName)
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(os.Getppid())), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AcquireLock(dir); err == nil {
+		t.Error("AcquireLock() should fail while another instance holds the lock")
+	}
+
+	// A stale lock is taken over
+	if err := os.WriteFile(lockPath, []byte("not a pid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lock, err = AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("AcquireLock() over a stale lock error = %v", err)
+	}
+	lock.Release()
+}
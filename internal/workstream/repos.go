@@ -0,0 +1,70 @@
+package workstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// reposFileName lists the additional repositories opened alongside the one
+// ccells was started in. It lives in the primary repository's state directory.
+const reposFileName = ".claude-cells-repos.json"
+
+// ReposFilePath returns the path to the repository list in the given state directory.
+func ReposFilePath(dir string) string {
+	return filepath.Join(dir, reposFileName)
+}
+
+// LoadRepoList returns the additional repository paths saved in dir.
+// A missing file yields an empty list.
+func LoadRepoList(dir string) ([]string, error) {
+	data, err := os.ReadFile(ReposFilePath(dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// SaveRepoList saves the additional repository paths to dir, dropping
+// duplicates. An empty list removes the file.
+func SaveRepoList(dir string, paths []string) error {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, p := range paths {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			unique = append(unique, p)
+		}
+	}
+
+	finalPath := ReposFilePath(dir)
+	if len(unique) == 0 {
+		if err := os.Remove(finalPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(unique, "", "  ")
+	if err != nil {
+		return err
+	}
+	tempPath := fmt.Sprintf("%s.tmp.%d", finalPath, time.Now().UnixNano())
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp repo list: %w", err)
+	}
+	if err := os.Rename(tempPath, finalPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename temp repo list: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package workstream
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRepoList_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	paths, err := LoadRepoList(dir)
+	if err != nil || len(paths) != 0 {
+		t.Fatalf("LoadRepoList() on empty dir = %v, %v", paths, err)
+	}
+
+	if err := SaveRepoList(dir, []string{"/src/api", "", "/src/web", "/src/api"}); err != nil {
+		t.Fatalf("SaveRepoList() error = %v", err)
+	}
+	paths, err = LoadRepoList(dir)
+	if err != nil {
+		t.Fatalf("LoadRepoList() error = %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/src/api" || paths[1] != "/src/web" {
+		t.Errorf("LoadRepoList() = %v, want [/src/api /src/web]", paths)
+	}
+
+	// Saving an empty list removes the file
+	if err := SaveRepoList(dir, nil); err != nil {
+		t.Fatalf("SaveRepoList(nil) error = %v", err)
+	}
+	if _, err := os.Stat(ReposFilePath(dir)); !os.IsNotExist(err) {
+		t.Error("repo list file should be removed when empty")
+	}
+}
+
+func TestRepoList_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(ReposFilePath(dir), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRepoList(dir); err == nil {
+		t.Error("expected error for corrupt repo list")
+	}
+}
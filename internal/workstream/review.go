@@ -0,0 +1,99 @@
+package workstream
+
+import "time"
+
+// ReviewComment is a comment left on a file or line of a workstream's diff.
+// Comments are kept after they are sent to Claude so it can be checked later
+// whether each one was addressed.
+type ReviewComment struct {
+	ID          int       `json:"id"`
+	File        string    `json:"file"`
+	Line        int       `json:"line,omitempty"`    // 0 = the whole file
+	Removed     bool      `json:"removed,omitempty"` // Line numbers the old side (a removed line)
+	Code        string    `json:"code,omitempty"`    // The diff line commented on
+	Body        string    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+	SubmittedAt time.Time `json:"submitted_at,omitempty"` // Zero until sent to Claude
+	Addressed   bool      `json:"addressed,omitempty"`
+}
+
+// Submitted reports whether the comment has been sent to Claude.
+func (c ReviewComment) Submitted() bool {
+	return !c.SubmittedAt.IsZero()
+}
+
+// AddReviewComment adds a comment and returns it with its ID assigned.
+func (w *Workstream) AddReviewComment(c ReviewComment) ReviewComment {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	c.ID = 1
+	for _, existing := range w.Review {
+		c.ID = max(c.ID, existing.ID+1)
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	w.Review = append(w.Review, c)
+	return c
+}
+
+// GetReviewComments returns a copy of the workstream's review comments.
+func (w *Workstream) GetReviewComments() []ReviewComment {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return append([]ReviewComment(nil), w.Review...)
+}
+
+// PendingReviewComments returns the comments not yet sent to Claude.
+func (w *Workstream) PendingReviewComments() []ReviewComment {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	var pending []ReviewComment
+	for _, c := range w.Review {
+		if !c.Submitted() {
+			pending = append(pending, c)
+		}
+	}
+	return pending
+}
+
+// MarkReviewSubmitted records that all pending comments were sent to Claude.
+func (w *Workstream) MarkReviewSubmitted(at time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i := range w.Review {
+		if !w.Review[i].Submitted() {
+			w.Review[i].SubmittedAt = at
+		}
+	}
+}
+
+// ToggleReviewAddressed flips whether a comment was addressed. It reports
+// false if there is no comment with that ID.
+func (w *Workstream) ToggleReviewAddressed(id int) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i := range w.Review {
+		if w.Review[i].ID == id {
+			w.Review[i].Addressed = !w.Review[i].Addressed
+			return true
+		}
+	}
+	return false
+}
+
+// GetPRFeedbackSeen returns when the latest PR review activity the user was
+// notified about happened.
+func (w *Workstream) GetPRFeedbackSeen() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.PRFeedbackSeen
+}
+
+// SetPRFeedbackSeen records the latest PR review activity the user was
+// notified about.
+func (w *Workstream) SetPRFeedbackSeen(at time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.PRFeedbackSeen = at
+}
@@ -0,0 +1,55 @@
+package workstream
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkstream_ReviewComments(t *testing.T) {
+	ws := NewWithID("1", "feature", "task")
+	first := ws.AddReviewComment(ReviewComment{File: "api.go", Line: 12, Body: "rename this"})
+	second := ws.AddReviewComment(ReviewComment{File: "README.md", Body: "document the flag"})
+	if first.ID != 1 || second.ID != 2 || first.CreatedAt.IsZero() {
+		t.Fatalf("comments = %+v, %+v; want IDs 1 and 2 with creation times", first, second)
+	}
+	if got := len(ws.PendingReviewComments()); got != 2 {
+		t.Fatalf("pending = %d, want 2", got)
+	}
+
+	ws.MarkReviewSubmitted(time.Now())
+	third := ws.AddReviewComment(ReviewComment{File: "api.go", Line: 3, Body: "unused import"})
+	if pending := ws.PendingReviewComments(); len(pending) != 1 || pending[0].ID != third.ID {
+		t.Errorf("pending = %+v, want only the comment added after submitting", pending)
+	}
+
+	if !ws.ToggleReviewAddressed(first.ID) || !ws.GetReviewComments()[0].Addressed {
+		t.Error("comment 1 should be marked addressed")
+	}
+	if ws.ToggleReviewAddressed(99) {
+		t.Error("toggling an unknown comment should report false")
+	}
+}
+
+func TestSaveStatePreservesReviewComments(t *testing.T) {
+	dir := t.TempDir()
+	ws := NewWithID("1", "feature", "task")
+	ws.AddReviewComment(ReviewComment{File: "api.go", Line: 12, Body: "rename this"})
+	ws.MarkReviewSubmitted(time.Now())
+	seen := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	ws.SetPRFeedbackSeen(seen)
+
+	if err := SaveState(dir, []*Workstream{ws}, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	review := state.Workstreams[0].Review
+	if len(review) != 1 || review[0].Body != "rename this" || !review[0].Submitted() {
+		t.Errorf("saved review = %+v, want the submitted comment", review)
+	}
+	if got := state.Workstreams[0].PRFeedbackSeen; got == nil || !got.Equal(seen) {
+		t.Errorf("PRFeedbackSeen = %v, want %v", state.Workstreams[0].PRFeedbackSeen, seen)
+	}
+}
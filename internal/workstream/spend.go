@@ -0,0 +1,98 @@
+package workstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/STRML/claude-cells/internal/claude"
+)
+
+const spendFileName = ".claude-cells-spend.json"
+
+// spendMu serializes spend file updates.
+var spendMu sync.Mutex
+
+// SpendFilePath returns the path to the spend file in the given state directory.
+// It records the token usage of destroyed and archived workstreams, so a
+// project's spend isn't reset by removing its cells.
+func SpendFilePath(dir string) string {
+	return filepath.Join(dir, spendFileName)
+}
+
+// LoadSpend returns the usage of removed workstreams by workstream ID.
+// A missing spend file yields an empty map.
+func LoadSpend(dir string) (map[string]claude.Usage, error) {
+	spendMu.Lock()
+	defer spendMu.Unlock()
+	return loadSpendUnsafe(dir)
+}
+
+func loadSpendUnsafe(dir string) (map[string]claude.Usage, error) {
+	spend := make(map[string]claude.Usage)
+	data, err := os.ReadFile(SpendFilePath(dir))
+	if os.IsNotExist(err) {
+		return spend, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &spend); err != nil {
+		return nil, err
+	}
+	return spend, nil
+}
+
+func saveSpendUnsafe(dir string, spend map[string]claude.Usage) error {
+	data, err := json.MarshalIndent(spend, "", "  ")
+	if err != nil {
+		return err
+	}
+	finalPath := SpendFilePath(dir)
+	tempPath := fmt.Sprintf("%s.tmp.%d", finalPath, time.Now().UnixNano())
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp spend file: %w", err)
+	}
+	if err := os.Rename(tempPath, finalPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename temp spend file: %w", err)
+	}
+	return nil
+}
+
+// RecordSpend records the usage of a workstream that is being removed,
+// replacing any earlier record for it.
+func RecordSpend(dir, id string, usage claude.Usage) error {
+	if usage.IsZero() {
+		return nil
+	}
+	spendMu.Lock()
+	defer spendMu.Unlock()
+
+	spend, err := loadSpendUnsafe(dir)
+	if err != nil {
+		return err
+	}
+	spend[id] = usage
+	return saveSpendUnsafe(dir, spend)
+}
+
+// ForgetSpend drops the record of a restored workstream, whose usage counts
+// as live again.
+func ForgetSpend(dir, id string) error {
+	spendMu.Lock()
+	defer spendMu.Unlock()
+
+	spend, err := loadSpendUnsafe(dir)
+	if err != nil {
+		return err
+	}
+	if _, ok := spend[id]; !ok {
+		return nil
+	}
+	delete(spend, id)
+	return saveSpendUnsafe(dir, spend)
+}
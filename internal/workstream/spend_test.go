@@ -0,0 +1,50 @@
+package workstream
+
+import (
+	"testing"
+
+	"github.com/STRML/claude-cells/internal/claude"
+)
+
+func TestSpend_RecordLoadForget(t *testing.T) {
+	dir := t.TempDir()
+
+	spent, err := LoadSpend(dir)
+	if err != nil || len(spent) != 0 {
+		t.Fatalf("LoadSpend() on empty dir = %v, %v", spent, err)
+	}
+
+	if err := RecordSpend(dir, "id-1", claude.Usage{OutputTokens: 100, CostUSD: 1}); err != nil {
+		t.Fatalf("RecordSpend() error = %v", err)
+	}
+	if err := RecordSpend(dir, "id-2", claude.Usage{OutputTokens: 50}); err != nil {
+		t.Fatalf("RecordSpend() error = %v", err)
+	}
+	// Recording a workstream again replaces its usage instead of adding to it
+	if err := RecordSpend(dir, "id-1", claude.Usage{OutputTokens: 150, CostUSD: 2}); err != nil {
+		t.Fatalf("RecordSpend() error = %v", err)
+	}
+	// Workstreams without usage aren't recorded
+	if err := RecordSpend(dir, "id-3", claude.Usage{}); err != nil {
+		t.Fatalf("RecordSpend() error = %v", err)
+	}
+
+	spent, err = LoadSpend(dir)
+	if err != nil {
+		t.Fatalf("LoadSpend() error = %v", err)
+	}
+	if len(spent) != 2 || spent["id-1"].OutputTokens != 150 || spent["id-2"].OutputTokens != 50 {
+		t.Errorf("LoadSpend() = %+v, want id-1 replaced and id-2 kept", spent)
+	}
+
+	if err := ForgetSpend(dir, "id-1"); err != nil {
+		t.Fatalf("ForgetSpend() error = %v", err)
+	}
+	if err := ForgetSpend(dir, "missing"); err != nil {
+		t.Fatalf("ForgetSpend() of an unknown ID error = %v", err)
+	}
+	spent, _ = LoadSpend(dir)
+	if _, ok := spent["id-1"]; ok || len(spent) != 1 {
+		t.Errorf("LoadSpend() = %+v, want id-1 forgotten", spent)
+	}
+}
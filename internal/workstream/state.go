@@ -2,11 +2,14 @@ package workstream
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/STRML/claude-cells/internal/claude"
 )
 
 // stateMu protects concurrent SaveState calls from racing on temp file
@@ -79,19 +82,71 @@ type RepoInfo struct {
 
 // SavedWorkstream represents a workstream saved to disk
 type SavedWorkstream struct {
-	ID              string    `json:"id"`
-	BranchName      string    `json:"branch_name"`
-	Prompt          string    `json:"prompt"`
-	Title           string    `json:"title,omitempty"`    // Short summary title
-	Synopsis        string    `json:"synopsis,omitempty"` // Brief description of work accomplished
-	ContainerID     string    `json:"container_id"`
-	ClaudeSessionID string    `json:"claude_session_id,omitempty"` // Claude Code session ID for --resume
-	Runtime         string    `json:"runtime,omitempty"`           // Runtime: "claude" or "claudesp"
-	WasInterrupted  bool      `json:"was_interrupted,omitempty"`   // True if Claude was working when session ended
-	HasBeenPushed   bool      `json:"has_been_pushed,omitempty"`   // True if branch has been pushed to remote
-	PRNumber        int       `json:"pr_number,omitempty"`         // GitHub PR number if created
-	PRURL           string    `json:"pr_url,omitempty"`            // GitHub PR URL if created
-	CreatedAt       time.Time `json:"created_at"`
+	ID              string                  `json:"id"`
+	BranchName      string                  `json:"branch_name"`
+	Prompt          string                  `json:"prompt"`
+	Title           string                  `json:"title,omitempty"`    // Short summary title
+	Synopsis        string                  `json:"synopsis,omitempty"` // Brief description of work accomplished
+	ContainerID     string                  `json:"container_id"`
+	ClaudeSessionID string                  `json:"claude_session_id,omitempty"` // Claude Code session ID for --resume
+	Runtime         string                  `json:"runtime,omitempty"`           // Runtime: "claude" or "claudesp"
+	WasInterrupted  bool                    `json:"was_interrupted,omitempty"`   // True if Claude was working when session ended
+	HasBeenPushed   bool                    `json:"has_been_pushed,omitempty"`   // True if branch has been pushed to remote
+	PRNumber        int                     `json:"pr_number,omitempty"`         // GitHub PR number if created
+	PRURL           string                  `json:"pr_url,omitempty"`            // GitHub PR URL if created
+	GroupID         string                  `json:"group_id,omitempty"`          // Best-of-N group shared with sibling workstreams
+	Template        string                  `json:"template,omitempty"`          // Template the workstream was created from
+	SparsePaths     []string                `json:"sparse_paths,omitempty"`      // Sparse checkout directories; empty = full checkout
+	Labels          []string                `json:"labels,omitempty"`            // User-assigned labels
+	Priority        int                     `json:"priority,omitempty"`          // 1 (most urgent) to MaxPriority; 0 = unset
+	LastActivity    *time.Time              `json:"last_activity,omitempty"`     // Last interaction time (for sorting)
+	Usage           map[string]claude.Usage `json:"usage,omitempty"`             // Token usage per Claude session ID
+	Budget          *claude.Budget          `json:"budget,omitempty"`            // Budget override, if set
+	Review          []ReviewComment         `json:"review,omitempty"`            // Review comments left in the diff viewer
+	PRFeedbackSeen  *time.Time              `json:"pr_feedback_seen,omitempty"`  // Latest PR review activity notified about
+	CIFixAttempts   int                     `json:"ci_fix_attempts,omitempty"`   // CI auto-fix attempts since checks last passed
+	CIFixSHA        string                  `json:"ci_fix_sha,omitempty"`        // PR head commit of the last CI fix attempt
+	CreatedAt       time.Time               `json:"created_at"`
+}
+
+// Restore recreates a workstream from its saved state, for resuming it or
+// for rewriting the state file. The timeline and repo path are not part of
+// the saved workstream and are set by the caller.
+func (s SavedWorkstream) Restore() *Workstream {
+	ws := NewWithID(s.ID, s.BranchName, s.Prompt)
+	ws.ContainerID = s.ContainerID
+	ws.CreatedAt = s.CreatedAt
+	ws.Title = s.Title
+	ws.Synopsis = s.Synopsis
+	ws.ClaudeSessionID = s.ClaudeSessionID
+	ws.Runtime = s.Runtime
+	ws.WasInterrupted = s.WasInterrupted
+	ws.HasBeenPushed = s.HasBeenPushed
+	ws.PRNumber = s.PRNumber
+	ws.PRURL = s.PRURL
+	ws.GroupID = s.GroupID
+	ws.Template = s.Template
+	ws.SparsePaths = s.SparsePaths
+	ws.Labels = s.Labels
+	ws.Priority = s.Priority
+	ws.Usage = s.Usage
+	if s.Budget != nil {
+		ws.Budget = *s.Budget
+	}
+	ws.Review = s.Review
+	if s.PRFeedbackSeen != nil {
+		ws.PRFeedbackSeen = *s.PRFeedbackSeen
+	}
+	ws.CIFixAttempts = s.CIFixAttempts
+	ws.CIFixSHA = s.CIFixSHA
+	// Files from before activity was tracked fall back to the creation time
+	switch {
+	case s.LastActivity != nil:
+		ws.LastActivity = *s.LastActivity
+	case !s.CreatedAt.IsZero():
+		ws.LastActivity = s.CreatedAt
+	}
+	return ws
 }
 
 // AppState represents the saved application state
@@ -126,7 +181,12 @@ func SaveStateWithRepoInfo(dir string, workstreams []*Workstream, focusedIndex i
 
 	// Try to load existing state to preserve RepoInfo
 	var existingRepoInfo *RepoInfo
-	if existing, err := loadStateUnsafe(dir); err == nil && existing.Repo != nil {
+	existing, err := loadStateUnsafe(dir)
+	if errors.Is(err, ErrStateTooNew) {
+		// Overwriting would drop whatever the newer version stored
+		return err
+	}
+	if err == nil && existing.Repo != nil {
 		existingRepoInfo = existing.Repo
 	}
 
@@ -137,7 +197,7 @@ func SaveStateWithRepoInfo(dir string, workstreams []*Workstream, focusedIndex i
 	}
 
 	state := AppState{
-		Version:      1,
+		Version:      CurrentStateVersion,
 		Repo:         finalRepoInfo,
 		FocusedIndex: focusedIndex,
 		Layout:       layout,
@@ -149,6 +209,7 @@ func SaveStateWithRepoInfo(dir string, workstreams []*Workstream, focusedIndex i
 		if ws.BranchName == "" {
 			continue
 		}
+		ciFixAttempts, ciFixSHA := ws.GetCIFix()
 		state.Workstreams = append(state.Workstreams, SavedWorkstream{
 			ID:              ws.ID,
 			BranchName:      ws.BranchName,
@@ -162,6 +223,18 @@ func SaveStateWithRepoInfo(dir string, workstreams []*Workstream, focusedIndex i
 			HasBeenPushed:   ws.HasBeenPushed,
 			PRNumber:        ws.PRNumber,
 			PRURL:           ws.PRURL,
+			GroupID:         ws.GroupID,
+			Template:        ws.Template,
+			SparsePaths:     ws.GetSparsePaths(),
+			Labels:          ws.GetLabels(),
+			Priority:        ws.GetPriority(),
+			LastActivity:    timeOrNil(ws.GetLastActivity()),
+			Usage:           ws.GetSessionUsage(),
+			Budget:          budgetOverride(ws.GetBudget()),
+			Review:          ws.GetReviewComments(),
+			PRFeedbackSeen:  timeOrNil(ws.GetPRFeedbackSeen()),
+			CIFixAttempts:   ciFixAttempts,
+			CIFixSHA:        ciFixSHA,
 			CreatedAt:       ws.CreatedAt,
 		})
 	}
@@ -185,10 +258,13 @@ func SaveStateWithRepoInfo(dir string, workstreams []*Workstream, focusedIndex i
 		return fmt.Errorf("failed to rename temp state file: %w", err)
 	}
 
-	return nil
+	// Timelines live in their own file so the state file stays small
+	return saveEventsUnsafe(dir, workstreams)
 }
 
-// LoadState loads the application state from a file
+// LoadState loads the application state from a file.
+// Older schema versions are migrated after backing up the file; a file from
+// a newer version of ccells returns a StateVersionError.
 func LoadState(dir string) (*AppState, error) {
 	return loadStateUnsafe(dir)
 }
@@ -201,12 +277,7 @@ func loadStateUnsafe(dir string) (*AppState, error) {
 		return nil, err
 	}
 
-	var state AppState
-	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, err
-	}
-
-	return &state, nil
+	return decodeState(dir, data)
 }
 
 // StateExists checks if a state file exists in the directory
@@ -215,11 +286,32 @@ func StateExists(dir string) bool {
 	return err == nil
 }
 
-// DeleteState removes the state file
+// DeleteState removes the state file and its events file
 func DeleteState(dir string) error {
+	if err := os.Remove(EventsFilePath(dir)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
 	path := StateFilePath(dir)
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return nil // Already deleted
 	}
 	return os.Remove(path)
 }
+
+// budgetOverride returns a pointer to b, or nil when no override is set, so
+// unset budgets are omitted from the state file.
+func budgetOverride(b claude.Budget) *claude.Budget {
+	if b.IsZero() {
+		return nil
+	}
+	return &b
+}
+
+// timeOrNil returns a pointer to t, or nil when t is zero, so unset times
+// are omitted from the state file.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
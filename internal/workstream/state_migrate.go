@@ -0,0 +1,184 @@
+package workstream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// CurrentStateVersion is the state file schema version written by this build.
+// Bump it and add a migration to stateMigrations only when the schema changes
+// in a way older files need converting for, or older builds can't read.
+// New optional fields keep the version: older builds ignore them, and a build
+// that understands them treats their absence as the default.
+const CurrentStateVersion = 1
+
+// ErrStateTooNew is returned when the state file was written by a newer ccells.
+var ErrStateTooNew = errors.New("state file is newer than this version of ccells")
+
+// StateVersionError describes a state file whose schema version is newer than
+// CurrentStateVersion. It matches ErrStateTooNew with errors.Is.
+type StateVersionError struct {
+	Path    string
+	Version int
+}
+
+func (e *StateVersionError) Error() string {
+	return fmt.Sprintf("state file %s has schema version %d, but this ccells only supports up to version %d; upgrade ccells to use it (the file was left untouched)",
+		e.Path, e.Version, CurrentStateVersion)
+}
+
+func (e *StateVersionError) Is(target error) bool {
+	return target == ErrStateTooNew
+}
+
+// stateMigration upgrades a raw state document from version From to From+1.
+// Migrations work on the decoded JSON rather than AppState so they keep
+// working after the Go types change.
+type stateMigration struct {
+	From        int
+	Description string
+	Migrate     func(doc map[string]any) error
+}
+
+// stateMigrations lists every migration step, one per version, in order.
+var stateMigrations = []stateMigration{
+	{
+		From:        0,
+		Description: "drop workstreams saved without a branch name",
+		Migrate:     migrateStateV0ToV1,
+	},
+}
+
+// migrateStateV0ToV1 handles files written before the version field existed.
+// Those builds also saved workstreams still generating a title, which have
+// no branch and can't be resumed.
+func migrateStateV0ToV1(doc map[string]any) error {
+	raw, ok := doc["workstreams"].([]any)
+	if !ok {
+		return nil
+	}
+	kept := make([]any, 0, len(raw))
+	for _, item := range raw {
+		ws, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("workstream entry is %T, want object", item)
+		}
+		if branch, _ := ws["branch_name"].(string); branch == "" {
+			continue
+		}
+		kept = append(kept, ws)
+	}
+	doc["workstreams"] = kept
+	return nil
+}
+
+// stateDocVersion returns the schema version of a raw state document.
+// Files without a version field predate versioning and count as version 0.
+func stateDocVersion(doc map[string]any) (int, error) {
+	v, ok := doc["version"]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := v.(float64)
+	if !ok || n < 0 || n != float64(int(n)) {
+		return 0, fmt.Errorf("invalid state file version %v", v)
+	}
+	return int(n), nil
+}
+
+// migrateStateDoc applies every migration from the document's version up to
+// CurrentStateVersion and returns the version it started from.
+func migrateStateDoc(doc map[string]any) (int, error) {
+	from, err := stateDocVersion(doc)
+	if err != nil {
+		return 0, err
+	}
+	for v := from; v < CurrentStateVersion; v++ {
+		m := stateMigrations[v]
+		if m.From != v {
+			return from, fmt.Errorf("state migrations out of order: step %d migrates from version %d", v, m.From)
+		}
+		if err := m.Migrate(doc); err != nil {
+			return from, fmt.Errorf("state migration v%d->v%d (%s) failed: %w", v, v+1, m.Description, err)
+		}
+		doc["version"] = v + 1
+	}
+	return from, nil
+}
+
+// StateBackupPath returns where a state file is backed up before migrating
+// it from the given version.
+func StateBackupPath(dir string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", StateFilePath(dir), version)
+}
+
+// decodeState parses state file contents, migrating older schemas.
+// A backup of the original contents is written to StateBackupPath before
+// migrating. Newer schemas are refused with a StateVersionError.
+func decodeState(dir string, data []byte) (*AppState, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	version, err := stateDocVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentStateVersion {
+		return nil, &StateVersionError{Path: StateFilePath(dir), Version: version}
+	}
+
+	if version < CurrentStateVersion {
+		// Keep the first backup per version; later loads re-migrate the same file
+		backup := StateBackupPath(dir, version)
+		if _, err := os.Stat(backup); os.IsNotExist(err) {
+			if err := os.WriteFile(backup, data, 0644); err != nil {
+				return nil, fmt.Errorf("failed to back up state file before migrating: %w", err)
+			}
+		}
+		if _, err := migrateStateDoc(doc); err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	var state AppState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// StateFileVersion returns the schema version of the state file in dir
+// without migrating it.
+func StateFileVersion(dir string) (int, error) {
+	data, err := os.ReadFile(StateFilePath(dir))
+	if err != nil {
+		return 0, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return 0, err
+	}
+	return stateDocVersion(doc)
+}
+
+// CheckStateVersion returns a StateVersionError if the state file in dir was
+// written by a newer ccells. A missing state file is not an error.
+func CheckStateVersion(dir string) error {
+	version, err := StateFileVersion(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if version > CurrentStateVersion {
+		return &StateVersionError{Path: StateFilePath(dir), Version: version}
+	}
+	return nil
+}
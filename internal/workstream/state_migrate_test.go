@@ -0,0 +1,160 @@
+package workstream
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func writeStateFile(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(StateFilePath(dir), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+}
+
+func TestStateMigrations_CoverEveryVersion(t *testing.T) {
+	if len(stateMigrations) != CurrentStateVersion {
+		t.Fatalf("have %d migrations, want one per version up to %d", len(stateMigrations), CurrentStateVersion)
+	}
+	for i, m := range stateMigrations {
+		if m.From != i {
+			t.Errorf("migration %d migrates from version %d", i, m.From)
+		}
+		if m.Description == "" || m.Migrate == nil {
+			t.Errorf("migration %d is incomplete", i)
+		}
+	}
+}
+
+func TestMigrateStateV0ToV1(t *testing.T) {
+	doc := map[string]any{
+		"workstreams": []any{
+			map[string]any{"id": "a", "branch_name": "feature"},
+			map[string]any{"id": "b", "branch_name": ""},
+			map[string]any{"id": "c"},
+		},
+	}
+	if err := migrateStateV0ToV1(doc); err != nil {
+		t.Fatalf("migrateStateV0ToV1() error = %v", err)
+	}
+	got := doc["workstreams"].([]any)
+	if len(got) != 1 || got[0].(map[string]any)["id"] != "a" {
+		t.Errorf("expected only workstream a to remain, got %v", got)
+	}
+
+	bad := map[string]any{"workstreams": []any{"not an object"}}
+	if err := migrateStateV0ToV1(bad); err == nil {
+		t.Error("expected error for malformed workstream entry")
+	}
+}
+
+func TestLoadState_MigratesFromEveryVersion(t *testing.T) {
+	files := map[int]string{
+		0: `{"workstreams": [{"id": "a", "branch_name": "feature", "prompt": "p", "container_id": "c1"}, {"id": "b", "branch_name": ""}], "focused_index": 0, "layout": 1}`,
+	}
+	for v := 0; v < CurrentStateVersion; v++ {
+		content, ok := files[v]
+		if !ok {
+			t.Fatalf("no fixture for state version %d", v)
+		}
+		dir := t.TempDir()
+		writeStateFile(t, dir, content)
+
+		state, err := LoadState(dir)
+		if err != nil {
+			t.Fatalf("v%d: LoadState() error = %v", v, err)
+		}
+		if state.Version != CurrentStateVersion {
+			t.Errorf("v%d: migrated Version = %d, want %d", v, state.Version, CurrentStateVersion)
+		}
+		if len(state.Workstreams) != 1 || state.Workstreams[0].BranchName != "feature" || state.Layout != 1 {
+			t.Errorf("v%d: unexpected migrated state: %+v", v, state)
+		}
+
+		// The original file is backed up and left as-is until the next save
+		backup, err := os.ReadFile(StateBackupPath(dir, v))
+		if err != nil {
+			t.Fatalf("v%d: expected backup: %v", v, err)
+		}
+		if string(backup) != content {
+			t.Errorf("v%d: backup does not match original", v)
+		}
+	}
+}
+
+func TestLoadState_CurrentVersionNoBackup(t *testing.T) {
+	dir := t.TempDir()
+	ws := NewWithID("a", "feature", "prompt")
+	if err := SaveState(dir, []*Workstream{ws}, 0, 0); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if _, err := LoadState(dir); err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	for v := 0; v <= CurrentStateVersion; v++ {
+		if _, err := os.Stat(StateBackupPath(dir, v)); err == nil {
+			t.Errorf("unexpected backup for version %d", v)
+		}
+	}
+}
+
+func TestLoadState_RefusesNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"version": 99, "workstreams": [{"id": "a", "branch_name": "feature"}], "future_field": true}`
+	writeStateFile(t, dir, content)
+
+	_, err := LoadState(dir)
+	if !errors.Is(err, ErrStateTooNew) {
+		t.Fatalf("LoadState() error = %v, want ErrStateTooNew", err)
+	}
+	if !strings.Contains(err.Error(), "version 99") {
+		t.Errorf("error should name the file's version: %v", err)
+	}
+	if err := CheckStateVersion(dir); !errors.Is(err, ErrStateTooNew) {
+		t.Errorf("CheckStateVersion() = %v, want ErrStateTooNew", err)
+	}
+
+	// Saving must not overwrite the newer file
+	if err := SaveState(dir, []*Workstream{NewWithID("b", "other", "p")}, 0, 0); !errors.Is(err, ErrStateTooNew) {
+		t.Errorf("SaveState() error = %v, want ErrStateTooNew", err)
+	}
+	data, _ := os.ReadFile(StateFilePath(dir))
+	if string(data) != content {
+		t.Error("state file from a newer version was modified")
+	}
+}
+
+func TestStateFileVersion(t *testing.T) {
+	dir := t.TempDir()
+	if err := CheckStateVersion(dir); err != nil {
+		t.Errorf("CheckStateVersion() with no state file = %v", err)
+	}
+
+	writeStateFile(t, dir, `{"workstreams": []}`)
+	if v, err := StateFileVersion(dir); err != nil || v != 0 {
+		t.Errorf("StateFileVersion() = %d, %v; want 0", v, err)
+	}
+
+	writeStateFile(t, dir, `{"version": "two"}`)
+	if _, err := StateFileVersion(dir); err == nil {
+		t.Error("expected error for non-numeric version")
+	}
+}
+
+func TestLoadState_OptionalFieldsKeepVersion(t *testing.T) {
+	// Fields added without a version bump load as their defaults when missing
+	dir := t.TempDir()
+	writeStateFile(t, dir, `{"version": 1, "workstreams": [{"id": "a", "branch_name": "feature", "prompt": "p", "container_id": "c1"}], "focused_index": 0, "layout": 1}`)
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if ws := state.Workstreams[0]; ws.Labels != nil || ws.Usage != nil || ws.SparsePaths != nil || ws.CIFixAttempts != 0 {
+		t.Errorf("unexpected defaults: %+v", ws)
+	}
+	if _, err := os.Stat(StateBackupPath(dir, 1)); err == nil {
+		t.Error("a current file should not be backed up")
+	}
+}
@@ -3,9 +3,13 @@ package workstream
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/STRML/claude-cells/internal/claude"
 )
 
 func TestStateFilePath(t *testing.T) {
@@ -53,8 +57,8 @@ func TestSaveAndLoadState(t *testing.T) {
 	}
 
 	// Verify loaded state
-	if state.Version != 1 {
-		t.Errorf("Version = %d, want 1", state.Version)
+	if state.Version != CurrentStateVersion {
+		t.Errorf("Version = %d, want %d", state.Version, CurrentStateVersion)
 	}
 	if state.FocusedIndex != focusedIndex {
 		t.Errorf("FocusedIndex = %d, want %d", state.FocusedIndex, focusedIndex)
@@ -291,6 +295,139 @@ func TestSaveStatePreservesAllFields(t *testing.T) {
 	}
 }
 
+// TestSavedWorkstreamRestore_RoundTrip checks that restoring a saved
+// workstream and saving it again, as --repair-state does, loses nothing.
+func TestSavedWorkstreamRestore_RoundTrip(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	saved := SavedWorkstream{
+		ID:              "ws-1",
+		BranchName:      "ccells/feature",
+		Prompt:          "add a feature",
+		Title:           "Feature",
+		Synopsis:        "Added the feature",
+		ContainerID:     "abc123def456",
+		ClaudeSessionID: "session-1",
+		Runtime:         "claudesp",
+		WasInterrupted:  true,
+		HasBeenPushed:   true,
+		PRNumber:        42,
+		PRURL:           "https://github.com/o/r/pull/42",
+		GroupID:         "group-1",
+		Template:        "bugfix",
+		SparsePaths:     []string{"api"},
+		Labels:          []string{"backend"},
+		Priority:        2,
+		LastActivity:    timeOrNil(at.Add(time.Hour)),
+		Usage:           map[string]claude.Usage{"session-1": {InputTokens: 10, OutputTokens: 20, CostUSD: 0.5}},
+		Budget:          &claude.Budget{CostUSD: 5},
+		Review:          []ReviewComment{{ID: 1, File: "a.go", Line: 3, Body: "Rename this", CreatedAt: at}},
+		PRFeedbackSeen:  timeOrNil(at.Add(2 * time.Hour)),
+		CIFixAttempts:   1,
+		CIFixSHA:        "deadbeef",
+		CreatedAt:       at,
+	}
+	// Every field is set, so a field Restore forgets shows up below
+	v := reflect.ValueOf(saved)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsZero() {
+			t.Fatalf("set %s in the test so the round trip covers it", v.Type().Field(i).Name)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := SaveState(dir, []*Workstream{saved.Restore()}, 0, 0); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if len(state.Workstreams) != 1 || !reflect.DeepEqual(state.Workstreams[0], saved) {
+		t.Errorf("round trip:\n got %+v\nwant %+v", state.Workstreams, saved)
+	}
+}
+
+func TestSaveStatePreservesLabelsAndPriority(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ws := NewWithID("id-1", "feature", "prompt")
+	ws.SetLabels([]string{"backend", "auth"})
+	ws.SetPriority(2)
+	if err := SaveState(tmpDir, []*Workstream{ws}, 0, 0); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	state, err := LoadState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	saved := state.Workstreams[0]
+	if len(saved.Labels) != 2 || saved.Labels[0] != "backend" || saved.Labels[1] != "auth" {
+		t.Errorf("Labels = %v, want [backend auth]", saved.Labels)
+	}
+	if saved.Priority != 2 {
+		t.Errorf("Priority = %d, want 2", saved.Priority)
+	}
+	if saved.LastActivity == nil || !saved.LastActivity.Equal(ws.GetLastActivity()) {
+		t.Errorf("LastActivity = %v, want %v", saved.LastActivity, ws.GetLastActivity())
+	}
+}
+
+func TestSaveStateOmitsUnsetTimes(t *testing.T) {
+	dir := t.TempDir()
+	ws := NewWithID("ws-1", "feature", "prompt")
+	ws.LastActivity = time.Time{}
+	if err := SaveState(dir, []*Workstream{ws}, 0, 0); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	data, err := os.ReadFile(StateFilePath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"last_activity", "pr_feedback_seen"} {
+		if strings.Contains(string(data), key) {
+			t.Errorf("state file should omit the unset %s:\n%s", key, data)
+		}
+	}
+}
+
+func TestSaveStatePreservesUsageAndBudget(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ws := NewWithID("id-1", "feature", "prompt")
+	ws.MergeSessionUsage(map[string]claude.Usage{
+		"s1": {InputTokens: 10, OutputTokens: 20, CostUSD: 0.5},
+		"s2": {OutputTokens: 5, CostUSD: 0.25},
+	})
+	if err := SaveState(tmpDir, []*Workstream{ws}, 0, 0); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	state, err := LoadState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	saved := state.Workstreams[0]
+	if len(saved.Usage) != 2 || saved.Usage["s1"].OutputTokens != 20 || saved.Usage["s2"].CostUSD != 0.25 {
+		t.Errorf("Usage = %+v, want both sessions", saved.Usage)
+	}
+	if saved.Budget != nil {
+		t.Errorf("Budget = %+v, want nil when no override is set", saved.Budget)
+	}
+
+	ws.SetBudget(claude.Budget{CostUSD: 5})
+	if err := SaveState(tmpDir, []*Workstream{ws}, 0, 0); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	state, err = LoadState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if b := state.Workstreams[0].Budget; b == nil || b.CostUSD != 5 {
+		t.Errorf("Budget = %+v, want $5 override", b)
+	}
+}
+
 func TestLoadStateCorruptJSON(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "ccells-test-*")
 	if err != nil {
@@ -434,7 +571,7 @@ func TestSaveStateConcurrent(t *testing.T) {
 	entries, _ := os.ReadDir(tmpDir)
 	for _, entry := range entries {
 		name := entry.Name()
-		if name != ".claude-cells-state.json" {
+		if name != ".claude-cells-state.json" && name != ".claude-cells-events.json" {
 			t.Errorf("Unexpected file after concurrent saves: %s", name)
 		}
 	}
@@ -613,3 +750,69 @@ func TestSaveStateWasInterruptedFalse(t *testing.T) {
 		t.Error("WasInterrupted should be false when not set")
 	}
 }
+
+func TestSaveStatePreservesGroupID(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	grouped := New("test prompt")
+	grouped.ContainerID = "container-123"
+	grouped.SetGroupID("bon-1")
+	solo := New("other prompt")
+	solo.ContainerID = "container-456"
+
+	if err := SaveState(tmpDir, []*Workstream{grouped, solo}, 0, 0); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	state, err := LoadState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+
+	if state.Workstreams[0].GroupID != "bon-1" {
+		t.Errorf("GroupID = %q, want %q", state.Workstreams[0].GroupID, "bon-1")
+	}
+	if state.Workstreams[1].GroupID != "" {
+		t.Errorf("GroupID should be empty for ungrouped workstream, got %q", state.Workstreams[1].GroupID)
+	}
+}
+
+func TestSaveStatePreservesTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ws := New("test prompt")
+	ws.ContainerID = "container-123"
+	ws.Template = "bugfix"
+
+	if err := SaveState(tmpDir, []*Workstream{ws}, 0, 0); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	state, err := LoadState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if state.Workstreams[0].Template != "bugfix" {
+		t.Errorf("Template = %q, want %q", state.Workstreams[0].Template, "bugfix")
+	}
+}
+
+func TestSaveStatePreservesSparsePaths(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ws := New("test prompt")
+	ws.ContainerID = "container-123"
+	ws.SetSparsePaths([]string{"services/api", "libs/core"})
+
+	if err := SaveState(tmpDir, []*Workstream{ws}, 0, 0); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	state, err := LoadState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if got := state.Workstreams[0].SparsePaths; len(got) != 2 || got[0] != "services/api" || got[1] != "libs/core" {
+		t.Errorf("SparsePaths = %v, want [services/api libs/core]", got)
+	}
+}
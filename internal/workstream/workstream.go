@@ -5,8 +5,13 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/STRML/claude-cells/internal/claude"
 )
 
+// MaxPriority is the lowest priority a workstream can be given (P1 is the most urgent).
+const MaxPriority = 5
+
 // idCounter ensures unique IDs even when created in quick succession
 var idCounter atomic.Uint64
 
@@ -43,6 +48,11 @@ type Workstream struct {
 	// Git worktree (container has isolated working directory)
 	WorktreePath string // Path to git worktree on host
 
+	// Repository the workstream belongs to. Empty for the repository ccells
+	// was started in; set for workstreams of additionally opened repositories.
+	// Not persisted: each repository keeps its own state file.
+	RepoPath string
+
 	// Claude Code session
 	ClaudeSessionID string // Claude Code session ID for --resume (captured from output)
 	Runtime         string // Runtime: "claude" (default) or "claudesp" (experimental)
@@ -57,6 +67,14 @@ type Workstream struct {
 	PRNumber int    // GitHub PR number if created
 	PRURL    string // GitHub PR URL if created
 
+	// Latest PR review activity the user has been notified about
+	PRFeedbackSeen time.Time
+
+	// CI auto-fix attempts since the PR's checks last passed, and the PR head
+	// commit the last attempt was made for
+	CIFixAttempts int
+	CIFixSHA      string
+
 	// Push tracking
 	HasBeenPushed bool // True if the branch has been pushed to remote (warns against commit amends)
 
@@ -65,6 +83,32 @@ type Workstream struct {
 
 	// Synopsis - brief description of what was accomplished
 	Synopsis string // Generated after session ends to summarize work done
+
+	// Best-of-N grouping (optional)
+	GroupID string // Shared by workstreams started from the same prompt to compare results
+
+	// Template preset (optional)
+	Template string // Name of the cells config template the workstream was created from
+
+	// Sparse checkout (optional)
+	SparsePaths []string // Directories checked out in cone mode; empty = full checkout
+
+	// Organization (user-assigned, optional)
+	Labels   []string // Free-form labels used to filter panes
+	Priority int      // 1 (most urgent) to MaxPriority; 0 = unset
+
+	// Token usage per Claude session ID. Kept after the session files are
+	// gone so the totals survive container rebuilds.
+	Usage map[string]claude.Usage
+
+	// Budget overrides the configured per-workstream budget when set
+	Budget claude.Budget
+
+	// Review comments left in the diff viewer
+	Review []ReviewComment
+
+	// Timeline (append-only, persisted next to the state file)
+	Events []Event
 }
 
 // New creates a new workstream from a prompt.
@@ -131,24 +175,41 @@ func (w *Workstream) SetBranchNameFromTitle(title string, existingBranches []str
 	w.BranchName = GenerateUniqueBranchName(title, existingBranches)
 }
 
+// SetBranchNameFromTitleWithPrefix is like SetBranchNameFromTitle but
+// prepends a branch prefix such as "bugfix/".
+func (w *Workstream) SetBranchNameFromTitleWithPrefix(title, prefix string, existingBranches []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.BranchName = GenerateUniqueBranchNameWithPrefix(title, prefix, existingBranches)
+}
+
 // SetState updates the workstream state.
 func (w *Workstream) SetState(state State) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	now := time.Now()
+	if w.State != state {
+		w.appendEventLocked(Event{Time: now, Type: EventStateChange, State: state, Detail: "from " + string(w.State)})
+	}
 	w.State = state
 	w.ErrorMessage = "" // Clear error when state changes
-	w.LastActivity = time.Now()
+	w.LastActivity = now
 }
 
 // SetError sets the workstream to error state with a message.
 func (w *Workstream) SetError(err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	now := time.Now()
+	if w.State != StateError {
+		w.appendEventLocked(Event{Time: now, Type: EventStateChange, State: StateError, Detail: "from " + string(w.State)})
+	}
 	w.State = StateError
 	if err != nil {
 		w.ErrorMessage = err.Error()
 	}
-	w.LastActivity = time.Now()
+	w.appendEventLocked(Event{Time: now, Type: EventError, Detail: w.ErrorMessage})
+	w.LastActivity = now
 }
 
 // SetContainerID sets the Docker container ID.
@@ -240,6 +301,23 @@ func (w *Workstream) GetPRInfo() (int, string) {
 	return w.PRNumber, w.PRURL
 }
 
+// SetCIFix records the CI auto-fix attempt count and the PR head commit of
+// the last attempt.
+func (w *Workstream) SetCIFix(attempts int, sha string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.CIFixAttempts = attempts
+	w.CIFixSHA = sha
+}
+
+// GetCIFix returns the CI auto-fix attempt count and the PR head commit of
+// the last attempt (thread-safe).
+func (w *Workstream) GetCIFix() (int, string) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.CIFixAttempts, w.CIFixSHA
+}
+
 // SetSynopsis sets the synopsis (brief description of work done).
 func (w *Workstream) SetSynopsis(synopsis string) {
 	w.mu.Lock()
@@ -253,3 +331,136 @@ func (w *Workstream) GetSynopsis() string {
 	defer w.mu.RUnlock()
 	return w.Synopsis
 }
+
+// SetGroupID sets the Best-of-N group this workstream belongs to.
+func (w *Workstream) SetGroupID(groupID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.GroupID = groupID
+}
+
+// GetGroupID returns the Best-of-N group ID (empty if not grouped).
+func (w *Workstream) GetGroupID() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.GroupID
+}
+
+// SetLabels replaces the workstream's labels.
+func (w *Workstream) SetLabels(labels []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Labels = append([]string(nil), labels...)
+}
+
+// GetLabels returns a copy of the workstream's labels (thread-safe).
+func (w *Workstream) GetLabels() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return append([]string(nil), w.Labels...)
+}
+
+// SetSparsePaths replaces the directories of the workstream's sparse checkout.
+func (w *Workstream) SetSparsePaths(paths []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.SparsePaths = append([]string(nil), paths...)
+}
+
+// GetSparsePaths returns a copy of the sparse checkout directories (thread-safe).
+func (w *Workstream) GetSparsePaths() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return append([]string(nil), w.SparsePaths...)
+}
+
+// HasLabel reports whether the workstream has the given label.
+func (w *Workstream) HasLabel(label string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, l := range w.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPriority sets the priority (0 clears it). Out-of-range values are clamped.
+func (w *Workstream) SetPriority(priority int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Priority = max(0, min(priority, MaxPriority))
+}
+
+// GetPriority returns the priority, or 0 if unset (thread-safe).
+func (w *Workstream) GetPriority() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.Priority
+}
+
+// GetLastActivity returns the last activity time (thread-safe).
+func (w *Workstream) GetLastActivity() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.LastActivity
+}
+
+// MergeSessionUsage records the latest usage of each given session and
+// reports whether anything changed. Sessions not in the map are kept.
+func (w *Workstream) MergeSessionUsage(sessions map[string]claude.Usage) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	changed := false
+	for id, u := range sessions {
+		if old, ok := w.Usage[id]; ok && old == u {
+			continue
+		}
+		if w.Usage == nil {
+			w.Usage = make(map[string]claude.Usage)
+		}
+		w.Usage[id] = u
+		changed = true
+	}
+	return changed
+}
+
+// GetUsage returns the total usage across all sessions (thread-safe).
+func (w *Workstream) GetUsage() claude.Usage {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	var total claude.Usage
+	for _, u := range w.Usage {
+		total = total.Add(u)
+	}
+	return total
+}
+
+// GetSessionUsage returns a copy of the per-session usage (thread-safe).
+func (w *Workstream) GetSessionUsage() map[string]claude.Usage {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if len(w.Usage) == 0 {
+		return nil
+	}
+	sessions := make(map[string]claude.Usage, len(w.Usage))
+	for id, u := range w.Usage {
+		sessions[id] = u
+	}
+	return sessions
+}
+
+// SetBudget sets the workstream's budget override (zero uses the configured default).
+func (w *Workstream) SetBudget(budget claude.Budget) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Budget = budget
+}
+
+// GetBudget returns the workstream's budget override (thread-safe).
+func (w *Workstream) GetBudget() claude.Budget {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.Budget
+}
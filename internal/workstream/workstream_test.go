@@ -3,6 +3,8 @@ package workstream
 import (
 	"testing"
 	"time"
+
+	"github.com/STRML/claude-cells/internal/claude"
 )
 
 func TestWorkstreamState(t *testing.T) {
@@ -186,3 +188,24 @@ func TestWorkstream_GetClaudeSessionID_Empty(t *testing.T) {
 		t.Errorf("GetClaudeSessionID() = %q, want empty string", got)
 	}
 }
+
+func TestWorkstream_MergeSessionUsage(t *testing.T) {
+	ws := New("test")
+	if !ws.GetUsage().IsZero() {
+		t.Fatal("new workstream should have no usage")
+	}
+
+	if !ws.MergeSessionUsage(map[string]claude.Usage{"s1": {OutputTokens: 10, CostUSD: 1}}) {
+		t.Error("first merge should report a change")
+	}
+	if ws.MergeSessionUsage(map[string]claude.Usage{"s1": {OutputTokens: 10, CostUSD: 1}}) {
+		t.Error("merging identical usage should not report a change")
+	}
+
+	// A later scan that no longer sees s1 keeps its usage
+	ws.MergeSessionUsage(map[string]claude.Usage{"s2": {OutputTokens: 5, CostUSD: 0.5}})
+	total := ws.GetUsage()
+	if total.OutputTokens != 15 || total.CostUSD != 1.5 {
+		t.Errorf("GetUsage() = %+v, want 15 output tokens and $1.50", total)
+	}
+}